@@ -1,19 +1,28 @@
 package main
 
 import (
+	"context"
+	"crypto/tls"
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	_ "github.com/k6zma/DockerMonitoringApp/backend/docs"
+	"github.com/k6zma/DockerMonitoringApp/backend/internal/application/usecases"
 	"github.com/k6zma/DockerMonitoringApp/backend/internal/infrastructure/config"
 	"github.com/k6zma/DockerMonitoringApp/backend/internal/infrastructure/db/postgres"
 	"github.com/k6zma/DockerMonitoringApp/backend/internal/infrastructure/flags"
+	"github.com/k6zma/DockerMonitoringApp/backend/internal/infrastructure/jwtauth"
 	"github.com/k6zma/DockerMonitoringApp/backend/internal/infrastructure/migrations"
+	"github.com/k6zma/DockerMonitoringApp/backend/internal/infrastructure/tlsreload"
+	"github.com/k6zma/DockerMonitoringApp/backend/internal/infrastructure/tracing"
 	"github.com/k6zma/DockerMonitoringApp/backend/internal/presentation/server"
 	"github.com/k6zma/DockerMonitoringApp/backend/pkg/utils"
 )
 
+const tracingShutdownTimeout = 5 * time.Second
+
 // @title Docker Monitoring API
 // @version 1.2
 // @description REST API for monitoring Docker containers.
@@ -24,6 +33,10 @@ import (
 // @securityDefinitions.apikey ApiKeyAuth
 // @in header
 // @name X-Api-Key
+// @securityDefinitions.apikey BearerAuth
+// @in header
+// @name Authorization
+// @description JWT issued by the configured identity provider, sent as "Bearer <token>"
 // @security ApiKeyAuth
 func main() {
 	appFlags, err := flags.ParseFlags()
@@ -31,25 +44,49 @@ func main() {
 		panic(err)
 	}
 
-	logger, err := utils.NewLogger(appFlags.LoggerLevel)
+	logger, err := utils.NewLogger(appFlags.LoggerLevel, appFlags.LoggerFormat)
 	if err != nil {
 		panic(err)
 	}
 
-	utils.LoggerInstance.Infof("ENTRY POINT: parsed flags: Config path - %+v, Logging level - %+v",
-		appFlags.ConfigFilePath, appFlags.LoggerLevel)
+	utils.LoggerInstance.Infof("ENTRY POINT: parsed flags: Config path - %+v, Logging level - %+v, Logging format - %+v",
+		appFlags.ConfigFilePath, appFlags.LoggerLevel, appFlags.LoggerFormat)
 	cfg, err := config.LoadConfig(appFlags.ConfigFilePath)
 	if err != nil {
 		utils.LoggerInstance.Fatalf("ENTRY POINT: failed to load configuration: %v", err)
 	}
 	utils.LoggerInstance.Infof(
-		"ENTRY POINT: loaded configuration: Server - %+v, DB - %+v, MigrationsConfig - %+v, API Key - %+v",
+		"ENTRY POINT: loaded configuration: Server - %+v, TLS - %+v, DB - %+v, MigrationsConfig - %+v, API Keys - %+v, JWT - %+v, "+
+			"Signatures - %+v, CrashLoop - %+v, Metrics - %+v, Tracing - %+v, RateLimit - %+v, CORS - %+v, Stream - %+v",
 		cfg.Server,
+		cfg.Server.TLS,
 		cfg.DB,
 		cfg.MigrationsConfig,
-		cfg.AuthAPI,
+		cfg.AuthAPI.Keys,
+		cfg.AuthAPI.JWT,
+		cfg.AuthAPI.Signatures,
+		cfg.CrashLoop,
+		cfg.Metrics,
+		cfg.Tracing,
+		cfg.RateLimit,
+		cfg.CORS,
+		cfg.Stream,
 	)
 
+	shutdownTracing, err := tracing.Setup(context.Background(), cfg.Tracing)
+	if err != nil {
+		utils.LoggerInstance.Fatalf("ENTRY POINT: failed to set up tracing: %v", err)
+	}
+
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), tracingShutdownTimeout)
+		defer cancel()
+
+		if err := shutdownTracing(ctx); err != nil {
+			utils.LoggerInstance.Errorf("ENTRY POINT: failed to flush traces: %v", err)
+		}
+	}()
+
 	utils.LoggerInstance.Infof(
 		"ENTRY POINT: conecting to database \"%s:%d\"",
 		cfg.DB.Host,
@@ -95,11 +132,43 @@ func main() {
 	}
 	utils.LoggerInstance.Info("ENTRY POINT: migrations applied successfully")
 
+	// Readiness only expects the newest schema when migrations are applied; after
+	// a drop or rollback the older schema is intentional.
+	var expectedMigrationVersion uint
+	if cfg.MigrationsConfig.Type == "apply" {
+		expectedMigrationVersion, err = mig.LatestVersion()
+		if err != nil {
+			utils.LoggerInstance.Fatalf("ENTRY POINT: failed to read latest migration version: %v", err)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var tokenVerifier usecases.TokenVerifier
+	if cfg.AuthAPI.JWT.Enabled {
+		verifier, err := jwtauth.NewVerifier(ctx, cfg.AuthAPI.JWT, logger)
+		if err != nil {
+			utils.LoggerInstance.Fatalf("ENTRY POINT: failed to set up JWT authentication: %v", err)
+		}
+		tokenVerifier = verifier
+	}
+
+	var tlsConfig *tls.Config
+	if cfg.Server.TLS.Enabled {
+		reloader, err := tlsreload.NewReloader(ctx, cfg.Server.TLS, logger)
+		if err != nil {
+			utils.LoggerInstance.Fatalf("ENTRY POINT: failed to load TLS certificates: %v", err)
+		}
+		tlsConfig = reloader.ServerConfig()
+	}
+
 	utils.LoggerInstance.Infof(
-		"ENTRY POINT: starting server on port \"localhost:%d\"",
+		"ENTRY POINT: starting server on port \"localhost:%d\" (TLS: %t)",
 		cfg.Server.Port,
+		cfg.Server.TLS.Enabled,
 	)
-	serv := server.NewServer(cfg, database, logger)
+	serv := server.NewServer(cfg, database, tokenVerifier, tlsConfig, expectedMigrationVersion, logger)
 	go func() {
 		if err := serv.Start(); err != nil {
 			utils.LoggerInstance.Fatalf("ENTRY POINT: failed to start server: %v", err)
@@ -0,0 +1,43 @@
+package dto
+
+import "time"
+
+type APIKeyDTO struct {
+	ID         int64
+	Name       string
+	Prefix     string
+	Scopes     []string
+	AuthMode   string
+	CreatedAt  time.Time
+	LastUsedAt *time.Time
+	ExpiresAt  *time.Time
+	RevokedAt  *time.Time
+}
+
+type CreateAPIKeyDTO struct {
+	Name      string
+	Scopes    []string
+	AuthMode  string
+	ExpiresAt *time.Time
+}
+
+// IssuedAPIKeyDTO carries a newly created key. The plain key is only available
+// at this point; afterwards just its digest is stored.
+type IssuedAPIKeyDTO struct {
+	APIKeyDTO
+	Key string
+}
+
+// RequestSignatureDTO is a request signed with an HMAC key. KeyID is the prefix
+// of a stored key or the name of a configured one; the signature covers the
+// method, the path with the query, the timestamp, the nonce and the SHA-256
+// digest of the body.
+type RequestSignatureDTO struct {
+	KeyID     string
+	Timestamp int64
+	Nonce     string
+	Signature string
+	Method    string
+	Path      string
+	BodyHash  string
+}
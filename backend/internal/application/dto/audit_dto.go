@@ -0,0 +1,31 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type AuditEntryDTO struct {
+	ID          int64
+	Actor       string
+	AuthMethod  string
+	ClientIP    string
+	Method      string
+	Route       string
+	Path        string
+	ContainerID string
+	StatusCode  int
+	Before      json.RawMessage
+	After       json.RawMessage
+	RequestID   string
+	CreatedAt   time.Time
+}
+
+type AuditFilter struct {
+	Actor        *string
+	Methods      []string
+	ContainerID  *string
+	CreatedAtGte *time.Time
+	CreatedAtLte *time.Time
+	Limit        *int
+}
@@ -0,0 +1,21 @@
+package dto
+
+import "time"
+
+type ContainerEventDTO struct {
+	ID          int64
+	ContainerID string
+	Type        string
+	Status      string
+	Message     string
+	CreatedAt   time.Time
+}
+
+type ContainerEventFilter struct {
+	ContainerID  *string
+	Types        []string
+	Statuses     []string
+	CreatedAtGte *time.Time
+	CreatedAtLte *time.Time
+	Limit        *int
+}
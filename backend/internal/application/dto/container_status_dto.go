@@ -2,6 +2,8 @@ package dto
 
 import "time"
 
+// ContainerStatusDTO carries a container status. In updates, a nil
+// RestartCount leaves the stored count unchanged.
 type ContainerStatusDTO struct {
 	ContainerID        string
 	Name               string
@@ -9,6 +11,10 @@ type ContainerStatusDTO struct {
 	Status             string
 	PingTime           float64
 	LastSuccessfulPing time.Time
+	RestartCount       *int
+	CrashLooping       bool
+	Labels             map[string]string
+	CheckedAt          time.Time
 	UpdatedAt          time.Time
 	CreatedAt          time.Time
 }
@@ -26,3 +32,31 @@ type ContainerStatusFilter struct {
 	UpdatedAtLte *time.Time
 	Limit        *int
 }
+
+// ContainerStatusListVersionDTO identifies the state of the container statuses
+// matching a filter, see domain.ContainerStatusListVersion.
+type ContainerStatusListVersionDTO struct {
+	Count      int
+	UpdatedAt  time.Time
+	ModifiedAt time.Time
+}
+
+// ContainerStatusChangeDTO is a change delivered to subscribers. ID orders the
+// changes and identifies the position to resume a stream from.
+type ContainerStatusChangeDTO struct {
+	ID         string
+	Type       string
+	Status     ContainerStatusDTO
+	Event      *ContainerEventDTO
+	OccurredAt time.Time
+}
+
+// StatusChangeFilter selects the container status changes a subscriber
+// receives. Empty fields match every container; Labels must all be present
+// with the given values. Alerts are only delivered when Alerts is set.
+type StatusChangeFilter struct {
+	Names    []string
+	Statuses []string
+	Labels   map[string]string
+	Alerts   bool
+}
@@ -0,0 +1,12 @@
+package dto
+
+type HealthCheckDTO struct {
+	Name    string
+	Healthy bool
+	Message string
+}
+
+type HealthReportDTO struct {
+	Healthy bool
+	Checks  []HealthCheckDTO
+}
@@ -0,0 +1,10 @@
+package dto
+
+import "time"
+
+type RoleAssignmentDTO struct {
+	Subject   string
+	Role      string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
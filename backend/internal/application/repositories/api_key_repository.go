@@ -0,0 +1,23 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/k6zma/DockerMonitoringApp/backend/internal/domain"
+)
+
+type APIKeyRepository interface {
+	// FindByID, FindByHash and FindSigningKey return nil without an error when
+	// no key matches. FindSigningKey only finds keys in the HMAC auth mode.
+	FindByID(ctx context.Context, id int64) (*domain.APIKey, error)
+	FindByHash(ctx context.Context, hash string) (*domain.APIKey, error)
+	FindSigningKey(ctx context.Context, prefix string) (*domain.APIKey, error)
+	FindAll(ctx context.Context) ([]*domain.APIKey, error)
+	Create(ctx context.Context, key *domain.APIKey) error
+	// Rotate creates key and limits the validity of the key with oldID to
+	// oldExpiresAt in a single transaction.
+	Rotate(ctx context.Context, oldID int64, oldExpiresAt time.Time, key *domain.APIKey) error
+	Revoke(ctx context.Context, id int64, revokedAt time.Time) error
+	UpdateLastUsed(ctx context.Context, id int64, lastUsedAt time.Time) error
+}
@@ -0,0 +1,13 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/k6zma/DockerMonitoringApp/backend/internal/application/dto"
+	"github.com/k6zma/DockerMonitoringApp/backend/internal/domain"
+)
+
+type AuditRepository interface {
+	Find(ctx context.Context, filter *dto.AuditFilter) ([]*domain.AuditEntry, error)
+	Create(ctx context.Context, entry *domain.AuditEntry) error
+}
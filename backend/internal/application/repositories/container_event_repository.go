@@ -0,0 +1,14 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/k6zma/DockerMonitoringApp/backend/internal/application/dto"
+	"github.com/k6zma/DockerMonitoringApp/backend/internal/domain"
+)
+
+type ContainerEventRepository interface {
+	Find(ctx context.Context, filter *dto.ContainerEventFilter) ([]*domain.ContainerEvent, error)
+	Count(ctx context.Context, filter *dto.ContainerEventFilter) (int, error)
+	Create(ctx context.Context, event *domain.ContainerEvent) error
+}
@@ -1,13 +1,18 @@
 package repositories
 
 import (
+	"context"
+
 	"github.com/k6zma/DockerMonitoringApp/backend/internal/application/dto"
 	"github.com/k6zma/DockerMonitoringApp/backend/internal/domain"
 )
 
 type ContainerStatusRepository interface {
-	Find(filter *dto.ContainerStatusFilter) ([]*domain.ContainerStatus, error)
-	Create(status *domain.ContainerStatus) error
-	Update(status *domain.ContainerStatus) error
-	DeleteByContainerID(containerID string) error
+	Find(ctx context.Context, filter *dto.ContainerStatusFilter) ([]*domain.ContainerStatus, error)
+	// Version describes the rows matching filter without reading them. The
+	// limit of filter is ignored.
+	Version(ctx context.Context, filter *dto.ContainerStatusFilter) (*domain.ContainerStatusListVersion, error)
+	Create(ctx context.Context, status *domain.ContainerStatus) error
+	Update(ctx context.Context, status *domain.ContainerStatus) error
+	DeleteByContainerID(ctx context.Context, containerID string) error
 }
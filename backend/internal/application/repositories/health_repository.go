@@ -0,0 +1,10 @@
+package repositories
+
+import "context"
+
+type HealthRepository interface {
+	Ping(ctx context.Context) error
+	// MigrationState returns the applied schema version and whether the last
+	// migration failed halfway.
+	MigrationState(ctx context.Context) (version uint, dirty bool, err error)
+}
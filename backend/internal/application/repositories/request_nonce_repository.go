@@ -0,0 +1,17 @@
+package repositories
+
+import (
+	"context"
+	"time"
+)
+
+// RequestNonceRepository remembers the nonces of accepted request signatures.
+// It is shared by all replicas of the backend, so a signed request is accepted
+// by at most one of them.
+type RequestNonceRepository interface {
+	// Claim records the nonce of keyID until expiresAt and reports whether it
+	// was unused. A nonce that expired by now can be claimed again.
+	Claim(ctx context.Context, keyID, nonce string, expiresAt, now time.Time) (bool, error)
+	// DeleteExpired removes the nonces that expired by now.
+	DeleteExpired(ctx context.Context, now time.Time) error
+}
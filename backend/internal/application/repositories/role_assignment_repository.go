@@ -0,0 +1,17 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/k6zma/DockerMonitoringApp/backend/internal/domain"
+)
+
+type RoleAssignmentRepository interface {
+	// FindBySubject returns nil without an error when the subject has no role.
+	FindBySubject(ctx context.Context, subject string) (*domain.RoleAssignment, error)
+	FindAll(ctx context.Context) ([]*domain.RoleAssignment, error)
+	// Upsert assigns the role, replacing the role the subject had before.
+	Upsert(ctx context.Context, assignment *domain.RoleAssignment) error
+	// Delete reports whether the subject had a role assigned.
+	Delete(ctx context.Context, subject string) (bool, error)
+}
@@ -0,0 +1,12 @@
+package repositories
+
+import "context"
+
+// Transactor runs several writes in one transaction. The repositories join the
+// transaction of the context they are given.
+type Transactor interface {
+	// WithinTransaction runs fn in a transaction that is committed when fn
+	// returns nil and rolled back otherwise. Called inside a transaction, fn
+	// joins it.
+	WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
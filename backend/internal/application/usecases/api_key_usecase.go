@@ -0,0 +1,516 @@
+package usecases
+
+import (
+	"context"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/k6zma/DockerMonitoringApp/backend/internal/application/dto"
+	"github.com/k6zma/DockerMonitoringApp/backend/internal/application/repositories"
+	"github.com/k6zma/DockerMonitoringApp/backend/internal/domain"
+	"github.com/k6zma/DockerMonitoringApp/backend/pkg/utils"
+)
+
+const (
+	apiKeyPrefix      = "dm_"
+	apiKeyRandomBytes = 32
+	// apiKeyDisplayLength is the number of leading characters of a key kept in
+	// plain text to tell keys apart.
+	apiKeyDisplayLength = 11
+	// lastUsedResolution limits how often the last use of a key is written to
+	// the database.
+	lastUsedResolution = time.Minute
+	// nonceSweepInterval is how often expired nonces are deleted.
+	nonceSweepInterval = time.Minute
+)
+
+// DefaultRotationGracePeriod is how long a rotated key stays valid next to its
+// replacement when no grace period is requested.
+const DefaultRotationGracePeriod = 24 * time.Hour
+
+var (
+	// ErrInvalidAPIKey is returned for unknown, expired and revoked keys.
+	ErrInvalidAPIKey = errors.New("invalid API key")
+	// ErrAPIKeyNotFound is returned when no stored key has the requested ID.
+	ErrAPIKeyNotFound = errors.New("API key not found")
+	// ErrAPIKeyInactive is returned when rotating an expired or revoked key.
+	ErrAPIKeyInactive = errors.New("API key is expired or revoked")
+	// ErrInvalidSignature is returned for signed requests with an unknown key, a
+	// wrong signature, a stale timestamp or a reused nonce.
+	ErrInvalidSignature = errors.New("invalid request signature")
+)
+
+type APIKeyUseCaseInterface interface {
+	Authenticate(ctx context.Context, rawKey string) (*domain.Principal, error)
+	AuthenticateSignature(ctx context.Context, signature *dto.RequestSignatureDTO) (*domain.Principal, error)
+	CreateAPIKey(ctx context.Context, keyDTO *dto.CreateAPIKeyDTO) (*dto.IssuedAPIKeyDTO, error)
+	ListAPIKeys(ctx context.Context) ([]*dto.APIKeyDTO, error)
+	RevokeAPIKey(ctx context.Context, id int64) error
+	RotateAPIKey(ctx context.Context, id int64, gracePeriod time.Duration) (*dto.IssuedAPIKeyDTO, error)
+}
+
+// StaticAPIKey is a key defined in the configuration. Static keys bootstrap the
+// service: they cannot be listed, revoked or rotated through the API. Requests
+// signed with a static key name it by Name.
+type StaticAPIKey struct {
+	Name     string
+	Key      string
+	Scopes   []domain.Scope
+	AuthMode domain.APIKeyAuthMode
+}
+
+// staticSigningKey is a static key in the HMAC auth mode.
+type staticSigningKey struct {
+	principal *domain.Principal
+	secret    []byte
+}
+
+type APIKeyUseCase struct {
+	repo              repositories.APIKeyRepository
+	nonceRepo         repositories.RequestNonceRepository
+	staticKeys        map[string]*domain.Principal
+	staticSigningKeys map[string]staticSigningKey
+	maxClockSkew      time.Duration
+	secrets           cipher.AEAD
+	nonceSweep        sync.Mutex
+	nextNonceSweep    time.Time
+	logger            utils.LoggerInterface
+}
+
+// NewAPIKeyUseCase creates the use case. Signed requests are accepted when
+// their timestamp differs from the server time by at most maxClockSkew. The
+// signing secrets of stored HMAC keys are encrypted with secretKey.
+func NewAPIKeyUseCase(
+	repo repositories.APIKeyRepository,
+	nonceRepo repositories.RequestNonceRepository,
+	staticKeys []StaticAPIKey,
+	maxClockSkew time.Duration,
+	secretKey string,
+	logger utils.LoggerInterface,
+) *APIKeyUseCase {
+	principals := make(map[string]*domain.Principal, len(staticKeys))
+	signingKeys := make(map[string]staticSigningKey)
+	for _, key := range staticKeys {
+		if key.AuthMode == domain.APIKeyAuthModeHMAC {
+			signingKeys[key.Name] = staticSigningKey{
+				principal: &domain.Principal{
+					Name:   key.Name,
+					Method: domain.AuthMethodHMAC,
+					Scopes: key.Scopes,
+				},
+				secret: deriveSigningSecret(key.Key),
+			}
+			continue
+		}
+
+		principals[hashAPIKey(key.Key)] = &domain.Principal{
+			Name:   key.Name,
+			Method: domain.AuthMethodAPIKey,
+			Scopes: key.Scopes,
+		}
+	}
+
+	return &APIKeyUseCase{
+		repo:              repo,
+		nonceRepo:         nonceRepo,
+		staticKeys:        principals,
+		staticSigningKeys: signingKeys,
+		maxClockSkew:      maxClockSkew,
+		secrets:           newSigningSecretCipher(secretKey),
+		logger:            logger,
+	}
+}
+
+// Authenticate resolves a raw key to the principal it belongs to. Keys are
+// looked up by their SHA-256 digest, so the comparison does not leak the key
+// through timing.
+func (uc *APIKeyUseCase) Authenticate(ctx context.Context, rawKey string) (_ *domain.Principal, err error) {
+	ctx, span := tracer.Start(ctx, "APIKeyUseCase.Authenticate")
+	defer func() {
+		if errors.Is(err, ErrInvalidAPIKey) {
+			span.End()
+			return
+		}
+		endSpan(span, err)
+	}()
+
+	if rawKey == "" {
+		return nil, ErrInvalidAPIKey
+	}
+
+	hash := hashAPIKey(rawKey)
+	if principal, ok := uc.staticKeys[hash]; ok {
+		return principal, nil
+	}
+
+	key, err := uc.repo.FindByHash(ctx, hash)
+	if err != nil {
+		utils.ContextLogger(ctx, uc.logger).Errorf("USECASES: failed to look up API key: %v", err)
+		return nil, fmt.Errorf("failed to look up API key: %w", err)
+	}
+
+	now := time.Now()
+	if key == nil || !key.IsActive(now) {
+		return nil, ErrInvalidAPIKey
+	}
+
+	if key.AuthMode == domain.APIKeyAuthModeHMAC {
+		utils.ContextLogger(ctx, uc.logger).Warnf("USECASES: API key %s was sent instead of signing the request", key.Prefix)
+		return nil, ErrInvalidAPIKey
+	}
+
+	uc.recordUse(ctx, key, now)
+
+	return &domain.Principal{
+		Name:   key.Name,
+		Method: domain.AuthMethodAPIKey,
+		Scopes: key.Scopes,
+	}, nil
+}
+
+// AuthenticateSignature resolves a signed request to the principal of the key
+// it was signed with. The signature is an HMAC-SHA256 keyed with the signing
+// secret derived from the API key. The timestamp may differ from the server
+// time by at most the maximum clock skew and a nonce is accepted only once, by
+// any replica, so captured requests cannot be replayed.
+func (uc *APIKeyUseCase) AuthenticateSignature(
+	ctx context.Context,
+	signature *dto.RequestSignatureDTO,
+) (_ *domain.Principal, err error) {
+	ctx, span := tracer.Start(ctx, "APIKeyUseCase.AuthenticateSignature",
+		trace.WithAttributes(attribute.String("api_key.id", signature.KeyID)))
+	defer func() {
+		if errors.Is(err, ErrInvalidSignature) {
+			span.End()
+			return
+		}
+		endSpan(span, err)
+	}()
+
+	logger := utils.ContextLogger(ctx, uc.logger)
+
+	provided, err := hex.DecodeString(signature.Signature)
+	if err != nil || signature.KeyID == "" || signature.Nonce == "" || len(signature.Nonce) > maxNonceLength {
+		return nil, ErrInvalidSignature
+	}
+
+	now := time.Now()
+	signedAt := time.Unix(signature.Timestamp, 0)
+	if signedAt.Before(now.Add(-uc.maxClockSkew)) || signedAt.After(now.Add(uc.maxClockSkew)) {
+		logger.Warnf("USECASES: request signed with key %s at %s is outside the allowed clock skew",
+			signature.KeyID, signedAt.Format(time.RFC3339))
+		return nil, ErrInvalidSignature
+	}
+
+	principal, key, secret, err := uc.signingKey(ctx, signature.KeyID, now)
+	if err != nil {
+		return nil, err
+	}
+
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(signaturePayload(signature)))
+	if !hmac.Equal(mac.Sum(nil), provided) {
+		logger.Warnf("USECASES: request signed with key %s has a wrong signature", signature.KeyID)
+		return nil, ErrInvalidSignature
+	}
+
+	// The nonce is only remembered for valid signatures, so forged requests
+	// cannot fill the table. It is needed until the timestamp falls out of the
+	// clock skew; older requests are refused by their timestamp anyway.
+	claimed, err := uc.nonceRepo.Claim(ctx, signature.KeyID, signature.Nonce, signedAt.Add(uc.maxClockSkew), now)
+	if err != nil {
+		logger.Errorf("USECASES: failed to claim nonce of signing key %s: %v", signature.KeyID, err)
+		return nil, fmt.Errorf("failed to claim nonce: %w", err)
+	}
+	if !claimed {
+		logger.Warnf("USECASES: request signed with key %s reuses nonce %s", signature.KeyID, signature.Nonce)
+		return nil, ErrInvalidSignature
+	}
+
+	uc.sweepNonces(ctx, now)
+
+	if key != nil {
+		uc.recordUse(ctx, key, now)
+	}
+
+	return principal, nil
+}
+
+func (uc *APIKeyUseCase) CreateAPIKey(
+	ctx context.Context,
+	keyDTO *dto.CreateAPIKeyDTO,
+) (_ *dto.IssuedAPIKeyDTO, err error) {
+	ctx, span := tracer.Start(ctx, "APIKeyUseCase.CreateAPIKey",
+		trace.WithAttributes(attribute.String("api_key.name", keyDTO.Name)))
+	defer func() { endSpan(span, err) }()
+
+	logger := utils.ContextLogger(ctx, uc.logger, "api_key_name", keyDTO.Name)
+
+	logger.Debugf("USECASES: creating API key %s with scopes %v", keyDTO.Name, keyDTO.Scopes)
+
+	scopes := make([]domain.Scope, 0, len(keyDTO.Scopes))
+	for _, scope := range keyDTO.Scopes {
+		scopes = append(scopes, domain.Scope(scope))
+	}
+
+	authMode := domain.APIKeyAuthMode(keyDTO.AuthMode)
+	if authMode == "" {
+		authMode = domain.APIKeyAuthModeHeader
+	}
+
+	rawKey, key, err := uc.newAPIKey(keyDTO.Name, scopes, authMode, keyDTO.ExpiresAt)
+	if err != nil {
+		logger.Errorf("USECASES: failed to generate API key: %v", err)
+		return nil, err
+	}
+
+	if err := uc.repo.Create(ctx, key); err != nil {
+		logger.Errorf("USECASES: failed to create API key: %v", err)
+		return nil, fmt.Errorf("failed to create API key: %w", err)
+	}
+
+	logger.Infof("USECASES: API key %s (%s) created with ID %d", key.Name, key.Prefix, key.ID)
+
+	return &dto.IssuedAPIKeyDTO{
+		APIKeyDTO: mapAPIKeyDomainToDTO(key),
+		Key:       rawKey,
+	}, nil
+}
+
+func (uc *APIKeyUseCase) ListAPIKeys(ctx context.Context) (_ []*dto.APIKeyDTO, err error) {
+	ctx, span := tracer.Start(ctx, "APIKeyUseCase.ListAPIKeys")
+	defer func() { endSpan(span, err) }()
+
+	logger := utils.ContextLogger(ctx, uc.logger)
+
+	keys, err := uc.repo.FindAll(ctx)
+	if err != nil {
+		logger.Errorf("USECASES: failed to list API keys: %v", err)
+		return nil, fmt.Errorf("failed to list API keys: %w", err)
+	}
+
+	dtos := make([]*dto.APIKeyDTO, 0, len(keys))
+	for _, key := range keys {
+		keyDTO := mapAPIKeyDomainToDTO(key)
+		dtos = append(dtos, &keyDTO)
+	}
+
+	logger.Debugf("USECASES: found %d API keys", len(dtos))
+
+	return dtos, nil
+}
+
+// RevokeAPIKey disables a key immediately. Revoking a revoked key is a no-op.
+func (uc *APIKeyUseCase) RevokeAPIKey(ctx context.Context, id int64) (err error) {
+	ctx, span := tracer.Start(ctx, "APIKeyUseCase.RevokeAPIKey",
+		trace.WithAttributes(attribute.Int64("api_key.id", id)))
+	defer func() { endSpan(span, err) }()
+
+	logger := utils.ContextLogger(ctx, uc.logger, "api_key_id", id)
+
+	key, err := uc.repo.FindByID(ctx, id)
+	if err != nil {
+		logger.Errorf("USECASES: failed to fetch API key with ID %d: %v", id, err)
+		return fmt.Errorf("failed to fetch API key: %w", err)
+	}
+	if key == nil {
+		return ErrAPIKeyNotFound
+	}
+
+	if key.RevokedAt != nil {
+		logger.Debugf("USECASES: API key %s is already revoked", key.Prefix)
+		return nil
+	}
+
+	if err := uc.repo.Revoke(ctx, id, time.Now()); err != nil {
+		logger.Errorf("USECASES: failed to revoke API key with ID %d: %v", id, err)
+		return fmt.Errorf("failed to revoke API key: %w", err)
+	}
+
+	logger.Infof("USECASES: API key %s (%s) revoked", key.Name, key.Prefix)
+
+	return nil
+}
+
+// RotateAPIKey issues a replacement for a key with the same name and scopes.
+// The old key stays valid for gracePeriod so that clients can switch over
+// without downtime.
+func (uc *APIKeyUseCase) RotateAPIKey(
+	ctx context.Context,
+	id int64,
+	gracePeriod time.Duration,
+) (_ *dto.IssuedAPIKeyDTO, err error) {
+	ctx, span := tracer.Start(ctx, "APIKeyUseCase.RotateAPIKey",
+		trace.WithAttributes(attribute.Int64("api_key.id", id)))
+	defer func() { endSpan(span, err) }()
+
+	logger := utils.ContextLogger(ctx, uc.logger, "api_key_id", id)
+
+	old, err := uc.repo.FindByID(ctx, id)
+	if err != nil {
+		logger.Errorf("USECASES: failed to fetch API key with ID %d: %v", id, err)
+		return nil, fmt.Errorf("failed to fetch API key: %w", err)
+	}
+	if old == nil {
+		return nil, ErrAPIKeyNotFound
+	}
+
+	now := time.Now()
+	if !old.IsActive(now) {
+		return nil, ErrAPIKeyInactive
+	}
+
+	rawKey, key, err := uc.newAPIKey(old.Name, old.Scopes, old.AuthMode, nil)
+	if err != nil {
+		logger.Errorf("USECASES: failed to generate API key: %v", err)
+		return nil, err
+	}
+
+	oldExpiresAt := now.Add(gracePeriod)
+	if err := uc.repo.Rotate(ctx, old.ID, oldExpiresAt, key); err != nil {
+		logger.Errorf("USECASES: failed to rotate API key with ID %d: %v", id, err)
+		return nil, fmt.Errorf("failed to rotate API key: %w", err)
+	}
+
+	logger.Infof("USECASES: API key %s (%s) rotated to %s, old key expires at %s",
+		old.Name, old.Prefix, key.Prefix, oldExpiresAt.Format(time.RFC3339))
+
+	return &dto.IssuedAPIKeyDTO{
+		APIKeyDTO: mapAPIKeyDomainToDTO(key),
+		Key:       rawKey,
+	}, nil
+}
+
+// signingKey returns the principal and the HMAC secret of the key with the
+// given ID. The stored key is returned as well, nil for a static key.
+func (uc *APIKeyUseCase) signingKey(
+	ctx context.Context,
+	keyID string,
+	now time.Time,
+) (*domain.Principal, *domain.APIKey, []byte, error) {
+	if static, ok := uc.staticSigningKeys[keyID]; ok {
+		return static.principal, nil, static.secret, nil
+	}
+
+	key, err := uc.repo.FindSigningKey(ctx, keyID)
+	if err != nil {
+		utils.ContextLogger(ctx, uc.logger).Errorf("USECASES: failed to look up signing key %s: %v", keyID, err)
+		return nil, nil, nil, fmt.Errorf("failed to look up signing key: %w", err)
+	}
+	if key == nil || !key.IsActive(now) {
+		return nil, nil, nil, ErrInvalidSignature
+	}
+
+	if len(key.SigningSecret) == 0 {
+		utils.ContextLogger(ctx, uc.logger).Warnf("USECASES: API key %s has no signing secret and must be rotated", key.Prefix)
+		return nil, nil, nil, ErrInvalidSignature
+	}
+
+	secret, err := openSigningSecret(uc.secrets, key.SigningSecret, key.Prefix)
+	if err != nil {
+		utils.ContextLogger(ctx, uc.logger).Errorf("USECASES: failed to decrypt signing secret of API key %s: %v", key.Prefix, err)
+		return nil, nil, nil, fmt.Errorf("failed to decrypt signing secret of API key %s: %w", key.Prefix, err)
+	}
+
+	return &domain.Principal{
+		Name:   key.Name,
+		Method: domain.AuthMethodHMAC,
+		Scopes: key.Scopes,
+	}, key, secret, nil
+}
+
+// recordUse stores the last use of a key at most once per lastUsedResolution.
+func (uc *APIKeyUseCase) recordUse(ctx context.Context, key *domain.APIKey, now time.Time) {
+	if key.LastUsedAt != nil && now.Sub(*key.LastUsedAt) < lastUsedResolution {
+		return
+	}
+
+	if err := uc.repo.UpdateLastUsed(ctx, key.ID, now); err != nil {
+		utils.ContextLogger(ctx, uc.logger).Warnf("USECASES: failed to record use of API key %s: %v", key.Prefix, err)
+	}
+}
+
+// sweepNonces deletes expired nonces at most once per nonceSweepInterval.
+func (uc *APIKeyUseCase) sweepNonces(ctx context.Context, now time.Time) {
+	uc.nonceSweep.Lock()
+	if now.Before(uc.nextNonceSweep) {
+		uc.nonceSweep.Unlock()
+		return
+	}
+	uc.nextNonceSweep = now.Add(nonceSweepInterval)
+	uc.nonceSweep.Unlock()
+
+	if err := uc.nonceRepo.DeleteExpired(ctx, now); err != nil {
+		utils.ContextLogger(ctx, uc.logger).Warnf("USECASES: failed to delete expired nonces: %v", err)
+	}
+}
+
+// newAPIKey generates a random key and returns it together with the record to
+// store for it. Keys in the HMAC auth mode get their signing secret encrypted.
+func (uc *APIKeyUseCase) newAPIKey(
+	name string,
+	scopes []domain.Scope,
+	authMode domain.APIKeyAuthMode,
+	expiresAt *time.Time,
+) (string, *domain.APIKey, error) {
+	secret := make([]byte, apiKeyRandomBytes)
+	if _, err := rand.Read(secret); err != nil {
+		return "", nil, fmt.Errorf("failed to generate API key: %w", err)
+	}
+
+	rawKey := apiKeyPrefix + base64.RawURLEncoding.EncodeToString(secret)
+	key := &domain.APIKey{
+		Name:      name,
+		Prefix:    rawKey[:apiKeyDisplayLength],
+		Hash:      hashAPIKey(rawKey),
+		Scopes:    scopes,
+		AuthMode:  authMode,
+		CreatedAt: time.Now(),
+		ExpiresAt: expiresAt,
+	}
+
+	if authMode == domain.APIKeyAuthModeHMAC {
+		sealed, err := sealSigningSecret(uc.secrets, deriveSigningSecret(rawKey), key.Prefix)
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to encrypt signing secret: %w", err)
+		}
+		key.SigningSecret = sealed
+	}
+
+	return rawKey, key, nil
+}
+
+func hashAPIKey(rawKey string) string {
+	digest := sha256.Sum256([]byte(rawKey))
+	return hex.EncodeToString(digest[:])
+}
+
+func mapAPIKeyDomainToDTO(key *domain.APIKey) dto.APIKeyDTO {
+	scopes := make([]string, 0, len(key.Scopes))
+	for _, scope := range key.Scopes {
+		scopes = append(scopes, string(scope))
+	}
+
+	return dto.APIKeyDTO{
+		ID:         key.ID,
+		Name:       key.Name,
+		Prefix:     key.Prefix,
+		Scopes:     scopes,
+		AuthMode:   string(key.AuthMode),
+		CreatedAt:  key.CreatedAt,
+		LastUsedAt: key.LastUsedAt,
+		ExpiresAt:  key.ExpiresAt,
+		RevokedAt:  key.RevokedAt,
+	}
+}
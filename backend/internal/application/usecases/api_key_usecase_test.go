@@ -0,0 +1,552 @@
+package usecases_test
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/k6zma/DockerMonitoringApp/backend/internal/application/dto"
+	"github.com/k6zma/DockerMonitoringApp/backend/internal/application/usecases"
+	"github.com/k6zma/DockerMonitoringApp/backend/internal/domain"
+	"github.com/k6zma/DockerMonitoringApp/backend/mocks"
+)
+
+const secretKey = "test-secret-key-of-at-least-32-characters"
+
+func hashKey(rawKey string) string {
+	digest := sha256.Sum256([]byte(rawKey))
+	return hex.EncodeToString(digest[:])
+}
+
+func TestAuthenticate_StaticKey(t *testing.T) {
+	mockRepo := new(mocks.APIKeyRepository)
+	mockLogger := new(mocks.LoggerInterface)
+
+	useCase := usecases.NewAPIKeyUseCase(mockRepo, nil, []usecases.StaticAPIKey{
+		{Name: "pinger", Key: "pinger-key", Scopes: []domain.Scope{domain.ScopeStatusWrite}},
+	}, time.Minute, secretKey, mockLogger)
+
+	principal, err := useCase.Authenticate(context.Background(), "pinger-key")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "pinger", principal.Name)
+	mockRepo.AssertNotCalled(t, "FindByHash", mock.Anything, mock.Anything)
+}
+
+func TestAuthenticate_StoredKey(t *testing.T) {
+	mockRepo := new(mocks.APIKeyRepository)
+	mockLogger := new(mocks.LoggerInterface)
+
+	useCase := usecases.NewAPIKeyUseCase(mockRepo, nil, nil, time.Minute, secretKey, mockLogger)
+
+	stored := &domain.APIKey{
+		ID:     7,
+		Name:   "dashboard",
+		Prefix: "dm_abcdefgh",
+		Scopes: []domain.Scope{domain.ScopeStatusRead},
+	}
+
+	mockRepo.On("FindByHash", mock.Anything, hashKey("stored-key")).Return(stored, nil)
+	mockRepo.On("UpdateLastUsed", mock.Anything, int64(7), mock.AnythingOfType("time.Time")).Return(nil)
+
+	principal, err := useCase.Authenticate(context.Background(), "stored-key")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "dashboard", principal.Name)
+	assert.True(t, principal.HasScope(domain.ScopeStatusRead))
+	mockRepo.AssertExpectations(t)
+}
+
+func TestAuthenticate_RecentlyUsedKey_SkipsLastUsedUpdate(t *testing.T) {
+	mockRepo := new(mocks.APIKeyRepository)
+	mockLogger := new(mocks.LoggerInterface)
+
+	useCase := usecases.NewAPIKeyUseCase(mockRepo, nil, nil, time.Minute, secretKey, mockLogger)
+
+	lastUsedAt := time.Now().Add(-10 * time.Second)
+	stored := &domain.APIKey{ID: 7, Name: "dashboard", LastUsedAt: &lastUsedAt}
+
+	mockRepo.On("FindByHash", mock.Anything, hashKey("stored-key")).Return(stored, nil)
+
+	_, err := useCase.Authenticate(context.Background(), "stored-key")
+
+	assert.NoError(t, err)
+	mockRepo.AssertNotCalled(t, "UpdateLastUsed", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestAuthenticate_InvalidKeys(t *testing.T) {
+	past := time.Now().Add(-time.Minute)
+
+	tests := []struct {
+		name   string
+		stored *domain.APIKey
+	}{
+		{name: "unknown", stored: nil},
+		{name: "expired", stored: &domain.APIKey{ID: 1, ExpiresAt: &past}},
+		{name: "revoked", stored: &domain.APIKey{ID: 1, RevokedAt: &past}},
+		{name: "signing key", stored: &domain.APIKey{ID: 1, AuthMode: domain.APIKeyAuthModeHMAC}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(mocks.APIKeyRepository)
+			mockLogger := new(mocks.LoggerInterface)
+
+			useCase := usecases.NewAPIKeyUseCase(mockRepo, nil, nil, time.Minute, secretKey, mockLogger)
+
+			mockRepo.On("FindByHash", mock.Anything, mock.Anything).Return(tt.stored, nil)
+			mockLogger.On("Warnf", mock.Anything, mock.Anything).Return()
+
+			principal, err := useCase.Authenticate(context.Background(), "some-key")
+
+			assert.ErrorIs(t, err, usecases.ErrInvalidAPIKey)
+			assert.Nil(t, principal)
+		})
+	}
+}
+
+func TestAuthenticate_LookupError(t *testing.T) {
+	mockRepo := new(mocks.APIKeyRepository)
+	mockLogger := new(mocks.LoggerInterface)
+
+	useCase := usecases.NewAPIKeyUseCase(mockRepo, nil, nil, time.Minute, secretKey, mockLogger)
+
+	mockRepo.On("FindByHash", mock.Anything, mock.Anything).Return(nil, errors.New("connection refused"))
+	mockLogger.On("Errorf", mock.Anything, mock.Anything).Return()
+
+	_, err := useCase.Authenticate(context.Background(), "some-key")
+
+	assert.Error(t, err)
+	assert.NotErrorIs(t, err, usecases.ErrInvalidAPIKey)
+}
+
+// signRequest signs a request the way clients do: with an HMAC-SHA256 keyed with
+// the signing secret derived from the raw key.
+func signRequest(rawKey string, signature *dto.RequestSignatureDTO) *dto.RequestSignatureDTO {
+	secret := hmac.New(sha256.New, []byte(rawKey))
+	secret.Write([]byte("DockerMonitoringApp request signing"))
+
+	mac := hmac.New(sha256.New, secret.Sum(nil))
+	mac.Write([]byte(strings.Join([]string{
+		signature.Method,
+		signature.Path,
+		strconv.FormatInt(signature.Timestamp, 10),
+		signature.Nonce,
+		signature.BodyHash,
+	}, "\n")))
+	signature.Signature = hex.EncodeToString(mac.Sum(nil))
+
+	return signature
+}
+
+// newNonceRepo returns a nonce repository on which every nonce is unused.
+func newNonceRepo() *mocks.RequestNonceRepository {
+	nonceRepo := new(mocks.RequestNonceRepository)
+	nonceRepo.On("Claim", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(true, nil)
+	nonceRepo.On("DeleteExpired", mock.Anything, mock.Anything).Return(nil)
+
+	return nonceRepo
+}
+
+func newSignature(keyID, nonce string, signedAt time.Time) *dto.RequestSignatureDTO {
+	return &dto.RequestSignatureDTO{
+		KeyID:     keyID,
+		Timestamp: signedAt.Unix(),
+		Nonce:     nonce,
+		Method:    "PATCH",
+		Path:      "/api/v1/container_status/abc",
+		BodyHash:  hashKey(`{"status":"running"}`),
+	}
+}
+
+func TestAuthenticateSignature_StaticKey(t *testing.T) {
+	mockRepo := new(mocks.APIKeyRepository)
+	mockLogger := new(mocks.LoggerInterface)
+
+	useCase := usecases.NewAPIKeyUseCase(mockRepo, newNonceRepo(), []usecases.StaticAPIKey{
+		{Name: "pinger", Key: "pinger-key", Scopes: []domain.Scope{domain.ScopeStatusWrite}, AuthMode: domain.APIKeyAuthModeHMAC},
+	}, time.Minute, secretKey, mockLogger)
+
+	principal, err := useCase.AuthenticateSignature(context.Background(),
+		signRequest("pinger-key", newSignature("pinger", "n-1", time.Now())))
+
+	assert.NoError(t, err)
+	assert.Equal(t, "pinger", principal.Name)
+	assert.Equal(t, domain.AuthMethodHMAC, principal.Method)
+	mockRepo.AssertNotCalled(t, "FindSigningKey", mock.Anything, mock.Anything)
+
+	// A static key in the HMAC mode is not accepted in the header.
+	mockRepo.On("FindByHash", mock.Anything, hashKey("pinger-key")).Return(nil, nil)
+
+	_, err = useCase.Authenticate(context.Background(), "pinger-key")
+
+	assert.ErrorIs(t, err, usecases.ErrInvalidAPIKey)
+}
+
+// createSigningKey creates an HMAC key through useCase and returns the raw key
+// and the record that was stored for it.
+func createSigningKey(t *testing.T, useCase *usecases.APIKeyUseCase, mockRepo *mocks.APIKeyRepository) (string, *domain.APIKey) {
+	t.Helper()
+
+	var stored *domain.APIKey
+	mockRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.APIKey")).
+		Run(func(args mock.Arguments) {
+			stored = args.Get(1).(*domain.APIKey)
+			stored.ID = 7
+		}).
+		Return(nil).Once()
+
+	issued, err := useCase.CreateAPIKey(context.Background(), &dto.CreateAPIKeyDTO{
+		Name:     "pinger",
+		Scopes:   []string{"status:write"},
+		AuthMode: "hmac",
+	})
+	assert.NoError(t, err)
+
+	return issued.Key, stored
+}
+
+func TestAuthenticateSignature_StoredKey(t *testing.T) {
+	mockRepo := new(mocks.APIKeyRepository)
+	mockLogger := new(mocks.LoggerInterface)
+	mockLogger.On("Debugf", mock.Anything, mock.Anything, mock.Anything).Return()
+	mockLogger.On("Infof", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return()
+
+	useCase := usecases.NewAPIKeyUseCase(mockRepo, newNonceRepo(), nil, time.Minute, secretKey, mockLogger)
+
+	rawKey, stored := createSigningKey(t, useCase, mockRepo)
+	assert.NotEmpty(t, stored.SigningSecret)
+
+	mockRepo.On("FindSigningKey", mock.Anything, stored.Prefix).Return(stored, nil)
+	mockRepo.On("UpdateLastUsed", mock.Anything, int64(7), mock.AnythingOfType("time.Time")).Return(nil)
+
+	principal, err := useCase.AuthenticateSignature(context.Background(),
+		signRequest(rawKey, newSignature(stored.Prefix, "n-1", time.Now())))
+
+	assert.NoError(t, err)
+	assert.Equal(t, "pinger", principal.Name)
+	assert.True(t, principal.HasScope(domain.ScopeStatusWrite))
+	mockRepo.AssertExpectations(t)
+}
+
+func TestAuthenticateSignature_StoredDigestCannotSign(t *testing.T) {
+	mockRepo := new(mocks.APIKeyRepository)
+	mockLogger := new(mocks.LoggerInterface)
+	mockLogger.On("Debugf", mock.Anything, mock.Anything, mock.Anything).Return()
+	mockLogger.On("Infof", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return()
+	mockLogger.On("Warnf", mock.Anything, mock.Anything).Return()
+
+	useCase := usecases.NewAPIKeyUseCase(mockRepo, newNonceRepo(), nil, time.Minute, secretKey, mockLogger)
+
+	_, stored := createSigningKey(t, useCase, mockRepo)
+	mockRepo.On("FindSigningKey", mock.Anything, stored.Prefix).Return(stored, nil)
+
+	// Whoever reads the api_keys table has the digest and the encrypted secret,
+	// neither of which signs requests.
+	digest, err := hex.DecodeString(stored.Hash)
+	assert.NoError(t, err)
+
+	for name, secret := range map[string][]byte{"digest": digest, "encrypted secret": stored.SigningSecret} {
+		signature := newSignature(stored.Prefix, "n-"+name, time.Now())
+		mac := hmac.New(sha256.New, secret)
+		mac.Write([]byte(strings.Join([]string{signature.Method, signature.Path,
+			strconv.FormatInt(signature.Timestamp, 10), signature.Nonce, signature.BodyHash}, "\n")))
+		signature.Signature = hex.EncodeToString(mac.Sum(nil))
+
+		_, err := useCase.AuthenticateSignature(context.Background(), signature)
+		assert.ErrorIs(t, err, usecases.ErrInvalidSignature, name)
+	}
+}
+
+func TestAuthenticateSignature_UnusableSigningSecret(t *testing.T) {
+	mockRepo := new(mocks.APIKeyRepository)
+	mockLogger := new(mocks.LoggerInterface)
+	mockLogger.On("Debugf", mock.Anything, mock.Anything, mock.Anything).Return()
+	mockLogger.On("Infof", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return()
+	mockLogger.On("Warnf", mock.Anything, mock.Anything).Return()
+	mockLogger.On("Errorf", mock.Anything, mock.Anything, mock.Anything).Return()
+
+	rawKey, stored := createSigningKey(t,
+		usecases.NewAPIKeyUseCase(mockRepo, nil, nil, time.Minute, secretKey, mockLogger), mockRepo)
+
+	// A key created before signing secrets were stored has to be rotated.
+	withoutSecret := *stored
+	withoutSecret.SigningSecret = nil
+	mockRepo.On("FindSigningKey", mock.Anything, "dm_old").Return(&withoutSecret, nil)
+
+	useCase := usecases.NewAPIKeyUseCase(mockRepo, newNonceRepo(), nil, time.Minute, secretKey, mockLogger)
+	_, err := useCase.AuthenticateSignature(context.Background(), signRequest(rawKey, newSignature("dm_old", "n-1", time.Now())))
+	assert.ErrorIs(t, err, usecases.ErrInvalidSignature)
+
+	// A changed server secret key is a configuration error, not a bad signature.
+	mockRepo.On("FindSigningKey", mock.Anything, stored.Prefix).Return(stored, nil)
+
+	otherServer := usecases.NewAPIKeyUseCase(mockRepo, newNonceRepo(), nil, time.Minute, "another-secret-key-of-32-characters", mockLogger)
+	_, err = otherServer.AuthenticateSignature(context.Background(), signRequest(rawKey, newSignature(stored.Prefix, "n-2", time.Now())))
+	assert.Error(t, err)
+	assert.NotErrorIs(t, err, usecases.ErrInvalidSignature)
+}
+
+func TestAuthenticateSignature_Rejected(t *testing.T) {
+	tests := []struct {
+		name      string
+		signature func() *dto.RequestSignatureDTO
+	}{
+		{
+			name: "wrong key",
+			signature: func() *dto.RequestSignatureDTO {
+				return signRequest("other-key", newSignature("pinger", "n-1", time.Now()))
+			},
+		},
+		{
+			name: "unknown key ID",
+			signature: func() *dto.RequestSignatureDTO {
+				return signRequest("pinger-key", newSignature("dm_unknown", "n-1", time.Now()))
+			},
+		},
+		{
+			name: "tampered path",
+			signature: func() *dto.RequestSignatureDTO {
+				signature := signRequest("pinger-key", newSignature("pinger", "n-1", time.Now()))
+				signature.Path = "/api/v1/container_status/other"
+				return signature
+			},
+		},
+		{
+			name: "tampered body",
+			signature: func() *dto.RequestSignatureDTO {
+				signature := signRequest("pinger-key", newSignature("pinger", "n-1", time.Now()))
+				signature.BodyHash = hashKey(`{"status":"exited"}`)
+				return signature
+			},
+		},
+		{
+			name: "stale timestamp",
+			signature: func() *dto.RequestSignatureDTO {
+				return signRequest("pinger-key", newSignature("pinger", "n-1", time.Now().Add(-2*time.Minute)))
+			},
+		},
+		{
+			name: "timestamp in the future",
+			signature: func() *dto.RequestSignatureDTO {
+				return signRequest("pinger-key", newSignature("pinger", "n-1", time.Now().Add(2*time.Minute)))
+			},
+		},
+		{
+			name: "missing nonce",
+			signature: func() *dto.RequestSignatureDTO {
+				return signRequest("pinger-key", newSignature("pinger", "", time.Now()))
+			},
+		},
+		{
+			name: "malformed signature",
+			signature: func() *dto.RequestSignatureDTO {
+				signature := newSignature("pinger", "n-1", time.Now())
+				signature.Signature = "not hex"
+				return signature
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(mocks.APIKeyRepository)
+			mockLogger := new(mocks.LoggerInterface)
+
+			useCase := usecases.NewAPIKeyUseCase(mockRepo, nil, []usecases.StaticAPIKey{
+				{Name: "pinger", Key: "pinger-key", Scopes: []domain.Scope{domain.ScopeStatusWrite}, AuthMode: domain.APIKeyAuthModeHMAC},
+			}, time.Minute, secretKey, mockLogger)
+
+			mockRepo.On("FindSigningKey", mock.Anything, mock.Anything).Return(nil, nil)
+			mockLogger.On("Warnf", mock.Anything, mock.Anything, mock.Anything).Return()
+
+			principal, err := useCase.AuthenticateSignature(context.Background(), tt.signature())
+
+			assert.ErrorIs(t, err, usecases.ErrInvalidSignature)
+			assert.Nil(t, principal)
+		})
+	}
+}
+
+func TestAuthenticateSignature_ReplayedNonce(t *testing.T) {
+	mockRepo := new(mocks.APIKeyRepository)
+	mockLogger := new(mocks.LoggerInterface)
+
+	// The repository is shared by all replicas: the nonce was claimed by this
+	// one or another one before.
+	nonceRepo := new(mocks.RequestNonceRepository)
+	nonceRepo.On("Claim", mock.Anything, "pinger", "n-1", mock.AnythingOfType("time.Time"), mock.AnythingOfType("time.Time")).
+		Return(true, nil).Once()
+	nonceRepo.On("Claim", mock.Anything, "pinger", "n-1", mock.AnythingOfType("time.Time"), mock.AnythingOfType("time.Time")).
+		Return(false, nil)
+	nonceRepo.On("Claim", mock.Anything, "pinger", "n-2", mock.AnythingOfType("time.Time"), mock.AnythingOfType("time.Time")).
+		Return(true, nil)
+	nonceRepo.On("DeleteExpired", mock.Anything, mock.AnythingOfType("time.Time")).Return(nil).Once()
+
+	useCase := usecases.NewAPIKeyUseCase(mockRepo, nonceRepo, []usecases.StaticAPIKey{
+		{Name: "pinger", Key: "pinger-key", Scopes: []domain.Scope{domain.ScopeStatusWrite}, AuthMode: domain.APIKeyAuthModeHMAC},
+	}, time.Minute, secretKey, mockLogger)
+
+	mockLogger.On("Warnf", mock.Anything, mock.Anything, mock.Anything).Return()
+
+	signedAt := time.Now()
+	signature := signRequest("pinger-key", newSignature("pinger", "n-1", signedAt))
+
+	_, err := useCase.AuthenticateSignature(context.Background(), signature)
+	assert.NoError(t, err)
+
+	_, err = useCase.AuthenticateSignature(context.Background(), signature)
+	assert.ErrorIs(t, err, usecases.ErrInvalidSignature)
+
+	_, err = useCase.AuthenticateSignature(context.Background(),
+		signRequest("pinger-key", newSignature("pinger", "n-2", time.Now())))
+	assert.NoError(t, err)
+
+	// A nonce is kept until its timestamp falls out of the clock skew, and the
+	// expired ones are swept at most once a minute.
+	nonceRepo.AssertCalled(t, "Claim", mock.Anything, "pinger", "n-1", time.Unix(signedAt.Unix(), 0).Add(time.Minute), mock.Anything)
+	nonceRepo.AssertExpectations(t)
+}
+
+func TestAuthenticateSignature_NonceRepositoryError(t *testing.T) {
+	mockRepo := new(mocks.APIKeyRepository)
+	mockLogger := new(mocks.LoggerInterface)
+	mockLogger.On("Errorf", mock.Anything, mock.Anything, mock.Anything).Return()
+
+	nonceRepo := new(mocks.RequestNonceRepository)
+	nonceRepo.On("Claim", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return(false, errors.New("connection refused"))
+
+	useCase := usecases.NewAPIKeyUseCase(mockRepo, nonceRepo, []usecases.StaticAPIKey{
+		{Name: "pinger", Key: "pinger-key", Scopes: []domain.Scope{domain.ScopeStatusWrite}, AuthMode: domain.APIKeyAuthModeHMAC},
+	}, time.Minute, secretKey, mockLogger)
+
+	_, err := useCase.AuthenticateSignature(context.Background(),
+		signRequest("pinger-key", newSignature("pinger", "n-1", time.Now())))
+
+	assert.Error(t, err)
+	assert.NotErrorIs(t, err, usecases.ErrInvalidSignature)
+}
+
+func TestCreateAPIKey_StoresOnlyTheHash(t *testing.T) {
+	mockRepo := new(mocks.APIKeyRepository)
+	mockLogger := new(mocks.LoggerInterface)
+
+	useCase := usecases.NewAPIKeyUseCase(mockRepo, nil, nil, time.Minute, secretKey, mockLogger)
+
+	var stored *domain.APIKey
+	mockRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.APIKey")).
+		Run(func(args mock.Arguments) {
+			stored = args.Get(1).(*domain.APIKey)
+			stored.ID = 3
+		}).
+		Return(nil)
+	mockLogger.On("Debugf", mock.Anything, mock.Anything, mock.Anything).Return()
+	mockLogger.On("Infof", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return()
+
+	issued, err := useCase.CreateAPIKey(context.Background(), &dto.CreateAPIKeyDTO{
+		Name:   "dashboard",
+		Scopes: []string{"status:read"},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), issued.ID)
+	assert.True(t, strings.HasPrefix(issued.Key, "dm_"))
+	assert.True(t, strings.HasPrefix(issued.Key, issued.Prefix))
+	assert.Equal(t, hashKey(issued.Key), stored.Hash)
+	assert.NotContains(t, stored.Hash, issued.Key)
+	assert.Equal(t, []domain.Scope{domain.ScopeStatusRead}, stored.Scopes)
+	assert.Equal(t, domain.APIKeyAuthModeHeader, stored.AuthMode)
+	assert.Empty(t, stored.SigningSecret)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestRotateAPIKey_KeepsOldKeyDuringGracePeriod(t *testing.T) {
+	mockRepo := new(mocks.APIKeyRepository)
+	mockLogger := new(mocks.LoggerInterface)
+
+	useCase := usecases.NewAPIKeyUseCase(mockRepo, nil, nil, time.Minute, secretKey, mockLogger)
+
+	old := &domain.APIKey{
+		ID:       5,
+		Name:     "pinger",
+		Prefix:   "dm_oldoldol",
+		Scopes:   []domain.Scope{domain.ScopeStatusRead, domain.ScopeStatusWrite},
+		AuthMode: domain.APIKeyAuthModeHMAC,
+	}
+
+	mockRepo.On("FindByID", mock.Anything, int64(5)).Return(old, nil)
+	mockRepo.On("Rotate", mock.Anything, int64(5), mock.AnythingOfType("time.Time"), mock.AnythingOfType("*domain.APIKey")).
+		Run(func(args mock.Arguments) {
+			expiresAt := args.Get(2).(time.Time)
+			assert.WithinDuration(t, time.Now().Add(time.Hour), expiresAt, time.Minute)
+
+			key := args.Get(3).(*domain.APIKey)
+			assert.Equal(t, old.Name, key.Name)
+			assert.Equal(t, old.Scopes, key.Scopes)
+			assert.Equal(t, old.AuthMode, key.AuthMode)
+			assert.NotEqual(t, old.Prefix, key.Prefix)
+			key.ID = 6
+		}).
+		Return(nil)
+	mockLogger.On("Infof", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return()
+
+	issued, err := useCase.RotateAPIKey(context.Background(), 5, time.Hour)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(6), issued.ID)
+	assert.NotEmpty(t, issued.Key)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestRotateAPIKey_Errors(t *testing.T) {
+	past := time.Now().Add(-time.Minute)
+
+	tests := []struct {
+		name     string
+		stored   *domain.APIKey
+		expected error
+	}{
+		{name: "not found", stored: nil, expected: usecases.ErrAPIKeyNotFound},
+		{name: "revoked", stored: &domain.APIKey{ID: 5, RevokedAt: &past}, expected: usecases.ErrAPIKeyInactive},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(mocks.APIKeyRepository)
+			mockLogger := new(mocks.LoggerInterface)
+
+			useCase := usecases.NewAPIKeyUseCase(mockRepo, nil, nil, time.Minute, secretKey, mockLogger)
+
+			mockRepo.On("FindByID", mock.Anything, int64(5)).Return(tt.stored, nil)
+
+			_, err := useCase.RotateAPIKey(context.Background(), 5, time.Hour)
+
+			assert.ErrorIs(t, err, tt.expected)
+			mockRepo.AssertNotCalled(t, "Rotate", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestRevokeAPIKey_NotFound(t *testing.T) {
+	mockRepo := new(mocks.APIKeyRepository)
+	mockLogger := new(mocks.LoggerInterface)
+
+	useCase := usecases.NewAPIKeyUseCase(mockRepo, nil, nil, time.Minute, secretKey, mockLogger)
+
+	mockRepo.On("FindByID", mock.Anything, int64(9)).Return(nil, nil)
+
+	err := useCase.RevokeAPIKey(context.Background(), 9)
+
+	assert.ErrorIs(t, err, usecases.ErrAPIKeyNotFound)
+	mockRepo.AssertNotCalled(t, "Revoke", mock.Anything, mock.Anything, mock.Anything)
+}
@@ -0,0 +1,165 @@
+package usecases
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/k6zma/DockerMonitoringApp/backend/internal/application/dto"
+	"github.com/k6zma/DockerMonitoringApp/backend/internal/application/repositories"
+	"github.com/k6zma/DockerMonitoringApp/backend/internal/domain"
+	"github.com/k6zma/DockerMonitoringApp/backend/pkg/utils"
+)
+
+// AuditChange collects the container status changed while handling a request.
+// The audit middleware stores one in the request context and the use cases
+// fill it in, so the audit record holds the state before and after the call.
+type AuditChange struct {
+	ContainerID string
+	Before      *domain.ContainerStatus
+	After       *domain.ContainerStatus
+}
+
+type auditChangeKey struct{}
+
+// ContextWithAuditChange returns a context carrying an empty change record.
+func ContextWithAuditChange(ctx context.Context) (context.Context, *AuditChange) {
+	change := &AuditChange{}
+	return context.WithValue(ctx, auditChangeKey{}, change), change
+}
+
+// recordAuditChange stores the change in the record carried by ctx, if any.
+func recordAuditChange(ctx context.Context, containerID string, before, after *domain.ContainerStatus) {
+	change, ok := ctx.Value(auditChangeKey{}).(*AuditChange)
+	if !ok {
+		return
+	}
+
+	change.ContainerID = containerID
+	change.Before = before
+	change.After = after
+}
+
+type AuditUseCaseInterface interface {
+	// Record stores entry together with the container status change collected
+	// in change, which may be nil.
+	Record(ctx context.Context, entry *dto.AuditEntryDTO, change *AuditChange) error
+	FindAuditEntries(ctx context.Context, filter *dto.AuditFilter) ([]*dto.AuditEntryDTO, error)
+}
+
+type AuditUseCase struct {
+	repo   repositories.AuditRepository
+	logger utils.LoggerInterface
+}
+
+func NewAuditUseCase(
+	repo repositories.AuditRepository,
+	logger utils.LoggerInterface,
+) *AuditUseCase {
+	return &AuditUseCase{
+		repo:   repo,
+		logger: logger,
+	}
+}
+
+func (uc *AuditUseCase) Record(ctx context.Context, entry *dto.AuditEntryDTO, change *AuditChange) (err error) {
+	ctx, span := tracer.Start(ctx, "AuditUseCase.Record",
+		trace.WithAttributes(attribute.String("audit.actor", entry.Actor), attribute.String("audit.route", entry.Route)))
+	defer func() { endSpan(span, err) }()
+
+	logger := utils.ContextLogger(ctx, uc.logger)
+
+	record := &domain.AuditEntry{
+		Actor:       entry.Actor,
+		AuthMethod:  domain.AuthMethod(entry.AuthMethod),
+		ClientIP:    entry.ClientIP,
+		Method:      entry.Method,
+		Route:       entry.Route,
+		Path:        entry.Path,
+		ContainerID: entry.ContainerID,
+		StatusCode:  entry.StatusCode,
+		RequestID:   entry.RequestID,
+		CreatedAt:   time.Now(),
+	}
+
+	if change != nil {
+		if change.ContainerID != "" {
+			record.ContainerID = change.ContainerID
+		}
+		if record.Before, err = marshalAuditSnapshot(change.Before); err != nil {
+			return err
+		}
+		if record.After, err = marshalAuditSnapshot(change.After); err != nil {
+			return err
+		}
+	}
+
+	if err = uc.repo.Create(ctx, record); err != nil {
+		logger.Errorf("USECASES: failed to record audit entry for %s %s: %v", entry.Method, entry.Path, err)
+		return fmt.Errorf("failed to record audit entry: %w", err)
+	}
+
+	return nil
+}
+
+func (uc *AuditUseCase) FindAuditEntries(
+	ctx context.Context,
+	filter *dto.AuditFilter,
+) (_ []*dto.AuditEntryDTO, err error) {
+	ctx, span := tracer.Start(ctx, "AuditUseCase.FindAuditEntries")
+	defer func() { endSpan(span, err) }()
+
+	logger := utils.ContextLogger(ctx, uc.logger)
+
+	logger.Debugf("USECASES: finding audit entries with filter: %+v", filter)
+
+	entries, err := uc.repo.Find(ctx, filter)
+	if err != nil {
+		logger.Errorf("USECASES: failed to fetch audit entries: %v", err)
+		return nil, fmt.Errorf("failed to fetch audit entries: %w", err)
+	}
+
+	var dtos = make([]*dto.AuditEntryDTO, 0, len(entries))
+	for _, entry := range entries {
+		dtos = append(dtos, mapAuditDomainToDTO(entry))
+	}
+
+	logger.Debugf("USECASES: found %d audit entries", len(dtos))
+
+	return dtos, nil
+}
+
+func marshalAuditSnapshot(status *domain.ContainerStatus) ([]byte, error) {
+	if status == nil {
+		return nil, nil
+	}
+
+	snapshot, err := json.Marshal(status)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode audit snapshot: %w", err)
+	}
+
+	return snapshot, nil
+}
+
+func mapAuditDomainToDTO(entry *domain.AuditEntry) *dto.AuditEntryDTO {
+	return &dto.AuditEntryDTO{
+		ID:          entry.ID,
+		Actor:       entry.Actor,
+		AuthMethod:  string(entry.AuthMethod),
+		ClientIP:    entry.ClientIP,
+		Method:      entry.Method,
+		Route:       entry.Route,
+		Path:        entry.Path,
+		ContainerID: entry.ContainerID,
+		StatusCode:  entry.StatusCode,
+		Before:      entry.Before,
+		After:       entry.After,
+		RequestID:   entry.RequestID,
+		CreatedAt:   entry.CreatedAt,
+	}
+}
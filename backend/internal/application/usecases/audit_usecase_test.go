@@ -0,0 +1,118 @@
+package usecases_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/k6zma/DockerMonitoringApp/backend/internal/application/dto"
+	"github.com/k6zma/DockerMonitoringApp/backend/internal/application/usecases"
+	"github.com/k6zma/DockerMonitoringApp/backend/internal/domain"
+	"github.com/k6zma/DockerMonitoringApp/backend/mocks"
+)
+
+func TestUpdateContainerStatus_RecordsAuditChange(t *testing.T) {
+	mockRepo := new(mocks.ContainerStatusRepository)
+	mockEventRepo := new(mocks.ContainerEventRepository)
+	mockLogger := new(mocks.LoggerInterface)
+	mockPublisher := new(mocks.StatusChangePublisher)
+
+	useCase := usecases.NewContainerStatusUseCase(mockRepo, mockEventRepo, inTransaction(), usecases.CrashLoopPolicy{}, mockPublisher, mockLogger)
+
+	mockContainerID := testContainerIDStr
+	existingStatus := []*domain.ContainerStatus{
+		{ContainerID: mockContainerID, IPAddress: testContainerIP, PingTime: testPingTimeDefault},
+	}
+
+	mockLogger.On("Debugf", mock.Anything, mock.Anything, mock.Anything).Return()
+	mockRepo.On("Find", mock.Anything, &dto.ContainerStatusFilter{ContainerID: &mockContainerID}).Return(existingStatus, nil)
+	mockRepo.On("Update", mock.Anything, mock.Anything).Return(nil)
+	mockPublisher.On("Publish", mock.Anything, mock.Anything).Return()
+
+	ctx, change := usecases.ContextWithAuditChange(context.Background())
+
+	err := useCase.UpdateContainerStatus(ctx, mockContainerID, &dto.ContainerStatusDTO{PingTime: testPingTimeUpdated})
+
+	assert.NoError(t, err)
+	assert.Equal(t, mockContainerID, change.ContainerID)
+	assert.Equal(t, testPingTimeDefault, change.Before.PingTime)
+	assert.Equal(t, testPingTimeUpdated, change.After.PingTime)
+}
+
+func TestDeleteContainerStatus_RecordsAuditChange(t *testing.T) {
+	mockRepo := new(mocks.ContainerStatusRepository)
+	mockEventRepo := new(mocks.ContainerEventRepository)
+	mockLogger := new(mocks.LoggerInterface)
+	mockPublisher := new(mocks.StatusChangePublisher)
+
+	useCase := usecases.NewContainerStatusUseCase(mockRepo, mockEventRepo, inTransaction(), usecases.CrashLoopPolicy{}, mockPublisher, mockLogger)
+
+	mockContainerID := testContainerIDStr
+	existing := &domain.ContainerStatus{ContainerID: mockContainerID, Status: "running"}
+
+	mockLogger.On("Debugf", mock.Anything, mock.Anything).Return()
+	mockRepo.On("Find", mock.Anything, mock.Anything).Return([]*domain.ContainerStatus{existing}, nil)
+	mockRepo.On("DeleteByContainerID", mock.Anything, mockContainerID).Return(nil)
+	mockPublisher.On("Publish", mock.Anything, mock.Anything).Return()
+
+	ctx, change := usecases.ContextWithAuditChange(context.Background())
+
+	err := useCase.DeleteContainerStatusByContainerID(ctx, mockContainerID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, existing, change.Before)
+	assert.Nil(t, change.After)
+}
+
+func TestRecordAuditEntry_EncodesSnapshots(t *testing.T) {
+	mockRepo := new(mocks.AuditRepository)
+	mockLogger := new(mocks.LoggerInterface)
+
+	useCase := usecases.NewAuditUseCase(mockRepo, mockLogger)
+
+	var stored *domain.AuditEntry
+	mockRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.AuditEntry")).
+		Run(func(args mock.Arguments) {
+			stored = args.Get(1).(*domain.AuditEntry)
+		}).
+		Return(nil)
+
+	change := &usecases.AuditChange{
+		ContainerID: "abc",
+		After:       &domain.ContainerStatus{ContainerID: "abc", Status: "running"},
+	}
+
+	err := useCase.Record(context.Background(), &dto.AuditEntryDTO{
+		Actor:      "pinger",
+		AuthMethod: "api_key",
+		Method:     "POST",
+		Route:      "/api/v1/container_status",
+		StatusCode: 201,
+	}, change)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "pinger", stored.Actor)
+	assert.Equal(t, domain.AuthMethodAPIKey, stored.AuthMethod)
+	assert.Equal(t, "abc", stored.ContainerID)
+	assert.Nil(t, stored.Before)
+	assert.Contains(t, string(stored.After), `"status":"running"`)
+	assert.WithinDuration(t, time.Now(), stored.CreatedAt, time.Minute)
+}
+
+func TestRecordAuditEntry_StoreError(t *testing.T) {
+	mockRepo := new(mocks.AuditRepository)
+	mockLogger := new(mocks.LoggerInterface)
+
+	useCase := usecases.NewAuditUseCase(mockRepo, mockLogger)
+
+	mockRepo.On("Create", mock.Anything, mock.Anything).Return(errors.New("connection refused"))
+	mockLogger.On("Errorf", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return()
+
+	err := useCase.Record(context.Background(), &dto.AuditEntryDTO{Method: "DELETE"}, nil)
+
+	assert.Error(t, err)
+}
@@ -0,0 +1,68 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/k6zma/DockerMonitoringApp/backend/internal/application/dto"
+	"github.com/k6zma/DockerMonitoringApp/backend/internal/application/repositories"
+	"github.com/k6zma/DockerMonitoringApp/backend/internal/domain"
+	"github.com/k6zma/DockerMonitoringApp/backend/pkg/utils"
+)
+
+type ContainerEventUseCaseInterface interface {
+	FindContainerEvents(ctx context.Context, filter *dto.ContainerEventFilter) ([]*dto.ContainerEventDTO, error)
+}
+
+type ContainerEventUseCase struct {
+	repo   repositories.ContainerEventRepository
+	logger utils.LoggerInterface
+}
+
+func NewContainerEventUseCase(
+	repo repositories.ContainerEventRepository,
+	logger utils.LoggerInterface,
+) *ContainerEventUseCase {
+	return &ContainerEventUseCase{
+		repo:   repo,
+		logger: logger,
+	}
+}
+
+func (uc *ContainerEventUseCase) FindContainerEvents(
+	ctx context.Context,
+	filter *dto.ContainerEventFilter,
+) (_ []*dto.ContainerEventDTO, err error) {
+	ctx, span := tracer.Start(ctx, "ContainerEventUseCase.FindContainerEvents")
+	defer func() { endSpan(span, err) }()
+
+	logger := utils.ContextLogger(ctx, uc.logger)
+
+	logger.Debugf("USECASES: finding container events with filter: %+v", filter)
+
+	events, err := uc.repo.Find(ctx, filter)
+	if err != nil {
+		logger.Errorf("USECASES: failed to fetch container events: %v", err)
+		return nil, fmt.Errorf("failed to fetch container events: %w", err)
+	}
+
+	var dtos = make([]*dto.ContainerEventDTO, 0, len(events))
+	for _, event := range events {
+		dtos = append(dtos, mapEventDomainToDTO(event))
+	}
+
+	logger.Debugf("USECASES: found %d container events", len(dtos))
+
+	return dtos, nil
+}
+
+func mapEventDomainToDTO(event *domain.ContainerEvent) *dto.ContainerEventDTO {
+	return &dto.ContainerEventDTO{
+		ID:          event.ID,
+		ContainerID: event.ContainerID,
+		Type:        event.Type,
+		Status:      event.Status,
+		Message:     event.Message,
+		CreatedAt:   event.CreatedAt,
+	}
+}
@@ -1,9 +1,15 @@
 package usecases
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"slices"
 	"time"
 
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
 	"github.com/k6zma/DockerMonitoringApp/backend/internal/application/dto"
 	"github.com/k6zma/DockerMonitoringApp/backend/internal/application/repositories"
 	"github.com/k6zma/DockerMonitoringApp/backend/internal/domain"
@@ -11,35 +17,70 @@ import (
 )
 
 type ContainerStatusUseCaseInterface interface {
-	FindContainerStatuses(filter *dto.ContainerStatusFilter) ([]*dto.ContainerStatusDTO, error)
-	CreateContainerStatus(statusDTO *dto.ContainerStatusDTO) (*dto.ContainerStatusDTO, error)
-	UpdateContainerStatus(containerID string, statusDTO *dto.ContainerStatusDTO) error
-	DeleteContainerStatusByContainerID(containerID string) error
+	FindContainerStatuses(ctx context.Context, filter *dto.ContainerStatusFilter) ([]*dto.ContainerStatusDTO, error)
+	GetContainerStatusesVersion(ctx context.Context, filter *dto.ContainerStatusFilter) (*dto.ContainerStatusListVersionDTO, error)
+	CreateContainerStatus(ctx context.Context, statusDTO *dto.ContainerStatusDTO) (*dto.ContainerStatusDTO, error)
+	UpdateContainerStatus(ctx context.Context, containerID string, statusDTO *dto.ContainerStatusDTO) error
+	DeleteContainerStatusByContainerID(ctx context.Context, containerID string) error
+}
+
+// ErrStaleContainerStatus is returned for results checked before the stored
+// status was, e.g. ones a pinger delivers late from its outbox.
+var ErrStaleContainerStatus = errors.New("container status is older than the stored one")
+
+// crashLoopFailureStatuses are the states a container passes through when it crashes
+// and is restarted by the Docker daemon.
+var crashLoopFailureStatuses = []string{"restarting", "exited", "dead"}
+
+// CrashLoopPolicy describes when a container is considered to be crash looping:
+// at least Threshold restarts or failure transitions observed within Window.
+// A zero Threshold disables detection.
+type CrashLoopPolicy struct {
+	Window    time.Duration
+	Threshold int
 }
 
 type ContainerStatusUseCase struct {
-	repo   repositories.ContainerStatusRepository
-	logger utils.LoggerInterface
+	repo            repositories.ContainerStatusRepository
+	eventRepo       repositories.ContainerEventRepository
+	transactor      repositories.Transactor
+	crashLoopPolicy CrashLoopPolicy
+	publisher       StatusChangePublisher
+	logger          utils.LoggerInterface
 }
 
 func NewContainerStatusUseCase(
 	repo repositories.ContainerStatusRepository,
+	eventRepo repositories.ContainerEventRepository,
+	transactor repositories.Transactor,
+	crashLoopPolicy CrashLoopPolicy,
+	publisher StatusChangePublisher,
 	logger utils.LoggerInterface,
 ) *ContainerStatusUseCase {
 	return &ContainerStatusUseCase{
-		repo:   repo,
-		logger: logger,
+		repo:            repo,
+		eventRepo:       eventRepo,
+		transactor:      transactor,
+		crashLoopPolicy: crashLoopPolicy,
+		publisher:       publisher,
+		logger:          logger,
 	}
 }
 
 func (uc *ContainerStatusUseCase) FindContainerStatuses(
+	ctx context.Context,
 	filter *dto.ContainerStatusFilter,
-) ([]*dto.ContainerStatusDTO, error) {
-	uc.logger.Debugf("USECASES: finding container statuses with filter: %+v", filter)
+) (_ []*dto.ContainerStatusDTO, err error) {
+	ctx, span := tracer.Start(ctx, "ContainerStatusUseCase.FindContainerStatuses")
+	defer func() { endSpan(span, err) }()
+
+	logger := utils.ContextLogger(ctx, uc.logger)
+
+	logger.Debugf("USECASES: finding container statuses with filter: %+v", filter)
 
-	statuses, err := uc.repo.Find(filter)
+	statuses, err := uc.repo.Find(ctx, filter)
 	if err != nil {
-		uc.logger.Errorf("USECASES: failed to fetch container statuses: %v", err)
+		logger.Errorf("USECASES: failed to fetch container statuses: %v", err)
 		return nil, fmt.Errorf("failed to fetch container statuses: %w", err)
 	}
 
@@ -48,16 +89,55 @@ func (uc *ContainerStatusUseCase) FindContainerStatuses(
 		dtos = append(dtos, mapDomainToDTO(status))
 	}
 
-	uc.logger.Debugf("USECASES: found %d container statuses", len(dtos))
+	logger.Debugf("USECASES: found %d container statuses", len(dtos))
 
 	return dtos, nil
 }
 
+// GetContainerStatusesVersion describes the statuses FindContainerStatuses
+// would return for filter, cheap enough to validate a cached list.
+func (uc *ContainerStatusUseCase) GetContainerStatusesVersion(
+	ctx context.Context,
+	filter *dto.ContainerStatusFilter,
+) (_ *dto.ContainerStatusListVersionDTO, err error) {
+	ctx, span := tracer.Start(ctx, "ContainerStatusUseCase.GetContainerStatusesVersion")
+	defer func() { endSpan(span, err) }()
+
+	logger := utils.ContextLogger(ctx, uc.logger)
+
+	logger.Debugf("USECASES: getting container statuses version with filter: %+v", filter)
+
+	version, err := uc.repo.Version(ctx, filter)
+	if err != nil {
+		logger.Errorf("USECASES: failed to fetch container statuses version: %v", err)
+		return nil, fmt.Errorf("failed to fetch container statuses version: %w", err)
+	}
+
+	return &dto.ContainerStatusListVersionDTO{
+		Count:      version.Count,
+		UpdatedAt:  version.UpdatedAt,
+		ModifiedAt: version.ModifiedAt,
+	}, nil
+}
+
 func (uc *ContainerStatusUseCase) CreateContainerStatus(
+	ctx context.Context,
 	statusDTO *dto.ContainerStatusDTO,
-) (*dto.ContainerStatusDTO, error) {
-	uc.logger.Debugf("USECASES: creating container status: %+v", statusDTO)
+) (_ *dto.ContainerStatusDTO, err error) {
+	ctx, span := tracer.Start(ctx, "ContainerStatusUseCase.CreateContainerStatus",
+		trace.WithAttributes(attribute.String("container.id", statusDTO.ContainerID)))
+	defer func() { endSpan(span, err) }()
+
+	logger := utils.ContextLogger(ctx, uc.logger, "container_id", statusDTO.ContainerID)
+
+	logger.Debugf("USECASES: creating container status: %+v", statusDTO)
+
+	restartCount := 0
+	if statusDTO.RestartCount != nil {
+		restartCount = *statusDTO.RestartCount
+	}
 
+	checkedAt := checkedAt(statusDTO)
 	newStatus := &domain.ContainerStatus{
 		ContainerID:        statusDTO.ContainerID,
 		Name:               statusDTO.Name,
@@ -65,39 +145,72 @@ func (uc *ContainerStatusUseCase) CreateContainerStatus(
 		Status:             statusDTO.Status,
 		PingTime:           statusDTO.PingTime,
 		LastSuccessfulPing: statusDTO.LastSuccessfulPing,
-		CreatedAt:          time.Now(),
-		UpdatedAt:          time.Now(),
+		RestartCount:       restartCount,
+		Labels:             statusDTO.Labels,
+		CreatedAt:          checkedAt,
+		UpdatedAt:          checkedAt,
 	}
 
-	err := uc.repo.Create(newStatus)
+	err = uc.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := uc.repo.Create(ctx, newStatus); err != nil {
+			return err
+		}
+
+		uc.publisher.Publish(ctx, &domain.ContainerStatusChange{
+			Type:       domain.StatusChangeCreated,
+			Status:     *newStatus,
+			OccurredAt: newStatus.CreatedAt,
+		})
+
+		return nil
+	})
 	if err != nil {
-		uc.logger.Errorf("USECASES: failed to create container status: %v", err)
+		logger.Errorf("USECASES: failed to create container status: %v", err)
 		return nil, fmt.Errorf("failed to create container status: %w", err)
 	}
 
-	uc.logger.Debugf("Created container status record")
+	recordAuditChange(ctx, newStatus.ContainerID, nil, newStatus)
+
+	logger.Debugf("Created container status record")
 
 	return mapDomainToDTO(newStatus), nil
 }
 
 func (uc *ContainerStatusUseCase) UpdateContainerStatus(
+	ctx context.Context,
 	containerID string,
 	statusDTO *dto.ContainerStatusDTO,
-) error {
-	uc.logger.Debugf("USECASES: updating container status for container ID: %s with data: %+v", containerID, statusDTO)
+) (err error) {
+	ctx, span := tracer.Start(ctx, "ContainerStatusUseCase.UpdateContainerStatus",
+		trace.WithAttributes(attribute.String("container.id", containerID)))
+	defer func() { endSpan(span, err) }()
+
+	logger := utils.ContextLogger(ctx, uc.logger, "container_id", containerID)
+
+	logger.Debugf("USECASES: updating container status for container ID: %s with data: %+v", containerID, statusDTO)
 
-	existing, err := uc.repo.Find(&dto.ContainerStatusFilter{ContainerID: &containerID})
+	existing, err := uc.repo.Find(ctx, &dto.ContainerStatusFilter{ContainerID: &containerID})
 	if err != nil {
-		uc.logger.Errorf("USECASES: error fetching container status for container ID %s: %v", containerID, err)
+		logger.Errorf("USECASES: error fetching container status for container ID %s: %v", containerID, err)
 		return fmt.Errorf("error fetching container status: %w", err)
 	}
 
 	if len(existing) == 0 {
-		uc.logger.Errorf("USECASES: error fetching container status with container ID %s not found", containerID)
+		logger.Errorf("USECASES: error fetching container status with container ID %s not found", containerID)
 		return fmt.Errorf("container status with container ID %s not found", containerID)
 	}
 
 	status := existing[0]
+	before := *status
+	now := checkedAt(statusDTO)
+
+	if now.Before(status.UpdatedAt) {
+		logger.Warnf("USECASES: ignoring container status for container ID %s checked at %s, stored one was checked at %s",
+			containerID, now.Format(time.RFC3339Nano), status.UpdatedAt.Format(time.RFC3339Nano))
+		return fmt.Errorf("%w: checked at %s", ErrStaleContainerStatus, now.Format(time.RFC3339Nano))
+	}
+
+	events := transitionEvents(status, statusDTO, now)
 
 	if statusDTO.PingTime != 0 {
 		status.PingTime = statusDTO.PingTime
@@ -111,41 +224,288 @@ func (uc *ContainerStatusUseCase) UpdateContainerStatus(
 	if statusDTO.Name != "" {
 		status.Name = statusDTO.Name
 	}
+	if statusDTO.RestartCount != nil {
+		status.RestartCount = *statusDTO.RestartCount
+	}
+	if statusDTO.Labels != nil {
+		status.Labels = statusDTO.Labels
+	}
+
+	alert := uc.updateCrashLoopCondition(ctx, status, events, now)
 
-	status.UpdatedAt = time.Now()
+	status.UpdatedAt = now
 
-	err = uc.repo.Update(status)
+	err = uc.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		return uc.writeUpdate(ctx, status, &before, events, alert)
+	})
 	if err != nil {
-		uc.logger.Errorf("USECASES: failed to update container status for container ID %s: %v", containerID, err)
+		logger.Errorf("USECASES: failed to update container status for container ID %s: %v", containerID, err)
 		return fmt.Errorf("failed to update container status: %w", err)
 	}
 
-	uc.logger.Debugf("Successfully updated container status for container ID: %s", containerID)
+	recordAuditChange(ctx, containerID, &before, status)
+
+	logger.Debugf("Successfully updated container status for container ID: %s", containerID)
+
+	return nil
+}
+
+// writeUpdate stores the updated status together with its events, and
+// publishes the changes they make.
+func (uc *ContainerStatusUseCase) writeUpdate(
+	ctx context.Context,
+	status *domain.ContainerStatus,
+	before *domain.ContainerStatus,
+	events []*domain.ContainerEvent,
+	alert *domain.ContainerEvent,
+) error {
+	if err := uc.repo.Update(ctx, status); err != nil {
+		return err
+	}
+
+	for _, event := range events {
+		if err := uc.createEvent(ctx, event); err != nil {
+			return err
+		}
+	}
+	if alert != nil {
+		if err := uc.raiseAlert(ctx, status, alert); err != nil {
+			return err
+		}
+	}
+
+	uc.publisher.Publish(ctx, &domain.ContainerStatusChange{
+		Type:       domain.StatusChangeUpdated,
+		Status:     *status,
+		Previous:   before,
+		OccurredAt: status.UpdatedAt,
+	})
 
 	return nil
 }
 
-func (uc *ContainerStatusUseCase) DeleteContainerStatusByContainerID(containerID string) error {
-	uc.logger.Debugf("USECASES: deleting container status for container_id: %s", containerID)
+func (uc *ContainerStatusUseCase) DeleteContainerStatusByContainerID(
+	ctx context.Context,
+	containerID string,
+) (err error) {
+	ctx, span := tracer.Start(ctx, "ContainerStatusUseCase.DeleteContainerStatusByContainerID",
+		trace.WithAttributes(attribute.String("container.id", containerID)))
+	defer func() { endSpan(span, err) }()
+
+	logger := utils.ContextLogger(ctx, uc.logger, "container_id", containerID)
+
+	logger.Debugf("USECASES: deleting container status for container_id: %s", containerID)
 
-	existing, err := uc.repo.Find(&dto.ContainerStatusFilter{ContainerID: &containerID})
+	existing, err := uc.repo.Find(ctx, &dto.ContainerStatusFilter{ContainerID: &containerID})
 	if err != nil {
-		uc.logger.Errorf("USECASES: error checking container status for container_id %s: %v", containerID, err)
+		logger.Errorf("USECASES: error checking container status for container_id %s: %v", containerID, err)
 		return fmt.Errorf("error checking container status: %w", err)
 	}
 
 	if len(existing) == 0 {
-		uc.logger.Warnf("USECASES: attempted to delete non-existent container status for container_id: %s", containerID)
+		logger.Warnf("USECASES: attempted to delete non-existent container status for container_id: %s", containerID)
 		return fmt.Errorf("container status with container_id %s not found", containerID)
 	}
 
-	err = uc.repo.DeleteByContainerID(containerID)
+	err = uc.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := uc.repo.DeleteByContainerID(ctx, containerID); err != nil {
+			return err
+		}
+
+		uc.publisher.Publish(ctx, &domain.ContainerStatusChange{
+			Type:       domain.StatusChangeDeleted,
+			Status:     *existing[0],
+			OccurredAt: time.Now(),
+		})
+
+		return nil
+	})
 	if err != nil {
-		uc.logger.Errorf("USECASES: failed to delete container status for container_id %s: %v", containerID, err)
+		logger.Errorf("USECASES: failed to delete container status for container_id %s: %v", containerID, err)
 		return fmt.Errorf("failed to delete container status: %w", err)
 	}
 
-	uc.logger.Debugf("USECASES: successfully deleted container status for container_id: %s", containerID)
+	recordAuditChange(ctx, containerID, existing[0], nil)
+
+	logger.Debugf("USECASES: successfully deleted container status for container_id: %s", containerID)
+	return nil
+}
+
+// checkedAt returns when the pinger checked the container the update is
+// about. Updates without a check time, or with one ahead of the clock, are
+// taken as checked now.
+func checkedAt(statusDTO *dto.ContainerStatusDTO) time.Time {
+	now := time.Now()
+	if statusDTO.CheckedAt.IsZero() || statusDTO.CheckedAt.After(now) {
+		return now
+	}
+
+	return statusDTO.CheckedAt
+}
+
+// transitionEvents returns the restart and status change events derived from
+// the difference between the stored status and the incoming update.
+func transitionEvents(
+	status *domain.ContainerStatus,
+	statusDTO *dto.ContainerStatusDTO,
+	now time.Time,
+) []*domain.ContainerEvent {
+	var events []*domain.ContainerEvent
+
+	if statusDTO.RestartCount != nil && *statusDTO.RestartCount > status.RestartCount {
+		events = append(events, &domain.ContainerEvent{
+			ContainerID: status.ContainerID,
+			Type:        domain.EventTypeRestarted,
+			Status:      status.Status,
+			Message:     fmt.Sprintf("restart count increased from %d to %d", status.RestartCount, *statusDTO.RestartCount),
+			CreatedAt:   now,
+		})
+	}
+
+	if statusDTO.Status != "" && statusDTO.Status != status.Status {
+		events = append(events, &domain.ContainerEvent{
+			ContainerID: status.ContainerID,
+			Type:        domain.EventTypeStatusChanged,
+			Status:      statusDTO.Status,
+			Message:     fmt.Sprintf("status changed from %s to %s", status.Status, statusDTO.Status),
+			CreatedAt:   now,
+		})
+	}
+
+	return events
+}
+
+// updateCrashLoopCondition counts the crashes inside the policy window, the
+// stored ones and those among the pending events of the update, and flips the
+// crash_looping condition. It returns the event to raise when the condition
+// changes.
+func (uc *ContainerStatusUseCase) updateCrashLoopCondition(
+	ctx context.Context,
+	status *domain.ContainerStatus,
+	pending []*domain.ContainerEvent,
+	now time.Time,
+) *domain.ContainerEvent {
+	logger := utils.ContextLogger(ctx, uc.logger, "container_id", status.ContainerID)
+
+	if uc.crashLoopPolicy.Threshold <= 0 {
+		return nil
+	}
+
+	since := now.Add(-uc.crashLoopPolicy.Window)
+
+	restarts, err := uc.eventRepo.Find(ctx, &dto.ContainerEventFilter{
+		ContainerID:  &status.ContainerID,
+		Types:        []string{domain.EventTypeRestarted},
+		CreatedAtGte: &since,
+	})
+	if err != nil {
+		logger.Errorf("USECASES: failed to fetch restarts for container ID %s: %v", status.ContainerID, err)
+		return nil
+	}
+
+	failures, err := uc.eventRepo.Find(ctx, &dto.ContainerEventFilter{
+		ContainerID:  &status.ContainerID,
+		Types:        []string{domain.EventTypeStatusChanged},
+		Statuses:     crashLoopFailureStatuses,
+		CreatedAtGte: &since,
+	})
+	if err != nil {
+		logger.Errorf("USECASES: failed to fetch status transitions for container ID %s: %v", status.ContainerID, err)
+		return nil
+	}
+
+	crashes := countCrashes(restarts, failures, crashEvents(pending))
+
+	crashLooping := crashes >= uc.crashLoopPolicy.Threshold
+	if crashLooping == status.CrashLooping {
+		return nil
+	}
+
+	status.CrashLooping = crashLooping
+
+	if crashLooping {
+		return &domain.ContainerEvent{
+			ContainerID: status.ContainerID,
+			Type:        domain.EventTypeCrashLoopDetected,
+			Status:      status.Status,
+			Message:     fmt.Sprintf("%d crashes within %s", crashes, uc.crashLoopPolicy.Window),
+			CreatedAt:   now,
+		}
+	}
+
+	return &domain.ContainerEvent{
+		ContainerID: status.ContainerID,
+		Type:        domain.EventTypeCrashLoopResolved,
+		Status:      status.Status,
+		Message:     fmt.Sprintf("fewer than %d crashes within %s", uc.crashLoopPolicy.Threshold, uc.crashLoopPolicy.Window),
+		CreatedAt:   now,
+	}
+}
+
+// crashEvents returns the restarts and failure transitions among events.
+func crashEvents(events []*domain.ContainerEvent) []*domain.ContainerEvent {
+	var crashes []*domain.ContainerEvent
+	for _, event := range events {
+		switch {
+		case event.Type == domain.EventTypeRestarted,
+			event.Type == domain.EventTypeStatusChanged && slices.Contains(crashLoopFailureStatuses, event.Status):
+			crashes = append(crashes, event)
+		}
+	}
+
+	return crashes
+}
+
+// countCrashes counts the distinct times of restart and failure transition
+// events. An update that raises the restart count and moves the container into
+// a failure status records both at the same time, they are a single crash.
+func countCrashes(events ...[]*domain.ContainerEvent) int {
+	crashes := make(map[int64]struct{})
+	for _, list := range events {
+		for _, event := range list {
+			crashes[event.CreatedAt.UnixMicro()] = struct{}{}
+		}
+	}
+
+	return len(crashes)
+}
+
+func (uc *ContainerStatusUseCase) createEvent(ctx context.Context, event *domain.ContainerEvent) error {
+	logger := utils.ContextLogger(ctx, uc.logger, "container_id", event.ContainerID)
+
+	if err := uc.eventRepo.Create(ctx, event); err != nil {
+		logger.Errorf("USECASES: failed to record %s event for container ID %s: %v", event.Type, event.ContainerID, err)
+		return fmt.Errorf("failed to record %s event: %w", event.Type, err)
+	}
+
+	return nil
+}
+
+// raiseAlert records event and publishes it as an alert for status.
+func (uc *ContainerStatusUseCase) raiseAlert(
+	ctx context.Context,
+	status *domain.ContainerStatus,
+	event *domain.ContainerEvent,
+) error {
+	logger := utils.ContextLogger(ctx, uc.logger, "container_id", status.ContainerID)
+
+	if event.Type == domain.EventTypeCrashLoopDetected {
+		logger.Warnf("USECASES: container ID %s is crash looping", status.ContainerID)
+	} else {
+		logger.Infof("USECASES: container ID %s is no longer crash looping", status.ContainerID)
+	}
+
+	if err := uc.createEvent(ctx, event); err != nil {
+		return err
+	}
+
+	uc.publisher.Publish(ctx, &domain.ContainerStatusChange{
+		Type:       domain.StatusChangeAlert,
+		Status:     *status,
+		Event:      event,
+		OccurredAt: event.CreatedAt,
+	})
+
 	return nil
 }
 
@@ -157,6 +517,9 @@ func mapDomainToDTO(status *domain.ContainerStatus) *dto.ContainerStatusDTO {
 		Status:             status.Status,
 		PingTime:           status.PingTime,
 		LastSuccessfulPing: status.LastSuccessfulPing,
+		RestartCount:       &status.RestartCount,
+		CrashLooping:       status.CrashLooping,
+		Labels:             status.Labels,
 		UpdatedAt:          status.UpdatedAt,
 		CreatedAt:          status.CreatedAt,
 	}
@@ -1,6 +1,8 @@
 package usecases_test
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"testing"
 	"time"
@@ -24,9 +26,11 @@ const (
 
 func TestFindContainerStatuses_Success(t *testing.T) {
 	mockRepo := new(mocks.ContainerStatusRepository)
+	mockEventRepo := new(mocks.ContainerEventRepository)
 	mockLogger := new(mocks.LoggerInterface)
+	mockPublisher := new(mocks.StatusChangePublisher)
 
-	useCase := usecases.NewContainerStatusUseCase(mockRepo, mockLogger)
+	useCase := usecases.NewContainerStatusUseCase(mockRepo, mockEventRepo, inTransaction(), usecases.CrashLoopPolicy{}, mockPublisher, mockLogger)
 
 	mockFilter := &dto.ContainerStatusFilter{
 		ContainerID: new(string),
@@ -42,9 +46,9 @@ func TestFindContainerStatuses_Success(t *testing.T) {
 	}
 
 	mockLogger.On("Debugf", mock.Anything, mock.Anything).Return()
-	mockRepo.On("Find", mockFilter).Return(mockResult, nil)
+	mockRepo.On("Find", mock.Anything, mockFilter).Return(mockResult, nil)
 
-	result, err := useCase.FindContainerStatuses(mockFilter)
+	result, err := useCase.FindContainerStatuses(context.Background(), mockFilter)
 
 	assert.NoError(t, err)
 	assert.Len(t, result, 1)
@@ -57,17 +61,72 @@ func TestFindContainerStatuses_Success(t *testing.T) {
 
 func TestFindContainerStatuses_Error(t *testing.T) {
 	mockRepo := new(mocks.ContainerStatusRepository)
+	mockEventRepo := new(mocks.ContainerEventRepository)
 	mockLogger := new(mocks.LoggerInterface)
+	mockPublisher := new(mocks.StatusChangePublisher)
 
-	useCase := usecases.NewContainerStatusUseCase(mockRepo, mockLogger)
+	useCase := usecases.NewContainerStatusUseCase(mockRepo, mockEventRepo, inTransaction(), usecases.CrashLoopPolicy{}, mockPublisher, mockLogger)
 
 	mockFilter := &dto.ContainerStatusFilter{}
 
 	mockLogger.On("Debugf", mock.Anything, mock.Anything).Return()
-	mockRepo.On("Find", mockFilter).Return(nil, fmt.Errorf("database error"))
+	mockRepo.On("Find", mock.Anything, mockFilter).Return(nil, fmt.Errorf("database error"))
 	mockLogger.On("Errorf", mock.Anything, mock.Anything, mock.Anything).Return()
 
-	result, err := useCase.FindContainerStatuses(mockFilter)
+	result, err := useCase.FindContainerStatuses(context.Background(), mockFilter)
+
+	assert.Error(t, err)
+	assert.Nil(t, result)
+
+	mockRepo.AssertExpectations(t)
+	mockLogger.AssertExpectations(t)
+}
+
+func TestGetContainerStatusesVersion_Success(t *testing.T) {
+	mockRepo := new(mocks.ContainerStatusRepository)
+	mockEventRepo := new(mocks.ContainerEventRepository)
+	mockLogger := new(mocks.LoggerInterface)
+	mockPublisher := new(mocks.StatusChangePublisher)
+
+	useCase := usecases.NewContainerStatusUseCase(mockRepo, mockEventRepo, inTransaction(), usecases.CrashLoopPolicy{}, mockPublisher, mockLogger)
+
+	mockFilter := &dto.ContainerStatusFilter{}
+	updatedAt := time.Now().Add(-time.Minute)
+	modifiedAt := time.Now()
+
+	mockLogger.On("Debugf", mock.Anything, mock.Anything).Return()
+	mockRepo.On("Version", mock.Anything, mockFilter).Return(&domain.ContainerStatusListVersion{
+		Count:      3,
+		UpdatedAt:  updatedAt,
+		ModifiedAt: modifiedAt,
+	}, nil)
+
+	result, err := useCase.GetContainerStatusesVersion(context.Background(), mockFilter)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, result.Count)
+	assert.Equal(t, updatedAt, result.UpdatedAt)
+	assert.Equal(t, modifiedAt, result.ModifiedAt)
+
+	mockRepo.AssertExpectations(t)
+	mockRepo.AssertNotCalled(t, "Find", mock.Anything, mock.Anything)
+}
+
+func TestGetContainerStatusesVersion_Error(t *testing.T) {
+	mockRepo := new(mocks.ContainerStatusRepository)
+	mockEventRepo := new(mocks.ContainerEventRepository)
+	mockLogger := new(mocks.LoggerInterface)
+	mockPublisher := new(mocks.StatusChangePublisher)
+
+	useCase := usecases.NewContainerStatusUseCase(mockRepo, mockEventRepo, inTransaction(), usecases.CrashLoopPolicy{}, mockPublisher, mockLogger)
+
+	mockFilter := &dto.ContainerStatusFilter{}
+
+	mockLogger.On("Debugf", mock.Anything, mock.Anything).Return()
+	mockRepo.On("Version", mock.Anything, mockFilter).Return(nil, fmt.Errorf("database error"))
+	mockLogger.On("Errorf", mock.Anything, mock.Anything).Return()
+
+	result, err := useCase.GetContainerStatusesVersion(context.Background(), mockFilter)
 
 	assert.Error(t, err)
 	assert.Nil(t, result)
@@ -78,9 +137,11 @@ func TestFindContainerStatuses_Error(t *testing.T) {
 
 func TestCreateContainerStatus_Success(t *testing.T) {
 	mockRepo := new(mocks.ContainerStatusRepository)
+	mockEventRepo := new(mocks.ContainerEventRepository)
 	mockLogger := new(mocks.LoggerInterface)
+	mockPublisher := new(mocks.StatusChangePublisher)
 
-	useCase := usecases.NewContainerStatusUseCase(mockRepo, mockLogger)
+	useCase := usecases.NewContainerStatusUseCase(mockRepo, mockEventRepo, inTransaction(), usecases.CrashLoopPolicy{}, mockPublisher, mockLogger)
 
 	mockDTO := &dto.ContainerStatusDTO{
 		ContainerID: testContainerIDStr,
@@ -89,9 +150,12 @@ func TestCreateContainerStatus_Success(t *testing.T) {
 	}
 
 	mockLogger.On("Debugf", mock.Anything, mock.Anything, mock.Anything).Return()
-	mockRepo.On("Create", mock.Anything).Return(nil)
+	mockRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+	mockPublisher.On("Publish", mock.Anything, mock.MatchedBy(func(change *domain.ContainerStatusChange) bool {
+		return change.Type == domain.StatusChangeCreated && change.Status.ContainerID == testContainerIDStr && change.Previous == nil
+	})).Return().Once()
 
-	result, err := useCase.CreateContainerStatus(mockDTO)
+	result, err := useCase.CreateContainerStatus(context.Background(), mockDTO)
 
 	assert.NoError(t, err)
 	assert.NotNil(t, result)
@@ -99,14 +163,17 @@ func TestCreateContainerStatus_Success(t *testing.T) {
 	assert.Equal(t, testContainerIDStr, result.ContainerID)
 
 	mockRepo.AssertExpectations(t)
+	mockPublisher.AssertExpectations(t)
 	mockLogger.AssertExpectations(t)
 }
 
 func TestCreateContainerStatus_Error(t *testing.T) {
 	mockRepo := new(mocks.ContainerStatusRepository)
+	mockEventRepo := new(mocks.ContainerEventRepository)
 	mockLogger := new(mocks.LoggerInterface)
+	mockPublisher := new(mocks.StatusChangePublisher)
 
-	useCase := usecases.NewContainerStatusUseCase(mockRepo, mockLogger)
+	useCase := usecases.NewContainerStatusUseCase(mockRepo, mockEventRepo, inTransaction(), usecases.CrashLoopPolicy{}, mockPublisher, mockLogger)
 
 	mockDTO := &dto.ContainerStatusDTO{
 		ContainerID: testContainerIDStr,
@@ -115,10 +182,10 @@ func TestCreateContainerStatus_Error(t *testing.T) {
 	}
 
 	mockLogger.On("Debugf", mock.Anything, mock.Anything, mock.Anything).Return()
-	mockRepo.On("Create", mock.Anything).Return(fmt.Errorf("failed to insert"))
+	mockRepo.On("Create", mock.Anything, mock.Anything).Return(fmt.Errorf("failed to insert"))
 	mockLogger.On("Errorf", mock.Anything, mock.Anything, mock.Anything).Return()
 
-	result, err := useCase.CreateContainerStatus(mockDTO)
+	result, err := useCase.CreateContainerStatus(context.Background(), mockDTO)
 
 	assert.Error(t, err)
 	assert.Nil(t, result)
@@ -129,9 +196,11 @@ func TestCreateContainerStatus_Error(t *testing.T) {
 
 func TestUpdateContainerStatus_Success(t *testing.T) {
 	mockRepo := new(mocks.ContainerStatusRepository)
+	mockEventRepo := new(mocks.ContainerEventRepository)
 	mockLogger := new(mocks.LoggerInterface)
+	mockPublisher := new(mocks.StatusChangePublisher)
 
-	useCase := usecases.NewContainerStatusUseCase(mockRepo, mockLogger)
+	useCase := usecases.NewContainerStatusUseCase(mockRepo, mockEventRepo, inTransaction(), usecases.CrashLoopPolicy{}, mockPublisher, mockLogger)
 
 	mockContainerID := testContainerIDStr
 	mockDTO := &dto.ContainerStatusDTO{
@@ -147,32 +216,40 @@ func TestUpdateContainerStatus_Success(t *testing.T) {
 	}
 
 	mockLogger.On("Debugf", mock.Anything, mock.Anything, mock.Anything).Return()
-	mockRepo.On("Find", &dto.ContainerStatusFilter{ContainerID: &mockContainerID}).Return(existingStatus, nil)
-	mockRepo.On("Update", mock.Anything).Return(nil)
+	mockRepo.On("Find", mock.Anything, &dto.ContainerStatusFilter{ContainerID: &mockContainerID}).Return(existingStatus, nil)
+	mockRepo.On("Update", mock.Anything, mock.Anything).Return(nil)
+	mockPublisher.On("Publish", mock.Anything, mock.MatchedBy(func(change *domain.ContainerStatusChange) bool {
+		return change.Type == domain.StatusChangeUpdated &&
+			change.Status.PingTime == testPingTimeUpdated &&
+			change.Previous.PingTime == testPingTimeDefault
+	})).Return().Once()
 
-	err := useCase.UpdateContainerStatus(mockContainerID, mockDTO)
+	err := useCase.UpdateContainerStatus(context.Background(), mockContainerID, mockDTO)
 
 	assert.NoError(t, err)
 
 	mockRepo.AssertExpectations(t)
+	mockPublisher.AssertExpectations(t)
 	mockLogger.AssertExpectations(t)
 }
 
 func TestUpdateContainerStatus_ErrorFetching(t *testing.T) {
 	mockRepo := new(mocks.ContainerStatusRepository)
+	mockEventRepo := new(mocks.ContainerEventRepository)
 	mockLogger := new(mocks.LoggerInterface)
+	mockPublisher := new(mocks.StatusChangePublisher)
 
-	useCase := usecases.NewContainerStatusUseCase(mockRepo, mockLogger)
+	useCase := usecases.NewContainerStatusUseCase(mockRepo, mockEventRepo, inTransaction(), usecases.CrashLoopPolicy{}, mockPublisher, mockLogger)
 
 	mockContainerID := testContainerIDStr
 	mockDTO := &dto.ContainerStatusDTO{PingTime: testPingTimeUpdated}
 
 	mockLogger.On("Debugf", mock.Anything, mock.Anything, mock.Anything).Return()
-	mockRepo.On("Find", &dto.ContainerStatusFilter{ContainerID: &mockContainerID}).
+	mockRepo.On("Find", mock.Anything, &dto.ContainerStatusFilter{ContainerID: &mockContainerID}).
 		Return(nil, fmt.Errorf("database error"))
 	mockLogger.On("Errorf", mock.Anything, mock.Anything, mock.Anything).Return()
 
-	err := useCase.UpdateContainerStatus(mockContainerID, mockDTO)
+	err := useCase.UpdateContainerStatus(context.Background(), mockContainerID, mockDTO)
 
 	assert.Error(t, err)
 
@@ -182,21 +259,23 @@ func TestUpdateContainerStatus_ErrorFetching(t *testing.T) {
 
 func TestUpdateContainerStatus_NotFound(t *testing.T) {
 	mockRepo := new(mocks.ContainerStatusRepository)
+	mockEventRepo := new(mocks.ContainerEventRepository)
 	mockLogger := new(mocks.LoggerInterface)
+	mockPublisher := new(mocks.StatusChangePublisher)
 
-	useCase := usecases.NewContainerStatusUseCase(mockRepo, mockLogger)
+	useCase := usecases.NewContainerStatusUseCase(mockRepo, mockEventRepo, inTransaction(), usecases.CrashLoopPolicy{}, mockPublisher, mockLogger)
 
 	mockContainerID := testContainerIDStr
 	mockDTO := &dto.ContainerStatusDTO{PingTime: testPingTimeUpdated}
 
 	mockLogger.On("Debugf", "USECASES: updating container status for container ID: %s with data: %+v", mockContainerID, mock.Anything).
 		Return()
-	mockRepo.On("Find", &dto.ContainerStatusFilter{ContainerID: &mockContainerID}).
+	mockRepo.On("Find", mock.Anything, &dto.ContainerStatusFilter{ContainerID: &mockContainerID}).
 		Return([]*domain.ContainerStatus{}, nil)
 	mockLogger.On("Errorf", "USECASES: error fetching container status with container ID %s not found", mockContainerID).
 		Return()
 
-	err := useCase.UpdateContainerStatus(mockContainerID, mockDTO)
+	err := useCase.UpdateContainerStatus(context.Background(), mockContainerID, mockDTO)
 
 	assert.Error(t, err)
 	assert.Equal(t, fmt.Errorf("container status with container ID %s not found", mockContainerID), err)
@@ -207,9 +286,11 @@ func TestUpdateContainerStatus_NotFound(t *testing.T) {
 
 func TestUpdateContainerStatus_UpdateError(t *testing.T) {
 	mockRepo := new(mocks.ContainerStatusRepository)
+	mockEventRepo := new(mocks.ContainerEventRepository)
 	mockLogger := new(mocks.LoggerInterface)
+	mockPublisher := new(mocks.StatusChangePublisher)
 
-	useCase := usecases.NewContainerStatusUseCase(mockRepo, mockLogger)
+	useCase := usecases.NewContainerStatusUseCase(mockRepo, mockEventRepo, inTransaction(), usecases.CrashLoopPolicy{}, mockPublisher, mockLogger)
 
 	mockContainerID := testContainerIDStr
 	mockDTO := &dto.ContainerStatusDTO{PingTime: testPingTimeUpdated}
@@ -223,12 +304,12 @@ func TestUpdateContainerStatus_UpdateError(t *testing.T) {
 
 	mockLogger.On("Debugf", "USECASES: updating container status for container ID: %s with data: %+v", mockContainerID, mock.Anything).
 		Return()
-	mockRepo.On("Find", &dto.ContainerStatusFilter{ContainerID: &mockContainerID}).Return(existingStatus, nil)
-	mockRepo.On("Update", mock.Anything).Return(fmt.Errorf("update failed"))
+	mockRepo.On("Find", mock.Anything, &dto.ContainerStatusFilter{ContainerID: &mockContainerID}).Return(existingStatus, nil)
+	mockRepo.On("Update", mock.Anything, mock.Anything).Return(fmt.Errorf("update failed"))
 	mockLogger.On("Errorf", "USECASES: failed to update container status for container ID %s: %v", mockContainerID, mock.Anything).
 		Return()
 
-	err := useCase.UpdateContainerStatus(mockContainerID, mockDTO)
+	err := useCase.UpdateContainerStatus(context.Background(), mockContainerID, mockDTO)
 
 	assert.Error(t, err)
 	assert.ErrorContains(t, err, "failed to update container status: update failed")
@@ -239,18 +320,20 @@ func TestUpdateContainerStatus_UpdateError(t *testing.T) {
 
 func TestDeleteContainerStatusByContainerID_ErrorFetching(t *testing.T) {
 	mockRepo := new(mocks.ContainerStatusRepository)
+	mockEventRepo := new(mocks.ContainerEventRepository)
 	mockLogger := new(mocks.LoggerInterface)
+	mockPublisher := new(mocks.StatusChangePublisher)
 
-	useCase := usecases.NewContainerStatusUseCase(mockRepo, mockLogger)
+	useCase := usecases.NewContainerStatusUseCase(mockRepo, mockEventRepo, inTransaction(), usecases.CrashLoopPolicy{}, mockPublisher, mockLogger)
 
 	mockContainerID := testContainerIDStr
 
 	mockLogger.On("Debugf", mock.Anything, mock.Anything).Return()
-	mockRepo.On("Find", &dto.ContainerStatusFilter{ContainerID: &mockContainerID}).
+	mockRepo.On("Find", mock.Anything, &dto.ContainerStatusFilter{ContainerID: &mockContainerID}).
 		Return(nil, fmt.Errorf("database error"))
 	mockLogger.On("Errorf", mock.Anything, mock.Anything, mock.Anything).Return()
 
-	err := useCase.DeleteContainerStatusByContainerID(mockContainerID)
+	err := useCase.DeleteContainerStatusByContainerID(context.Background(), mockContainerID)
 
 	assert.Error(t, err)
 
@@ -260,18 +343,20 @@ func TestDeleteContainerStatusByContainerID_ErrorFetching(t *testing.T) {
 
 func TestDeleteContainerStatusByContainerID_NotFound(t *testing.T) {
 	mockRepo := new(mocks.ContainerStatusRepository)
+	mockEventRepo := new(mocks.ContainerEventRepository)
 	mockLogger := new(mocks.LoggerInterface)
+	mockPublisher := new(mocks.StatusChangePublisher)
 
-	useCase := usecases.NewContainerStatusUseCase(mockRepo, mockLogger)
+	useCase := usecases.NewContainerStatusUseCase(mockRepo, mockEventRepo, inTransaction(), usecases.CrashLoopPolicy{}, mockPublisher, mockLogger)
 
 	mockContainerID := testContainerIDStr
 
 	mockLogger.On("Debugf", mock.Anything, mock.Anything).Return()
-	mockRepo.On("Find", &dto.ContainerStatusFilter{ContainerID: &mockContainerID}).
+	mockRepo.On("Find", mock.Anything, &dto.ContainerStatusFilter{ContainerID: &mockContainerID}).
 		Return([]*domain.ContainerStatus{}, nil)
 	mockLogger.On("Warnf", mock.Anything, mock.Anything, mock.Anything).Return()
 
-	err := useCase.DeleteContainerStatusByContainerID(mockContainerID)
+	err := useCase.DeleteContainerStatusByContainerID(context.Background(), mockContainerID)
 
 	assert.Error(t, err)
 
@@ -281,9 +366,11 @@ func TestDeleteContainerStatusByContainerID_NotFound(t *testing.T) {
 
 func TestDeleteContainerStatusByContainerID_ErrorDeleting(t *testing.T) {
 	mockRepo := new(mocks.ContainerStatusRepository)
+	mockEventRepo := new(mocks.ContainerEventRepository)
 	mockLogger := new(mocks.LoggerInterface)
+	mockPublisher := new(mocks.StatusChangePublisher)
 
-	useCase := usecases.NewContainerStatusUseCase(mockRepo, mockLogger)
+	useCase := usecases.NewContainerStatusUseCase(mockRepo, mockEventRepo, inTransaction(), usecases.CrashLoopPolicy{}, mockPublisher, mockLogger)
 
 	mockContainerID := testContainerIDStr
 	existingStatus := []*domain.ContainerStatus{
@@ -295,11 +382,11 @@ func TestDeleteContainerStatusByContainerID_ErrorDeleting(t *testing.T) {
 	}
 
 	mockLogger.On("Debugf", mock.Anything, mock.Anything).Return()
-	mockRepo.On("Find", &dto.ContainerStatusFilter{ContainerID: &mockContainerID}).Return(existingStatus, nil)
-	mockRepo.On("DeleteByContainerID", mockContainerID).Return(fmt.Errorf("delete failed"))
+	mockRepo.On("Find", mock.Anything, &dto.ContainerStatusFilter{ContainerID: &mockContainerID}).Return(existingStatus, nil)
+	mockRepo.On("DeleteByContainerID", mock.Anything, mockContainerID).Return(fmt.Errorf("delete failed"))
 	mockLogger.On("Errorf", mock.Anything, mock.Anything, mock.Anything).Return()
 
-	err := useCase.DeleteContainerStatusByContainerID(mockContainerID)
+	err := useCase.DeleteContainerStatusByContainerID(context.Background(), mockContainerID)
 
 	assert.Error(t, err)
 
@@ -309,9 +396,11 @@ func TestDeleteContainerStatusByContainerID_ErrorDeleting(t *testing.T) {
 
 func TestDeleteContainerStatusByContainerID_Success(t *testing.T) {
 	mockRepo := new(mocks.ContainerStatusRepository)
+	mockEventRepo := new(mocks.ContainerEventRepository)
 	mockLogger := new(mocks.LoggerInterface)
+	mockPublisher := new(mocks.StatusChangePublisher)
 
-	useCase := usecases.NewContainerStatusUseCase(mockRepo, mockLogger)
+	useCase := usecases.NewContainerStatusUseCase(mockRepo, mockEventRepo, inTransaction(), usecases.CrashLoopPolicy{}, mockPublisher, mockLogger)
 
 	mockContainerID := testContainerIDStr
 	existingStatus := []*domain.ContainerStatus{
@@ -323,15 +412,382 @@ func TestDeleteContainerStatusByContainerID_Success(t *testing.T) {
 	}
 
 	mockLogger.On("Debugf", mock.Anything, mock.Anything).Return()
-	mockRepo.On("Find", &dto.ContainerStatusFilter{ContainerID: &mockContainerID}).Return(existingStatus, nil)
-	mockRepo.On("DeleteByContainerID", mockContainerID).Return(nil)
+	mockRepo.On("Find", mock.Anything, &dto.ContainerStatusFilter{ContainerID: &mockContainerID}).Return(existingStatus, nil)
+	mockRepo.On("DeleteByContainerID", mock.Anything, mockContainerID).Return(nil)
+	mockPublisher.On("Publish", mock.Anything, mock.MatchedBy(func(change *domain.ContainerStatusChange) bool {
+		return change.Type == domain.StatusChangeDeleted && change.Status.IPAddress == testContainerIP
+	})).Return().Once()
 	mockLogger.On("Debugf", "USECASES: successfully deleted container status for container_id: %s", mockContainerID).
 		Return()
 
-	err := useCase.DeleteContainerStatusByContainerID(mockContainerID)
+	err := useCase.DeleteContainerStatusByContainerID(context.Background(), mockContainerID)
 
 	assert.NoError(t, err)
 
 	mockRepo.AssertExpectations(t)
+	mockPublisher.AssertExpectations(t)
 	mockLogger.AssertExpectations(t)
 }
+
+func TestUpdateContainerStatus_CrashLoopDetected(t *testing.T) {
+	mockRepo := new(mocks.ContainerStatusRepository)
+	mockEventRepo := new(mocks.ContainerEventRepository)
+	mockLogger := new(mocks.LoggerInterface)
+	mockPublisher := new(mocks.StatusChangePublisher)
+
+	policy := usecases.CrashLoopPolicy{Window: 5 * time.Minute, Threshold: 3}
+	useCase := usecases.NewContainerStatusUseCase(mockRepo, mockEventRepo, inTransaction(), policy, mockPublisher, mockLogger)
+
+	mockContainerID := testContainerIDStr
+	mockDTO := &dto.ContainerStatusDTO{
+		Status:       "restarting",
+		RestartCount: intPtr(3),
+	}
+	existingStatus := []*domain.ContainerStatus{
+		{
+			ContainerID:  mockContainerID,
+			IPAddress:    testContainerIP,
+			Status:       "running",
+			RestartCount: 2,
+		},
+	}
+
+	mockLogger.On("Debugf", mock.Anything, mock.Anything, mock.Anything).Return()
+	mockPublisher.On("Publish", mock.Anything, mock.MatchedBy(func(change *domain.ContainerStatusChange) bool {
+		return change.Type == domain.StatusChangeAlert && change.Event.Type == domain.EventTypeCrashLoopDetected && change.Status.CrashLooping
+	})).Return().Once()
+	mockPublisher.On("Publish", mock.Anything, mock.MatchedBy(func(change *domain.ContainerStatusChange) bool {
+		return change.Type == domain.StatusChangeUpdated
+	})).Return().Once()
+	mockLogger.On("Warnf", mock.Anything, mock.Anything).Return()
+	mockRepo.On("Find", mock.Anything, &dto.ContainerStatusFilter{ContainerID: &mockContainerID}).Return(existingStatus, nil)
+	mockEventRepo.On("Create", mock.Anything, mock.MatchedBy(func(event *domain.ContainerEvent) bool {
+		return event.Type == domain.EventTypeRestarted
+	})).Return(nil).Once()
+	mockEventRepo.On("Create", mock.Anything, mock.MatchedBy(func(event *domain.ContainerEvent) bool {
+		return event.Type == domain.EventTypeStatusChanged && event.Status == "restarting"
+	})).Return(nil).Once()
+	mockEventRepo.On("Create", mock.Anything, mock.MatchedBy(func(event *domain.ContainerEvent) bool {
+		return event.Type == domain.EventTypeCrashLoopDetected
+	})).Return(nil).Once()
+	mockEventRepo.On("Find", mock.Anything, mock.MatchedBy(func(filter *dto.ContainerEventFilter) bool {
+		return len(filter.Statuses) == 0
+	})).Return(crashEvents(-3*time.Minute, -2*time.Minute), nil)
+	mockEventRepo.On("Find", mock.Anything, mock.MatchedBy(func(filter *dto.ContainerEventFilter) bool {
+		return len(filter.Statuses) > 0
+	})).Return(crashEvents(-time.Minute), nil)
+	mockRepo.On("Update", mock.Anything, mock.MatchedBy(func(status *domain.ContainerStatus) bool {
+		return status.CrashLooping && status.RestartCount == 3 && status.Status == "restarting"
+	})).Return(nil)
+
+	err := useCase.UpdateContainerStatus(context.Background(), mockContainerID, mockDTO)
+
+	assert.NoError(t, err)
+
+	mockRepo.AssertExpectations(t)
+	mockEventRepo.AssertExpectations(t)
+	mockLogger.AssertExpectations(t)
+	mockPublisher.AssertExpectations(t)
+}
+
+func TestUpdateContainerStatus_CrashCountedOnce(t *testing.T) {
+	mockRepo := new(mocks.ContainerStatusRepository)
+	mockEventRepo := new(mocks.ContainerEventRepository)
+	mockLogger := new(mocks.LoggerInterface)
+	mockPublisher := new(mocks.StatusChangePublisher)
+
+	policy := usecases.CrashLoopPolicy{Window: 5 * time.Minute, Threshold: 2}
+	useCase := usecases.NewContainerStatusUseCase(mockRepo, mockEventRepo, inTransaction(), policy, mockPublisher, mockLogger)
+
+	mockContainerID := testContainerIDStr
+	mockDTO := &dto.ContainerStatusDTO{
+		Status:       "restarting",
+		RestartCount: intPtr(1),
+	}
+	existingStatus := []*domain.ContainerStatus{
+		{
+			ContainerID: mockContainerID,
+			IPAddress:   testContainerIP,
+			Status:      "running",
+		},
+	}
+
+	// The update yields a restart and a transition at the same time.
+	var recorded []*domain.ContainerEvent
+	mockLogger.On("Debugf", mock.Anything, mock.Anything, mock.Anything).Return()
+	mockPublisher.On("Publish", mock.Anything, mock.MatchedBy(func(change *domain.ContainerStatusChange) bool {
+		return change.Type == domain.StatusChangeUpdated
+	})).Return().Once()
+	mockRepo.On("Find", mock.Anything, &dto.ContainerStatusFilter{ContainerID: &mockContainerID}).Return(existingStatus, nil)
+	mockEventRepo.On("Create", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		recorded = append(recorded, args.Get(1).(*domain.ContainerEvent))
+	}).Return(nil).Twice()
+	mockEventRepo.On("Find", mock.Anything, mock.Anything).Return([]*domain.ContainerEvent{}, nil)
+	mockRepo.On("Update", mock.Anything, mock.MatchedBy(func(status *domain.ContainerStatus) bool {
+		return !status.CrashLooping && status.RestartCount == 1
+	})).Return(nil)
+
+	err := useCase.UpdateContainerStatus(context.Background(), mockContainerID, mockDTO)
+
+	assert.NoError(t, err)
+	assert.Len(t, recorded, 2)
+
+	mockRepo.AssertExpectations(t)
+	mockEventRepo.AssertExpectations(t)
+	mockPublisher.AssertExpectations(t)
+}
+
+func TestUpdateContainerStatus_CrashLoopResolved(t *testing.T) {
+	mockRepo := new(mocks.ContainerStatusRepository)
+	mockEventRepo := new(mocks.ContainerEventRepository)
+	mockLogger := new(mocks.LoggerInterface)
+	mockPublisher := new(mocks.StatusChangePublisher)
+
+	policy := usecases.CrashLoopPolicy{Window: 5 * time.Minute, Threshold: 3}
+	useCase := usecases.NewContainerStatusUseCase(mockRepo, mockEventRepo, inTransaction(), policy, mockPublisher, mockLogger)
+
+	mockContainerID := testContainerIDStr
+	mockDTO := &dto.ContainerStatusDTO{PingTime: testPingTimeUpdated}
+	existingStatus := []*domain.ContainerStatus{
+		{
+			ContainerID:  mockContainerID,
+			IPAddress:    testContainerIP,
+			Status:       "running",
+			RestartCount: 5,
+			CrashLooping: true,
+		},
+	}
+
+	mockLogger.On("Debugf", mock.Anything, mock.Anything, mock.Anything).Return()
+	mockPublisher.On("Publish", mock.Anything, mock.Anything).Return()
+	mockLogger.On("Infof", mock.Anything, mock.Anything).Return()
+	mockRepo.On("Find", mock.Anything, &dto.ContainerStatusFilter{ContainerID: &mockContainerID}).Return(existingStatus, nil)
+	mockEventRepo.On("Find", mock.Anything, mock.Anything).Return([]*domain.ContainerEvent{}, nil)
+	mockEventRepo.On("Create", mock.Anything, mock.MatchedBy(func(event *domain.ContainerEvent) bool {
+		return event.Type == domain.EventTypeCrashLoopResolved
+	})).Return(nil).Once()
+	mockRepo.On("Update", mock.Anything, mock.MatchedBy(func(status *domain.ContainerStatus) bool {
+		return !status.CrashLooping
+	})).Return(nil)
+
+	err := useCase.UpdateContainerStatus(context.Background(), mockContainerID, mockDTO)
+
+	assert.NoError(t, err)
+
+	mockRepo.AssertExpectations(t)
+	mockEventRepo.AssertExpectations(t)
+	mockLogger.AssertExpectations(t)
+}
+
+func TestUpdateContainerStatus_UsesCheckedAt(t *testing.T) {
+	mockRepo := new(mocks.ContainerStatusRepository)
+	mockEventRepo := new(mocks.ContainerEventRepository)
+	mockLogger := new(mocks.LoggerInterface)
+	mockPublisher := new(mocks.StatusChangePublisher)
+
+	useCase := usecases.NewContainerStatusUseCase(mockRepo, mockEventRepo, inTransaction(), usecases.CrashLoopPolicy{}, mockPublisher, mockLogger)
+
+	mockContainerID := testContainerIDStr
+	checkedAt := time.Now().Add(-time.Minute)
+	mockDTO := &dto.ContainerStatusDTO{
+		Status:    "exited",
+		CheckedAt: checkedAt,
+	}
+	existingStatus := []*domain.ContainerStatus{
+		{
+			ContainerID: mockContainerID,
+			IPAddress:   testContainerIP,
+			Status:      "running",
+			UpdatedAt:   checkedAt.Add(-time.Minute),
+		},
+	}
+
+	mockLogger.On("Debugf", mock.Anything, mock.Anything, mock.Anything).Return()
+	mockRepo.On("Find", mock.Anything, &dto.ContainerStatusFilter{ContainerID: &mockContainerID}).Return(existingStatus, nil)
+	mockEventRepo.On("Create", mock.Anything, mock.MatchedBy(func(event *domain.ContainerEvent) bool {
+		return event.Type == domain.EventTypeStatusChanged && event.CreatedAt.Equal(checkedAt)
+	})).Return(nil).Once()
+	mockRepo.On("Update", mock.Anything, mock.MatchedBy(func(status *domain.ContainerStatus) bool {
+		return status.UpdatedAt.Equal(checkedAt)
+	})).Return(nil).Once()
+	mockPublisher.On("Publish", mock.Anything, mock.MatchedBy(func(change *domain.ContainerStatusChange) bool {
+		return change.Type == domain.StatusChangeUpdated && change.OccurredAt.Equal(checkedAt)
+	})).Return().Once()
+
+	err := useCase.UpdateContainerStatus(context.Background(), mockContainerID, mockDTO)
+
+	assert.NoError(t, err)
+
+	mockRepo.AssertExpectations(t)
+	mockEventRepo.AssertExpectations(t)
+	mockPublisher.AssertExpectations(t)
+}
+
+func TestUpdateContainerStatus_Stale(t *testing.T) {
+	mockRepo := new(mocks.ContainerStatusRepository)
+	mockEventRepo := new(mocks.ContainerEventRepository)
+	mockLogger := new(mocks.LoggerInterface)
+	mockPublisher := new(mocks.StatusChangePublisher)
+
+	useCase := usecases.NewContainerStatusUseCase(mockRepo, mockEventRepo, inTransaction(), usecases.CrashLoopPolicy{}, mockPublisher, mockLogger)
+
+	mockContainerID := testContainerIDStr
+	storedAt := time.Now().Add(-time.Minute)
+	mockDTO := &dto.ContainerStatusDTO{
+		Status:       "exited",
+		RestartCount: intPtr(3),
+		CheckedAt:    storedAt.Add(-time.Second),
+	}
+	existingStatus := []*domain.ContainerStatus{
+		{
+			ContainerID: mockContainerID,
+			IPAddress:   testContainerIP,
+			Status:      "running",
+			UpdatedAt:   storedAt,
+		},
+	}
+
+	mockLogger.On("Debugf", mock.Anything, mock.Anything, mock.Anything).Return()
+	mockLogger.On("Warnf", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return()
+	mockRepo.On("Find", mock.Anything, &dto.ContainerStatusFilter{ContainerID: &mockContainerID}).Return(existingStatus, nil)
+
+	err := useCase.UpdateContainerStatus(context.Background(), mockContainerID, mockDTO)
+
+	assert.ErrorIs(t, err, usecases.ErrStaleContainerStatus)
+
+	mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	mockEventRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	mockPublisher.AssertNotCalled(t, "Publish", mock.Anything, mock.Anything)
+}
+
+func TestUpdateContainerStatus_ResetsRestartCount(t *testing.T) {
+	mockRepo := new(mocks.ContainerStatusRepository)
+	mockEventRepo := new(mocks.ContainerEventRepository)
+	mockLogger := new(mocks.LoggerInterface)
+	mockPublisher := new(mocks.StatusChangePublisher)
+
+	useCase := usecases.NewContainerStatusUseCase(mockRepo, mockEventRepo, inTransaction(), usecases.CrashLoopPolicy{}, mockPublisher, mockLogger)
+
+	mockContainerID := testContainerIDStr
+	mockDTO := &dto.ContainerStatusDTO{RestartCount: intPtr(0)}
+	existingStatus := []*domain.ContainerStatus{
+		{
+			ContainerID:  mockContainerID,
+			IPAddress:    testContainerIP,
+			Status:       "running",
+			RestartCount: 4,
+		},
+	}
+
+	mockLogger.On("Debugf", mock.Anything, mock.Anything, mock.Anything).Return()
+	mockRepo.On("Find", mock.Anything, &dto.ContainerStatusFilter{ContainerID: &mockContainerID}).Return(existingStatus, nil)
+	mockRepo.On("Update", mock.Anything, mock.MatchedBy(func(status *domain.ContainerStatus) bool {
+		return status.RestartCount == 0
+	})).Return(nil).Once()
+	mockPublisher.On("Publish", mock.Anything, mock.Anything).Return().Once()
+
+	err := useCase.UpdateContainerStatus(context.Background(), mockContainerID, mockDTO)
+
+	assert.NoError(t, err)
+
+	mockRepo.AssertExpectations(t)
+	mockEventRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	mockPublisher.AssertExpectations(t)
+}
+
+func TestUpdateContainerStatus_UpdateFailed_RecordsNothing(t *testing.T) {
+	mockRepo := new(mocks.ContainerStatusRepository)
+	mockEventRepo := new(mocks.ContainerEventRepository)
+	mockLogger := new(mocks.LoggerInterface)
+	mockPublisher := new(mocks.StatusChangePublisher)
+
+	policy := usecases.CrashLoopPolicy{Window: 5 * time.Minute, Threshold: 1}
+	useCase := usecases.NewContainerStatusUseCase(mockRepo, mockEventRepo, inTransaction(), policy, mockPublisher, mockLogger)
+
+	mockContainerID := testContainerIDStr
+	mockDTO := &dto.ContainerStatusDTO{
+		Status:       "restarting",
+		RestartCount: intPtr(1),
+	}
+	existingStatus := []*domain.ContainerStatus{
+		{
+			ContainerID: mockContainerID,
+			IPAddress:   testContainerIP,
+			Status:      "running",
+		},
+	}
+
+	mockLogger.On("Debugf", mock.Anything, mock.Anything, mock.Anything).Return()
+	mockLogger.On("Errorf", mock.Anything, mock.Anything, mock.Anything).Return()
+	mockRepo.On("Find", mock.Anything, &dto.ContainerStatusFilter{ContainerID: &mockContainerID}).Return(existingStatus, nil)
+	mockEventRepo.On("Find", mock.Anything, mock.Anything).Return([]*domain.ContainerEvent{}, nil)
+	mockRepo.On("Update", mock.Anything, mock.Anything).Return(errors.New("database unavailable")).Once()
+
+	err := useCase.UpdateContainerStatus(context.Background(), mockContainerID, mockDTO)
+
+	assert.Error(t, err)
+
+	mockRepo.AssertExpectations(t)
+	mockEventRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	mockPublisher.AssertNotCalled(t, "Publish", mock.Anything, mock.Anything)
+}
+
+func TestUpdateContainerStatus_EventFailed(t *testing.T) {
+	mockRepo := new(mocks.ContainerStatusRepository)
+	mockEventRepo := new(mocks.ContainerEventRepository)
+	mockLogger := new(mocks.LoggerInterface)
+	mockPublisher := new(mocks.StatusChangePublisher)
+
+	useCase := usecases.NewContainerStatusUseCase(mockRepo, mockEventRepo, inTransaction(), usecases.CrashLoopPolicy{}, mockPublisher, mockLogger)
+
+	mockContainerID := testContainerIDStr
+	mockDTO := &dto.ContainerStatusDTO{Status: "exited"}
+	existingStatus := []*domain.ContainerStatus{
+		{
+			ContainerID: mockContainerID,
+			IPAddress:   testContainerIP,
+			Status:      "running",
+		},
+	}
+
+	// The status is written in the same transaction as its events, it is
+	// rolled back with them.
+	mockLogger.On("Debugf", mock.Anything, mock.Anything, mock.Anything).Return()
+	mockLogger.On("Errorf", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return()
+	mockLogger.On("Errorf", mock.Anything, mock.Anything, mock.Anything).Return()
+	mockRepo.On("Find", mock.Anything, &dto.ContainerStatusFilter{ContainerID: &mockContainerID}).Return(existingStatus, nil)
+	mockRepo.On("Update", mock.Anything, mock.Anything).Return(nil).Once()
+	mockEventRepo.On("Create", mock.Anything, mock.Anything).Return(errors.New("connection refused")).Once()
+
+	err := useCase.UpdateContainerStatus(context.Background(), mockContainerID, mockDTO)
+
+	assert.Error(t, err)
+
+	mockRepo.AssertExpectations(t)
+	mockEventRepo.AssertExpectations(t)
+	mockPublisher.AssertNotCalled(t, "Publish", mock.Anything, mock.Anything)
+}
+
+// inTransaction returns a Transactor that runs the transaction functions
+// directly.
+func inTransaction() *mocks.Transactor {
+	transactor := new(mocks.Transactor)
+	transactor.On("WithinTransaction", mock.Anything, mock.Anything).
+		Return(func(ctx context.Context, fn func(context.Context) error) error { return fn(ctx) })
+
+	return transactor
+}
+
+// crashEvents returns events created at the given offsets from now.
+func crashEvents(offsets ...time.Duration) []*domain.ContainerEvent {
+	events := make([]*domain.ContainerEvent, 0, len(offsets))
+	for _, offset := range offsets {
+		events = append(events, &domain.ContainerEvent{ContainerID: testContainerIDStr, CreatedAt: time.Now().Add(offset)})
+	}
+
+	return events
+}
+
+func intPtr(v int) *int {
+	return &v
+}
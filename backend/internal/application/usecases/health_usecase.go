@@ -0,0 +1,100 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/k6zma/DockerMonitoringApp/backend/internal/application/dto"
+	"github.com/k6zma/DockerMonitoringApp/backend/internal/application/repositories"
+	"github.com/k6zma/DockerMonitoringApp/backend/pkg/utils"
+)
+
+const (
+	HealthCheckDatabase   = "database"
+	HealthCheckMigrations = "migrations"
+)
+
+type HealthUseCaseInterface interface {
+	CheckReadiness(ctx context.Context) *dto.HealthReportDTO
+}
+
+type HealthUseCase struct {
+	repo repositories.HealthRepository
+	// expectedMigrationVersion is the newest migration shipped with the service.
+	// Zero disables the version check, e.g. after migrations were rolled back on
+	// purpose.
+	expectedMigrationVersion uint
+	logger                   utils.LoggerInterface
+}
+
+func NewHealthUseCase(
+	repo repositories.HealthRepository,
+	expectedMigrationVersion uint,
+	logger utils.LoggerInterface,
+) *HealthUseCase {
+	return &HealthUseCase{
+		repo:                     repo,
+		expectedMigrationVersion: expectedMigrationVersion,
+		logger:                   logger,
+	}
+}
+
+func (uc *HealthUseCase) CheckReadiness(ctx context.Context) *dto.HealthReportDTO {
+	ctx, span := tracer.Start(ctx, "HealthUseCase.CheckReadiness")
+	defer span.End()
+
+	checks := []dto.HealthCheckDTO{uc.checkDatabase(ctx)}
+	if checks[0].Healthy {
+		checks = append(checks, uc.checkMigrations(ctx))
+	} else {
+		checks = append(checks, dto.HealthCheckDTO{
+			Name:    HealthCheckMigrations,
+			Message: "skipped: database is unavailable",
+		})
+	}
+
+	report := &dto.HealthReportDTO{Healthy: true, Checks: checks}
+	for _, check := range checks {
+		if !check.Healthy {
+			report.Healthy = false
+			uc.logger.Warnf("USECASES: readiness check %s failed: %s", check.Name, check.Message)
+		}
+	}
+
+	return report
+}
+
+func (uc *HealthUseCase) checkDatabase(ctx context.Context) dto.HealthCheckDTO {
+	if err := uc.repo.Ping(ctx); err != nil {
+		return dto.HealthCheckDTO{Name: HealthCheckDatabase, Message: err.Error()}
+	}
+
+	return dto.HealthCheckDTO{Name: HealthCheckDatabase, Healthy: true, Message: "ok"}
+}
+
+func (uc *HealthUseCase) checkMigrations(ctx context.Context) dto.HealthCheckDTO {
+	version, dirty, err := uc.repo.MigrationState(ctx)
+	if err != nil {
+		return dto.HealthCheckDTO{Name: HealthCheckMigrations, Message: err.Error()}
+	}
+
+	if dirty {
+		return dto.HealthCheckDTO{
+			Name:    HealthCheckMigrations,
+			Message: fmt.Sprintf("migration %d is dirty", version),
+		}
+	}
+
+	if version < uc.expectedMigrationVersion {
+		return dto.HealthCheckDTO{
+			Name:    HealthCheckMigrations,
+			Message: fmt.Sprintf("schema version %d is behind expected version %d", version, uc.expectedMigrationVersion),
+		}
+	}
+
+	return dto.HealthCheckDTO{
+		Name:    HealthCheckMigrations,
+		Healthy: true,
+		Message: fmt.Sprintf("version %d", version),
+	}
+}
@@ -0,0 +1,87 @@
+package usecases_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/k6zma/DockerMonitoringApp/backend/internal/application/usecases"
+	"github.com/k6zma/DockerMonitoringApp/backend/mocks"
+)
+
+const testExpectedMigrationVersion = 3
+
+func TestCheckReadiness_Healthy(t *testing.T) {
+	mockRepo := new(mocks.HealthRepository)
+	mockLogger := new(mocks.LoggerInterface)
+
+	useCase := usecases.NewHealthUseCase(mockRepo, testExpectedMigrationVersion, mockLogger)
+
+	mockRepo.On("Ping", mock.Anything).Return(nil)
+	mockRepo.On("MigrationState", mock.Anything).Return(uint(testExpectedMigrationVersion), false, nil)
+
+	report := useCase.CheckReadiness(context.Background())
+
+	assert.True(t, report.Healthy)
+	assert.Len(t, report.Checks, 2)
+	assert.True(t, report.Checks[0].Healthy)
+	assert.True(t, report.Checks[1].Healthy)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestCheckReadiness_DatabaseUnavailable(t *testing.T) {
+	mockRepo := new(mocks.HealthRepository)
+	mockLogger := new(mocks.LoggerInterface)
+
+	useCase := usecases.NewHealthUseCase(mockRepo, testExpectedMigrationVersion, mockLogger)
+
+	mockRepo.On("Ping", mock.Anything).Return(errors.New("connection refused"))
+	mockLogger.On("Warnf", mock.Anything, mock.Anything, mock.Anything).Return()
+
+	report := useCase.CheckReadiness(context.Background())
+
+	assert.False(t, report.Healthy)
+	assert.Equal(t, usecases.HealthCheckDatabase, report.Checks[0].Name)
+	assert.False(t, report.Checks[0].Healthy)
+	assert.Equal(t, "connection refused", report.Checks[0].Message)
+	mockRepo.AssertNotCalled(t, "MigrationState", mock.Anything)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestCheckReadiness_DirtyMigration(t *testing.T) {
+	mockRepo := new(mocks.HealthRepository)
+	mockLogger := new(mocks.LoggerInterface)
+
+	useCase := usecases.NewHealthUseCase(mockRepo, testExpectedMigrationVersion, mockLogger)
+
+	mockRepo.On("Ping", mock.Anything).Return(nil)
+	mockRepo.On("MigrationState", mock.Anything).Return(uint(testExpectedMigrationVersion), true, nil)
+	mockLogger.On("Warnf", mock.Anything, mock.Anything, mock.Anything).Return()
+
+	report := useCase.CheckReadiness(context.Background())
+
+	assert.False(t, report.Healthy)
+	assert.Equal(t, usecases.HealthCheckMigrations, report.Checks[1].Name)
+	assert.Equal(t, "migration 3 is dirty", report.Checks[1].Message)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestCheckReadiness_MigrationBehind(t *testing.T) {
+	mockRepo := new(mocks.HealthRepository)
+	mockLogger := new(mocks.LoggerInterface)
+
+	useCase := usecases.NewHealthUseCase(mockRepo, testExpectedMigrationVersion, mockLogger)
+
+	mockRepo.On("Ping", mock.Anything).Return(nil)
+	mockRepo.On("MigrationState", mock.Anything).Return(uint(2), false, nil)
+	mockLogger.On("Warnf", mock.Anything, mock.Anything, mock.Anything).Return()
+
+	report := useCase.CheckReadiness(context.Background())
+
+	assert.False(t, report.Healthy)
+	assert.Equal(t, "schema version 2 is behind expected version 3", report.Checks[1].Message)
+	mockRepo.AssertExpectations(t)
+}
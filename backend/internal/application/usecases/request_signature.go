@@ -0,0 +1,80 @@
+package usecases
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/k6zma/DockerMonitoringApp/backend/internal/application/dto"
+)
+
+// maxNonceLength bounds the length of a remembered nonce.
+const maxNonceLength = 128
+
+// signingSecretLabel is the message the signing secret of an API key is derived
+// from. Clients derive the same secret from the key they hold.
+const signingSecretLabel = "DockerMonitoringApp request signing"
+
+// signaturePayload returns the string a request signature is computed over: the
+// method, the path with the query, the Unix timestamp, the nonce and the hex
+// encoded SHA-256 digest of the body, each on its own line.
+func signaturePayload(signature *dto.RequestSignatureDTO) string {
+	return strings.Join([]string{
+		signature.Method,
+		signature.Path,
+		strconv.FormatInt(signature.Timestamp, 10),
+		signature.Nonce,
+		signature.BodyHash,
+	}, "\n")
+}
+
+// deriveSigningSecret returns the secret requests are signed with: an
+// HMAC-SHA256 of signingSecretLabel keyed with the API key. Unlike the SHA-256
+// digest the key is looked up by, it cannot be computed from what is stored.
+func deriveSigningSecret(rawKey string) []byte {
+	mac := hmac.New(sha256.New, []byte(rawKey))
+	mac.Write([]byte(signingSecretLabel))
+
+	return mac.Sum(nil)
+}
+
+// newSigningSecretCipher returns the AES-256-GCM cipher the signing secrets of
+// stored keys are encrypted with, keyed with the SHA-256 digest of secretKey.
+func newSigningSecretCipher(secretKey string) cipher.AEAD {
+	key := sha256.Sum256([]byte(secretKey))
+
+	// Neither can fail with a 32-byte key and the standard nonce size.
+	block, _ := aes.NewCipher(key[:])
+	aead, _ := cipher.NewGCM(block)
+
+	return aead
+}
+
+// sealSigningSecret encrypts secret and prepends the random nonce. The key
+// prefix is authenticated with it, so a secret copied to another row does not
+// decrypt.
+func sealSigningSecret(aead cipher.AEAD, secret []byte, prefix string) ([]byte, error) {
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(secret)+aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	return aead.Seal(nonce, nonce, secret, []byte(prefix)), nil
+}
+
+// openSigningSecret decrypts a secret sealed by sealSigningSecret.
+func openSigningSecret(aead cipher.AEAD, sealed []byte, prefix string) ([]byte, error) {
+	if len(sealed) < aead.NonceSize() {
+		return nil, errors.New("sealed secret is too short")
+	}
+
+	nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+
+	return aead.Open(nil, nonce, ciphertext, []byte(prefix))
+}
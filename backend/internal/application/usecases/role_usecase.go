@@ -0,0 +1,150 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/k6zma/DockerMonitoringApp/backend/internal/application/dto"
+	"github.com/k6zma/DockerMonitoringApp/backend/internal/application/repositories"
+	"github.com/k6zma/DockerMonitoringApp/backend/internal/domain"
+	"github.com/k6zma/DockerMonitoringApp/backend/pkg/utils"
+)
+
+var (
+	// ErrUnknownRole is returned when assigning a role that is not defined.
+	ErrUnknownRole = errors.New("unknown role")
+	// ErrRoleAssignmentNotFound is returned when the subject has no role assigned.
+	ErrRoleAssignmentNotFound = errors.New("role assignment not found")
+)
+
+type RoleUseCaseInterface interface {
+	// ResolveRoles adds the role assigned through the API to a user
+	// authenticated by a bearer token. API keys are left untouched.
+	ResolveRoles(ctx context.Context, principal *domain.Principal) error
+	ListRoleAssignments(ctx context.Context) ([]*dto.RoleAssignmentDTO, error)
+	AssignRole(ctx context.Context, subject, role string) (*dto.RoleAssignmentDTO, error)
+	RemoveRoleAssignment(ctx context.Context, subject string) error
+}
+
+type RoleUseCase struct {
+	repo   repositories.RoleAssignmentRepository
+	logger utils.LoggerInterface
+}
+
+func NewRoleUseCase(
+	repo repositories.RoleAssignmentRepository,
+	logger utils.LoggerInterface,
+) *RoleUseCase {
+	return &RoleUseCase{
+		repo:   repo,
+		logger: logger,
+	}
+}
+
+func (uc *RoleUseCase) ResolveRoles(ctx context.Context, principal *domain.Principal) (err error) {
+	if principal.Method != domain.AuthMethodJWT {
+		return nil
+	}
+
+	ctx, span := tracer.Start(ctx, "RoleUseCase.ResolveRoles")
+	defer func() { endSpan(span, err) }()
+
+	assignment, err := uc.repo.FindBySubject(ctx, principal.Name)
+	if err != nil {
+		utils.ContextLogger(ctx, uc.logger).Errorf("USECASES: failed to fetch role of %s: %v", principal.Name, err)
+		return fmt.Errorf("failed to fetch role assignment: %w", err)
+	}
+
+	if assignment != nil {
+		principal.AddRole(assignment.Role)
+	}
+
+	return nil
+}
+
+func (uc *RoleUseCase) ListRoleAssignments(ctx context.Context) (_ []*dto.RoleAssignmentDTO, err error) {
+	ctx, span := tracer.Start(ctx, "RoleUseCase.ListRoleAssignments")
+	defer func() { endSpan(span, err) }()
+
+	logger := utils.ContextLogger(ctx, uc.logger)
+
+	assignments, err := uc.repo.FindAll(ctx)
+	if err != nil {
+		logger.Errorf("USECASES: failed to list role assignments: %v", err)
+		return nil, fmt.Errorf("failed to list role assignments: %w", err)
+	}
+
+	dtos := make([]*dto.RoleAssignmentDTO, 0, len(assignments))
+	for _, assignment := range assignments {
+		dtos = append(dtos, mapRoleAssignmentDomainToDTO(assignment))
+	}
+
+	logger.Debugf("USECASES: found %d role assignments", len(dtos))
+
+	return dtos, nil
+}
+
+func (uc *RoleUseCase) AssignRole(
+	ctx context.Context,
+	subject, role string,
+) (_ *dto.RoleAssignmentDTO, err error) {
+	ctx, span := tracer.Start(ctx, "RoleUseCase.AssignRole",
+		trace.WithAttributes(attribute.String("role.subject", subject), attribute.String("role.name", role)))
+	defer func() { endSpan(span, err) }()
+
+	logger := utils.ContextLogger(ctx, uc.logger, "subject", subject)
+
+	if !domain.Role(role).IsValid() {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownRole, role)
+	}
+
+	assignment := &domain.RoleAssignment{
+		Subject:   subject,
+		Role:      domain.Role(role),
+		UpdatedAt: time.Now(),
+	}
+
+	if err := uc.repo.Upsert(ctx, assignment); err != nil {
+		logger.Errorf("USECASES: failed to assign role %s to %s: %v", role, subject, err)
+		return nil, fmt.Errorf("failed to assign role: %w", err)
+	}
+
+	logger.Infof("USECASES: role %s assigned to %s", role, subject)
+
+	return mapRoleAssignmentDomainToDTO(assignment), nil
+}
+
+func (uc *RoleUseCase) RemoveRoleAssignment(ctx context.Context, subject string) (err error) {
+	ctx, span := tracer.Start(ctx, "RoleUseCase.RemoveRoleAssignment",
+		trace.WithAttributes(attribute.String("role.subject", subject)))
+	defer func() { endSpan(span, err) }()
+
+	logger := utils.ContextLogger(ctx, uc.logger, "subject", subject)
+
+	deleted, err := uc.repo.Delete(ctx, subject)
+	if err != nil {
+		logger.Errorf("USECASES: failed to remove role of %s: %v", subject, err)
+		return fmt.Errorf("failed to remove role assignment: %w", err)
+	}
+	if !deleted {
+		return ErrRoleAssignmentNotFound
+	}
+
+	logger.Infof("USECASES: role of %s removed", subject)
+
+	return nil
+}
+
+func mapRoleAssignmentDomainToDTO(assignment *domain.RoleAssignment) *dto.RoleAssignmentDTO {
+	return &dto.RoleAssignmentDTO{
+		Subject:   assignment.Subject,
+		Role:      string(assignment.Role),
+		CreatedAt: assignment.CreatedAt,
+		UpdatedAt: assignment.UpdatedAt,
+	}
+}
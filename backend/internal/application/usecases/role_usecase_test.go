@@ -0,0 +1,105 @@
+package usecases_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/k6zma/DockerMonitoringApp/backend/internal/application/usecases"
+	"github.com/k6zma/DockerMonitoringApp/backend/internal/domain"
+	"github.com/k6zma/DockerMonitoringApp/backend/mocks"
+)
+
+func TestResolveRoles_AddsAssignedRole(t *testing.T) {
+	mockRepo := new(mocks.RoleAssignmentRepository)
+	mockLogger := new(mocks.LoggerInterface)
+
+	useCase := usecases.NewRoleUseCase(mockRepo, mockLogger)
+
+	mockRepo.On("FindBySubject", mock.Anything, "alice").
+		Return(&domain.RoleAssignment{Subject: "alice", Role: domain.RoleOperator}, nil)
+
+	principal := &domain.Principal{Name: "alice", Method: domain.AuthMethodJWT, Roles: []domain.Role{domain.RoleViewer}}
+
+	err := useCase.ResolveRoles(context.Background(), principal)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.Role{domain.RoleViewer, domain.RoleOperator}, principal.Roles)
+	assert.True(t, principal.HasScope(domain.ScopeStatusWrite))
+	assert.False(t, principal.HasScope(domain.ScopeAdmin))
+}
+
+func TestResolveRoles_IgnoresAPIKeys(t *testing.T) {
+	mockRepo := new(mocks.RoleAssignmentRepository)
+	mockLogger := new(mocks.LoggerInterface)
+
+	useCase := usecases.NewRoleUseCase(mockRepo, mockLogger)
+
+	principal := &domain.Principal{Name: "alice", Method: domain.AuthMethodAPIKey}
+
+	err := useCase.ResolveRoles(context.Background(), principal)
+
+	assert.NoError(t, err)
+	assert.Empty(t, principal.Roles)
+	mockRepo.AssertNotCalled(t, "FindBySubject", mock.Anything, mock.Anything)
+}
+
+func TestResolveRoles_LookupError(t *testing.T) {
+	mockRepo := new(mocks.RoleAssignmentRepository)
+	mockLogger := new(mocks.LoggerInterface)
+
+	useCase := usecases.NewRoleUseCase(mockRepo, mockLogger)
+
+	mockRepo.On("FindBySubject", mock.Anything, "alice").Return(nil, errors.New("connection refused"))
+	mockLogger.On("Errorf", mock.Anything, mock.Anything, mock.Anything).Return()
+
+	err := useCase.ResolveRoles(context.Background(), &domain.Principal{Name: "alice", Method: domain.AuthMethodJWT})
+
+	assert.Error(t, err)
+}
+
+func TestAssignRole(t *testing.T) {
+	mockRepo := new(mocks.RoleAssignmentRepository)
+	mockLogger := new(mocks.LoggerInterface)
+
+	useCase := usecases.NewRoleUseCase(mockRepo, mockLogger)
+
+	mockRepo.On("Upsert", mock.Anything, mock.MatchedBy(func(assignment *domain.RoleAssignment) bool {
+		return assignment.Subject == "alice" && assignment.Role == domain.RoleAdmin
+	})).Return(nil)
+	mockLogger.On("Infof", mock.Anything, mock.Anything, mock.Anything).Return()
+
+	assignment, err := useCase.AssignRole(context.Background(), "alice", "admin")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "admin", assignment.Role)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestAssignRole_UnknownRole(t *testing.T) {
+	mockRepo := new(mocks.RoleAssignmentRepository)
+	mockLogger := new(mocks.LoggerInterface)
+
+	useCase := usecases.NewRoleUseCase(mockRepo, mockLogger)
+
+	_, err := useCase.AssignRole(context.Background(), "alice", "superuser")
+
+	assert.ErrorIs(t, err, usecases.ErrUnknownRole)
+	mockRepo.AssertNotCalled(t, "Upsert", mock.Anything, mock.Anything)
+}
+
+func TestRemoveRoleAssignment_NotFound(t *testing.T) {
+	mockRepo := new(mocks.RoleAssignmentRepository)
+	mockLogger := new(mocks.LoggerInterface)
+
+	useCase := usecases.NewRoleUseCase(mockRepo, mockLogger)
+
+	mockRepo.On("Delete", mock.Anything, "alice").Return(false, nil)
+
+	err := useCase.RemoveRoleAssignment(context.Background(), "alice")
+
+	assert.ErrorIs(t, err, usecases.ErrRoleAssignmentNotFound)
+}
@@ -0,0 +1,321 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/k6zma/DockerMonitoringApp/backend/internal/application/dto"
+	"github.com/k6zma/DockerMonitoringApp/backend/internal/domain"
+	"github.com/k6zma/DockerMonitoringApp/backend/pkg/utils"
+)
+
+var (
+	ErrTooManySubscribers = errors.New("too many subscribers")
+	ErrSubscriberTooSlow  = errors.New("subscriber does not keep up with the changes")
+	ErrStatusHubClosed    = errors.New("status hub is closed")
+	ErrChangesMissed      = errors.New("changes were missed")
+)
+
+// StatusChangePublisher is told about every container status written by
+// ContainerStatusUseCase, within the transaction of the write.
+type StatusChangePublisher interface {
+	Publish(ctx context.Context, change *domain.ContainerStatusChange)
+}
+
+// StatusSubscriber hands out subscriptions to the container status changes.
+// A subscription with a lastEventID first receives the kept changes published
+// after that change.
+type StatusSubscriber interface {
+	Subscribe(ctx context.Context, filter *dto.StatusChangeFilter, lastEventID string) (*StatusSubscription, error)
+	Unsubscribe(subscription *StatusSubscription)
+}
+
+// StatusSubscription receives the changes matching its filter until it is
+// unsubscribed or dropped by the hub.
+type StatusSubscription struct {
+	changes     chan *dto.ContainerStatusChangeDTO
+	filter      atomic.Pointer[dto.StatusChangeFilter]
+	lastEventID string
+	historyLost bool
+	err         error
+}
+
+// Changes returns the channel the matching changes are delivered on. It is
+// closed when the subscription ends.
+func (s *StatusSubscription) Changes() <-chan *dto.ContainerStatusChangeDTO {
+	return s.changes
+}
+
+// Err tells why the subscription ended once Changes is closed: nil after
+// Unsubscribe, ErrSubscriberTooSlow, ErrChangesMissed or ErrStatusHubClosed
+// otherwise.
+func (s *StatusSubscription) Err() error {
+	return s.err
+}
+
+// SetFilter replaces the filter for the changes published from now on.
+func (s *StatusSubscription) SetFilter(filter *dto.StatusChangeFilter) {
+	s.filter.Store(filter)
+}
+
+// LastEventID returns the ID of the last change published before the
+// subscription started, which a stream resumes from when no change was
+// delivered yet.
+func (s *StatusSubscription) LastEventID() string {
+	return s.lastEventID
+}
+
+// HistoryLost reports whether changes published after the requested last
+// event ID are no longer kept, or the ID is unknown. The subscriber missed
+// changes and has to reload the statuses.
+func (s *StatusSubscription) HistoryLost() bool {
+	return s.historyLost
+}
+
+// StatusHub fans the container status changes out to the subscribers in this
+// process. Publishing never blocks: a subscriber with bufferSize changes
+// waiting is dropped, so one stalled client cannot hold up the writes.
+//
+// The last historySize changes are kept for subscribers resuming a stream.
+// Change IDs start with the time the hub was created, so IDs handed out before
+// a restart are recognized as unknown.
+type StatusHub struct {
+	mu             sync.Mutex
+	subscriptions  map[*StatusSubscription]struct{}
+	closed         bool
+	maxSubscribers int
+	bufferSize     int
+	epoch          string
+	sequence       uint64
+	history        []statusHistoryEntry
+	historyStart   int
+	historySize    int
+	logger         utils.LoggerInterface
+}
+
+// statusHistoryEntry is a kept change. history is a ring buffer whose oldest
+// entry is at historyStart.
+type statusHistoryEntry struct {
+	sequence uint64
+	change   *domain.ContainerStatusChange
+	message  *dto.ContainerStatusChangeDTO
+}
+
+var (
+	_ StatusChangePublisher = (*StatusHub)(nil)
+	_ StatusSubscriber      = (*StatusHub)(nil)
+)
+
+func NewStatusHub(maxSubscribers, bufferSize, historySize int, logger utils.LoggerInterface) *StatusHub {
+	return &StatusHub{
+		subscriptions:  make(map[*StatusSubscription]struct{}),
+		maxSubscribers: maxSubscribers,
+		bufferSize:     bufferSize,
+		epoch:          strconv.FormatInt(time.Now().UnixMilli(), 36),
+		history:        make([]statusHistoryEntry, 0, historySize),
+		historySize:    historySize,
+		logger:         logger,
+	}
+}
+
+func (h *StatusHub) Subscribe(
+	ctx context.Context,
+	filter *dto.StatusChangeFilter,
+	lastEventID string,
+) (*StatusSubscription, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.closed {
+		return nil, ErrStatusHubClosed
+	}
+	if len(h.subscriptions) >= h.maxSubscribers {
+		utils.ContextLogger(ctx, h.logger).Warnf("USECASES: refusing subscriber, %d subscribers connected", len(h.subscriptions))
+		return nil, ErrTooManySubscribers
+	}
+
+	subscription := &StatusSubscription{lastEventID: h.eventID(h.sequence)}
+	subscription.SetFilter(filter)
+
+	var missed []*dto.ContainerStatusChangeDTO
+	if lastEventID != "" {
+		missed, subscription.historyLost = h.changesAfter(lastEventID, filter)
+	}
+
+	subscription.changes = make(chan *dto.ContainerStatusChangeDTO, h.bufferSize+len(missed))
+	for _, message := range missed {
+		subscription.changes <- message
+	}
+	h.subscriptions[subscription] = struct{}{}
+
+	return subscription, nil
+}
+
+func (h *StatusHub) Unsubscribe(subscription *StatusSubscription) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.remove(subscription, nil)
+}
+
+func (h *StatusHub) Publish(ctx context.Context, change *domain.ContainerStatusChange) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.sequence++
+	message := &dto.ContainerStatusChangeDTO{
+		ID:         h.eventID(h.sequence),
+		Type:       change.Type,
+		Status:     *mapDomainToDTO(&change.Status),
+		OccurredAt: change.OccurredAt,
+	}
+	if change.Event != nil {
+		message.Event = mapEventDomainToDTO(change.Event)
+	}
+
+	h.remember(statusHistoryEntry{sequence: h.sequence, change: change, message: message})
+
+	for subscription := range h.subscriptions {
+		if !changeMatches(subscription.filter.Load(), change) {
+			continue
+		}
+
+		select {
+		case subscription.changes <- message:
+		default:
+			utils.ContextLogger(ctx, h.logger).Warnf("USECASES: dropping subscriber with %d undelivered changes", h.bufferSize)
+			h.remove(subscription, ErrSubscriberTooSlow)
+		}
+	}
+}
+
+// Close ends all subscriptions and refuses new ones.
+func (h *StatusHub) Close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.closed = true
+	for subscription := range h.subscriptions {
+		h.remove(subscription, ErrStatusHubClosed)
+	}
+}
+
+func (h *StatusHub) eventID(sequence uint64) string {
+	return fmt.Sprintf("%s-%d", h.epoch, sequence)
+}
+
+// Reset ends all subscriptions with ErrChangesMissed and forgets the kept
+// changes. It is called when changes may not have reached the hub, so that
+// subscribers reload the statuses instead of resuming.
+func (h *StatusHub) Reset() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	// The missed changes get a sequence number, so resuming from any change
+	// published before them is refused.
+	h.sequence++
+	h.history = h.history[:0]
+	h.historyStart = 0
+
+	for subscription := range h.subscriptions {
+		h.remove(subscription, ErrChangesMissed)
+	}
+}
+
+// remember keeps entry in the history, replacing the oldest entry once
+// historySize entries are kept. It must be called with h.mu held.
+func (h *StatusHub) remember(entry statusHistoryEntry) {
+	if len(h.history) < h.historySize {
+		h.history = append(h.history, entry)
+		return
+	}
+
+	h.history[h.historyStart] = entry
+	h.historyStart = (h.historyStart + 1) % h.historySize
+}
+
+// changesAfter returns the kept changes matching filter that were published
+// after the change with lastEventID, and whether some of them are no longer
+// kept. It must be called with h.mu held.
+func (h *StatusHub) changesAfter(
+	lastEventID string,
+	filter *dto.StatusChangeFilter,
+) ([]*dto.ContainerStatusChangeDTO, bool) {
+	epoch, rawSequence, ok := strings.Cut(lastEventID, "-")
+	if !ok || epoch != h.epoch {
+		return nil, true
+	}
+
+	sequence, err := strconv.ParseUint(rawSequence, 10, 64)
+	if err != nil || sequence > h.sequence {
+		return nil, true
+	}
+
+	oldest := h.sequence + 1
+	if len(h.history) > 0 {
+		oldest = h.history[h.historyStart].sequence
+	}
+	if sequence+1 < oldest {
+		return nil, true
+	}
+
+	var changes []*dto.ContainerStatusChangeDTO
+	for i := range h.history {
+		entry := h.history[(h.historyStart+i)%len(h.history)]
+		if entry.sequence > sequence && changeMatches(filter, entry.change) {
+			changes = append(changes, entry.message)
+		}
+	}
+
+	return changes, false
+}
+
+// remove ends subscription with err. It must be called with h.mu held.
+func (h *StatusHub) remove(subscription *StatusSubscription, err error) {
+	if _, ok := h.subscriptions[subscription]; !ok {
+		return
+	}
+
+	delete(h.subscriptions, subscription)
+	subscription.err = err
+	close(subscription.changes)
+}
+
+// changeMatches reports whether filter selects the container before or after
+// the change, so subscribers also learn when a container leaves their filter.
+// Alerts are only selected by filters asking for them.
+func changeMatches(filter *dto.StatusChangeFilter, change *domain.ContainerStatusChange) bool {
+	if change.Type == domain.StatusChangeAlert && (filter == nil || !filter.Alerts) {
+		return false
+	}
+	if filter == nil {
+		return true
+	}
+
+	return statusMatches(filter, &change.Status) ||
+		(change.Previous != nil && statusMatches(filter, change.Previous))
+}
+
+func statusMatches(filter *dto.StatusChangeFilter, status *domain.ContainerStatus) bool {
+	if len(filter.Names) > 0 && !slices.Contains(filter.Names, status.Name) {
+		return false
+	}
+	if len(filter.Statuses) > 0 && !slices.Contains(filter.Statuses, status.Status) {
+		return false
+	}
+
+	for key, value := range filter.Labels {
+		if label, ok := status.Labels[key]; !ok || label != value {
+			return false
+		}
+	}
+
+	return true
+}
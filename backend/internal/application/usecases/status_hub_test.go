@@ -0,0 +1,277 @@
+package usecases_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/k6zma/DockerMonitoringApp/backend/internal/application/dto"
+	"github.com/k6zma/DockerMonitoringApp/backend/internal/application/usecases"
+	"github.com/k6zma/DockerMonitoringApp/backend/internal/domain"
+	"github.com/k6zma/DockerMonitoringApp/backend/mocks"
+)
+
+func statusChange(changeType, name, status string, labels map[string]string) *domain.ContainerStatusChange {
+	return &domain.ContainerStatusChange{
+		Type: changeType,
+		Status: domain.ContainerStatus{
+			ContainerID: name + "-id",
+			Name:        name,
+			Status:      status,
+			Labels:      labels,
+		},
+		OccurredAt: time.Now(),
+	}
+}
+
+func TestStatusHub_DeliversMatchingChanges(t *testing.T) {
+	hub := usecases.NewStatusHub(10, 10, 10, new(mocks.LoggerInterface))
+
+	tests := []struct {
+		name   string
+		filter *dto.StatusChangeFilter
+		change *domain.ContainerStatusChange
+		match  bool
+	}{
+		{name: "no filter", change: statusChange(domain.StatusChangeCreated, "web", "running", nil), match: true},
+		{
+			name:   "name",
+			filter: &dto.StatusChangeFilter{Names: []string{"web", "db"}},
+			change: statusChange(domain.StatusChangeCreated, "db", "running", nil),
+			match:  true,
+		},
+		{
+			name:   "other name",
+			filter: &dto.StatusChangeFilter{Names: []string{"web"}},
+			change: statusChange(domain.StatusChangeCreated, "db", "running", nil),
+		},
+		{
+			name:   "status",
+			filter: &dto.StatusChangeFilter{Statuses: []string{"exited"}},
+			change: statusChange(domain.StatusChangeDeleted, "web", "exited", nil),
+			match:  true,
+		},
+		{
+			name:   "labels",
+			filter: &dto.StatusChangeFilter{Labels: map[string]string{"team": "payments", "tier": "backend"}},
+			change: statusChange(domain.StatusChangeCreated, "web", "running", map[string]string{"team": "payments", "tier": "backend", "x": "y"}),
+			match:  true,
+		},
+		{
+			name:   "missing label",
+			filter: &dto.StatusChangeFilter{Labels: map[string]string{"team": "payments", "tier": "backend"}},
+			change: statusChange(domain.StatusChangeCreated, "web", "running", map[string]string{"team": "payments"}),
+		},
+		{
+			name:   "previous state matches",
+			filter: &dto.StatusChangeFilter{Statuses: []string{"running"}},
+			change: func() *domain.ContainerStatusChange {
+				change := statusChange(domain.StatusChangeUpdated, "web", "exited", nil)
+				change.Previous = &domain.ContainerStatus{Name: "web", Status: "running"}
+				return change
+			}(),
+			match: true,
+		},
+		{
+			name:   "alert without asking for alerts",
+			change: statusChange(domain.StatusChangeAlert, "web", "restarting", nil),
+		},
+		{
+			name:   "alert",
+			filter: &dto.StatusChangeFilter{Names: []string{"web"}, Alerts: true},
+			change: statusChange(domain.StatusChangeAlert, "web", "restarting", nil),
+			match:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			subscription, err := hub.Subscribe(context.Background(), tt.filter, "")
+			require.NoError(t, err)
+			defer hub.Unsubscribe(subscription)
+
+			hub.Publish(context.Background(), tt.change)
+
+			select {
+			case change := <-subscription.Changes():
+				assert.True(t, tt.match, "unexpected change %+v", change)
+				assert.Equal(t, tt.change.Type, change.Type)
+				assert.Equal(t, tt.change.Status.Name, change.Status.Name)
+			default:
+				assert.False(t, tt.match, "change was not delivered")
+			}
+		})
+	}
+}
+
+func TestStatusHub_SetFilter(t *testing.T) {
+	hub := usecases.NewStatusHub(10, 10, 10, new(mocks.LoggerInterface))
+
+	subscription, err := hub.Subscribe(context.Background(), &dto.StatusChangeFilter{Names: []string{"web"}}, "")
+	require.NoError(t, err)
+
+	subscription.SetFilter(&dto.StatusChangeFilter{Names: []string{"db"}})
+	hub.Publish(context.Background(), statusChange(domain.StatusChangeCreated, "web", "running", nil))
+	hub.Publish(context.Background(), statusChange(domain.StatusChangeCreated, "db", "running", nil))
+
+	change := <-subscription.Changes()
+	assert.Equal(t, "db", change.Status.Name)
+	assert.Empty(t, subscription.Changes())
+}
+
+func TestStatusHub_DropsSlowSubscriber(t *testing.T) {
+	mockLogger := new(mocks.LoggerInterface)
+	mockLogger.On("Warnf", mock.Anything, mock.Anything).Return()
+
+	hub := usecases.NewStatusHub(10, 2, 10, mockLogger)
+
+	slow, err := hub.Subscribe(context.Background(), nil, "")
+	require.NoError(t, err)
+
+	for range 3 {
+		hub.Publish(context.Background(), statusChange(domain.StatusChangeUpdated, "web", "running", nil))
+	}
+
+	var received int
+	for range slow.Changes() {
+		received++
+	}
+	assert.Equal(t, 2, received)
+	assert.ErrorIs(t, slow.Err(), usecases.ErrSubscriberTooSlow)
+}
+
+func TestStatusHub_LimitsSubscribers(t *testing.T) {
+	mockLogger := new(mocks.LoggerInterface)
+	mockLogger.On("Warnf", mock.Anything, mock.Anything).Return()
+
+	hub := usecases.NewStatusHub(1, 10, 10, mockLogger)
+
+	first, err := hub.Subscribe(context.Background(), nil, "")
+	require.NoError(t, err)
+
+	_, err = hub.Subscribe(context.Background(), nil, "")
+	assert.ErrorIs(t, err, usecases.ErrTooManySubscribers)
+
+	hub.Unsubscribe(first)
+	_, ok := <-first.Changes()
+	assert.False(t, ok)
+	assert.NoError(t, first.Err())
+
+	_, err = hub.Subscribe(context.Background(), nil, "")
+	assert.NoError(t, err)
+}
+
+func TestStatusHub_Close(t *testing.T) {
+	hub := usecases.NewStatusHub(10, 10, 10, new(mocks.LoggerInterface))
+
+	subscription, err := hub.Subscribe(context.Background(), nil, "")
+	require.NoError(t, err)
+
+	hub.Close()
+
+	_, ok := <-subscription.Changes()
+	assert.False(t, ok)
+	assert.ErrorIs(t, subscription.Err(), usecases.ErrStatusHubClosed)
+
+	_, err = hub.Subscribe(context.Background(), nil, "")
+	assert.ErrorIs(t, err, usecases.ErrStatusHubClosed)
+}
+
+func TestStatusHub_Reset(t *testing.T) {
+	hub := usecases.NewStatusHub(10, 10, 10, new(mocks.LoggerInterface))
+
+	subscription, err := hub.Subscribe(context.Background(), nil, "")
+	require.NoError(t, err)
+	hub.Publish(context.Background(), statusChange(domain.StatusChangeCreated, "web", "running", nil))
+	lastEventID := (<-subscription.Changes()).ID
+
+	hub.Reset()
+
+	_, ok := <-subscription.Changes()
+	assert.False(t, ok)
+	assert.ErrorIs(t, subscription.Err(), usecases.ErrChangesMissed)
+
+	resumed, err := hub.Subscribe(context.Background(), nil, lastEventID)
+	require.NoError(t, err)
+	assert.True(t, resumed.HistoryLost())
+
+	// Streams started after the reset resume without loss.
+	hub.Publish(context.Background(), statusChange(domain.StatusChangeUpdated, "web", "exited", nil))
+	fresh, err := hub.Subscribe(context.Background(), nil, resumed.LastEventID())
+	require.NoError(t, err)
+	assert.False(t, fresh.HistoryLost())
+	assert.Len(t, fresh.Changes(), 1)
+}
+
+func TestStatusHub_ResumesAfterLastEventID(t *testing.T) {
+	hub := usecases.NewStatusHub(10, 1, 10, new(mocks.LoggerInterface))
+
+	first, err := hub.Subscribe(context.Background(), nil, "")
+	require.NoError(t, err)
+	hub.Publish(context.Background(), statusChange(domain.StatusChangeCreated, "web", "running", nil))
+	lastEventID := (<-first.Changes()).ID
+	hub.Unsubscribe(first)
+
+	hub.Publish(context.Background(), statusChange(domain.StatusChangeCreated, "db", "running", nil))
+	hub.Publish(context.Background(), statusChange(domain.StatusChangeCreated, "cache", "running", nil))
+	hub.Publish(context.Background(), statusChange(domain.StatusChangeUpdated, "db", "exited", nil))
+
+	resumed, err := hub.Subscribe(context.Background(), &dto.StatusChangeFilter{Names: []string{"db"}}, lastEventID)
+	require.NoError(t, err)
+	assert.False(t, resumed.HistoryLost())
+
+	var statuses []string
+	for range 2 {
+		statuses = append(statuses, (<-resumed.Changes()).Status.Status)
+	}
+	assert.Equal(t, []string{"running", "exited"}, statuses)
+
+	hub.Publish(context.Background(), statusChange(domain.StatusChangeDeleted, "db", "exited", nil))
+	change := <-resumed.Changes()
+	assert.Equal(t, domain.StatusChangeDeleted, change.Type)
+	assert.NotEqual(t, lastEventID, change.ID)
+}
+
+func TestStatusHub_HistoryLost(t *testing.T) {
+	hub := usecases.NewStatusHub(10, 10, 2, new(mocks.LoggerInterface))
+
+	first, err := hub.Subscribe(context.Background(), nil, "")
+	require.NoError(t, err)
+	for range 4 {
+		hub.Publish(context.Background(), statusChange(domain.StatusChangeUpdated, "web", "running", nil))
+	}
+
+	var ids []string
+	for range 4 {
+		ids = append(ids, (<-first.Changes()).ID)
+	}
+
+	tests := []struct {
+		name        string
+		lastEventID string
+		lost        bool
+		missed      int
+	}{
+		{name: "kept", lastEventID: ids[1], missed: 2},
+		{name: "latest", lastEventID: ids[3]},
+		{name: "before the first change", lastEventID: first.LastEventID(), lost: true},
+		{name: "discarded", lastEventID: ids[0], lost: true},
+		{name: "before restart", lastEventID: "abc-2", lost: true},
+		{name: "malformed", lastEventID: "latest", lost: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			subscription, err := hub.Subscribe(context.Background(), nil, tt.lastEventID)
+			require.NoError(t, err)
+			defer hub.Unsubscribe(subscription)
+
+			assert.Equal(t, tt.lost, subscription.HistoryLost())
+			assert.Len(t, subscription.Changes(), tt.missed)
+		})
+	}
+}
@@ -0,0 +1,17 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+
+	"github.com/k6zma/DockerMonitoringApp/backend/internal/domain"
+)
+
+// ErrInvalidToken is returned for bearer tokens that are malformed, expired,
+// not signed by a trusted key or issued for another issuer or audience.
+var ErrInvalidToken = errors.New("invalid bearer token")
+
+// TokenVerifier authenticates users by the bearer token they were issued.
+type TokenVerifier interface {
+	Verify(ctx context.Context, token string) (*domain.Principal, error)
+}
@@ -0,0 +1,23 @@
+package domain
+
+import "time"
+
+// AuditEntry records a mutating API call: who made it, from where, what it
+// targeted and how it ended. Before and After hold the JSON encoded state of
+// the changed container status and are nil when there was none, e.g. Before
+// for a created status.
+type AuditEntry struct {
+	ID          int64      `db:"id"`
+	Actor       string     `db:"actor"`
+	AuthMethod  AuthMethod `db:"auth_method"`
+	ClientIP    string     `db:"client_ip"`
+	Method      string     `db:"method"`
+	Route       string     `db:"route"`
+	Path        string     `db:"path"`
+	ContainerID string     `db:"container_id"`
+	StatusCode  int        `db:"status_code"`
+	Before      []byte     `db:"before"`
+	After       []byte     `db:"after"`
+	RequestID   string     `db:"request_id"`
+	CreatedAt   time.Time  `db:"created_at"`
+}
@@ -0,0 +1,141 @@
+package domain
+
+import "time"
+
+type Scope string
+
+const (
+	ScopeStatusRead  Scope = "status:read"
+	ScopeStatusWrite Scope = "status:write"
+	ScopeAdmin       Scope = "admin"
+)
+
+// Role bundles the scopes needed for a kind of work with the API.
+type Role string
+
+const (
+	// RoleViewer may read container statuses and events.
+	RoleViewer Role = "viewer"
+	// RoleOperator may additionally create, update and delete container statuses.
+	RoleOperator Role = "operator"
+	// RoleAdmin may use every route, including key and role management.
+	RoleAdmin Role = "admin"
+)
+
+var roleScopes = map[Role][]Scope{
+	RoleViewer:   {ScopeStatusRead},
+	RoleOperator: {ScopeStatusRead, ScopeStatusWrite},
+	RoleAdmin:    {ScopeAdmin},
+}
+
+// Scopes returns the scopes granted by the role, none for an unknown role.
+func (r Role) Scopes() []Scope {
+	return roleScopes[r]
+}
+
+// IsValid reports whether r is one of the defined roles.
+func (r Role) IsValid() bool {
+	_, ok := roleScopes[r]
+	return ok
+}
+
+// RoleAssignment grants a role to the user with the given subject, the name a
+// user is authenticated under.
+type RoleAssignment struct {
+	Subject   string    `db:"subject"`
+	Role      Role      `db:"role"`
+	CreatedAt time.Time `db:"created_at"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
+// AuthMethod is the way a principal proved its identity.
+type AuthMethod string
+
+const (
+	AuthMethodAPIKey AuthMethod = "api_key"
+	AuthMethodHMAC   AuthMethod = "hmac"
+	AuthMethodJWT    AuthMethod = "jwt"
+)
+
+// Principal is the authenticated caller of an API request. Name is the key name
+// for API keys and the user name taken from the token for JWTs.
+type Principal struct {
+	Name   string
+	Method AuthMethod
+	Scopes []Scope
+	Roles  []Role
+}
+
+// HasScope reports whether the principal was granted scope directly or through
+// one of its roles. The admin scope grants every other scope.
+func (p *Principal) HasScope(scope Scope) bool {
+	for _, granted := range p.Scopes {
+		if granted == scope || granted == ScopeAdmin {
+			return true
+		}
+	}
+
+	for _, role := range p.Roles {
+		for _, granted := range role.Scopes() {
+			if granted == scope || granted == ScopeAdmin {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// AddRole grants role unless the principal already has it.
+func (p *Principal) AddRole(role Role) {
+	for _, existing := range p.Roles {
+		if existing == role {
+			return
+		}
+	}
+
+	p.Roles = append(p.Roles, role)
+}
+
+// APIKeyAuthMode is the way the holder of an API key authenticates requests.
+type APIKeyAuthMode string
+
+const (
+	// APIKeyAuthModeHeader sends the key itself in the X-Api-Key header.
+	APIKeyAuthModeHeader APIKeyAuthMode = "api_key"
+	// APIKeyAuthModeHMAC signs every request with the key, which is never sent.
+	// Such keys are refused in the X-Api-Key header.
+	APIKeyAuthModeHMAC APIKeyAuthMode = "hmac"
+)
+
+// IsValid reports whether m is one of the defined auth modes.
+func (m APIKeyAuthMode) IsValid() bool {
+	return m == APIKeyAuthModeHeader || m == APIKeyAuthModeHMAC
+}
+
+// APIKey is an API key stored in the database. Only the SHA-256 digest of the
+// key is kept; Prefix holds its first characters so that keys can be told apart.
+// Keys in the HMAC auth mode also keep the secret requests are signed with in
+// SigningSecret, encrypted with the server secret key.
+type APIKey struct {
+	ID            int64
+	Name          string
+	Prefix        string
+	Hash          string
+	SigningSecret []byte
+	Scopes        []Scope
+	AuthMode      APIKeyAuthMode
+	CreatedAt     time.Time
+	LastUsedAt    *time.Time
+	ExpiresAt     *time.Time
+	RevokedAt     *time.Time
+}
+
+// IsActive reports whether the key may be used at the given time.
+func (k *APIKey) IsActive(now time.Time) bool {
+	if k.RevokedAt != nil && !k.RevokedAt.After(now) {
+		return false
+	}
+
+	return k.ExpiresAt == nil || k.ExpiresAt.After(now)
+}
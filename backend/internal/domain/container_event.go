@@ -0,0 +1,19 @@
+package domain
+
+import "time"
+
+const (
+	EventTypeStatusChanged     = "status_changed"
+	EventTypeRestarted         = "restarted"
+	EventTypeCrashLoopDetected = "crash_loop_detected"
+	EventTypeCrashLoopResolved = "crash_loop_resolved"
+)
+
+type ContainerEvent struct {
+	ID          int64     `db:"id" json:"id"`
+	ContainerID string    `db:"container_id" json:"container_id"`
+	Type        string    `db:"type" json:"type"`
+	Status      string    `db:"status" json:"status"`
+	Message     string    `db:"message" json:"message"`
+	CreatedAt   time.Time `db:"created_at" json:"created_at"`
+}
@@ -2,13 +2,51 @@ package domain
 
 import "time"
 
+const ConditionCrashLooping = "crash_looping"
+
+const (
+	StatusChangeCreated = "created"
+	StatusChangeUpdated = "updated"
+	StatusChangeDeleted = "deleted"
+	// StatusChangeAlert carries a crash-loop event raised for the container.
+	StatusChangeAlert = "alert"
+)
+
+// ContainerStatus is the last known state of a container. The JSON encoding is
+// used for the snapshots kept in the audit log.
 type ContainerStatus struct {
-	ContainerID        string    `db:"container_id"`
-	Name               string    `db:"name"`
-	IPAddress          string    `db:"ip_address"`
-	Status             string    `db:"status"`
-	PingTime           float64   `db:"ping_time"`
-	LastSuccessfulPing time.Time `db:"last_successful_ping"`
-	UpdatedAt          time.Time `db:"updated_at"`
-	CreatedAt          time.Time `db:"created_at"`
+	ContainerID        string            `db:"container_id" json:"container_id"`
+	Name               string            `db:"name" json:"name"`
+	IPAddress          string            `db:"ip_address" json:"ip_address"`
+	Status             string            `db:"status" json:"status"`
+	PingTime           float64           `db:"ping_time" json:"ping_time"`
+	LastSuccessfulPing time.Time         `db:"last_successful_ping" json:"last_successful_ping"`
+	RestartCount       int               `db:"restart_count" json:"restart_count"`
+	CrashLooping       bool              `db:"crash_looping" json:"crash_looping"`
+	Labels             map[string]string `db:"labels" json:"labels"`
+	UpdatedAt          time.Time         `db:"updated_at" json:"updated_at"`
+	CreatedAt          time.Time         `db:"created_at" json:"created_at"`
+}
+
+// ContainerStatusListVersion identifies the state of a container status list.
+// Count and UpdatedAt describe the listed rows, UpdatedAt being the time of
+// their last write rather than of the last check. ModifiedAt is the time of
+// the last write or deletion of any row.
+type ContainerStatusListVersion struct {
+	Count      int
+	UpdatedAt  time.Time
+	ModifiedAt time.Time
+}
+
+// ContainerStatusChange describes a write to a container status. Status holds
+// the state after the write, or the last known state for a deletion, and
+// Previous the state before an update. Alerts hold the raised Event and the
+// state it was raised for. The JSON encoding is the payload of the change
+// notifications sent between backend instances.
+type ContainerStatusChange struct {
+	Type       string           `json:"type"`
+	Status     ContainerStatus  `json:"status"`
+	Previous   *ContainerStatus `json:"previous,omitempty"`
+	Event      *ContainerEvent  `json:"event,omitempty"`
+	OccurredAt time.Time        `json:"occurred_at"`
 }
@@ -1,7 +1,10 @@
 package config
 
 import (
+	"errors"
 	"fmt"
+	"slices"
+	"time"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/spf13/viper"
@@ -12,10 +15,43 @@ type Config struct {
 	DB               *DBConfig         `mapstructure:"db"         validate:"required"`
 	MigrationsConfig *MigrationsConfig `mapstructure:"migrations" validate:"required"`
 	AuthAPI          *AuthAPIConfig    `mapstructure:"auth_api"   validate:"required"`
+	CrashLoop        *CrashLoopConfig  `mapstructure:"crash_loop" validate:"required"`
+	Metrics          *MetricsConfig    `mapstructure:"metrics"    validate:"required"`
+	Tracing          *TracingConfig    `mapstructure:"tracing"    validate:"required"`
+	RateLimit        *RateLimitConfig  `mapstructure:"rate_limit" validate:"required"`
+	CORS             *CORSConfig       `mapstructure:"cors"       validate:"required"`
+	Stream           *StreamConfig     `mapstructure:"stream"     validate:"required"`
 }
 
+// ServerConfig configures the HTTP server. MaxInFlight caps the API requests
+// handled at the same time; further requests are refused with 503 until one
+// finishes. Zero disables the cap. TrustedProxies lists the addresses or CIDR
+// ranges of the reverse proxies whose X-Forwarded-For header is believed.
 type ServerConfig struct {
-	Port uint16 `mapstructure:"port" validate:"required,gt=0"`
+	Port           uint16             `mapstructure:"port"            validate:"required,gt=0"`
+	MaxInFlight    int                `mapstructure:"max_in_flight"   validate:"gte=0"`
+	TrustedProxies []string           `mapstructure:"trusted_proxies" validate:"dive,cidr|ip"`
+	TLS            *TLSConfig         `mapstructure:"tls"             validate:"required"`
+	Compression    *CompressionConfig `mapstructure:"compression"     validate:"required"`
+}
+
+// CompressionConfig compresses responses of at least MinSize bytes with gzip or
+// zstd and accepts request bodies compressed with either, decoding at most
+// MaxRequestSize bytes of them.
+type CompressionConfig struct {
+	Enabled        bool  `mapstructure:"enabled"`
+	MinSize        int   `mapstructure:"min_size"         validate:"gte=0"`
+	MaxRequestSize int64 `mapstructure:"max_request_size" validate:"required_if=Enabled true,gte=0"`
+}
+
+// TLSConfig serves HTTPS with the certificate in CertFile and KeyFile. With a
+// ClientCAFile, the ingestion routes require a client certificate signed by one
+// of its CAs. The files are reloaded when they change.
+type TLSConfig struct {
+	Enabled      bool   `mapstructure:"enabled"`
+	CertFile     string `mapstructure:"cert_file"      validate:"required_if=Enabled true,omitempty,file"`
+	KeyFile      string `mapstructure:"key_file"       validate:"required_if=Enabled true,omitempty,file"`
+	ClientCAFile string `mapstructure:"client_ca_file" validate:"omitempty,file"`
 }
 
 type DBConfig struct {
@@ -31,13 +67,179 @@ type MigrationsConfig struct {
 	Type string `mapstructure:"type" validate:"required,oneof=apply drop rollback"`
 }
 
+// AuthAPIConfig holds static API keys. They are accepted next to the keys stored
+// in the database and are needed to create the first key through the API.
 type AuthAPIConfig struct {
-	APIKey string `mapstructure:"api_key" validate:"required"`
+	Keys       []APIKeyConfig   `mapstructure:"keys"       validate:"min=1,unique=Name,unique=Key,dive"`
+	JWT        *JWTConfig       `mapstructure:"jwt"        validate:"required"`
+	Signatures *SignatureConfig `mapstructure:"signatures" validate:"required"`
+}
+
+// APIKeyConfig is a named API key limited to the given scopes: status:read,
+// status:write or admin, which grants every scope. AuthMode is api_key (the
+// default) to accept the key in the X-Api-Key header or hmac to accept only
+// requests signed with it.
+type APIKeyConfig struct {
+	Name     string   `mapstructure:"name"      validate:"required"`
+	Key      string   `mapstructure:"key"       validate:"required"`
+	Scopes   []string `mapstructure:"scopes"    validate:"required,min=1,dive,oneof=status:read status:write admin"`
+	AuthMode string   `mapstructure:"auth_mode" validate:"omitempty,oneof=api_key hmac"`
+}
+
+// legacyAPIKeyName names the key taken over from auth_api.api_key, the single
+// key configurations had before keys were scoped. It keeps the access that key
+// had to every route.
+const legacyAPIKeyName = "api_key"
+
+// String keeps the key itself out of the logs.
+func (k APIKeyConfig) String() string {
+	return fmt.Sprintf("{Name:%s Scopes:%v AuthMode:%s}", k.Name, k.Scopes, k.AuthMode)
+}
+
+// SignatureConfig configures requests signed with HMAC keys. A signature is
+// accepted when its timestamp differs from the server time by at most
+// MaxClockSkew. SecretKey encrypts the signing secrets of the keys stored in
+// the database; changing it invalidates them.
+type SignatureConfig struct {
+	MaxClockSkew time.Duration `mapstructure:"max_clock_skew" validate:"required,gt=0"`
+	SecretKey    string        `mapstructure:"secret_key"     validate:"required,min=32"`
+}
+
+// String keeps the secret key out of the logs.
+func (c SignatureConfig) String() string {
+	return fmt.Sprintf("{MaxClockSkew:%s}", c.MaxClockSkew)
+}
+
+// JWTConfig enables Authorization: Bearer tokens for users. Tokens are verified
+// with the keys published at JWKSURL and the StaticKeys, must be signed with one
+// of Algorithms and are mapped to roles through the values of RolesClaim.
+type JWTConfig struct {
+	Enabled             bool             `mapstructure:"enabled"`
+	JWKSURL             string           `mapstructure:"jwks_url"              validate:"omitempty,url"`
+	JWKSRefreshInterval time.Duration    `mapstructure:"jwks_refresh_interval" validate:"gte=0"`
+	StaticKeys          []JWTKeyConfig   `mapstructure:"static_keys"           validate:"unique=KeyID,dive"`
+	Algorithms          []string         `mapstructure:"algorithms"            validate:"required_if=Enabled true,dive,oneof=RS256 RS384 RS512 PS256 PS384 PS512 ES256 ES384 ES512 EdDSA HS256 HS384 HS512"`
+	Issuer              string           `mapstructure:"issuer"                validate:"required_if=Enabled true"`
+	Audience            string           `mapstructure:"audience"              validate:"required_if=Enabled true"`
+	Leeway              time.Duration    `mapstructure:"leeway"                validate:"gte=0"`
+	NameClaim           string           `mapstructure:"name_claim"`
+	RolesClaim          string           `mapstructure:"roles_claim"           validate:"required_if=Enabled true"`
+	RoleMappings        []JWTRoleMapping `mapstructure:"role_mappings"         validate:"unique=Claim,dive"`
+}
+
+// JWTKeyConfig is a verification key with the given key ID: a PEM encoded
+// public key for asymmetric algorithms or a shared secret for HS256/384/512.
+type JWTKeyConfig struct {
+	KeyID         string `mapstructure:"kid"             validate:"required"`
+	PublicKeyFile string `mapstructure:"public_key_file" validate:"required_without=Secret,excluded_with=Secret,omitempty,file"`
+	Secret        string `mapstructure:"secret"          validate:"required_without=PublicKeyFile"`
+}
+
+// String keeps the secret out of the logs.
+func (k JWTKeyConfig) String() string {
+	return fmt.Sprintf("{KeyID:%s PublicKeyFile:%s}", k.KeyID, k.PublicKeyFile)
+}
+
+// JWTRoleMapping grants Role (viewer, operator or admin) to tokens whose roles
+// claim contains Claim.
+type JWTRoleMapping struct {
+	Claim string `mapstructure:"claim" validate:"required"`
+	Role  string `mapstructure:"role"  validate:"required,oneof=viewer operator admin"`
+}
+
+type CrashLoopConfig struct {
+	Window           time.Duration `mapstructure:"window"            validate:"required,gt=0"`
+	RestartThreshold int           `mapstructure:"restart_threshold" validate:"gte=0"`
+}
+
+type MetricsConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+}
+
+type TracingConfig struct {
+	Enabled     bool    `mapstructure:"enabled"`
+	Exporter    string  `mapstructure:"exporter"     validate:"required_if=Enabled true,omitempty,oneof=otlp stdout file"`
+	Endpoint    string  `mapstructure:"endpoint"`
+	Insecure    bool    `mapstructure:"insecure"`
+	FilePath    string  `mapstructure:"file_path"    validate:"required_if=Exporter file"`
+	SampleRatio float64 `mapstructure:"sample_ratio" validate:"gte=0,lte=1"`
+	ServiceName string  `mapstructure:"service_name" validate:"required"`
+}
+
+// RateLimitConfig limits the request rate of every client per route group:
+// read, write or admin. Each group has a token bucket per API key or user and
+// one per client IP. Buckets of clients idle for IdleTimeout are dropped.
+type RateLimitConfig struct {
+	Enabled     bool                            `mapstructure:"enabled"`
+	IdleTimeout time.Duration                   `mapstructure:"idle_timeout" validate:"required_if=Enabled true,gte=0"`
+	Groups      map[string]RateLimitGroupConfig `mapstructure:"groups"       validate:"required_if=Enabled true,dive,keys,oneof=read write admin,endkeys"`
+}
+
+// RateLimitGroupConfig is a token bucket refilled with RequestsPerSecond tokens
+// per second up to Burst.
+type RateLimitGroupConfig struct {
+	RequestsPerSecond float64 `mapstructure:"requests_per_second" validate:"gt=0"`
+	Burst             int     `mapstructure:"burst"               validate:"gt=0"`
+}
+
+// CORSConfig lists what browsers on other origins may do with the API. An
+// AllowedOrigins entry of "*" allows every origin, which cannot be combined
+// with AllowCredentials.
+type CORSConfig struct {
+	AllowedOrigins   []string      `mapstructure:"allowed_origins"   validate:"dive,required"`
+	AllowedMethods   []string      `mapstructure:"allowed_methods"   validate:"required,min=1,dive,oneof=GET POST PUT PATCH DELETE"`
+	AllowedHeaders   []string      `mapstructure:"allowed_headers"   validate:"dive,required"`
+	ExposedHeaders   []string      `mapstructure:"exposed_headers"   validate:"dive,required"`
+	AllowCredentials bool          `mapstructure:"allow_credentials"`
+	MaxAge           time.Duration `mapstructure:"max_age"           validate:"gte=0"`
+}
+
+// StreamConfig configures the live container status streams. At most
+// MaxSubscribers clients are connected at a time, each is disconnected once
+// BufferSize changes wait for it. Connections are kept alive with a ping every
+// PingInterval. The last HistorySize changes are kept for resuming streams.
+type StreamConfig struct {
+	MaxSubscribers int           `mapstructure:"max_subscribers" validate:"required,gt=0"`
+	BufferSize     int           `mapstructure:"buffer_size"     validate:"required,gt=0"`
+	HistorySize    int           `mapstructure:"history_size"    validate:"required,gt=0"`
+	PingInterval   time.Duration `mapstructure:"ping_interval"   validate:"required,gt=0"`
 }
 
 func LoadConfig(configPath string) (*Config, error) {
 	viper.SetConfigFile(configPath)
 	viper.SetConfigType("json")
+	viper.SetDefault("server.max_in_flight", 0)
+	viper.SetDefault("server.trusted_proxies", []string{})
+	viper.SetDefault("server.tls.enabled", false)
+	viper.SetDefault("server.tls.cert_file", "")
+	viper.SetDefault("server.tls.key_file", "")
+	viper.SetDefault("server.tls.client_ca_file", "")
+	viper.SetDefault("server.compression.enabled", false)
+	viper.SetDefault("server.compression.min_size", 1024)
+	viper.SetDefault("server.compression.max_request_size", 1<<20)
+	viper.SetDefault("auth_api.jwt.enabled", false)
+	viper.SetDefault("auth_api.jwt.jwks_refresh_interval", "1h")
+	viper.SetDefault("auth_api.jwt.leeway", "30s")
+	viper.SetDefault("auth_api.signatures.max_clock_skew", "5m")
+	viper.SetDefault("crash_loop.window", "5m")
+	viper.SetDefault("crash_loop.restart_threshold", 3)
+	viper.SetDefault("metrics.enabled", false)
+	viper.SetDefault("tracing.enabled", false)
+	viper.SetDefault("tracing.exporter", "otlp")
+	viper.SetDefault("tracing.sample_ratio", 1.0)
+	viper.SetDefault("tracing.service_name", "docker-monitoring-backend")
+	viper.SetDefault("rate_limit.enabled", false)
+	viper.SetDefault("rate_limit.idle_timeout", "10m")
+	viper.SetDefault("cors.allowed_origins", []string{})
+	viper.SetDefault("cors.allowed_methods", []string{"GET", "POST", "PUT", "PATCH", "DELETE"})
+	viper.SetDefault("cors.allowed_headers", []string{"Authorization", "Content-Type", "X-Api-Key", "X-Request-ID"})
+	viper.SetDefault("cors.exposed_headers", []string{"Retry-After", "X-Request-ID"})
+	viper.SetDefault("cors.allow_credentials", false)
+	viper.SetDefault("cors.max_age", "10m")
+	viper.SetDefault("stream.max_subscribers", 1000)
+	viper.SetDefault("stream.buffer_size", 64)
+	viper.SetDefault("stream.history_size", 1000)
+	viper.SetDefault("stream.ping_interval", "30s")
 
 	if err := viper.ReadInConfig(); err != nil {
 		return nil, fmt.Errorf("error reading config file: %w", err)
@@ -48,10 +250,22 @@ func LoadConfig(configPath string) (*Config, error) {
 		return nil, fmt.Errorf("unable to decode into struct: %w", err)
 	}
 
+	if key := viper.GetString("auth_api.api_key"); key != "" && config.AuthAPI != nil {
+		config.AuthAPI.Keys = append(config.AuthAPI.Keys, APIKeyConfig{
+			Name:   legacyAPIKeyName,
+			Key:    key,
+			Scopes: []string{"admin"},
+		})
+	}
+
 	validate := validator.New()
 	if err := validate.Struct(&config); err != nil {
 		return nil, fmt.Errorf("config validation failed: %w", err)
 	}
 
+	if config.CORS.AllowCredentials && slices.Contains(config.CORS.AllowedOrigins, "*") {
+		return nil, errors.New("config validation failed: cors.allow_credentials cannot be used with the * origin")
+	}
+
 	return &config, nil
 }
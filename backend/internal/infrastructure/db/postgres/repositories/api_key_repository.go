@@ -0,0 +1,270 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jmoiron/sqlx"
+
+	appRepo "github.com/k6zma/DockerMonitoringApp/backend/internal/application/repositories"
+	"github.com/k6zma/DockerMonitoringApp/backend/internal/domain"
+	"github.com/k6zma/DockerMonitoringApp/backend/pkg/utils"
+)
+
+const apiKeyColumns = `id, name, key_prefix, key_hash, signing_secret, scopes, auth_mode, created_at, last_used_at, expires_at, revoked_at`
+
+type APIKeyRepositoryImpl struct {
+	db     *sqlx.DB
+	logger utils.LoggerInterface
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func NewAPIKeyRepositoryImpl(
+	db *sqlx.DB,
+	logger utils.LoggerInterface,
+) appRepo.APIKeyRepository {
+	return &APIKeyRepositoryImpl{
+		db:     db,
+		logger: logger,
+	}
+}
+
+func (r *APIKeyRepositoryImpl) FindByID(ctx context.Context, id int64) (key *domain.APIKey, err error) {
+	query := `SELECT ` + apiKeyColumns + ` FROM api_keys WHERE id = $1`
+
+	ctx, span := startQuerySpan(ctx, "APIKeyRepository.FindByID", "api_keys", query)
+	defer func() { endSpan(span, err) }()
+
+	return r.findOne(ctx, query, id)
+}
+
+func (r *APIKeyRepositoryImpl) FindByHash(ctx context.Context, hash string) (key *domain.APIKey, err error) {
+	query := `SELECT ` + apiKeyColumns + ` FROM api_keys WHERE key_hash = $1`
+
+	ctx, span := startQuerySpan(ctx, "APIKeyRepository.FindByHash", "api_keys", query)
+	defer func() { endSpan(span, err) }()
+
+	return r.findOne(ctx, query, hash)
+}
+
+func (r *APIKeyRepositoryImpl) FindSigningKey(ctx context.Context, prefix string) (key *domain.APIKey, err error) {
+	query := `SELECT ` + apiKeyColumns + ` FROM api_keys WHERE key_prefix = $1 AND auth_mode = 'hmac'`
+
+	ctx, span := startQuerySpan(ctx, "APIKeyRepository.FindSigningKey", "api_keys", query)
+	defer func() { endSpan(span, err) }()
+
+	return r.findOne(ctx, query, prefix)
+}
+
+func (r *APIKeyRepositoryImpl) FindAll(ctx context.Context) (results []*domain.APIKey, err error) {
+	r.logger.Debugf("REPOSITORIES: listing API keys")
+
+	query := `SELECT ` + apiKeyColumns + ` FROM api_keys ORDER BY created_at DESC`
+
+	ctx, span := startQuerySpan(ctx, "APIKeyRepository.FindAll", "api_keys", query)
+	defer func() { endSpan(span, err) }()
+
+	rows, err := r.db.QueryxContext(ctx, query)
+	if err != nil {
+		r.logger.Errorf("REPOSITORIES: failed to list API keys: %v", err)
+		return nil, fmt.Errorf("database query error: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		key, err := r.scan(rows)
+		if err != nil {
+			r.logger.Errorf("REPOSITORIES: failed to scan API key: %v", err)
+			return nil, fmt.Errorf("database scan error: %w", err)
+		}
+		results = append(results, key)
+	}
+
+	if err := rows.Err(); err != nil {
+		r.logger.Errorf("REPOSITORIES: failed to iterate API keys: %v", err)
+		return nil, fmt.Errorf("database scan error: %w", err)
+	}
+
+	r.logger.Debugf("REPOSITORIES: found %d API keys", len(results))
+
+	return results, nil
+}
+
+func (r *APIKeyRepositoryImpl) Create(ctx context.Context, key *domain.APIKey) (err error) {
+	r.logger.Debugf("REPOSITORIES: creating API key %s (%s)", key.Name, key.Prefix)
+
+	query := `
+		INSERT INTO api_keys (name, key_prefix, key_hash, signing_secret, scopes, auth_mode, created_at, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id
+	`
+
+	ctx, span := startQuerySpan(ctx, "APIKeyRepository.Create", "api_keys", query)
+	defer func() { endSpan(span, err) }()
+
+	err = r.insert(ctx, r.db, query, key)
+	if err != nil {
+		r.logger.Errorf("REPOSITORIES: failed to create API key: %v", err)
+		return fmt.Errorf("failed to create API key: %w", err)
+	}
+
+	r.logger.Debugf("REPOSITORIES: API key created with ID: %d", key.ID)
+
+	return nil
+}
+
+func (r *APIKeyRepositoryImpl) Rotate(
+	ctx context.Context,
+	oldID int64,
+	oldExpiresAt time.Time,
+	key *domain.APIKey,
+) (err error) {
+	r.logger.Debugf("REPOSITORIES: rotating API key with ID %d, old key expires at %s", oldID, oldExpiresAt)
+
+	insertQuery := `
+		INSERT INTO api_keys (name, key_prefix, key_hash, signing_secret, scopes, auth_mode, created_at, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id
+	`
+	expireQuery := `
+		UPDATE api_keys
+		SET expires_at = LEAST(COALESCE(expires_at, $2), $2)
+		WHERE id = $1
+	`
+
+	ctx, span := startQuerySpan(ctx, "APIKeyRepository.Rotate", "api_keys", insertQuery)
+	defer func() { endSpan(span, err) }()
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		r.logger.Errorf("REPOSITORIES: failed to begin API key rotation: %v", err)
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	if err = r.insert(ctx, tx, insertQuery, key); err != nil {
+		r.logger.Errorf("REPOSITORIES: failed to create rotated API key: %v", err)
+		return fmt.Errorf("failed to create API key: %w", err)
+	}
+
+	if _, err = tx.ExecContext(ctx, expireQuery, oldID, oldExpiresAt); err != nil {
+		r.logger.Errorf("REPOSITORIES: failed to expire API key with ID %d: %v", oldID, err)
+		return fmt.Errorf("failed to expire API key: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		r.logger.Errorf("REPOSITORIES: failed to commit API key rotation: %v", err)
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	r.logger.Debugf("REPOSITORIES: API key with ID %d rotated to ID %d", oldID, key.ID)
+
+	return nil
+}
+
+func (r *APIKeyRepositoryImpl) Revoke(ctx context.Context, id int64, revokedAt time.Time) (err error) {
+	r.logger.Debugf("REPOSITORIES: revoking API key with ID: %d", id)
+
+	query := `
+		UPDATE api_keys
+		SET revoked_at = $2
+		WHERE id = $1 AND revoked_at IS NULL
+	`
+
+	ctx, span := startQuerySpan(ctx, "APIKeyRepository.Revoke", "api_keys", query)
+	defer func() { endSpan(span, err) }()
+
+	if _, err = r.db.ExecContext(ctx, query, id, revokedAt); err != nil {
+		r.logger.Errorf("REPOSITORIES: failed to revoke API key with ID %d: %v", id, err)
+		return fmt.Errorf("failed to revoke API key: %w", err)
+	}
+
+	return nil
+}
+
+func (r *APIKeyRepositoryImpl) UpdateLastUsed(ctx context.Context, id int64, lastUsedAt time.Time) (err error) {
+	query := `UPDATE api_keys SET last_used_at = $2 WHERE id = $1`
+
+	ctx, span := startQuerySpan(ctx, "APIKeyRepository.UpdateLastUsed", "api_keys", query)
+	defer func() { endSpan(span, err) }()
+
+	if _, err = r.db.ExecContext(ctx, query, id, lastUsedAt); err != nil {
+		r.logger.Errorf("REPOSITORIES: failed to update last use of API key with ID %d: %v", id, err)
+		return fmt.Errorf("failed to update API key: %w", err)
+	}
+
+	return nil
+}
+
+func (r *APIKeyRepositoryImpl) findOne(ctx context.Context, query string, arg interface{}) (*domain.APIKey, error) {
+	key, err := r.scan(r.db.QueryRowxContext(ctx, query, arg))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		r.logger.Errorf("REPOSITORIES: failed to fetch API key: %v", err)
+		return nil, fmt.Errorf("database query error: %w", err)
+	}
+
+	return key, nil
+}
+
+func (r *APIKeyRepositoryImpl) insert(ctx context.Context, db sqlx.QueryerContext, query string, key *domain.APIKey) error {
+	scopes := make([]string, 0, len(key.Scopes))
+	for _, scope := range key.Scopes {
+		scopes = append(scopes, string(scope))
+	}
+
+	return db.QueryRowxContext(ctx, query,
+		key.Name,
+		key.Prefix,
+		key.Hash,
+		key.SigningSecret,
+		scopes,
+		key.AuthMode,
+		key.CreatedAt,
+		key.ExpiresAt,
+	).Scan(&key.ID)
+}
+
+// scan reads a row selected with apiKeyColumns. A pgtype.Map is not safe for
+// concurrent use, so every call decodes the scopes array with its own map.
+func (r *APIKeyRepositoryImpl) scan(row rowScanner) (*domain.APIKey, error) {
+	var key domain.APIKey
+	var scopes []string
+
+	err := row.Scan(
+		&key.ID,
+		&key.Name,
+		&key.Prefix,
+		&key.Hash,
+		&key.SigningSecret,
+		pgtype.NewMap().SQLScanner(&scopes),
+		&key.AuthMode,
+		&key.CreatedAt,
+		&key.LastUsedAt,
+		&key.ExpiresAt,
+		&key.RevokedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	key.Scopes = make([]domain.Scope, 0, len(scopes))
+	for _, scope := range scopes {
+		key.Scopes = append(key.Scopes, domain.Scope(scope))
+	}
+
+	return &key, nil
+}
@@ -0,0 +1,138 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/k6zma/DockerMonitoringApp/backend/internal/application/dto"
+	appRepo "github.com/k6zma/DockerMonitoringApp/backend/internal/application/repositories"
+	"github.com/k6zma/DockerMonitoringApp/backend/internal/domain"
+	"github.com/k6zma/DockerMonitoringApp/backend/pkg/utils"
+)
+
+type AuditRepositoryImpl struct {
+	db     *sqlx.DB
+	logger utils.LoggerInterface
+}
+
+func NewAuditRepositoryImpl(
+	db *sqlx.DB,
+	logger utils.LoggerInterface,
+) appRepo.AuditRepository {
+	return &AuditRepositoryImpl{
+		db:     db,
+		logger: logger,
+	}
+}
+
+func (r *AuditRepositoryImpl) Find(
+	ctx context.Context,
+	filter *dto.AuditFilter,
+) (results []*domain.AuditEntry, err error) {
+	r.logger.Debugf("REPOSITORIES: executing audit Find with filter: %+v", *filter)
+
+	query := `
+		SELECT id, actor, auth_method, client_ip, method, route, path, container_id,
+			status_code, before, after, request_id, created_at
+		FROM audit_log
+	`
+
+	where, args := buildAuditConditions(filter)
+	query += where + " ORDER BY created_at DESC, id DESC"
+
+	if filter.Limit != nil {
+		query += fmt.Sprintf(" LIMIT $%d", len(args)+1)
+		args = append(args, *filter.Limit)
+	}
+
+	r.logger.Debugf("REPOSITORIES: final Query: %s, Args: %+v", query, args)
+
+	ctx, span := startQuerySpan(ctx, "AuditRepository.Find", "audit_log", query)
+	defer func() { endSpan(span, err) }()
+
+	if err = r.db.SelectContext(ctx, &results, query, args...); err != nil {
+		r.logger.Errorf("REPOSITORIES: failed to execute audit query: %v", err)
+		return nil, fmt.Errorf("database query error: %w", err)
+	}
+
+	r.logger.Debugf("REPOSITORIES: audit query executed successfully, found %d records", len(results))
+
+	return results, nil
+}
+
+func (r *AuditRepositoryImpl) Create(ctx context.Context, entry *domain.AuditEntry) (err error) {
+	r.logger.Debugf("REPOSITORIES: creating audit record for %s %s", entry.Method, entry.Path)
+
+	query := `
+		INSERT INTO audit_log (actor, auth_method, client_ip, method, route, path, container_id,
+			status_code, before, after, request_id, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		RETURNING id
+	`
+
+	ctx, span := startQuerySpan(ctx, "AuditRepository.Create", "audit_log", query)
+	defer func() { endSpan(span, err) }()
+
+	err = r.db.QueryRowxContext(ctx, query,
+		entry.Actor,
+		entry.AuthMethod,
+		entry.ClientIP,
+		entry.Method,
+		entry.Route,
+		entry.Path,
+		entry.ContainerID,
+		entry.StatusCode,
+		entry.Before,
+		entry.After,
+		entry.RequestID,
+		entry.CreatedAt,
+	).Scan(&entry.ID)
+	if err != nil {
+		r.logger.Errorf("REPOSITORIES: failed to create audit record: %v", err)
+		return fmt.Errorf("failed to create audit record: %w", err)
+	}
+
+	r.logger.Debugf("REPOSITORIES: audit record created with ID: %d", entry.ID)
+
+	return nil
+}
+
+func buildAuditConditions(filter *dto.AuditFilter) (string, []interface{}) {
+	var conditions []string
+	var args []interface{}
+
+	if filter.Actor != nil {
+		args = append(args, *filter.Actor)
+		conditions = append(conditions, fmt.Sprintf("actor = $%d", len(args)))
+	}
+
+	if len(filter.Methods) > 0 {
+		var condition string
+		condition, args = inCondition("method", filter.Methods, args)
+		conditions = append(conditions, condition)
+	}
+
+	if filter.ContainerID != nil {
+		args = append(args, *filter.ContainerID)
+		conditions = append(conditions, fmt.Sprintf("container_id = $%d", len(args)))
+	}
+
+	if filter.CreatedAtGte != nil {
+		args = append(args, *filter.CreatedAtGte)
+		conditions = append(conditions, fmt.Sprintf("created_at >= $%d", len(args)))
+	}
+
+	if filter.CreatedAtLte != nil {
+		args = append(args, *filter.CreatedAtLte)
+		conditions = append(conditions, fmt.Sprintf("created_at <= $%d", len(args)))
+	}
+
+	if len(conditions) == 0 {
+		return "", args
+	}
+
+	return " WHERE " + strings.Join(conditions, " AND "), args
+}
@@ -0,0 +1,157 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/k6zma/DockerMonitoringApp/backend/internal/application/dto"
+	appRepo "github.com/k6zma/DockerMonitoringApp/backend/internal/application/repositories"
+	"github.com/k6zma/DockerMonitoringApp/backend/internal/domain"
+	"github.com/k6zma/DockerMonitoringApp/backend/pkg/utils"
+)
+
+type ContainerEventRepositoryImpl struct {
+	db     *sqlx.DB
+	logger utils.LoggerInterface
+}
+
+func NewContainerEventRepositoryImpl(
+	db *sqlx.DB,
+	logger utils.LoggerInterface,
+) appRepo.ContainerEventRepository {
+	return &ContainerEventRepositoryImpl{
+		db:     db,
+		logger: logger,
+	}
+}
+
+func (r *ContainerEventRepositoryImpl) Find(
+	ctx context.Context,
+	filter *dto.ContainerEventFilter,
+) (results []*domain.ContainerEvent, err error) {
+	r.logger.Debugf("REPOSITORIES: executing events Find with filter: %+v", *filter)
+
+	query := `
+		SELECT id, container_id, type, status, message, created_at
+		FROM container_events
+	`
+
+	where, args := buildContainerEventConditions(filter)
+	query += where + " ORDER BY created_at DESC, id DESC"
+
+	if filter.Limit != nil {
+		query += fmt.Sprintf(" LIMIT $%d", len(args)+1)
+		args = append(args, *filter.Limit)
+	}
+
+	r.logger.Debugf("REPOSITORIES: final Query: %s, Args: %+v", query, args)
+
+	ctx, span := startQuerySpan(ctx, "ContainerEventRepository.Find", "container_events", query)
+	defer func() { endSpan(span, err) }()
+
+	if err = r.db.SelectContext(ctx, &results, query, args...); err != nil {
+		r.logger.Errorf("REPOSITORIES: failed to execute events query: %v", err)
+		return nil, fmt.Errorf("database query error: %w", err)
+	}
+
+	r.logger.Debugf("REPOSITORIES: events query executed successfully, found %d records", len(results))
+
+	return results, nil
+}
+
+func (r *ContainerEventRepositoryImpl) Count(ctx context.Context, filter *dto.ContainerEventFilter) (count int, err error) {
+	r.logger.Debugf("REPOSITORIES: executing events Count with filter: %+v", *filter)
+
+	where, args := buildContainerEventConditions(filter)
+	query := "SELECT COUNT(*) FROM container_events" + where
+
+	ctx, span := startQuerySpan(ctx, "ContainerEventRepository.Count", "container_events", query)
+	defer func() { endSpan(span, err) }()
+
+	if err = r.db.GetContext(ctx, &count, query, args...); err != nil {
+		r.logger.Errorf("REPOSITORIES: failed to count events: %v", err)
+		return 0, fmt.Errorf("database query error: %w", err)
+	}
+
+	return count, nil
+}
+
+func (r *ContainerEventRepositoryImpl) Create(ctx context.Context, event *domain.ContainerEvent) (err error) {
+	r.logger.Debugf("REPOSITORIES: creating container event record: %+v", event)
+
+	query := `
+		INSERT INTO container_events (container_id, type, status, message, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id
+	`
+
+	ctx, span := startQuerySpan(ctx, "ContainerEventRepository.Create", "container_events", query)
+	defer func() { endSpan(span, err) }()
+
+	err = executor(ctx, r.db).QueryRowxContext(ctx, query,
+		event.ContainerID,
+		event.Type,
+		event.Status,
+		event.Message,
+		event.CreatedAt,
+	).Scan(&event.ID)
+	if err != nil {
+		r.logger.Errorf("REPOSITORIES: failed to create container event: %v", err)
+		return fmt.Errorf("failed to create container event: %w", err)
+	}
+
+	r.logger.Debugf("REPOSITORIES: container event created with ID: %d", event.ID)
+
+	return nil
+}
+
+func buildContainerEventConditions(filter *dto.ContainerEventFilter) (string, []interface{}) {
+	var conditions []string
+	var args []interface{}
+
+	if filter.ContainerID != nil {
+		args = append(args, *filter.ContainerID)
+		conditions = append(conditions, fmt.Sprintf("container_id = $%d", len(args)))
+	}
+
+	if len(filter.Types) > 0 {
+		var condition string
+		condition, args = inCondition("type", filter.Types, args)
+		conditions = append(conditions, condition)
+	}
+
+	if len(filter.Statuses) > 0 {
+		var condition string
+		condition, args = inCondition("status", filter.Statuses, args)
+		conditions = append(conditions, condition)
+	}
+
+	if filter.CreatedAtGte != nil {
+		args = append(args, *filter.CreatedAtGte)
+		conditions = append(conditions, fmt.Sprintf("created_at >= $%d", len(args)))
+	}
+
+	if filter.CreatedAtLte != nil {
+		args = append(args, *filter.CreatedAtLte)
+		conditions = append(conditions, fmt.Sprintf("created_at <= $%d", len(args)))
+	}
+
+	if len(conditions) == 0 {
+		return "", args
+	}
+
+	return " WHERE " + strings.Join(conditions, " AND "), args
+}
+
+func inCondition(column string, values []string, args []interface{}) (string, []interface{}) {
+	placeholders := make([]string, 0, len(values))
+	for _, value := range values {
+		args = append(args, value)
+		placeholders = append(placeholders, fmt.Sprintf("$%d", len(args)))
+	}
+
+	return column + " IN (" + strings.Join(placeholders, ", ") + ")", args
+}
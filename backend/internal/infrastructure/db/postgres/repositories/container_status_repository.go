@@ -1,8 +1,12 @@
 package repositories
 
 import (
+	"context"
+	"database/sql"
+	"encoding/json"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/jmoiron/sqlx"
 
@@ -28,100 +32,41 @@ func NewContainerStatusRepositoryImpl(
 }
 
 func (r *ContainerStatusRepositoryImpl) Find(
+	ctx context.Context,
 	filter *dto.ContainerStatusFilter,
-) ([]*domain.ContainerStatus, error) {
+) (results []*domain.ContainerStatus, err error) {
 	r.logger.Debugf("REPOSITORIES: executing Find with filter: %+v", *filter)
 
 	query := `
-		SELECT container_id, ip_address, name, status, ping_time, last_successful_ping, created_at, updated_at
+		SELECT container_id, ip_address, name, status, ping_time, last_successful_ping, restart_count, crash_looping, labels,
+			created_at, updated_at
 		FROM container_status
 	`
 
-	var conditions []string
-	var args []interface{}
-	argCounter := 1
-
-	if filter.IPAddress != nil {
-		conditions = append(conditions, fmt.Sprintf("ip_address = $%d", argCounter))
-		args = append(args, *filter.IPAddress)
-		argCounter++
-	}
-
-	if filter.ContainerID != nil {
-		conditions = append(conditions, fmt.Sprintf("container_id = $%d", argCounter))
-		args = append(args, *filter.ContainerID)
-		argCounter++
-	}
-
-	if filter.Name != nil {
-		conditions = append(conditions, fmt.Sprintf("name = $%d", argCounter))
-		args = append(args, *filter.Name)
-		argCounter++
-	}
-
-	if filter.Status != nil {
-		conditions = append(conditions, fmt.Sprintf("status = $%d", argCounter))
-		args = append(args, *filter.Status)
-		argCounter++
-	}
-
-	if filter.PingTimeMin != nil {
-		conditions = append(conditions, fmt.Sprintf("ping_time >= $%d", argCounter))
-		args = append(args, *filter.PingTimeMin)
-		argCounter++
-	}
-
-	if filter.PingTimeMax != nil {
-		conditions = append(conditions, fmt.Sprintf("ping_time <= $%d", argCounter))
-		args = append(args, *filter.PingTimeMax)
-		argCounter++
-	}
-
-	if filter.CreatedAtGte != nil {
-		conditions = append(conditions, fmt.Sprintf("created_at >= $%d", argCounter))
-		args = append(args, *filter.CreatedAtGte)
-		argCounter++
-	}
-
-	if filter.CreatedAtLte != nil {
-		conditions = append(conditions, fmt.Sprintf("created_at <= $%d", argCounter))
-		args = append(args, *filter.CreatedAtLte)
-		argCounter++
-	}
-
-	if filter.UpdatedAtGte != nil {
-		conditions = append(conditions, fmt.Sprintf("updated_at >= $%d", argCounter))
-		args = append(args, *filter.UpdatedAtGte)
-		argCounter++
-	}
-
-	if filter.UpdatedAtLte != nil {
-		conditions = append(conditions, fmt.Sprintf("updated_at <= $%d", argCounter))
-		args = append(args, *filter.UpdatedAtLte)
-		argCounter++
-	}
-
-	if len(conditions) > 0 {
-		query += " WHERE " + strings.Join(conditions, " AND ")
-	}
+	where, args := buildContainerStatusConditions(filter)
+	query += where
 
 	if filter.Limit != nil {
-		query += fmt.Sprintf(" LIMIT $%d", argCounter)
 		args = append(args, *filter.Limit)
+		query += fmt.Sprintf(" LIMIT $%d", len(args))
 	}
 
 	r.logger.Debugf("REPOSITORIES: final Query: %s, Args: %+v", query, args)
 
-	rows, err := r.db.Queryx(query, args...)
+	ctx, span := startQuerySpan(ctx, "ContainerStatusRepository.Find", "container_status", query)
+	defer func() { endSpan(span, err) }()
+
+	rows, err := r.db.QueryxContext(ctx, query, args...)
 	if err != nil {
 		r.logger.Errorf("REPOSITORIES: failed to execute query: %v\n", err)
 		return nil, fmt.Errorf("database query error: %w", err)
 	}
+	defer rows.Close()
 
-	var results []*domain.ContainerStatus
 	for rows.Next() {
 		var status domain.ContainerStatus
 		var pingTime float64
+		var labels []byte
 
 		err := rows.Scan(
 			&status.ContainerID,
@@ -130,6 +75,9 @@ func (r *ContainerStatusRepositoryImpl) Find(
 			&status.Status,
 			&pingTime,
 			&status.LastSuccessfulPing,
+			&status.RestartCount,
+			&status.CrashLooping,
+			&labels,
 			&status.CreatedAt,
 			&status.UpdatedAt,
 		)
@@ -138,33 +86,91 @@ func (r *ContainerStatusRepositoryImpl) Find(
 			return nil, fmt.Errorf("database scan error: %w", err)
 		}
 
+		if err := json.Unmarshal(labels, &status.Labels); err != nil {
+			r.logger.Errorf("REPOSITORIES: failed to decode labels of container %s: %v\n", status.ContainerID, err)
+			return nil, fmt.Errorf("database scan error: %w", err)
+		}
+
 		status.PingTime = pingTime
 		results = append(results, &status)
 	}
 
+	if err := rows.Err(); err != nil {
+		r.logger.Errorf("REPOSITORIES: failed to iterate rows: %v\n", err)
+		return nil, fmt.Errorf("database scan error: %w", err)
+	}
+
 	r.logger.Debugf("REPOSITORIES: query executed successfully, found %d records", len(results))
 
 	return results, nil
 }
 
-func (r *ContainerStatusRepositoryImpl) Create(status *domain.ContainerStatus) error {
+func (r *ContainerStatusRepositoryImpl) Version(
+	ctx context.Context,
+	filter *dto.ContainerStatusFilter,
+) (_ *domain.ContainerStatusListVersion, err error) {
+	r.logger.Debugf("REPOSITORIES: executing Version with filter: %+v", *filter)
+
+	where, args := buildContainerStatusConditions(filter)
+	query := `
+		SELECT COUNT(*), MAX(modified_at), GREATEST(
+			(SELECT MAX(modified_at) FROM container_status),
+			(SELECT MAX(deleted_at) FROM container_status_deletions)
+		)
+		FROM container_status
+	` + where
+
+	ctx, span := startQuerySpan(ctx, "ContainerStatusRepository.Version", "container_status", query)
+	defer func() { endSpan(span, err) }()
+
+	var version domain.ContainerStatusListVersion
+	var updatedAt, modifiedAt sql.NullTime
+
+	err = r.db.QueryRowxContext(ctx, query, args...).Scan(&version.Count, &updatedAt, &modifiedAt)
+	if err != nil {
+		r.logger.Errorf("REPOSITORIES: failed to query container status version: %v", err)
+		return nil, fmt.Errorf("database query error: %w", err)
+	}
+
+	version.UpdatedAt = updatedAt.Time
+	version.ModifiedAt = modifiedAt.Time
+
+	return &version, nil
+}
+
+func (r *ContainerStatusRepositoryImpl) Create(ctx context.Context, status *domain.ContainerStatus) (err error) {
 	r.logger.Debugf("REPOSITORIES: creating container status record: %+v", status)
 
 	query := `
-		INSERT INTO container_status (container_id, ip_address, name, status, ping_time, last_successful_ping, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		INSERT INTO container_status (
+			container_id, ip_address, name, status, ping_time, last_successful_ping, restart_count, crash_looping, labels,
+			created_at, updated_at, modified_at
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
 		RETURNING container_id
 	`
 
-	err := r.db.QueryRowx(query,
+	labels, err := marshalLabels(status.Labels)
+	if err != nil {
+		return err
+	}
+
+	ctx, span := startQuerySpan(ctx, "ContainerStatusRepository.Create", "container_status", query)
+	defer func() { endSpan(span, err) }()
+
+	err = executor(ctx, r.db).QueryRowxContext(ctx, query,
 		status.ContainerID,
 		status.IPAddress,
 		status.Name,
 		status.Status,
 		status.PingTime,
 		status.LastSuccessfulPing,
+		status.RestartCount,
+		status.CrashLooping,
+		labels,
 		status.CreatedAt,
 		status.UpdatedAt,
+		time.Now(),
 	).Scan(&status.ContainerID)
 	if err != nil {
 		r.logger.Errorf("REPOSITORIES: failed to create container status: %v", err)
@@ -176,22 +182,35 @@ func (r *ContainerStatusRepositoryImpl) Create(status *domain.ContainerStatus) e
 	return nil
 }
 
-func (r *ContainerStatusRepositoryImpl) Update(status *domain.ContainerStatus) error {
+func (r *ContainerStatusRepositoryImpl) Update(ctx context.Context, status *domain.ContainerStatus) (err error) {
 	r.logger.Debugf("REPOSITORIES: updating container status record for ID: %s, IP: %s", status.ContainerID, status.IPAddress)
 
 	query := `
 		UPDATE container_status
-		SET name = $1, status = $2, ping_time = $3, last_successful_ping = $4, updated_at = $5, ip_address = $6
-		WHERE container_id = $7
+		SET name = $1, status = $2, ping_time = $3, last_successful_ping = $4, updated_at = $5, ip_address = $6,
+			restart_count = $7, crash_looping = $8, labels = $9, modified_at = $10
+		WHERE container_id = $11
 	`
 
-	_, err := r.db.Exec(query,
+	labels, err := marshalLabels(status.Labels)
+	if err != nil {
+		return err
+	}
+
+	ctx, span := startQuerySpan(ctx, "ContainerStatusRepository.Update", "container_status", query)
+	defer func() { endSpan(span, err) }()
+
+	_, err = executor(ctx, r.db).ExecContext(ctx, query,
 		status.Name,
 		status.Status,
 		status.PingTime,
 		status.LastSuccessfulPing,
 		status.UpdatedAt,
 		status.IPAddress,
+		status.RestartCount,
+		status.CrashLooping,
+		labels,
+		time.Now(),
 		status.ContainerID,
 	)
 	if err != nil {
@@ -213,15 +232,25 @@ func (r *ContainerStatusRepositoryImpl) Update(status *domain.ContainerStatus) e
 	return nil
 }
 
-func (r *ContainerStatusRepositoryImpl) DeleteByContainerID(containerID string) error {
+func (r *ContainerStatusRepositoryImpl) DeleteByContainerID(ctx context.Context, containerID string) (err error) {
 	r.logger.Debugf("REPOSITORIES: deleting container status record for container id: %s", containerID)
 
+	// The deletion time is recorded in the same statement, see Version.
 	query := `
-		DELETE FROM container_status
-		WHERE container_id = $1
+		WITH deleted AS (
+			DELETE FROM container_status
+			WHERE container_id = $1
+			RETURNING container_id
+		)
+		INSERT INTO container_status_deletions (id, deleted_at)
+		SELECT true, $2 FROM deleted
+		ON CONFLICT (id) DO UPDATE SET deleted_at = GREATEST(container_status_deletions.deleted_at, EXCLUDED.deleted_at)
 	`
 
-	_, err := r.db.Exec(query, containerID)
+	ctx, span := startQuerySpan(ctx, "ContainerStatusRepository.DeleteByContainerID", "container_status", query)
+	defer func() { endSpan(span, err) }()
+
+	_, err = executor(ctx, r.db).ExecContext(ctx, query, containerID, time.Now())
 	if err != nil {
 		r.logger.Errorf(
 			"REPOSITORIES: failed to delete container status for container id %s: %v",
@@ -235,3 +264,79 @@ func (r *ContainerStatusRepositoryImpl) DeleteByContainerID(containerID string)
 
 	return nil
 }
+
+func buildContainerStatusConditions(filter *dto.ContainerStatusFilter) (string, []interface{}) {
+	var conditions []string
+	var args []interface{}
+
+	if filter.IPAddress != nil {
+		args = append(args, *filter.IPAddress)
+		conditions = append(conditions, fmt.Sprintf("ip_address = $%d", len(args)))
+	}
+
+	if filter.ContainerID != nil {
+		args = append(args, *filter.ContainerID)
+		conditions = append(conditions, fmt.Sprintf("container_id = $%d", len(args)))
+	}
+
+	if filter.Name != nil {
+		args = append(args, *filter.Name)
+		conditions = append(conditions, fmt.Sprintf("name = $%d", len(args)))
+	}
+
+	if filter.Status != nil {
+		args = append(args, *filter.Status)
+		conditions = append(conditions, fmt.Sprintf("status = $%d", len(args)))
+	}
+
+	if filter.PingTimeMin != nil {
+		args = append(args, *filter.PingTimeMin)
+		conditions = append(conditions, fmt.Sprintf("ping_time >= $%d", len(args)))
+	}
+
+	if filter.PingTimeMax != nil {
+		args = append(args, *filter.PingTimeMax)
+		conditions = append(conditions, fmt.Sprintf("ping_time <= $%d", len(args)))
+	}
+
+	if filter.CreatedAtGte != nil {
+		args = append(args, *filter.CreatedAtGte)
+		conditions = append(conditions, fmt.Sprintf("created_at >= $%d", len(args)))
+	}
+
+	if filter.CreatedAtLte != nil {
+		args = append(args, *filter.CreatedAtLte)
+		conditions = append(conditions, fmt.Sprintf("created_at <= $%d", len(args)))
+	}
+
+	if filter.UpdatedAtGte != nil {
+		args = append(args, *filter.UpdatedAtGte)
+		conditions = append(conditions, fmt.Sprintf("updated_at >= $%d", len(args)))
+	}
+
+	if filter.UpdatedAtLte != nil {
+		args = append(args, *filter.UpdatedAtLte)
+		conditions = append(conditions, fmt.Sprintf("updated_at <= $%d", len(args)))
+	}
+
+	if len(conditions) == 0 {
+		return "", args
+	}
+
+	return " WHERE " + strings.Join(conditions, " AND "), args
+}
+
+// marshalLabels encodes the labels for the JSONB column, storing an empty
+// object rather than null.
+func marshalLabels(labels map[string]string) ([]byte, error) {
+	if labels == nil {
+		labels = map[string]string{}
+	}
+
+	data, err := json.Marshal(labels)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode labels: %w", err)
+	}
+
+	return data, nil
+}
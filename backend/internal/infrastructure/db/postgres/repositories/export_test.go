@@ -0,0 +1,26 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/k6zma/DockerMonitoringApp/backend/pkg/utils"
+)
+
+// Lets the tests hand the status change listener connections of their own.
+
+type NotificationConn = notificationConn
+
+func NewStatusChangeListenerWithConnect(
+	connect func(ctx context.Context, listen func(conn NotificationConn) error) error,
+	forwarder StatusChangeForwarder,
+	retryInterval time.Duration,
+	logger utils.LoggerInterface,
+) *StatusChangeListener {
+	return &StatusChangeListener{
+		connect:       connect,
+		forwarder:     forwarder,
+		retryInterval: retryInterval,
+		logger:        logger,
+	}
+}
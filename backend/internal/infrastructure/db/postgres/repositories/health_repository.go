@@ -0,0 +1,61 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+
+	appRepo "github.com/k6zma/DockerMonitoringApp/backend/internal/application/repositories"
+	"github.com/k6zma/DockerMonitoringApp/backend/pkg/utils"
+)
+
+type HealthRepositoryImpl struct {
+	db     *sqlx.DB
+	logger utils.LoggerInterface
+}
+
+func NewHealthRepositoryImpl(
+	db *sqlx.DB,
+	logger utils.LoggerInterface,
+) appRepo.HealthRepository {
+	return &HealthRepositoryImpl{
+		db:     db,
+		logger: logger,
+	}
+}
+
+func (r *HealthRepositoryImpl) Ping(ctx context.Context) error {
+	if err := r.db.PingContext(ctx); err != nil {
+		r.logger.Errorf("REPOSITORIES: database ping failed: %v", err)
+		return fmt.Errorf("database ping failed: %w", err)
+	}
+
+	return nil
+}
+
+func (r *HealthRepositoryImpl) MigrationState(ctx context.Context) (version uint, dirty bool, err error) {
+	// schema_migrations is maintained by golang-migrate and holds a single row.
+	query := `SELECT version, dirty FROM schema_migrations LIMIT 1`
+
+	var state struct {
+		Version int64 `db:"version"`
+		Dirty   bool  `db:"dirty"`
+	}
+
+	ctx, span := startQuerySpan(ctx, "HealthRepository.MigrationState", "schema_migrations", query)
+	defer func() { endSpan(span, err) }()
+
+	err = r.db.GetContext(ctx, &state, query)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, false, nil
+	}
+	if err != nil {
+		r.logger.Errorf("REPOSITORIES: failed to read migration state: %v", err)
+		return 0, false, fmt.Errorf("failed to read migration state: %w", err)
+	}
+
+	return uint(state.Version), state.Dirty, nil
+}
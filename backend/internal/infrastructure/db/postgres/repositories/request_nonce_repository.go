@@ -0,0 +1,81 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	appRepo "github.com/k6zma/DockerMonitoringApp/backend/internal/application/repositories"
+	"github.com/k6zma/DockerMonitoringApp/backend/pkg/utils"
+)
+
+type RequestNonceRepositoryImpl struct {
+	db     *sqlx.DB
+	logger utils.LoggerInterface
+}
+
+func NewRequestNonceRepositoryImpl(
+	db *sqlx.DB,
+	logger utils.LoggerInterface,
+) appRepo.RequestNonceRepository {
+	return &RequestNonceRepositoryImpl{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// Claim inserts the nonce in a single statement, so concurrent requests with
+// the same nonce cannot both claim it. An expired row left behind is taken
+// over instead of blocking the nonce.
+func (r *RequestNonceRepositoryImpl) Claim(
+	ctx context.Context,
+	keyID string,
+	nonce string,
+	expiresAt time.Time,
+	now time.Time,
+) (_ bool, err error) {
+	query := `
+		INSERT INTO request_nonces (key_id, nonce, expires_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (key_id, nonce) DO UPDATE
+		SET expires_at = EXCLUDED.expires_at
+		WHERE request_nonces.expires_at <= $4
+	`
+
+	ctx, span := startQuerySpan(ctx, "RequestNonceRepository.Claim", "request_nonces", query)
+	defer func() { endSpan(span, err) }()
+
+	result, err := r.db.ExecContext(ctx, query, keyID, nonce, expiresAt, now)
+	if err != nil {
+		r.logger.Errorf("REPOSITORIES: failed to claim nonce of key %s: %v", keyID, err)
+		return false, fmt.Errorf("failed to claim nonce: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	return rowsAffected == 1, nil
+}
+
+func (r *RequestNonceRepositoryImpl) DeleteExpired(ctx context.Context, now time.Time) (err error) {
+	query := `DELETE FROM request_nonces WHERE expires_at <= $1`
+
+	ctx, span := startQuerySpan(ctx, "RequestNonceRepository.DeleteExpired", "request_nonces", query)
+	defer func() { endSpan(span, err) }()
+
+	result, err := r.db.ExecContext(ctx, query, now)
+	if err != nil {
+		r.logger.Errorf("REPOSITORIES: failed to delete expired nonces: %v", err)
+		return fmt.Errorf("failed to delete expired nonces: %w", err)
+	}
+
+	if deleted, err := result.RowsAffected(); err == nil && deleted > 0 {
+		r.logger.Debugf("REPOSITORIES: deleted %d expired nonces", deleted)
+	}
+
+	return nil
+}
@@ -0,0 +1,114 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+
+	appRepo "github.com/k6zma/DockerMonitoringApp/backend/internal/application/repositories"
+	"github.com/k6zma/DockerMonitoringApp/backend/internal/domain"
+	"github.com/k6zma/DockerMonitoringApp/backend/pkg/utils"
+)
+
+type RoleAssignmentRepositoryImpl struct {
+	db     *sqlx.DB
+	logger utils.LoggerInterface
+}
+
+func NewRoleAssignmentRepositoryImpl(
+	db *sqlx.DB,
+	logger utils.LoggerInterface,
+) appRepo.RoleAssignmentRepository {
+	return &RoleAssignmentRepositoryImpl{
+		db:     db,
+		logger: logger,
+	}
+}
+
+func (r *RoleAssignmentRepositoryImpl) FindBySubject(
+	ctx context.Context,
+	subject string,
+) (_ *domain.RoleAssignment, err error) {
+	query := `SELECT subject, role, created_at, updated_at FROM role_assignments WHERE subject = $1`
+
+	ctx, span := startQuerySpan(ctx, "RoleAssignmentRepository.FindBySubject", "role_assignments", query)
+	defer func() { endSpan(span, err) }()
+
+	var result domain.RoleAssignment
+	err = r.db.GetContext(ctx, &result, query, subject)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		r.logger.Errorf("REPOSITORIES: failed to fetch role of %s: %v", subject, err)
+		return nil, fmt.Errorf("database query error: %w", err)
+	}
+
+	return &result, nil
+}
+
+func (r *RoleAssignmentRepositoryImpl) FindAll(ctx context.Context) (results []*domain.RoleAssignment, err error) {
+	r.logger.Debugf("REPOSITORIES: listing role assignments")
+
+	query := `SELECT subject, role, created_at, updated_at FROM role_assignments ORDER BY subject`
+
+	ctx, span := startQuerySpan(ctx, "RoleAssignmentRepository.FindAll", "role_assignments", query)
+	defer func() { endSpan(span, err) }()
+
+	if err = r.db.SelectContext(ctx, &results, query); err != nil {
+		r.logger.Errorf("REPOSITORIES: failed to list role assignments: %v", err)
+		return nil, fmt.Errorf("database query error: %w", err)
+	}
+
+	r.logger.Debugf("REPOSITORIES: found %d role assignments", len(results))
+
+	return results, nil
+}
+
+func (r *RoleAssignmentRepositoryImpl) Upsert(ctx context.Context, assignment *domain.RoleAssignment) (err error) {
+	r.logger.Debugf("REPOSITORIES: assigning role %s to %s", assignment.Role, assignment.Subject)
+
+	query := `
+		INSERT INTO role_assignments (subject, role, created_at, updated_at)
+		VALUES ($1, $2, $3, $3)
+		ON CONFLICT (subject) DO UPDATE SET role = EXCLUDED.role, updated_at = EXCLUDED.updated_at
+		RETURNING created_at, updated_at
+	`
+
+	ctx, span := startQuerySpan(ctx, "RoleAssignmentRepository.Upsert", "role_assignments", query)
+	defer func() { endSpan(span, err) }()
+
+	err = r.db.QueryRowxContext(ctx, query, assignment.Subject, string(assignment.Role), assignment.UpdatedAt).
+		Scan(&assignment.CreatedAt, &assignment.UpdatedAt)
+	if err != nil {
+		r.logger.Errorf("REPOSITORIES: failed to assign role to %s: %v", assignment.Subject, err)
+		return fmt.Errorf("failed to assign role: %w", err)
+	}
+
+	return nil
+}
+
+func (r *RoleAssignmentRepositoryImpl) Delete(ctx context.Context, subject string) (_ bool, err error) {
+	r.logger.Debugf("REPOSITORIES: removing role of %s", subject)
+
+	query := `DELETE FROM role_assignments WHERE subject = $1`
+
+	ctx, span := startQuerySpan(ctx, "RoleAssignmentRepository.Delete", "role_assignments", query)
+	defer func() { endSpan(span, err) }()
+
+	result, err := r.db.ExecContext(ctx, query, subject)
+	if err != nil {
+		r.logger.Errorf("REPOSITORIES: failed to remove role of %s: %v", subject, err)
+		return false, fmt.Errorf("failed to remove role assignment: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	return rowsAffected > 0, nil
+}
@@ -0,0 +1,230 @@
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/stdlib"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/k6zma/DockerMonitoringApp/backend/internal/application/usecases"
+	"github.com/k6zma/DockerMonitoringApp/backend/internal/domain"
+	"github.com/k6zma/DockerMonitoringApp/backend/pkg/utils"
+)
+
+// StatusChangeChannel is the Postgres notification channel the container status
+// changes are sent on.
+const StatusChangeChannel = "container_status_changes"
+
+const (
+	// maxNotificationPayload is the largest payload Postgres accepts, less one
+	// byte.
+	maxNotificationPayload = 7999
+	// listenRetryInterval is how long the listener waits before it reconnects.
+	listenRetryInterval = 5 * time.Second
+)
+
+// StatusChangeNotifier sends every container status change as a Postgres
+// notification, so the StatusChangeListener of every backend instance can
+// forward it to its subscribers.
+//
+// Changes are published in the transaction that writes them. Postgres delivers
+// the notification when that transaction commits and drops it when it rolls
+// back, and a notification that cannot be sent aborts the transaction, so the
+// subscribers hear of exactly the changes that were written. A change too
+// large for a notification resets the subscribers of every instance through an
+// empty one instead.
+type StatusChangeNotifier struct {
+	db     sqlx.ExecerContext
+	logger utils.LoggerInterface
+}
+
+var _ usecases.StatusChangePublisher = (*StatusChangeNotifier)(nil)
+
+// NewStatusChangeNotifier sends the notifications through db outside of a
+// transaction.
+func NewStatusChangeNotifier(
+	db sqlx.ExecerContext,
+	logger utils.LoggerInterface,
+) *StatusChangeNotifier {
+	return &StatusChangeNotifier{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// Publish notifies the listeners of change once the transaction of ctx
+// commits. Failures are logged, the transaction then fails to commit.
+func (n *StatusChangeNotifier) Publish(ctx context.Context, change *domain.ContainerStatusChange) {
+	logger := utils.ContextLogger(ctx, n.logger, "container_id", change.Status.ContainerID)
+
+	payload, err := json.Marshal(change)
+	if err == nil && len(payload) > maxNotificationPayload && change.Previous != nil {
+		// Subscribers then only match the state after the change.
+		trimmed := *change
+		trimmed.Previous = nil
+		payload, err = json.Marshal(&trimmed)
+	}
+	if err != nil {
+		logger.Errorf("REPOSITORIES: failed to encode %s change notification, resetting subscribers: %v", change.Type, err)
+		payload = nil
+	} else if len(payload) > maxNotificationPayload {
+		logger.Errorf("REPOSITORIES: dropping %s change notification of %d bytes, resetting subscribers", change.Type, len(payload))
+		payload = nil
+	}
+
+	if err := n.notify(ctx, payload); err != nil {
+		logger.Errorf("REPOSITORIES: failed to send %s change notification: %v", change.Type, err)
+	}
+}
+
+// notify sends payload on the StatusChangeChannel. An empty payload tells the
+// listeners to reset their subscribers.
+func (n *StatusChangeNotifier) notify(ctx context.Context, payload []byte) (err error) {
+	query := `SELECT pg_notify($1, $2)`
+
+	ctx, span := startQuerySpan(ctx, "StatusChangeNotifier.Publish", StatusChangeChannel, query)
+	defer func() { endSpan(span, err) }()
+
+	var db sqlx.ExecerContext = n.db
+	if tx, ok := txFromContext(ctx); ok {
+		db = tx
+	}
+
+	_, err = db.ExecContext(ctx, query, StatusChangeChannel, string(payload))
+
+	return err
+}
+
+// StatusChangeForwarder receives the changes a StatusChangeListener hears of.
+// Reset is called when changes may have been missed while reconnecting.
+type StatusChangeForwarder interface {
+	usecases.StatusChangePublisher
+	Reset()
+}
+
+// notificationConn is the connection a StatusChangeListener listens on.
+type notificationConn interface {
+	Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error)
+	WaitForNotification(ctx context.Context) (*pgconn.Notification, error)
+}
+
+// connectFunc calls listen with a connection of its own, which is closed once
+// listen returns.
+type connectFunc func(ctx context.Context, listen func(conn notificationConn) error) error
+
+// StatusChangeListener forwards the change notifications sent by the backend
+// instances, this one included. It holds one connection of the pool.
+type StatusChangeListener struct {
+	connect       connectFunc
+	forwarder     StatusChangeForwarder
+	retryInterval time.Duration
+	logger        utils.LoggerInterface
+}
+
+func NewStatusChangeListener(
+	db *sqlx.DB,
+	forwarder StatusChangeForwarder,
+	logger utils.LoggerInterface,
+) *StatusChangeListener {
+	return &StatusChangeListener{
+		connect:       poolConnection(db),
+		forwarder:     forwarder,
+		retryInterval: listenRetryInterval,
+		logger:        logger,
+	}
+}
+
+// Run listens until ctx is done, reconnecting after listenRetryInterval when
+// the connection fails.
+func (l *StatusChangeListener) Run(ctx context.Context) {
+	missed := false
+	for {
+		err := l.listen(ctx, missed)
+		if ctx.Err() != nil {
+			return
+		}
+
+		l.logger.Errorf("REPOSITORIES: listening for status change notifications failed, retrying in %s: %v",
+			l.retryInterval, err)
+		missed = true
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(l.retryInterval):
+		}
+	}
+}
+
+// listen forwards the notifications until the connection fails. With missed,
+// the forwarder is reset once listening, notifications sent while the listener
+// was disconnected are lost.
+func (l *StatusChangeListener) listen(ctx context.Context, missed bool) error {
+	return l.connect(ctx, func(conn notificationConn) error {
+		if _, err := conn.Exec(ctx, "LISTEN "+pgx.Identifier{StatusChangeChannel}.Sanitize()); err != nil {
+			return fmt.Errorf("failed to listen: %w", err)
+		}
+
+		l.logger.Infof("REPOSITORIES: listening for status change notifications on %s", StatusChangeChannel)
+		if missed {
+			l.logger.Warnf("REPOSITORIES: status change notifications may have been missed, resetting subscribers")
+			l.forwarder.Reset()
+		}
+
+		for {
+			notification, err := conn.WaitForNotification(ctx)
+			if err != nil {
+				return fmt.Errorf("failed to wait for notification: %w", err)
+			}
+
+			if notification.Payload == "" {
+				l.logger.Warnf("REPOSITORIES: a status change notification was dropped, resetting subscribers")
+				l.forwarder.Reset()
+				continue
+			}
+
+			var change domain.ContainerStatusChange
+			if err := json.Unmarshal([]byte(notification.Payload), &change); err != nil {
+				l.logger.Errorf("REPOSITORIES: failed to decode status change notification, resetting subscribers: %v", err)
+				l.forwarder.Reset()
+				continue
+			}
+
+			l.forwarder.Publish(ctx, &change)
+		}
+	})
+}
+
+// poolConnection takes a connection out of the pool of db for good: a
+// listening connection must not go back to it.
+func poolConnection(db *sqlx.DB) connectFunc {
+	return func(ctx context.Context, listen func(conn notificationConn) error) error {
+		conn, err := db.Conn(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get connection: %w", err)
+		}
+		defer conn.Close()
+
+		return conn.Raw(func(driverConn any) error {
+			stdlibConn, ok := driverConn.(*stdlib.Conn)
+			if !ok {
+				return errors.New("not a pgx connection")
+			}
+			pgxConn := stdlibConn.Conn()
+
+			defer func() {
+				closeCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), time.Second)
+				defer cancel()
+				_ = pgxConn.Close(closeCtx)
+			}()
+
+			return listen(pgxConn)
+		})
+	}
+}
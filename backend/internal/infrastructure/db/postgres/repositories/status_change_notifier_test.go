@@ -0,0 +1,355 @@
+package repositories_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/k6zma/DockerMonitoringApp/backend/internal/domain"
+	"github.com/k6zma/DockerMonitoringApp/backend/internal/infrastructure/db/postgres/repositories"
+	"github.com/k6zma/DockerMonitoringApp/backend/pkg/utils"
+)
+
+var (
+	logger = &utils.Logger{SugaredLogger: zap.NewNop().Sugar()}
+
+	errConnectionLost    = errors.New("connection lost")
+	errConnectionRefused = errors.New("connection refused")
+)
+
+// recordingForwarder reports the changes published to it and its resets.
+type recordingForwarder struct {
+	events chan string
+}
+
+func newRecordingForwarder() *recordingForwarder {
+	return &recordingForwarder{events: make(chan string, 16)}
+}
+
+func (f *recordingForwarder) Publish(_ context.Context, change *domain.ContainerStatusChange) {
+	f.events <- "publish " + change.Status.ContainerID
+}
+
+func (f *recordingForwarder) Reset() {
+	f.events <- "reset"
+}
+
+// recordingExecer records the payloads of the notifications sent through it.
+type recordingExecer struct {
+	payloads []string
+	err      error
+}
+
+func (e *recordingExecer) ExecContext(_ context.Context, _ string, args ...any) (sql.Result, error) {
+	e.payloads = append(e.payloads, args[1].(string))
+
+	return driver.RowsAffected(1), e.err
+}
+
+// fakeConn delivers the notifications sent on it until it is closed, when the
+// connection is lost.
+type fakeConn struct {
+	statements    chan string
+	notifications chan *pgconn.Notification
+}
+
+func newFakeConn() *fakeConn {
+	return &fakeConn{
+		statements:    make(chan string, 1),
+		notifications: make(chan *pgconn.Notification),
+	}
+}
+
+func (c *fakeConn) Exec(_ context.Context, sql string, _ ...any) (pgconn.CommandTag, error) {
+	c.statements <- sql
+
+	return pgconn.CommandTag{}, nil
+}
+
+func (c *fakeConn) WaitForNotification(ctx context.Context) (*pgconn.Notification, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case notification, ok := <-c.notifications:
+		if !ok {
+			return nil, errConnectionLost
+		}
+
+		return notification, nil
+	}
+}
+
+func (c *fakeConn) send(payload string) {
+	c.notifications <- &pgconn.Notification{Channel: repositories.StatusChangeChannel, Payload: payload}
+}
+
+// connectTo hands out the connections of conns in order, a nil connection
+// being refused.
+func connectTo(conns <-chan *fakeConn) func(context.Context, func(repositories.NotificationConn) error) error {
+	return func(ctx context.Context, listen func(repositories.NotificationConn) error) error {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case conn := <-conns:
+			if conn == nil {
+				return errConnectionRefused
+			}
+
+			return listen(conn)
+		}
+	}
+}
+
+func next[T any](t *testing.T, ch <-chan T) T {
+	t.Helper()
+
+	select {
+	case value := <-ch:
+		return value
+	case <-time.After(time.Second):
+		require.FailNow(t, "timed out")
+		var zero T
+		return zero
+	}
+}
+
+// runListener runs listener until the test ends.
+func runListener(t *testing.T, listener *repositories.StatusChangeListener) {
+	t.Helper()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		listener.Run(ctx)
+		close(done)
+	}()
+
+	t.Cleanup(func() {
+		cancel()
+		next(t, done)
+	})
+}
+
+func changePayload(t *testing.T, containerID string) string {
+	t.Helper()
+
+	payload, err := json.Marshal(&domain.ContainerStatusChange{
+		Type:   domain.StatusChangeUpdated,
+		Status: domain.ContainerStatus{ContainerID: containerID, Status: "running"},
+	})
+	require.NoError(t, err)
+
+	return string(payload)
+}
+
+func TestStatusChangeListener_Forwards(t *testing.T) {
+	forwarder := newRecordingForwarder()
+	conn := newFakeConn()
+	conns := make(chan *fakeConn, 1)
+	conns <- conn
+
+	runListener(t, repositories.NewStatusChangeListenerWithConnect(connectTo(conns), forwarder, time.Millisecond, logger))
+
+	assert.Equal(t, `LISTEN "container_status_changes"`, next(t, conn.statements))
+
+	conn.send(changePayload(t, "abc123"))
+	assert.Equal(t, "publish abc123", next(t, forwarder.events))
+
+	// A change too large to be sent arrives as an empty notification, one that
+	// cannot be decoded is lost as well.
+	conn.send("")
+	assert.Equal(t, "reset", next(t, forwarder.events))
+
+	conn.send(`{"type":`)
+	assert.Equal(t, "reset", next(t, forwarder.events))
+
+	conn.send(changePayload(t, "def456"))
+	assert.Equal(t, "publish def456", next(t, forwarder.events))
+}
+
+func TestStatusChangeListener_ResetsAfterReconnect(t *testing.T) {
+	forwarder := newRecordingForwarder()
+	first, second := newFakeConn(), newFakeConn()
+	conns := make(chan *fakeConn, 3)
+	conns <- first
+	conns <- nil
+	conns <- second
+
+	runListener(t, repositories.NewStatusChangeListenerWithConnect(connectTo(conns), forwarder, time.Millisecond, logger))
+
+	// Nothing can have been missed before the first connection.
+	next(t, first.statements)
+	first.send(changePayload(t, "abc123"))
+	assert.Equal(t, "publish abc123", next(t, forwarder.events))
+
+	// Changes sent while reconnecting are lost, the subscribers are reset once
+	// listening again.
+	close(first.notifications)
+	next(t, second.statements)
+	assert.Equal(t, "reset", next(t, forwarder.events))
+
+	second.send(changePayload(t, "def456"))
+	assert.Equal(t, "publish def456", next(t, forwarder.events))
+	assert.Empty(t, forwarder.events)
+}
+
+func TestStatusChangeNotifier_Publish(t *testing.T) {
+	previous := &domain.ContainerStatus{ContainerID: "abc123", Status: "running"}
+	large := map[string]string{"note": strings.Repeat("x", 5000)}
+
+	tests := []struct {
+		name         string
+		change       *domain.ContainerStatusChange
+		sendErr      error
+		previousSent bool
+	}{
+		{
+			name: "sent",
+			change: &domain.ContainerStatusChange{
+				Type:     domain.StatusChangeUpdated,
+				Status:   domain.ContainerStatus{ContainerID: "abc123", Status: "exited"},
+				Previous: previous,
+			},
+			previousSent: true,
+		},
+		{
+			name: "previous state left out when too large",
+			change: &domain.ContainerStatusChange{
+				Type:     domain.StatusChangeUpdated,
+				Status:   domain.ContainerStatus{ContainerID: "abc123", Status: "exited", Labels: large},
+				Previous: &domain.ContainerStatus{ContainerID: "abc123", Status: "running", Labels: large},
+			},
+		},
+		{
+			name:    "failure only logged",
+			change:  &domain.ContainerStatusChange{Type: domain.StatusChangeCreated, Status: *previous},
+			sendErr: errConnectionLost,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := &recordingExecer{err: tt.sendErr}
+
+			repositories.NewStatusChangeNotifier(db, logger).Publish(context.Background(), tt.change)
+
+			require.Len(t, db.payloads, 1)
+			var sent domain.ContainerStatusChange
+			require.NoError(t, json.Unmarshal([]byte(db.payloads[0]), &sent))
+			assert.Equal(t, tt.change.Status.Status, sent.Status.Status)
+			assert.Equal(t, tt.previousSent, sent.Previous != nil)
+		})
+	}
+}
+
+func TestStatusChangeNotifier_DropsOversizeChange(t *testing.T) {
+	change := &domain.ContainerStatusChange{
+		Type: domain.StatusChangeCreated,
+		Status: domain.ContainerStatus{
+			ContainerID: "abc123",
+			Labels:      map[string]string{"note": strings.Repeat("x", 9000)},
+		},
+	}
+
+	db := &recordingExecer{}
+
+	repositories.NewStatusChangeNotifier(db, logger).Publish(context.Background(), change)
+
+	// The listeners are told to reset their subscribers instead.
+	assert.Equal(t, []string{""}, db.payloads)
+}
+
+func TestStatusChangeNotifier_SendsInTransaction(t *testing.T) {
+	change := &domain.ContainerStatusChange{
+		Type:   domain.StatusChangeCreated,
+		Status: domain.ContainerStatus{ContainerID: "abc123", Status: "running"},
+	}
+	errWriteFailed := errors.New("write failed")
+
+	tests := []struct {
+		name       string
+		writeErr   error
+		statements []string
+	}{
+		{
+			name:       "committed with the write",
+			statements: []string{"BEGIN", "SELECT pg_notify($1, $2)", "COMMIT"},
+		},
+		{
+			name:       "rolled back with the write",
+			writeErr:   errWriteFailed,
+			statements: []string{"BEGIN", "SELECT pg_notify($1, $2)", "ROLLBACK"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conn := &recordingConn{}
+			db := sqlx.NewDb(sql.OpenDB(conn), "pgx")
+			t.Cleanup(func() { db.Close() })
+
+			// The notifier is handed a pool of its own, the transaction is
+			// taken from the context.
+			notifier := repositories.NewStatusChangeNotifier(&recordingExecer{}, logger)
+
+			err := repositories.NewTransactor(db, logger).WithinTransaction(context.Background(),
+				func(ctx context.Context) error {
+					notifier.Publish(ctx, change)
+					return tt.writeErr
+				})
+
+			assert.ErrorIs(t, err, tt.writeErr)
+			assert.Equal(t, tt.statements, conn.statements)
+		})
+	}
+}
+
+// recordingConn is a database connection recording the statements run on it.
+type recordingConn struct {
+	statements []string
+}
+
+func (c *recordingConn) Connect(context.Context) (driver.Conn, error) { return c, nil }
+
+func (c *recordingConn) Driver() driver.Driver { return nil }
+
+func (c *recordingConn) Prepare(string) (driver.Stmt, error) {
+	return nil, errors.New("prepared statements are not supported")
+}
+
+func (c *recordingConn) Close() error { return nil }
+
+func (c *recordingConn) Begin() (driver.Tx, error) {
+	c.statements = append(c.statements, "BEGIN")
+
+	return c, nil
+}
+
+func (c *recordingConn) Commit() error {
+	c.statements = append(c.statements, "COMMIT")
+
+	return nil
+}
+
+func (c *recordingConn) Rollback() error {
+	c.statements = append(c.statements, "ROLLBACK")
+
+	return nil
+}
+
+func (c *recordingConn) ExecContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Result, error) {
+	c.statements = append(c.statements, query)
+
+	return driver.RowsAffected(1), nil
+}
@@ -0,0 +1,74 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+
+	appRepo "github.com/k6zma/DockerMonitoringApp/backend/internal/application/repositories"
+	"github.com/k6zma/DockerMonitoringApp/backend/pkg/utils"
+)
+
+type txKey struct{}
+
+type TransactorImpl struct {
+	db     *sqlx.DB
+	logger utils.LoggerInterface
+}
+
+func NewTransactor(
+	db *sqlx.DB,
+	logger utils.LoggerInterface,
+) appRepo.Transactor {
+	return &TransactorImpl{
+		db:     db,
+		logger: logger,
+	}
+}
+
+func (t *TransactorImpl) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) (err error) {
+	if _, ok := txFromContext(ctx); ok {
+		return fn(ctx)
+	}
+
+	tx, err := t.db.BeginTxx(ctx, nil)
+	if err != nil {
+		t.logger.Errorf("REPOSITORIES: failed to begin transaction: %v", err)
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	if err = fn(context.WithValue(ctx, txKey{}, tx)); err != nil {
+		return err
+	}
+
+	// A statement that failed in fn aborts the transaction, the commit then
+	// fails as well.
+	if err = tx.Commit(); err != nil {
+		t.logger.Errorf("REPOSITORIES: failed to commit transaction: %v", err)
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// txFromContext returns the transaction ctx runs in, if any.
+func txFromContext(ctx context.Context) (*sqlx.Tx, bool) {
+	tx, ok := ctx.Value(txKey{}).(*sqlx.Tx)
+
+	return tx, ok
+}
+
+// executor returns the transaction ctx runs in, or db outside of one.
+func executor(ctx context.Context, db sqlx.ExtContext) sqlx.ExtContext {
+	if tx, ok := txFromContext(ctx); ok {
+		return tx
+	}
+
+	return db
+}
@@ -10,6 +10,7 @@ import (
 type AppFlags struct {
 	ConfigFilePath string `validate:"required,file"`
 	LoggerLevel    string `validate:"oneof=debug info warn error dpanic panic fatal"`
+	LoggerFormat   string `validate:"oneof=console json"`
 }
 
 func ParseFlags() (*AppFlags, error) {
@@ -19,12 +20,18 @@ func ParseFlags() (*AppFlags, error) {
 		"debug",
 		"Logger level (debug, info, warn, error, dpanic, panic, fatal)",
 	)
+	loggerFormat := flag.String(
+		"logger_format",
+		"console",
+		"Logger format (console, json)",
+	)
 
 	flag.Parse()
 
 	appFlags := &AppFlags{
 		ConfigFilePath: *configFile,
 		LoggerLevel:    *loggerLevel,
+		LoggerFormat:   *loggerFormat,
 	}
 
 	validate := validator.New()
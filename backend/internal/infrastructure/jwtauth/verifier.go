@@ -0,0 +1,188 @@
+package jwtauth
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/MicahParks/keyfunc/v3"
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/k6zma/DockerMonitoringApp/backend/internal/application/usecases"
+	"github.com/k6zma/DockerMonitoringApp/backend/internal/domain"
+	"github.com/k6zma/DockerMonitoringApp/backend/internal/infrastructure/config"
+	"github.com/k6zma/DockerMonitoringApp/backend/pkg/utils"
+)
+
+const defaultNameClaim = "sub"
+
+// Verifier validates JWTs against the keys published at a JWKS URL and a set of
+// static keys, checks their issuer, audience and lifetime and maps the values
+// of the roles claim to roles.
+type Verifier struct {
+	jwks       keyfunc.Keyfunc
+	staticKeys map[string]interface{}
+	parser     *jwt.Parser
+	nameClaim  string
+	rolesClaim []string
+	roles      map[string]domain.Role
+	logger     utils.LoggerInterface
+}
+
+var _ usecases.TokenVerifier = (*Verifier)(nil)
+
+// NewVerifier builds a verifier from cfg. The JWKS is fetched in the background
+// and refreshed until ctx is done; an unreachable JWKS URL does not fail
+// startup, tokens are rejected until the keys can be loaded.
+func NewVerifier(ctx context.Context, cfg *config.JWTConfig, logger utils.LoggerInterface) (*Verifier, error) {
+	if cfg.JWKSURL == "" && len(cfg.StaticKeys) == 0 {
+		return nil, errors.New("either jwks_url or static_keys must be configured")
+	}
+
+	v := &Verifier{
+		staticKeys: make(map[string]interface{}, len(cfg.StaticKeys)),
+		parser: jwt.NewParser(
+			jwt.WithValidMethods(cfg.Algorithms),
+			jwt.WithIssuer(cfg.Issuer),
+			jwt.WithAudience(cfg.Audience),
+			jwt.WithLeeway(cfg.Leeway),
+			jwt.WithExpirationRequired(),
+		),
+		nameClaim:  cfg.NameClaim,
+		rolesClaim: strings.Split(cfg.RolesClaim, "."),
+		roles:      make(map[string]domain.Role, len(cfg.RoleMappings)),
+		logger:     logger,
+	}
+
+	if v.nameClaim == "" {
+		v.nameClaim = defaultNameClaim
+	}
+
+	for _, mapping := range cfg.RoleMappings {
+		v.roles[mapping.Claim] = domain.Role(mapping.Role)
+	}
+
+	for _, key := range cfg.StaticKeys {
+		verificationKey, err := loadStaticKey(key)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load static key %s: %w", key.KeyID, err)
+		}
+		v.staticKeys[key.KeyID] = verificationKey
+	}
+
+	if cfg.JWKSURL != "" {
+		jwks, err := keyfunc.NewDefaultOverrideCtx(ctx, []string{cfg.JWKSURL}, keyfunc.Override{
+			RefreshInterval: cfg.JWKSRefreshInterval,
+			RefreshErrorHandlerFunc: func(u string) func(ctx context.Context, err error) {
+				return func(_ context.Context, err error) {
+					logger.Errorf("JWT: failed to refresh JWKS from %s: %v", u, err)
+				}
+			},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to set up JWKS: %w", err)
+		}
+		v.jwks = jwks
+	}
+
+	return v, nil
+}
+
+func (v *Verifier) Verify(ctx context.Context, token string) (*domain.Principal, error) {
+	claims := jwt.MapClaims{}
+	if _, err := v.parser.ParseWithClaims(token, claims, v.keyfunc(ctx)); err != nil {
+		utils.ContextLogger(ctx, v.logger).Warnf("JWT: rejected token: %v", err)
+		return nil, fmt.Errorf("%w: %v", usecases.ErrInvalidToken, err)
+	}
+
+	name, _ := claims[v.nameClaim].(string)
+	if name == "" {
+		name, _ = claims[defaultNameClaim].(string)
+	}
+	if name == "" {
+		return nil, fmt.Errorf("%w: token has no %s claim", usecases.ErrInvalidToken, v.nameClaim)
+	}
+
+	principal := &domain.Principal{
+		Name:   name,
+		Method: domain.AuthMethodJWT,
+	}
+	for _, value := range v.roleClaimValues(claims) {
+		if role, ok := v.roles[value]; ok {
+			principal.AddRole(role)
+		}
+	}
+
+	return principal, nil
+}
+
+// keyfunc picks a static key by the kid header and falls back to the JWKS.
+func (v *Verifier) keyfunc(ctx context.Context) jwt.Keyfunc {
+	return func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		if key, ok := v.staticKeys[kid]; ok {
+			return key, nil
+		}
+
+		if v.jwks == nil {
+			return nil, fmt.Errorf("unknown key ID %q", kid)
+		}
+
+		return v.jwks.KeyfuncCtx(ctx)(token)
+	}
+}
+
+// roleClaimValues returns the values found under the configured claim path.
+// The claim may hold a list of values or a space separated string.
+func (v *Verifier) roleClaimValues(claims jwt.MapClaims) []string {
+	var value interface{} = map[string]interface{}(claims)
+	for _, part := range v.rolesClaim {
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		value = object[part]
+	}
+
+	switch typed := value.(type) {
+	case string:
+		return strings.Fields(typed)
+	case []interface{}:
+		values := make([]string, 0, len(typed))
+		for _, item := range typed {
+			if item, ok := item.(string); ok {
+				values = append(values, item)
+			}
+		}
+		return values
+	default:
+		return nil
+	}
+}
+
+func loadStaticKey(cfg config.JWTKeyConfig) (interface{}, error) {
+	if cfg.Secret != "" {
+		return []byte(cfg.Secret), nil
+	}
+
+	data, err := os.ReadFile(cfg.PublicKeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read public key: %w", err)
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("public key file does not contain a PEM block")
+	}
+
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse public key: %w", err)
+	}
+
+	return key, nil
+}
@@ -0,0 +1,178 @@
+package jwtauth_test
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/k6zma/DockerMonitoringApp/backend/internal/application/usecases"
+	"github.com/k6zma/DockerMonitoringApp/backend/internal/domain"
+	"github.com/k6zma/DockerMonitoringApp/backend/internal/infrastructure/config"
+	"github.com/k6zma/DockerMonitoringApp/backend/internal/infrastructure/jwtauth"
+	"github.com/k6zma/DockerMonitoringApp/backend/mocks"
+)
+
+const (
+	testIssuer   = "https://sso.example.com/realms/monitoring"
+	testAudience = "docker-monitoring"
+	testSecret   = "0123456789abcdef0123456789abcdef"
+)
+
+func newTestConfig() *config.JWTConfig {
+	return &config.JWTConfig{
+		Enabled:    true,
+		StaticKeys: []config.JWTKeyConfig{{KeyID: "static", Secret: testSecret}},
+		Algorithms: []string{"HS256", "RS256"},
+		Issuer:     testIssuer,
+		Audience:   testAudience,
+		NameClaim:  "preferred_username",
+		RolesClaim: "realm_access.roles",
+		RoleMappings: []config.JWTRoleMapping{
+			{Claim: "monitoring-viewer", Role: "viewer"},
+			{Claim: "monitoring-operator", Role: "operator"},
+		},
+	}
+}
+
+func validClaims() jwt.MapClaims {
+	return jwt.MapClaims{
+		"iss":                testIssuer,
+		"aud":                testAudience,
+		"sub":                "5f0e2b9c",
+		"preferred_username": "alice",
+		"exp":                time.Now().Add(time.Hour).Unix(),
+		"realm_access":       map[string]interface{}{"roles": []string{"monitoring-viewer", "offline_access"}},
+	}
+}
+
+func sign(t *testing.T, method jwt.SigningMethod, kid string, key interface{}, claims jwt.MapClaims) string {
+	t.Helper()
+
+	token := jwt.NewWithClaims(method, claims)
+	token.Header["kid"] = kid
+
+	signed, err := token.SignedString(key)
+	require.NoError(t, err)
+
+	return signed
+}
+
+func TestVerify_StaticKey_MapsClaimsToRoles(t *testing.T) {
+	verifier, err := jwtauth.NewVerifier(context.Background(), newTestConfig(), new(mocks.LoggerInterface))
+	require.NoError(t, err)
+
+	principal, err := verifier.Verify(context.Background(),
+		sign(t, jwt.SigningMethodHS256, "static", []byte(testSecret), validClaims()))
+
+	require.NoError(t, err)
+	assert.Equal(t, "alice", principal.Name)
+	assert.Equal(t, domain.AuthMethodJWT, principal.Method)
+	assert.Equal(t, []domain.Role{domain.RoleViewer}, principal.Roles)
+	assert.False(t, principal.HasScope(domain.ScopeStatusWrite))
+}
+
+func TestVerify_RejectsInvalidTokens(t *testing.T) {
+	mockLogger := new(mocks.LoggerInterface)
+	mockLogger.On("Warnf", mock.Anything, mock.Anything).Return()
+
+	verifier, err := jwtauth.NewVerifier(context.Background(), newTestConfig(), mockLogger)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name   string
+		modify func(claims jwt.MapClaims)
+		method jwt.SigningMethod
+		kid    string
+		key    interface{}
+	}{
+		{name: "wrong issuer", modify: func(c jwt.MapClaims) { c["iss"] = "https://evil.example.com" }},
+		{name: "wrong audience", modify: func(c jwt.MapClaims) { c["aud"] = "another-service" }},
+		{name: "expired", modify: func(c jwt.MapClaims) { c["exp"] = time.Now().Add(-time.Hour).Unix() }},
+		{name: "no expiry", modify: func(c jwt.MapClaims) { delete(c, "exp") }},
+		{name: "wrong secret", key: []byte("another-secret-another-secret-00")},
+		{name: "unknown key ID", kid: "unknown"},
+		{name: "algorithm not allowed", method: jwt.SigningMethodHS512},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims := validClaims()
+			if tt.modify != nil {
+				tt.modify(claims)
+			}
+
+			method, kid, key := tt.method, tt.kid, tt.key
+			if method == nil {
+				method = jwt.SigningMethodHS256
+			}
+			if kid == "" {
+				kid = "static"
+			}
+			if key == nil {
+				key = []byte(testSecret)
+			}
+
+			_, err := verifier.Verify(context.Background(), sign(t, method, kid, key, claims))
+
+			assert.ErrorIs(t, err, usecases.ErrInvalidToken)
+		})
+	}
+}
+
+func TestVerify_JWKS(t *testing.T) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	jwksServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"keys": []map[string]string{{
+				"kty": "RSA",
+				"kid": "remote",
+				"alg": "RS256",
+				"use": "sig",
+				"n":   base64.RawURLEncoding.EncodeToString(privateKey.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(privateKey.E)).Bytes()),
+			}},
+		})
+	}))
+	defer jwksServer.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	cfg := newTestConfig()
+	cfg.JWKSURL = jwksServer.URL
+
+	verifier, err := jwtauth.NewVerifier(ctx, cfg, new(mocks.LoggerInterface))
+	require.NoError(t, err)
+
+	claims := validClaims()
+	claims["realm_access"] = map[string]interface{}{"roles": []string{"monitoring-operator"}}
+
+	principal, err := verifier.Verify(ctx, sign(t, jwt.SigningMethodRS256, "remote", privateKey, claims))
+
+	require.NoError(t, err)
+	assert.True(t, principal.HasScope(domain.ScopeStatusWrite))
+}
+
+func TestNewVerifier_RequiresKeys(t *testing.T) {
+	cfg := newTestConfig()
+	cfg.StaticKeys = nil
+
+	_, err := jwtauth.NewVerifier(context.Background(), cfg, new(mocks.LoggerInterface))
+
+	assert.Error(t, err)
+}
@@ -3,9 +3,11 @@ package migrations
 import (
 	"errors"
 	"fmt"
+	"os"
 
 	"github.com/golang-migrate/migrate/v4"
 	"github.com/golang-migrate/migrate/v4/database/pgx"
+	"github.com/golang-migrate/migrate/v4/source"
 	_ "github.com/golang-migrate/migrate/v4/source/file" // import for side effects
 	"github.com/jmoiron/sqlx"
 
@@ -123,3 +125,37 @@ func (m *Migrations) DropMigrations() error {
 
 	return nil
 }
+
+// LatestVersion returns the version of the newest migration in the migrations
+// folder, or zero if there are none.
+func (m *Migrations) LatestVersion() (uint, error) {
+	m.logger.Debugf("MIGRATIONS: reading latest migration version from path: %s", m.migrationsPath)
+
+	src, err := source.Open("file://" + m.migrationsPath)
+	if err != nil {
+		m.logger.Errorf("MIGRATIONS: failed to open migrations source: %v", err)
+		return 0, fmt.Errorf("failed to open migrations source: %w", err)
+	}
+	defer src.Close()
+
+	version, err := src.First()
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		m.logger.Errorf("MIGRATIONS: failed to read first migration: %v", err)
+		return 0, fmt.Errorf("failed to read first migration: %w", err)
+	}
+
+	for {
+		next, err := src.Next(version)
+		if errors.Is(err, os.ErrNotExist) {
+			return version, nil
+		}
+		if err != nil {
+			m.logger.Errorf("MIGRATIONS: failed to read migration after version %d: %v", version, err)
+			return 0, fmt.Errorf("failed to read migration after version %d: %w", version, err)
+		}
+		version = next
+	}
+}
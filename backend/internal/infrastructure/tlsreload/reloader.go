@@ -0,0 +1,179 @@
+package tlsreload
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+
+	"github.com/fsnotify/fsnotify"
+
+	"github.com/k6zma/DockerMonitoringApp/backend/internal/infrastructure/config"
+	"github.com/k6zma/DockerMonitoringApp/backend/pkg/utils"
+)
+
+// Reloader serves the certificate and client CA bundle loaded from the files
+// in the TLS configuration and reloads them when the files change, so renewed
+// certificates are picked up without a restart.
+type Reloader struct {
+	certFile     string
+	keyFile      string
+	clientCAFile string
+
+	mu        sync.RWMutex
+	contents  [][]byte
+	cert      *tls.Certificate
+	clientCAs *x509.CertPool
+
+	logger utils.LoggerInterface
+}
+
+// NewReloader loads the files of cfg and watches their directories until ctx
+// is done. A change that leaves the files unreadable or mismatched is logged
+// and the previous certificate stays in use.
+func NewReloader(ctx context.Context, cfg *config.TLSConfig, logger utils.LoggerInterface) (*Reloader, error) {
+	r := &Reloader{
+		certFile:     cfg.CertFile,
+		keyFile:      cfg.KeyFile,
+		clientCAFile: cfg.ClientCAFile,
+		logger:       logger,
+	}
+
+	if err := r.reload(); err != nil {
+		return nil, err
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create file watcher: %w", err)
+	}
+
+	// Directories are watched instead of the files, so files replaced by a
+	// rename or a symlink swap are noticed as well.
+	for _, dir := range r.dirs() {
+		if err := watcher.Add(dir); err != nil {
+			_ = watcher.Close()
+			return nil, fmt.Errorf("failed to watch %s: %w", dir, err)
+		}
+	}
+
+	go r.watch(ctx, watcher)
+
+	return r, nil
+}
+
+// ServerConfig returns a TLS configuration that always presents the latest
+// certificate. With a client CA bundle, client certificates are requested and
+// verified when presented; routes that require one check for it themselves.
+func (r *Reloader) ServerConfig() *tls.Config {
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			r.mu.RLock()
+			defer r.mu.RUnlock()
+
+			cfg := &tls.Config{
+				MinVersion:   tls.VersionTLS12,
+				Certificates: []tls.Certificate{*r.cert},
+				NextProtos:   []string{"h2", "http/1.1"},
+			}
+			if r.clientCAs != nil {
+				cfg.ClientAuth = tls.VerifyClientCertIfGiven
+				cfg.ClientCAs = r.clientCAs
+			}
+
+			return cfg, nil
+		},
+	}
+}
+
+func (r *Reloader) watch(ctx context.Context, watcher *fsnotify.Watcher) {
+	defer watcher.Close()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return
+			}
+			if event.Op == fsnotify.Chmod {
+				continue
+			}
+
+			if err := r.reload(); err != nil {
+				r.logger.Errorf("TLS: failed to reload certificates after change of %s: %v", event.Name, err)
+			}
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return
+			}
+			r.logger.Errorf("TLS: file watcher error: %v", err)
+		}
+	}
+}
+
+// reload reads the files and replaces the certificate and the CA bundle if
+// their contents changed.
+func (r *Reloader) reload() error {
+	files := []string{r.certFile, r.keyFile}
+	if r.clientCAFile != "" {
+		files = append(files, r.clientCAFile)
+	}
+
+	contents := make([][]byte, 0, len(files))
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", file, err)
+		}
+		contents = append(contents, data)
+	}
+
+	r.mu.RLock()
+	unchanged := slices.EqualFunc(contents, r.contents, bytes.Equal)
+	r.mu.RUnlock()
+	if unchanged {
+		return nil
+	}
+
+	cert, err := tls.X509KeyPair(contents[0], contents[1])
+	if err != nil {
+		return fmt.Errorf("failed to load key pair from %s and %s: %w", r.certFile, r.keyFile, err)
+	}
+
+	var clientCAs *x509.CertPool
+	if r.clientCAFile != "" {
+		clientCAs = x509.NewCertPool()
+		if !clientCAs.AppendCertsFromPEM(contents[2]) {
+			return errors.New("no certificates found in " + r.clientCAFile)
+		}
+	}
+
+	r.mu.Lock()
+	r.contents = contents
+	r.cert = &cert
+	r.clientCAs = clientCAs
+	r.mu.Unlock()
+
+	r.logger.Infof("TLS: loaded certificate for %s valid until %s", cert.Leaf.Subject, cert.Leaf.NotAfter)
+
+	return nil
+}
+
+func (r *Reloader) dirs() []string {
+	dirs := []string{filepath.Dir(r.certFile), filepath.Dir(r.keyFile)}
+	if r.clientCAFile != "" {
+		dirs = append(dirs, filepath.Dir(r.clientCAFile))
+	}
+	slices.Sort(dirs)
+
+	return slices.Compact(dirs)
+}
@@ -0,0 +1,226 @@
+package tlsreload_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/k6zma/DockerMonitoringApp/backend/internal/infrastructure/config"
+	"github.com/k6zma/DockerMonitoringApp/backend/internal/infrastructure/tlsreload"
+	"github.com/k6zma/DockerMonitoringApp/backend/mocks"
+)
+
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pem  []byte
+}
+
+func newTestCA(t *testing.T) *testCA {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	return &testCA{cert: cert, key: key, pem: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})}
+}
+
+// issue returns a PEM encoded certificate and key for name signed by the CA.
+func (ca *testCA) issue(t *testing.T, serial int64, name string, usage x509.ExtKeyUsage) ([]byte, []byte) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: name},
+		DNSNames:     []string{name},
+		IPAddresses:  []net.IP{net.IPv4(127, 0, 0, 1)},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, &key.PublicKey, ca.key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
+
+func writeFile(t *testing.T, path string, data []byte) {
+	t.Helper()
+
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+}
+
+func newLogger() *mocks.LoggerInterface {
+	logger := new(mocks.LoggerInterface)
+	logger.On("Infof", mock.Anything, mock.Anything, mock.Anything).Return()
+	logger.On("Errorf", mock.Anything, mock.Anything, mock.Anything).Return()
+
+	return logger
+}
+
+func servedSerial(t *testing.T, cfg *tls.Config) int64 {
+	t.Helper()
+
+	served, err := cfg.GetConfigForClient(&tls.ClientHelloInfo{})
+	require.NoError(t, err)
+
+	return served.Certificates[0].Leaf.SerialNumber.Int64()
+}
+
+func TestReloader_ReloadsChangedCertificate(t *testing.T) {
+	ca := newTestCA(t)
+	dir := t.TempDir()
+	cfg := &config.TLSConfig{
+		Enabled:  true,
+		CertFile: filepath.Join(dir, "tls.crt"),
+		KeyFile:  filepath.Join(dir, "tls.key"),
+	}
+
+	certPEM, keyPEM := ca.issue(t, 2, "backend", x509.ExtKeyUsageServerAuth)
+	writeFile(t, cfg.CertFile, certPEM)
+	writeFile(t, cfg.KeyFile, keyPEM)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	reloader, err := tlsreload.NewReloader(ctx, cfg, newLogger())
+	require.NoError(t, err)
+	serverConfig := reloader.ServerConfig()
+
+	assert.Equal(t, int64(2), servedSerial(t, serverConfig))
+
+	// A certificate without its key cannot be loaded; the old pair stays in use.
+	certPEM, keyPEM = ca.issue(t, 3, "backend", x509.ExtKeyUsageServerAuth)
+	writeFile(t, cfg.CertFile, certPEM)
+	time.Sleep(100 * time.Millisecond)
+	assert.Equal(t, int64(2), servedSerial(t, serverConfig))
+
+	writeFile(t, cfg.KeyFile, keyPEM)
+	assert.Eventually(t, func() bool {
+		return servedSerial(t, serverConfig) == 3
+	}, 2*time.Second, 10*time.Millisecond)
+}
+
+func TestReloader_VerifiesClientCertificates(t *testing.T) {
+	ca := newTestCA(t)
+	dir := t.TempDir()
+	cfg := &config.TLSConfig{
+		Enabled:      true,
+		CertFile:     filepath.Join(dir, "tls.crt"),
+		KeyFile:      filepath.Join(dir, "tls.key"),
+		ClientCAFile: filepath.Join(dir, "ca.crt"),
+	}
+
+	certPEM, keyPEM := ca.issue(t, 2, "backend", x509.ExtKeyUsageServerAuth)
+	writeFile(t, cfg.CertFile, certPEM)
+	writeFile(t, cfg.KeyFile, keyPEM)
+	writeFile(t, cfg.ClientCAFile, ca.pem)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	reloader, err := tlsreload.NewReloader(ctx, cfg, newLogger())
+	require.NoError(t, err)
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if len(r.TLS.VerifiedChains) > 0 {
+			_, _ = w.Write([]byte(r.TLS.VerifiedChains[0][0].Subject.CommonName))
+		}
+	}))
+	server.TLS = reloader.ServerConfig()
+	server.StartTLS()
+	defer server.Close()
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.cert)
+
+	clientCertPEM, clientKeyPEM := ca.issue(t, 4, "pinger", x509.ExtKeyUsageClientAuth)
+	clientCert, err := tls.X509KeyPair(clientCertPEM, clientKeyPEM)
+	require.NoError(t, err)
+
+	otherCA := newTestCA(t)
+	otherCertPEM, otherKeyPEM := otherCA.issue(t, 5, "intruder", x509.ExtKeyUsageClientAuth)
+	otherCert, err := tls.X509KeyPair(otherCertPEM, otherKeyPEM)
+	require.NoError(t, err)
+
+	get := func(certificates []tls.Certificate) (string, error) {
+		client := &http.Client{Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{RootCAs: roots, Certificates: certificates, MinVersion: tls.VersionTLS12},
+		}}
+
+		resp, err := client.Get(server.URL)
+		if err != nil {
+			return "", err
+		}
+		defer resp.Body.Close()
+
+		buf := make([]byte, 64)
+		n, _ := resp.Body.Read(buf)
+
+		return string(buf[:n]), nil
+	}
+
+	name, err := get([]tls.Certificate{clientCert})
+	require.NoError(t, err)
+	assert.Equal(t, "pinger", name)
+
+	name, err = get(nil)
+	require.NoError(t, err)
+	assert.Empty(t, name)
+
+	_, err = get([]tls.Certificate{otherCert})
+	assert.Error(t, err)
+}
+
+func TestNewReloader_InvalidFiles(t *testing.T) {
+	dir := t.TempDir()
+	cfg := &config.TLSConfig{
+		Enabled:  true,
+		CertFile: filepath.Join(dir, "tls.crt"),
+		KeyFile:  filepath.Join(dir, "tls.key"),
+	}
+	writeFile(t, cfg.CertFile, []byte("not a certificate"))
+	writeFile(t, cfg.KeyFile, []byte("not a key"))
+
+	_, err := tlsreload.NewReloader(context.Background(), cfg, newLogger())
+
+	assert.Error(t, err)
+}
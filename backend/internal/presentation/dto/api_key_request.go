@@ -0,0 +1,18 @@
+package dto
+
+import "time"
+
+// CreateAPIKeyRequest describes a new key. AuthMode is api_key (default) to
+// send the key in the X-Api-Key header or hmac to sign every request with it.
+type CreateAPIKeyRequest struct {
+	Name      string     `json:"name" validate:"required,max=255"`
+	Scopes    []string   `json:"scopes" validate:"required,min=1,unique,dive,oneof=status:read status:write admin"`
+	AuthMode  string     `json:"auth_mode,omitempty" validate:"omitempty,oneof=api_key hmac" example:"hmac"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+type RotateAPIKeyRequest struct {
+	// GracePeriod is how long the old key stays valid, as a Go duration such as
+	// "1h30m". Defaults to 24h.
+	GracePeriod string `json:"grace_period,omitempty" example:"24h"`
+}
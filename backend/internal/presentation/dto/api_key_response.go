@@ -0,0 +1,22 @@
+package dto
+
+import "time"
+
+type GetAPIKeyResponse struct {
+	ID         int64      `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Scopes     []string   `json:"scopes"`
+	AuthMode   string     `json:"auth_mode"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+	RevokedAt  *time.Time `json:"revoked_at"`
+}
+
+// IssuedAPIKeyResponse is returned once when a key is created or rotated; the
+// key cannot be retrieved afterwards.
+type IssuedAPIKeyResponse struct {
+	GetAPIKeyResponse
+	Key string `json:"key"`
+}
@@ -0,0 +1,22 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type GetAuditEntryResponse struct {
+	ID          int64           `json:"id"`
+	Actor       string          `json:"actor"`
+	AuthMethod  string          `json:"auth_method"`
+	ClientIP    string          `json:"client_ip"`
+	Method      string          `json:"method"`
+	Route       string          `json:"route"`
+	Path        string          `json:"path"`
+	ContainerID string          `json:"container_id"`
+	StatusCode  int             `json:"status_code"`
+	Before      json.RawMessage `json:"before" swaggertype:"object"`
+	After       json.RawMessage `json:"after" swaggertype:"object"`
+	RequestID   string          `json:"request_id"`
+	CreatedAt   time.Time       `json:"created_at"`
+}
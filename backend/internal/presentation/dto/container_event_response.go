@@ -0,0 +1,12 @@
+package dto
+
+import "time"
+
+type GetContainerEventResponse struct {
+	ID          int64     `json:"id"`
+	ContainerID string    `json:"container_id"`
+	Type        string    `json:"type"`
+	Status      string    `json:"status"`
+	Message     string    `json:"message"`
+	CreatedAt   time.Time `json:"created_at"`
+}
@@ -3,17 +3,34 @@ package dto
 import "time"
 
 type CreateContainerStatusRequest struct {
-	ContainerID        string    `json:"container_id" validate:"required"`
-	IPAddress          string    `json:"ip_address" validate:"required,ip"`
-	Name               string    `json:"name"`
-	Status             string    `json:"status" validate:"required,oneof=created restarting running removing paused exited dead"`
-	PingTime           float64   `json:"ping_time"`
-	LastSuccessfulPing time.Time `json:"last_successful_ping" validate:"required"`
+	ContainerID        string            `json:"container_id" validate:"required"`
+	IPAddress          string            `json:"ip_address" validate:"required,ip"`
+	Name               string            `json:"name"`
+	Status             string            `json:"status" validate:"required,oneof=created restarting running removing paused exited dead"`
+	PingTime           float64           `json:"ping_time"`
+	LastSuccessfulPing time.Time         `json:"last_successful_ping" validate:"required"`
+	RestartCount       int               `json:"restart_count" validate:"gte=0"`
+	Labels             map[string]string `json:"labels"`
+	CheckedAt          time.Time         `json:"checked_at,omitempty"`
 }
 
+// UpdateContainerStatusRequest holds the fields to change, omitted fields keep
+// their stored value. Labels replace all stored labels when present.
 type UpdateContainerStatusRequest struct {
-	Name               string    `json:"name"`
-	Status             string    `json:"status" validate:"omitempty,oneof=created restarting running removing paused exited dead"`
-	PingTime           float64   `json:"ping_time"`
-	LastSuccessfulPing time.Time `json:"last_successful_ping,omitempty"`
+	Name               string            `json:"name"`
+	Status             string            `json:"status" validate:"omitempty,oneof=created restarting running removing paused exited dead"`
+	PingTime           float64           `json:"ping_time"`
+	LastSuccessfulPing time.Time         `json:"last_successful_ping,omitempty"`
+	RestartCount       *int              `json:"restart_count,omitempty" validate:"omitempty,gte=0"`
+	Labels             map[string]string `json:"labels,omitempty"`
+	CheckedAt          time.Time         `json:"checked_at,omitempty"`
+}
+
+// StatusStreamRequest is a message a status stream client sends to replace the
+// filter of its connection with the one given.
+type StatusStreamRequest struct {
+	Action   string            `json:"action" validate:"required,oneof=subscribe"`
+	Names    []string          `json:"names"`
+	Statuses []string          `json:"statuses"`
+	Labels   map[string]string `json:"labels"`
 }
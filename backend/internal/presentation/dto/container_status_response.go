@@ -3,14 +3,35 @@ package dto
 import "time"
 
 type GetContainerStatusResponse struct {
-	ContainerID        string    `json:"container_id"`
-	Name               string    `json:"name"`
-	IPAddress          string    `json:"ip_address"`
-	Status             string    `json:"status"`
-	PingTime           float64   `json:"ping_time"`
-	LastSuccessfulPing time.Time `json:"last_successful_ping"`
-	CreatedAt          time.Time `json:"created_at"`
-	UpdatedAt          time.Time `json:"updated_at"`
+	ContainerID        string            `json:"container_id"`
+	Name               string            `json:"name"`
+	IPAddress          string            `json:"ip_address"`
+	Status             string            `json:"status"`
+	PingTime           float64           `json:"ping_time"`
+	LastSuccessfulPing time.Time         `json:"last_successful_ping"`
+	RestartCount       int               `json:"restart_count"`
+	Conditions         []string          `json:"conditions"`
+	Labels             map[string]string `json:"labels"`
+	CreatedAt          time.Time         `json:"created_at"`
+	UpdatedAt          time.Time         `json:"updated_at"`
+}
+
+// StatusChangeMessage is sent to status stream clients for every change of a
+// container matching their filter. Container holds the state after the change,
+// or the last known state when it was deleted.
+type StatusChangeMessage struct {
+	Type       string                     `json:"type" enums:"created,updated,deleted"`
+	Container  GetContainerStatusResponse `json:"container"`
+	OccurredAt time.Time                  `json:"occurred_at"`
+}
+
+// StatusAlertMessage is sent to event stream clients for every crash-loop event
+// raised for a container matching their filter. Container holds the state the
+// event was raised for.
+type StatusAlertMessage struct {
+	Event      GetContainerEventResponse  `json:"event"`
+	Container  GetContainerStatusResponse `json:"container"`
+	OccurredAt time.Time                  `json:"occurred_at"`
 }
 
 type DeleteContainerStatusResponse struct {
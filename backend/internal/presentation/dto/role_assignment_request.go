@@ -0,0 +1,5 @@
+package dto
+
+type AssignRoleRequest struct {
+	Role string `json:"role" validate:"required,oneof=viewer operator admin" example:"operator"`
+}
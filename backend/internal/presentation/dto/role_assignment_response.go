@@ -0,0 +1,10 @@
+package dto
+
+import "time"
+
+type GetRoleAssignmentResponse struct {
+	Subject   string    `json:"subject"`
+	Role      string    `json:"role"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
@@ -0,0 +1,230 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gorilla/mux"
+
+	"github.com/k6zma/DockerMonitoringApp/backend/internal/application/usecases"
+	pdto "github.com/k6zma/DockerMonitoringApp/backend/internal/presentation/dto"
+	"github.com/k6zma/DockerMonitoringApp/backend/internal/presentation/mapper"
+	"github.com/k6zma/DockerMonitoringApp/backend/pkg/utils"
+)
+
+type APIKeyHandler struct {
+	useCase  usecases.APIKeyUseCaseInterface
+	validate *validator.Validate
+	logger   utils.LoggerInterface
+}
+
+func NewAPIKeyHandler(
+	useCase usecases.APIKeyUseCaseInterface,
+	logger utils.LoggerInterface,
+) *APIKeyHandler {
+	return &APIKeyHandler{
+		useCase:  useCase,
+		validate: validator.New(),
+		logger:   logger,
+	}
+}
+
+// CreateAPIKey godoc
+// @Summary Create an API key
+// @Description Issues a new API key. The key is only returned in this response; just its digest is stored
+// @Tags API Keys
+// @Accept json
+// @Produce json
+// @Param request body dto.CreateAPIKeyRequest true "Key name, scopes and optional expiry"
+// @Success 201 {object} dto.IssuedAPIKeyResponse
+// @Failure 400 {string} string "Bad Request"
+// @Failure 403 {string} string "Forbidden"
+// @Failure 500 {string} string "Internal Server Error"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api_keys [post].
+func (h *APIKeyHandler) CreateAPIKey(w http.ResponseWriter, r *http.Request) {
+	logger := utils.ContextLogger(r.Context(), h.logger)
+
+	logger.Debugf("HANDLERS: received CreateAPIKey request")
+
+	var req pdto.CreateAPIKeyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Errorf("HANDLERS: createAPIKey decode error: %v", err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		logger.Errorf("HANDLERS: createAPIKey validation error: %v", err)
+		http.Error(w, "Validation error: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		logger.Errorf("HANDLERS: createAPIKey validation error: expires_at %s is in the past", req.ExpiresAt)
+		http.Error(w, "Validation error: expires_at must be in the future", http.StatusBadRequest)
+		return
+	}
+
+	appDTO := mapper.MapCreateAPIKeyRequestToAppDTO(req)
+
+	issued, err := h.useCase.CreateAPIKey(r.Context(), &appDTO)
+	if err != nil {
+		logger.Errorf("HANDLERS: createAPIKey error: %v", err)
+		http.Error(w, "Failed to create API key", http.StatusInternalServerError)
+		return
+	}
+
+	h.writeJSON(w, r, http.StatusCreated, mapper.MapIssuedAPIKeyDTOToResponse(*issued))
+}
+
+// ListAPIKeys godoc
+// @Summary List API keys
+// @Description Returns the API keys stored in the database, newest first. Keys from the configuration are not listed
+// @Tags API Keys
+// @Produce json
+// @Success 200 {array} dto.GetAPIKeyResponse
+// @Failure 403 {string} string "Forbidden"
+// @Failure 500 {string} string "Internal Server Error"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api_keys [get].
+func (h *APIKeyHandler) ListAPIKeys(w http.ResponseWriter, r *http.Request) {
+	logger := utils.ContextLogger(r.Context(), h.logger)
+
+	logger.Debugf("HANDLERS: received ListAPIKeys request")
+
+	keys, err := h.useCase.ListAPIKeys(r.Context())
+	if err != nil {
+		logger.Errorf("HANDLERS: listAPIKeys error: %v", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+
+	h.writeJSON(w, r, http.StatusOK, mapper.MapAPIKeyDTOsToResponse(keys))
+}
+
+// RevokeAPIKey godoc
+// @Summary Revoke an API key
+// @Description Disables an API key immediately
+// @Tags API Keys
+// @Param id path int true "API key ID"
+// @Success 204 "No Content"
+// @Failure 400 {string} string "Bad Request"
+// @Failure 403 {string} string "Forbidden"
+// @Failure 404 {string} string "Not Found"
+// @Failure 500 {string} string "Internal Server Error"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api_keys/{id} [delete].
+func (h *APIKeyHandler) RevokeAPIKey(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.parseID(w, r)
+	if !ok {
+		return
+	}
+	logger := utils.ContextLogger(r.Context(), h.logger, "api_key_id", id)
+
+	logger.Debugf("HANDLERS: received RevokeAPIKey request for id: %d", id)
+
+	err := h.useCase.RevokeAPIKey(r.Context(), id)
+	if errors.Is(err, usecases.ErrAPIKeyNotFound) {
+		logger.Warnf("HANDLERS: API key with id %d not found", id)
+		http.Error(w, "API key not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		logger.Errorf("HANDLERS: failed to revoke API key with id %d: %v", id, err)
+		http.Error(w, "Failed to revoke API key", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// RotateAPIKey godoc
+// @Summary Rotate an API key
+// @Description Issues a replacement with the same name and scopes. The old key stays valid for the grace period, so clients can switch without downtime
+// @Tags API Keys
+// @Accept json
+// @Produce json
+// @Param id path int true "API key ID"
+// @Param request body dto.RotateAPIKeyRequest false "Grace period of the old key"
+// @Success 201 {object} dto.IssuedAPIKeyResponse
+// @Failure 400 {string} string "Bad Request"
+// @Failure 403 {string} string "Forbidden"
+// @Failure 404 {string} string "Not Found"
+// @Failure 409 {string} string "Conflict"
+// @Failure 500 {string} string "Internal Server Error"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api_keys/{id}/rotate [post].
+func (h *APIKeyHandler) RotateAPIKey(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.parseID(w, r)
+	if !ok {
+		return
+	}
+	logger := utils.ContextLogger(r.Context(), h.logger, "api_key_id", id)
+
+	logger.Debugf("HANDLERS: received RotateAPIKey request for id: %d", id)
+
+	var req pdto.RotateAPIKeyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		logger.Errorf("HANDLERS: rotateAPIKey decode error: %v", err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	gracePeriod := usecases.DefaultRotationGracePeriod
+	if req.GracePeriod != "" {
+		var err error
+		gracePeriod, err = time.ParseDuration(req.GracePeriod)
+		if err != nil || gracePeriod < 0 {
+			logger.Errorf("HANDLERS: rotateAPIKey invalid grace_period %q", req.GracePeriod)
+			http.Error(w, "Invalid grace_period", http.StatusBadRequest)
+			return
+		}
+	}
+
+	issued, err := h.useCase.RotateAPIKey(r.Context(), id, gracePeriod)
+	switch {
+	case errors.Is(err, usecases.ErrAPIKeyNotFound):
+		logger.Warnf("HANDLERS: API key with id %d not found", id)
+		http.Error(w, "API key not found", http.StatusNotFound)
+		return
+	case errors.Is(err, usecases.ErrAPIKeyInactive):
+		logger.Warnf("HANDLERS: API key with id %d is expired or revoked", id)
+		http.Error(w, "API key is expired or revoked", http.StatusConflict)
+		return
+	case err != nil:
+		logger.Errorf("HANDLERS: failed to rotate API key with id %d: %v", id, err)
+		http.Error(w, "Failed to rotate API key", http.StatusInternalServerError)
+		return
+	}
+
+	h.writeJSON(w, r, http.StatusCreated, mapper.MapIssuedAPIKeyDTOToResponse(*issued))
+}
+
+func (h *APIKeyHandler) parseID(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	id, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+	if err != nil {
+		utils.ContextLogger(r.Context(), h.logger).Errorf("HANDLERS: error parsing API key id: %v", err)
+		http.Error(w, "Invalid API key id", http.StatusBadRequest)
+		return 0, false
+	}
+
+	return id, true
+}
+
+func (h *APIKeyHandler) writeJSON(w http.ResponseWriter, r *http.Request, status int, response interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		utils.ContextLogger(r.Context(), h.logger).Errorf("HANDLERS: error encoding response: %v", err)
+	}
+}
@@ -0,0 +1,146 @@
+package handlers_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	adto "github.com/k6zma/DockerMonitoringApp/backend/internal/application/dto"
+	"github.com/k6zma/DockerMonitoringApp/backend/internal/application/usecases"
+	pdto "github.com/k6zma/DockerMonitoringApp/backend/internal/presentation/dto"
+	"github.com/k6zma/DockerMonitoringApp/backend/internal/presentation/handlers"
+	"github.com/k6zma/DockerMonitoringApp/backend/mocks"
+)
+
+func TestCreateAPIKey_ReturnsIssuedKey(t *testing.T) {
+	mockUseCase := new(mocks.APIKeyUseCaseInterface)
+	mockLogger := new(mocks.LoggerInterface)
+
+	handler := handlers.NewAPIKeyHandler(mockUseCase, mockLogger)
+
+	issued := &adto.IssuedAPIKeyDTO{
+		APIKeyDTO: adto.APIKeyDTO{ID: 1, Name: "dashboard", Prefix: "dm_abcdefgh", Scopes: []string{"status:read"}},
+		Key:       "dm_abcdefghijklmnop",
+	}
+
+	mockUseCase.On("CreateAPIKey", mock.Anything, &adto.CreateAPIKeyDTO{
+		Name:   "dashboard",
+		Scopes: []string{"status:read"},
+	}).Return(issued, nil)
+	mockLogger.On("Debugf", mock.Anything).Return()
+
+	body, _ := json.Marshal(pdto.CreateAPIKeyRequest{Name: "dashboard", Scopes: []string{"status:read"}})
+	req := httptest.NewRequest(http.MethodPost, "/api_keys", bytes.NewReader(body))
+	rec := httptest.NewRecorder()
+
+	handler.CreateAPIKey(rec, req)
+
+	assert.Equal(t, http.StatusCreated, rec.Code)
+
+	var response pdto.IssuedAPIKeyResponse
+	err := json.NewDecoder(rec.Body).Decode(&response)
+	assert.NoError(t, err)
+	assert.Equal(t, "dm_abcdefghijklmnop", response.Key)
+	assert.Equal(t, "dm_abcdefgh", response.Prefix)
+
+	mockUseCase.AssertExpectations(t)
+}
+
+func TestCreateAPIKey_UnknownScope_ReturnsBadRequest(t *testing.T) {
+	mockUseCase := new(mocks.APIKeyUseCaseInterface)
+	mockLogger := new(mocks.LoggerInterface)
+
+	handler := handlers.NewAPIKeyHandler(mockUseCase, mockLogger)
+
+	mockLogger.On("Debugf", mock.Anything).Return()
+	mockLogger.On("Errorf", mock.Anything, mock.Anything).Return()
+
+	body, _ := json.Marshal(pdto.CreateAPIKeyRequest{Name: "dashboard", Scopes: []string{"everything"}})
+	req := httptest.NewRequest(http.MethodPost, "/api_keys", bytes.NewReader(body))
+	rec := httptest.NewRecorder()
+
+	handler.CreateAPIKey(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	mockUseCase.AssertNotCalled(t, "CreateAPIKey", mock.Anything, mock.Anything)
+}
+
+func TestRevokeAPIKey_NotFound_ReturnsNotFound(t *testing.T) {
+	mockUseCase := new(mocks.APIKeyUseCaseInterface)
+	mockLogger := new(mocks.LoggerInterface)
+
+	handler := handlers.NewAPIKeyHandler(mockUseCase, mockLogger)
+
+	mockUseCase.On("RevokeAPIKey", mock.Anything, int64(42)).Return(usecases.ErrAPIKeyNotFound)
+	mockLogger.On("Debugf", mock.Anything, mock.Anything).Return()
+	mockLogger.On("Warnf", mock.Anything, mock.Anything).Return()
+
+	req := httptest.NewRequest(http.MethodDelete, "/api_keys/42", http.NoBody)
+	req = mux.SetURLVars(req, map[string]string{"id": "42"})
+	rec := httptest.NewRecorder()
+
+	handler.RevokeAPIKey(rec, req)
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	mockUseCase.AssertExpectations(t)
+}
+
+func TestRotateAPIKey_GracePeriod(t *testing.T) {
+	tests := []struct {
+		name        string
+		body        string
+		gracePeriod time.Duration
+	}{
+		{name: "default", body: "", gracePeriod: usecases.DefaultRotationGracePeriod},
+		{name: "explicit", body: `{"grace_period":"1h"}`, gracePeriod: time.Hour},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUseCase := new(mocks.APIKeyUseCaseInterface)
+			mockLogger := new(mocks.LoggerInterface)
+
+			handler := handlers.NewAPIKeyHandler(mockUseCase, mockLogger)
+
+			issued := &adto.IssuedAPIKeyDTO{APIKeyDTO: adto.APIKeyDTO{ID: 8}, Key: "dm_new"}
+
+			mockUseCase.On("RotateAPIKey", mock.Anything, int64(7), tt.gracePeriod).Return(issued, nil)
+			mockLogger.On("Debugf", mock.Anything, mock.Anything).Return()
+
+			req := httptest.NewRequest(http.MethodPost, "/api_keys/7/rotate", bytes.NewBufferString(tt.body))
+			req = mux.SetURLVars(req, map[string]string{"id": "7"})
+			rec := httptest.NewRecorder()
+
+			handler.RotateAPIKey(rec, req)
+
+			assert.Equal(t, http.StatusCreated, rec.Code)
+			mockUseCase.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRotateAPIKey_RevokedKey_ReturnsConflict(t *testing.T) {
+	mockUseCase := new(mocks.APIKeyUseCaseInterface)
+	mockLogger := new(mocks.LoggerInterface)
+
+	handler := handlers.NewAPIKeyHandler(mockUseCase, mockLogger)
+
+	mockUseCase.On("RotateAPIKey", mock.Anything, int64(7), mock.Anything).Return(nil, usecases.ErrAPIKeyInactive)
+	mockLogger.On("Debugf", mock.Anything, mock.Anything).Return()
+	mockLogger.On("Warnf", mock.Anything, mock.Anything).Return()
+
+	req := httptest.NewRequest(http.MethodPost, "/api_keys/7/rotate", http.NoBody)
+	req = mux.SetURLVars(req, map[string]string{"id": "7"})
+	rec := httptest.NewRecorder()
+
+	handler.RotateAPIKey(rec, req)
+
+	assert.Equal(t, http.StatusConflict, rec.Code)
+}
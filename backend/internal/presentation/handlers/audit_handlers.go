@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	adto "github.com/k6zma/DockerMonitoringApp/backend/internal/application/dto"
+	"github.com/k6zma/DockerMonitoringApp/backend/internal/application/usecases"
+	"github.com/k6zma/DockerMonitoringApp/backend/internal/presentation/mapper"
+	"github.com/k6zma/DockerMonitoringApp/backend/pkg/utils"
+)
+
+type AuditHandler struct {
+	useCase usecases.AuditUseCaseInterface
+	logger  utils.LoggerInterface
+}
+
+func NewAuditHandler(
+	useCase usecases.AuditUseCaseInterface,
+	logger utils.LoggerInterface,
+) *AuditHandler {
+	return &AuditHandler{
+		useCase: useCase,
+		logger:  logger,
+	}
+}
+
+// GetAuditEntries godoc
+// @Summary Retrieve the audit log
+// @Description Returns the recorded POST, PUT, PATCH and DELETE calls, newest first
+// @Tags Audit
+// @Produce json
+// @Param actor query string false "Filter by key name or user name"
+// @Param method query string false "Comma separated list of HTTP methods"
+// @Param container_id query string false "Filter by target container ID"
+// @Param created_at_gte query string false "Filter by time of the call (greater than or equal to), format: RFC3339"
+// @Param created_at_lte query string false "Filter by time of the call (less than or equal to), format: RFC3339"
+// @Param limit query int false "Limit the number of returned records"
+// @Success 200 {array} dto.GetAuditEntryResponse
+// @Failure 400 {string} string "Bad Request"
+// @Failure 403 {string} string "Forbidden"
+// @Failure 500 {string} string "Internal Server Error"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /audit [get].
+func (h *AuditHandler) GetAuditEntries(w http.ResponseWriter, r *http.Request) {
+	logger := utils.ContextLogger(r.Context(), h.logger)
+
+	logger.Debugf("HANDLERS: received GetAuditEntries request with query: %s", r.URL.RawQuery)
+
+	queryParams := r.URL.Query()
+	filter := adto.AuditFilter{}
+
+	if actor := queryParams.Get("actor"); actor != "" {
+		filter.Actor = &actor
+	}
+
+	if methods := queryParams.Get("method"); methods != "" {
+		filter.Methods = strings.Split(strings.ToUpper(methods), ",")
+	}
+
+	if containerID := queryParams.Get("container_id"); containerID != "" {
+		filter.ContainerID = &containerID
+	}
+
+	if createdAtGteStr := queryParams.Get("created_at_gte"); createdAtGteStr != "" {
+		createdAtGte, err := time.Parse(time.RFC3339, createdAtGteStr)
+		if err != nil {
+			logger.Errorf("HANDLERS: error parsing created_at_gte param: %v", err)
+			http.Error(w, "Invalid created_at_gte param", http.StatusBadRequest)
+			return
+		}
+		filter.CreatedAtGte = &createdAtGte
+	}
+
+	if createdAtLteStr := queryParams.Get("created_at_lte"); createdAtLteStr != "" {
+		createdAtLte, err := time.Parse(time.RFC3339, createdAtLteStr)
+		if err != nil {
+			logger.Errorf("HANDLERS: error parsing created_at_lte param: %v", err)
+			http.Error(w, "Invalid created_at_lte param", http.StatusBadRequest)
+			return
+		}
+		filter.CreatedAtLte = &createdAtLte
+	}
+
+	if limitStr := queryParams.Get("limit"); limitStr != "" {
+		limit, err := strconv.Atoi(limitStr)
+		if err != nil {
+			logger.Errorf("HANDLERS: error parsing limit param: %v", err)
+			http.Error(w, "Invalid limit param", http.StatusBadRequest)
+			return
+		}
+		filter.Limit = &limit
+	}
+
+	entries, err := h.useCase.FindAuditEntries(r.Context(), &filter)
+	if err != nil {
+		logger.Errorf("HANDLERS: getAuditEntries error: %v", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+
+	logger.Debugf("HANDLERS: found %d audit entries", len(entries))
+	response := mapper.MapAuditEntryDTOsToResponse(entries)
+
+	w.Header().Set("Content-Type", "application/json")
+	if err = json.NewEncoder(w).Encode(response); err != nil {
+		logger.Errorf("HANDLERS: error encoding response: %v", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+}
@@ -0,0 +1,76 @@
+package handlers_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	adto "github.com/k6zma/DockerMonitoringApp/backend/internal/application/dto"
+	pdto "github.com/k6zma/DockerMonitoringApp/backend/internal/presentation/dto"
+	"github.com/k6zma/DockerMonitoringApp/backend/internal/presentation/handlers"
+	"github.com/k6zma/DockerMonitoringApp/backend/mocks"
+)
+
+func TestGetAuditEntries_AppliesFilters(t *testing.T) {
+	mockUseCase := new(mocks.AuditUseCaseInterface)
+	mockLogger := new(mocks.LoggerInterface)
+
+	handler := handlers.NewAuditHandler(mockUseCase, mockLogger)
+
+	actor := "pinger"
+	containerID := "abc"
+	createdAtGte := time.Date(2025, 2, 10, 0, 0, 0, 0, time.UTC)
+	limit := 10
+
+	mockUseCase.On("FindAuditEntries", mock.Anything, &adto.AuditFilter{
+		Actor:        &actor,
+		Methods:      []string{"PATCH", "DELETE"},
+		ContainerID:  &containerID,
+		CreatedAtGte: &createdAtGte,
+		Limit:        &limit,
+	}).Return([]*adto.AuditEntryDTO{
+		{ID: 1, Actor: "pinger", Method: "DELETE", Before: json.RawMessage(`{"status":"running"}`)},
+	}, nil)
+	mockLogger.On("Debugf", mock.Anything, mock.Anything).Return()
+
+	req := httptest.NewRequest(http.MethodGet,
+		"/audit?actor=pinger&method=patch,delete&container_id=abc&created_at_gte=2025-02-10T00:00:00Z&limit=10",
+		http.NoBody)
+	rec := httptest.NewRecorder()
+
+	handler.GetAuditEntries(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var response []pdto.GetAuditEntryResponse
+	err := json.NewDecoder(rec.Body).Decode(&response)
+	assert.NoError(t, err)
+	assert.Len(t, response, 1)
+	assert.JSONEq(t, `{"status":"running"}`, string(response[0].Before))
+	assert.Equal(t, "null", string(response[0].After))
+
+	mockUseCase.AssertExpectations(t)
+}
+
+func TestGetAuditEntries_InvalidTimeRange_ReturnsBadRequest(t *testing.T) {
+	mockUseCase := new(mocks.AuditUseCaseInterface)
+	mockLogger := new(mocks.LoggerInterface)
+
+	handler := handlers.NewAuditHandler(mockUseCase, mockLogger)
+
+	mockLogger.On("Debugf", mock.Anything, mock.Anything).Return()
+	mockLogger.On("Errorf", mock.Anything, mock.Anything).Return()
+
+	req := httptest.NewRequest(http.MethodGet, "/audit?created_at_lte=yesterday", http.NoBody)
+	rec := httptest.NewRecorder()
+
+	handler.GetAuditEntries(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	mockUseCase.AssertNotCalled(t, "FindAuditEntries", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,111 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	adto "github.com/k6zma/DockerMonitoringApp/backend/internal/application/dto"
+	"github.com/k6zma/DockerMonitoringApp/backend/internal/application/usecases"
+	"github.com/k6zma/DockerMonitoringApp/backend/internal/presentation/mapper"
+	"github.com/k6zma/DockerMonitoringApp/backend/pkg/utils"
+)
+
+type ContainerEventHandler struct {
+	useCase usecases.ContainerEventUseCaseInterface
+	logger  utils.LoggerInterface
+}
+
+func NewContainerEventHandler(
+	useCase usecases.ContainerEventUseCaseInterface,
+	logger utils.LoggerInterface,
+) *ContainerEventHandler {
+	return &ContainerEventHandler{
+		useCase: useCase,
+		logger:  logger,
+	}
+}
+
+// GetContainerEvents godoc
+// @Summary Retrieve container events
+// @Description Returns container events (restarts, status changes, crash loops), newest first
+// @Tags Events
+// @Accept json
+// @Produce json
+// @Param container_id query string false "Filter by container ID"
+// @Param type query string false "Comma separated list of event types (status_changed, restarted, crash_loop_detected, crash_loop_resolved)"
+// @Param created_at_gte query string false "Filter by creation date (greater than or equal to), format: RFC3339"
+// @Param created_at_lte query string false "Filter by creation date (less than or equal to), format: RFC3339"
+// @Param limit query int false "Limit the number of returned records"
+// @Success 200 {array} dto.GetContainerEventResponse
+// @Failure 400 {string} string "Bad Request"
+// @Failure 500 {string} string "Internal Server Error"
+// @Failure 403 {string} string "Forbidden"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /events [get].
+func (h *ContainerEventHandler) GetContainerEvents(w http.ResponseWriter, r *http.Request) {
+	logger := utils.ContextLogger(r.Context(), h.logger)
+
+	logger.Debugf("HANDLERS: received GetContainerEvents request with query: %s", r.URL.RawQuery)
+
+	queryParams := r.URL.Query()
+	filter := adto.ContainerEventFilter{}
+
+	if containerID := queryParams.Get("container_id"); containerID != "" {
+		filter.ContainerID = &containerID
+	}
+
+	if types := queryParams.Get("type"); types != "" {
+		filter.Types = strings.Split(types, ",")
+	}
+
+	if createdAtGteStr := queryParams.Get("created_at_gte"); createdAtGteStr != "" {
+		createdAtGte, err := time.Parse(time.RFC3339, createdAtGteStr)
+		if err != nil {
+			logger.Errorf("HANDLERS: error parsing created_at_gte param: %v", err)
+			http.Error(w, "Invalid created_at_gte param", http.StatusBadRequest)
+			return
+		}
+		filter.CreatedAtGte = &createdAtGte
+	}
+
+	if createdAtLteStr := queryParams.Get("created_at_lte"); createdAtLteStr != "" {
+		createdAtLte, err := time.Parse(time.RFC3339, createdAtLteStr)
+		if err != nil {
+			logger.Errorf("HANDLERS: error parsing created_at_lte param: %v", err)
+			http.Error(w, "Invalid created_at_lte param", http.StatusBadRequest)
+			return
+		}
+		filter.CreatedAtLte = &createdAtLte
+	}
+
+	if limitStr := queryParams.Get("limit"); limitStr != "" {
+		limit, err := strconv.Atoi(limitStr)
+		if err != nil {
+			logger.Errorf("HANDLERS: error parsing limit param: %v", err)
+			http.Error(w, "Invalid limit param", http.StatusBadRequest)
+			return
+		}
+		filter.Limit = &limit
+	}
+
+	events, err := h.useCase.FindContainerEvents(r.Context(), &filter)
+	if err != nil {
+		logger.Errorf("HANDLERS: getContainerEvents error: %v", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+
+	logger.Debugf("HANDLERS: found %d container events", len(events))
+	response := mapper.MapEventDTOsToResponse(events)
+
+	w.Header().Set("Content-Type", "application/json")
+	if err = json.NewEncoder(w).Encode(response); err != nil {
+		logger.Errorf("HANDLERS: error encoding response: %v", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+}
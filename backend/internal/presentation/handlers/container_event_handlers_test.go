@@ -0,0 +1,75 @@
+package handlers_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	adto "github.com/k6zma/DockerMonitoringApp/backend/internal/application/dto"
+	"github.com/k6zma/DockerMonitoringApp/backend/internal/domain"
+	pdto "github.com/k6zma/DockerMonitoringApp/backend/internal/presentation/dto"
+	"github.com/k6zma/DockerMonitoringApp/backend/internal/presentation/handlers"
+	"github.com/k6zma/DockerMonitoringApp/backend/mocks"
+)
+
+func TestGetContainerEvents_WithQueryParams_ReturnsFilteredData(t *testing.T) {
+	mockUseCase := new(mocks.ContainerEventUseCaseInterface)
+	mockLogger := new(mocks.LoggerInterface)
+
+	handler := handlers.NewContainerEventHandler(mockUseCase, mockLogger)
+
+	expectedEvents := []*adto.ContainerEventDTO{
+		{ID: 1, ContainerID: containerID, Type: domain.EventTypeCrashLoopDetected, CreatedAt: time.Now()},
+	}
+
+	mockUseCase.On("FindContainerEvents", mock.Anything, mock.MatchedBy(func(filter *adto.ContainerEventFilter) bool {
+		return filter.ContainerID != nil && *filter.ContainerID == containerID &&
+			assert.ObjectsAreEqual([]string{domain.EventTypeRestarted, domain.EventTypeCrashLoopDetected}, filter.Types) &&
+			filter.Limit != nil && *filter.Limit == 10
+	})).Return(expectedEvents, nil)
+	mockLogger.On("Debugf", mock.Anything, mock.Anything).Return()
+
+	req := httptest.NewRequest(
+		http.MethodGet,
+		"/events?container_id=container123&type=restarted,crash_loop_detected&limit=10",
+		http.NoBody,
+	)
+	rec := httptest.NewRecorder()
+
+	handler.GetContainerEvents(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var response []pdto.GetContainerEventResponse
+	err := json.NewDecoder(rec.Body).Decode(&response)
+	assert.NoError(t, err)
+	assert.Len(t, response, 1)
+	assert.Equal(t, domain.EventTypeCrashLoopDetected, response[0].Type)
+
+	mockUseCase.AssertExpectations(t)
+	mockLogger.AssertExpectations(t)
+}
+
+func TestGetContainerEvents_InvalidLimit_ReturnsBadRequest(t *testing.T) {
+	mockUseCase := new(mocks.ContainerEventUseCaseInterface)
+	mockLogger := new(mocks.LoggerInterface)
+
+	handler := handlers.NewContainerEventHandler(mockUseCase, mockLogger)
+
+	mockLogger.On("Debugf", mock.Anything, mock.Anything).Return()
+	mockLogger.On("Errorf", mock.Anything, mock.Anything).Return()
+
+	req := httptest.NewRequest(http.MethodGet, "/events?limit=abc", http.NoBody)
+	rec := httptest.NewRecorder()
+
+	handler.GetContainerEvents(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	mockUseCase.AssertNotCalled(t, "FindContainerEvents", mock.Anything)
+	mockLogger.AssertExpectations(t)
+}
@@ -2,9 +2,11 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/go-playground/validator/v10"
@@ -51,15 +53,24 @@ func NewContainerStatusHandler(
 // @Param updated_at_gte query string false "Filter by last update date (greater than or equal to), format: RFC3339"
 // @Param updated_at_lte query string false "Filter by last update date (less than or equal to), format: RFC3339"
 // @Param limit query int false "Limit the number of returned records"
+// @Param If-None-Match header string false "ETag of a cached list"
+// @Param If-Modified-Since header string false "Last-Modified of a cached list, ignored with If-None-Match"
 // @Success 200 {array} dto.GetContainerStatusResponse
+// @Header 200,304 {string} ETag "Weak validator of the list"
+// @Header 200,304 {string} Last-Modified "Time of the last write or deletion of any container"
+// @Success 304 "Not Modified"
 // @Failure 500 {string} string "Internal Server Error"
+// @Failure 403 {string} string "Forbidden"
 // @Security ApiKeyAuth
+// @Security BearerAuth
 // @Router /container_status [get].
 func (h *ContainerStatusHandler) GetFilteredContainerStatuses(
 	w http.ResponseWriter,
 	r *http.Request,
 ) {
-	h.logger.Debugf("HANDLERS: received GetFilteredContainerStatuses request with query: %s", r.URL.RawQuery)
+	logger := utils.ContextLogger(r.Context(), h.logger)
+
+	logger.Debugf("HANDLERS: received GetFilteredContainerStatuses request with query: %s", r.URL.RawQuery)
 
 	queryParams := r.URL.Query()
 	filter := adto.ContainerStatusFilter{}
@@ -85,7 +96,7 @@ func (h *ContainerStatusHandler) GetFilteredContainerStatuses(
 		if err == nil {
 			filter.PingTimeMin = &pingMin
 		} else {
-			h.logger.Errorf("HANDLERS: error parsing ping_time_min param: %v", err)
+			logger.Errorf("HANDLERS: error parsing ping_time_min param: %v", err)
 			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
 			return
 		}
@@ -96,7 +107,7 @@ func (h *ContainerStatusHandler) GetFilteredContainerStatuses(
 		if err == nil {
 			filter.PingTimeMax = &pingMax
 		} else {
-			h.logger.Errorf("HANDLERS: error parsing ping_time_max param: %v", err)
+			logger.Errorf("HANDLERS: error parsing ping_time_max param: %v", err)
 			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
 			return
 		}
@@ -107,7 +118,7 @@ func (h *ContainerStatusHandler) GetFilteredContainerStatuses(
 		if err == nil {
 			filter.CreatedAtGte = &createdAtGte
 		} else {
-			h.logger.Errorf("HANDLERS: error parsing created_at_gte param: %v", err)
+			logger.Errorf("HANDLERS: error parsing created_at_gte param: %v", err)
 			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
 			return
 		}
@@ -118,7 +129,7 @@ func (h *ContainerStatusHandler) GetFilteredContainerStatuses(
 		if err == nil {
 			filter.CreatedAtLte = &createdAtLte
 		} else {
-			h.logger.Errorf("HANDLERS: error parsing created_at_lte param: %v", err)
+			logger.Errorf("HANDLERS: error parsing created_at_lte param: %v", err)
 			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
 			return
 		}
@@ -129,7 +140,7 @@ func (h *ContainerStatusHandler) GetFilteredContainerStatuses(
 		if err == nil {
 			filter.UpdatedAtGte = &updatedAtGte
 		} else {
-			h.logger.Errorf("HANDLERS: error parsing updated_at_gte param: %v", err)
+			logger.Errorf("HANDLERS: error parsing updated_at_gte param: %v", err)
 			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
 			return
 		}
@@ -140,7 +151,7 @@ func (h *ContainerStatusHandler) GetFilteredContainerStatuses(
 		if err == nil {
 			filter.UpdatedAtLte = &updatedAtLte
 		} else {
-			h.logger.Errorf("HANDLERS: error parsing updated_at_lte param: %v", err)
+			logger.Errorf("HANDLERS: error parsing updated_at_lte param: %v", err)
 			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
 			return
 		}
@@ -151,25 +162,46 @@ func (h *ContainerStatusHandler) GetFilteredContainerStatuses(
 		if err == nil {
 			filter.Limit = &limit
 		} else {
-			h.logger.Errorf("HANDLERS: error parsing limit param: %v", err)
+			logger.Errorf("HANDLERS: error parsing limit param: %v", err)
 			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
 			return
 		}
 	}
 
-	statuses, err := h.useCase.FindContainerStatuses(&filter)
+	version, err := h.useCase.GetContainerStatusesVersion(r.Context(), &filter)
 	if err != nil {
-		h.logger.Errorf("HANDLERS: getFilteredContainerStatuses error: %v", err)
+		logger.Errorf("HANDLERS: getFilteredContainerStatuses version error: %v", err)
 		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
 		return
 	}
 
-	h.logger.Debugf("HANDLERS: found %d container statuses", len(statuses))
+	etag := containerStatusListETag(version)
+	w.Header().Set("ETag", etag)
+	// Clients revalidate on every request rather than guessing a freshness.
+	w.Header().Set("Cache-Control", "private, no-cache")
+	if !version.ModifiedAt.IsZero() {
+		w.Header().Set("Last-Modified", version.ModifiedAt.UTC().Format(http.TimeFormat))
+	}
+
+	if notModified(r, etag, version.ModifiedAt) {
+		logger.Debugf("HANDLERS: container statuses not modified, etag: %s", etag)
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	statuses, err := h.useCase.FindContainerStatuses(r.Context(), &filter)
+	if err != nil {
+		logger.Errorf("HANDLERS: getFilteredContainerStatuses error: %v", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+
+	logger.Debugf("HANDLERS: found %d container statuses", len(statuses))
 	response := mapper.MapAppDTOsToResponse(statuses)
 
 	w.Header().Set("Content-Type", "application/json")
 	if err = json.NewEncoder(w).Encode(response); err != nil {
-		h.logger.Errorf("HANDLERS: error encoding response: %v", err)
+		logger.Errorf("HANDLERS: error encoding response: %v", err)
 		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
 		return
 	}
@@ -185,41 +217,45 @@ func (h *ContainerStatusHandler) GetFilteredContainerStatuses(
 // @Success 201 {object} dto.GetContainerStatusResponse
 // @Failure 400 {string} string "Bad Request"
 // @Failure 500 {string} string "Internal Server Error"
+// @Failure 403 {string} string "Forbidden"
 // @Security ApiKeyAuth
+// @Security BearerAuth
 // @Router /container_status [post].
 func (h *ContainerStatusHandler) CreateContainerStatus(w http.ResponseWriter, r *http.Request) {
-	h.logger.Debugf("HANDLERS: received CreateContainerStatus request")
+	logger := utils.ContextLogger(r.Context(), h.logger)
+
+	logger.Debugf("HANDLERS: received CreateContainerStatus request")
 
 	var req pdto.CreateContainerStatusRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		h.logger.Errorf("HANDLERS: createContainerStatus decode error: %v", err)
+		logger.Errorf("HANDLERS: createContainerStatus decode error: %v", err)
 		http.Error(w, "Invalid request body", http.StatusBadRequest)
 		return
 	}
 
 	if err := h.validate.Struct(req); err != nil {
-		h.logger.Errorf("HANDLERS: createContainerStatus validation error: %v", err)
+		logger.Errorf("HANDLERS: createContainerStatus validation error: %v", err)
 		http.Error(w, "Validation error: "+err.Error(), http.StatusBadRequest)
 		return
 	}
 
 	appDTO := mapper.MapCreateRequestToAppDTO(req)
 
-	createdStatus, err := h.useCase.CreateContainerStatus(&appDTO)
+	createdStatus, err := h.useCase.CreateContainerStatus(r.Context(), &appDTO)
 	if err != nil {
-		h.logger.Errorf("HANDLERS: createContainerStatus error: %v", err)
+		logger.Errorf("HANDLERS: createContainerStatus error: %v", err)
 		http.Error(w, "Failed to create container status", http.StatusInternalServerError)
 		return
 	}
 
-	h.logger.Debugf("HANDLERS: container status created with container_id: %s", createdStatus.ContainerID)
+	logger.Debugf("HANDLERS: container status created with container_id: %s", createdStatus.ContainerID)
 
 	response := mapper.MapAppDTOToResponse(*createdStatus)
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusCreated)
 	if err := json.NewEncoder(w).Encode(response); err != nil {
-		h.logger.Errorf("HANDLERS: error encoding response: %v", err)
+		logger.Errorf("HANDLERS: error encoding response: %v", err)
 		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
 		return
 	}
@@ -235,38 +271,52 @@ func (h *ContainerStatusHandler) CreateContainerStatus(w http.ResponseWriter, r
 // @Param request body dto.UpdateContainerStatusRequest true "Fields to update"
 // @Success 204
 // @Failure 400 {string} string "Bad Request"
+// @Failure 404 {string} string "Not Found"
+// @Failure 409 {string} string "Conflict"
 // @Failure 500 {string} string "Internal Server Error"
+// @Failure 403 {string} string "Forbidden"
 // @Security ApiKeyAuth
+// @Security BearerAuth
 // @Router /container_status/{container_id} [patch].
 func (h *ContainerStatusHandler) UpdateContainerStatus(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	containerID := vars["container_id"]
+	logger := utils.ContextLogger(r.Context(), h.logger, "container_id", containerID)
 
-	h.logger.Debugf("HANDLERS: received UpdateContainerStatus request for container_id: %s", containerID)
+	logger.Debugf("HANDLERS: received UpdateContainerStatus request for container_id: %s", containerID)
 
 	var req pdto.UpdateContainerStatusRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		h.logger.Errorf("HANDLERS: updateContainerStatus decode error for container_id %s: %v", containerID, err)
+		logger.Errorf("HANDLERS: updateContainerStatus decode error for container_id %s: %v", containerID, err)
 		http.Error(w, "Invalid request body", http.StatusBadRequest)
 		return
 	}
 
-	if req.PingTime == 0 && req.LastSuccessfulPing.IsZero() && req.Status == "" {
-		h.logger.Errorf("HANDLERS: updateContainerStatus validation error for container_id %s: No fields provided", containerID)
+	if req.PingTime == 0 && req.LastSuccessfulPing.IsZero() && req.Status == "" && req.RestartCount == nil {
+		logger.Errorf("HANDLERS: updateContainerStatus validation error for container_id %s: No fields provided", containerID)
 		http.Error(w, "At least one field must be provided", http.StatusBadRequest)
 		return
 	}
 
 	appDTO := mapper.MapUpdateRequestToAppDTO(req)
 
-	err := h.useCase.UpdateContainerStatus(containerID, &appDTO)
+	err := h.useCase.UpdateContainerStatus(r.Context(), containerID, &appDTO)
 	if err != nil {
-		h.logger.Errorf("HANDLERS: failed to update container status for container_id %s: %v", containerID, err)
+		if err.Error() == fmt.Sprintf("container status with container ID %s not found", containerID) {
+			logger.Warnf("HANDLERS: container status with container_id %s not found", containerID)
+			http.Error(w, "Container not found", http.StatusNotFound)
+			return
+		}
+		if errors.Is(err, usecases.ErrStaleContainerStatus) {
+			http.Error(w, "A more recent container status is stored", http.StatusConflict)
+			return
+		}
+		logger.Errorf("HANDLERS: failed to update container status for container_id %s: %v", containerID, err)
 		http.Error(w, "Failed to update container status", http.StatusInternalServerError)
 		return
 	}
 
-	h.logger.Debugf("HANDLERS: successfully updated container status for container_id: %s", containerID)
+	logger.Debugf("HANDLERS: successfully updated container status for container_id: %s", containerID)
 	w.WriteHeader(http.StatusNoContent)
 }
 
@@ -280,26 +330,68 @@ func (h *ContainerStatusHandler) UpdateContainerStatus(w http.ResponseWriter, r
 // @Success 204 "No Content"
 // @Failure 404 {string} string "Not Found"
 // @Failure 500 {string} string "Internal Server Error"
+// @Failure 403 {string} string "Forbidden"
 // @Security ApiKeyAuth
+// @Security BearerAuth
 // @Router /container_status/{container_id} [delete].
 func (h *ContainerStatusHandler) DeleteContainerStatus(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	containerID := vars["container_id"]
+	logger := utils.ContextLogger(r.Context(), h.logger, "container_id", containerID)
 
-	h.logger.Debugf("HANDLERS: received DeleteContainerStatus request for container_id: %s", containerID)
+	logger.Debugf("HANDLERS: received DeleteContainerStatus request for container_id: %s", containerID)
 
-	err := h.useCase.DeleteContainerStatusByContainerID(containerID)
+	err := h.useCase.DeleteContainerStatusByContainerID(r.Context(), containerID)
 	if err != nil {
 		if err.Error() == fmt.Sprintf("container status with container_id %s not found", containerID) {
-			h.logger.Warnf("HANDLERS: container status with container_id %s not found", containerID)
+			logger.Warnf("HANDLERS: container status with container_id %s not found", containerID)
 			http.Error(w, "Container not found", http.StatusNotFound)
 			return
 		}
-		h.logger.Errorf("HANDLERS: failed to delete container status for container_id %s: %v", containerID, err)
+		logger.Errorf("HANDLERS: failed to delete container status for container_id %s: %v", containerID, err)
 		http.Error(w, "Failed to delete container status", http.StatusInternalServerError)
 		return
 	}
 
-	h.logger.Debugf("HANDLERS: successfully deleted container status for container_id: %s", containerID)
+	logger.Debugf("HANDLERS: successfully deleted container status for container_id: %s", containerID)
 	w.WriteHeader(http.StatusNoContent)
 }
+
+// containerStatusListETag derives a weak ETag from the number of listed
+// statuses and their latest write. A status leaving the list lowers the
+// count, one joining it raises the latest write.
+func containerStatusListETag(version *adto.ContainerStatusListVersionDTO) string {
+	var updatedAt int64
+	if !version.UpdatedAt.IsZero() {
+		updatedAt = version.UpdatedAt.UnixMicro()
+	}
+
+	return fmt.Sprintf(`W/"%d-%s"`, version.Count, strconv.FormatInt(updatedAt, 36))
+}
+
+// notModified evaluates the conditional headers of r. If-Modified-Since is
+// only considered without If-None-Match, as RFC 9110 requires.
+func notModified(r *http.Request, etag string, modifiedAt time.Time) bool {
+	if ifNoneMatch := r.Header.Get("If-None-Match"); ifNoneMatch != "" {
+		for _, candidate := range strings.Split(ifNoneMatch, ",") {
+			candidate = strings.TrimSpace(candidate)
+			if candidate == "*" || strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	if modifiedAt.IsZero() {
+		return false
+	}
+
+	since, err := http.ParseTime(r.Header.Get("If-Modified-Since"))
+	if err != nil {
+		return false
+	}
+
+	// Last-Modified only has a precision of seconds.
+	return !modifiedAt.Truncate(time.Second).After(since)
+}
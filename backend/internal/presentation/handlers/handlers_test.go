@@ -14,6 +14,7 @@ import (
 	"github.com/stretchr/testify/mock"
 
 	adto "github.com/k6zma/DockerMonitoringApp/backend/internal/application/dto"
+	"github.com/k6zma/DockerMonitoringApp/backend/internal/application/usecases"
 	pdto "github.com/k6zma/DockerMonitoringApp/backend/internal/presentation/dto"
 	"github.com/k6zma/DockerMonitoringApp/backend/internal/presentation/handlers"
 	"github.com/k6zma/DockerMonitoringApp/backend/mocks"
@@ -25,6 +26,12 @@ const (
 	pingTime    = 15.5
 )
 
+var listVersion = &adto.ContainerStatusListVersionDTO{
+	Count:      1,
+	UpdatedAt:  time.Date(2024, 5, 1, 12, 0, 0, 500, time.UTC),
+	ModifiedAt: time.Date(2024, 5, 1, 12, 0, 0, 500, time.UTC),
+}
+
 func TestGetContainerStatuses_ReturnsDataSuccessfully(t *testing.T) {
 	mockUseCase := new(mocks.ContainerStatusUseCaseInterface)
 	mockLogger := new(mocks.LoggerInterface)
@@ -35,7 +42,8 @@ func TestGetContainerStatuses_ReturnsDataSuccessfully(t *testing.T) {
 		{IPAddress: ipAddress, PingTime: pingTime, LastSuccessfulPing: time.Now()},
 	}
 
-	mockUseCase.On("FindContainerStatuses", mock.Anything).Return(expectedStatuses, nil)
+	mockUseCase.On("GetContainerStatusesVersion", mock.Anything, mock.Anything).Return(listVersion, nil)
+	mockUseCase.On("FindContainerStatuses", mock.Anything, mock.Anything).Return(expectedStatuses, nil)
 	mockLogger.On("Debugf", mock.Anything, mock.Anything).Return()
 
 	req := httptest.NewRequest(http.MethodGet, "/container_status", http.NoBody)
@@ -65,7 +73,8 @@ func TestGetContainerStatuses_WithQueryParams_ReturnsFilteredData(t *testing.T)
 		{IPAddress: ipAddress, PingTime: pingTime, LastSuccessfulPing: time.Now()},
 	}
 
-	mockUseCase.On("FindContainerStatuses", mock.Anything).Return(expectedStatuses, nil)
+	mockUseCase.On("GetContainerStatusesVersion", mock.Anything, mock.Anything).Return(listVersion, nil)
+	mockUseCase.On("FindContainerStatuses", mock.Anything, mock.Anything).Return(expectedStatuses, nil)
 	mockLogger.On("Debugf", mock.Anything, mock.Anything).Return()
 
 	req := httptest.NewRequest(
@@ -96,7 +105,8 @@ func TestGetContainerStatuses_ErrorFromUseCase_ReturnsInternalServerError(t *tes
 
 	handler := handlers.NewContainerStatusHandler(mockUseCase, mockLogger)
 
-	mockUseCase.On("FindContainerStatuses", mock.Anything).Return(nil, fmt.Errorf("database error"))
+	mockUseCase.On("GetContainerStatusesVersion", mock.Anything, mock.Anything).Return(listVersion, nil)
+	mockUseCase.On("FindContainerStatuses", mock.Anything, mock.Anything).Return(nil, fmt.Errorf("database error"))
 	mockLogger.On("Debugf", mock.Anything, mock.Anything).Return()
 	mockLogger.On("Errorf", mock.Anything, mock.Anything).Return()
 
@@ -110,6 +120,127 @@ func TestGetContainerStatuses_ErrorFromUseCase_ReturnsInternalServerError(t *tes
 	mockLogger.AssertExpectations(t)
 }
 
+func TestGetContainerStatuses_SetsValidators(t *testing.T) {
+	mockUseCase := new(mocks.ContainerStatusUseCaseInterface)
+	mockLogger := new(mocks.LoggerInterface)
+
+	handler := handlers.NewContainerStatusHandler(mockUseCase, mockLogger)
+
+	mockUseCase.On("GetContainerStatusesVersion", mock.Anything, mock.Anything).Return(listVersion, nil)
+	mockUseCase.On("FindContainerStatuses", mock.Anything, mock.Anything).Return([]*adto.ContainerStatusDTO{}, nil)
+	mockLogger.On("Debugf", mock.Anything, mock.Anything).Return()
+
+	req := httptest.NewRequest(http.MethodGet, "/container_status", http.NoBody)
+	rec := httptest.NewRecorder()
+
+	handler.GetFilteredContainerStatuses(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.NotEmpty(t, rec.Header().Get("ETag"))
+	assert.Equal(t, "Wed, 01 May 2024 12:00:00 GMT", rec.Header().Get("Last-Modified"))
+	assert.Equal(t, "private, no-cache", rec.Header().Get("Cache-Control"))
+	mockUseCase.AssertExpectations(t)
+}
+
+func TestGetContainerStatuses_MatchingETag_ReturnsNotModified(t *testing.T) {
+	mockUseCase := new(mocks.ContainerStatusUseCaseInterface)
+	mockLogger := new(mocks.LoggerInterface)
+
+	handler := handlers.NewContainerStatusHandler(mockUseCase, mockLogger)
+
+	mockUseCase.On("GetContainerStatusesVersion", mock.Anything, mock.Anything).Return(listVersion, nil)
+	mockUseCase.On("FindContainerStatuses", mock.Anything, mock.Anything).Return([]*adto.ContainerStatusDTO{}, nil).Once()
+	mockLogger.On("Debugf", mock.Anything, mock.Anything).Return()
+
+	rec := httptest.NewRecorder()
+	handler.GetFilteredContainerStatuses(rec, httptest.NewRequest(http.MethodGet, "/container_status", http.NoBody))
+	etag := rec.Header().Get("ETag")
+
+	req := httptest.NewRequest(http.MethodGet, "/container_status", http.NoBody)
+	req.Header.Set("If-None-Match", `"other", `+etag)
+	rec = httptest.NewRecorder()
+
+	handler.GetFilteredContainerStatuses(rec, req)
+
+	assert.Equal(t, http.StatusNotModified, rec.Code)
+	assert.Equal(t, etag, rec.Header().Get("ETag"))
+	assert.Empty(t, rec.Body.String())
+	mockUseCase.AssertNumberOfCalls(t, "FindContainerStatuses", 1)
+}
+
+func TestGetContainerStatuses_ChangedETag_IgnoresIfModifiedSince(t *testing.T) {
+	mockUseCase := new(mocks.ContainerStatusUseCaseInterface)
+	mockLogger := new(mocks.LoggerInterface)
+
+	handler := handlers.NewContainerStatusHandler(mockUseCase, mockLogger)
+
+	mockUseCase.On("GetContainerStatusesVersion", mock.Anything, mock.Anything).Return(listVersion, nil)
+	mockUseCase.On("FindContainerStatuses", mock.Anything, mock.Anything).Return([]*adto.ContainerStatusDTO{}, nil)
+	mockLogger.On("Debugf", mock.Anything, mock.Anything).Return()
+
+	req := httptest.NewRequest(http.MethodGet, "/container_status", http.NoBody)
+	req.Header.Set("If-None-Match", `W/"0-0"`)
+	req.Header.Set("If-Modified-Since", "Wed, 01 May 2024 12:00:00 GMT")
+	rec := httptest.NewRecorder()
+
+	handler.GetFilteredContainerStatuses(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	mockUseCase.AssertExpectations(t)
+}
+
+func TestGetContainerStatuses_IfModifiedSince(t *testing.T) {
+	tests := []struct {
+		name         string
+		since        string
+		expectedCode int
+	}{
+		{name: "unchanged", since: "Wed, 01 May 2024 12:00:00 GMT", expectedCode: http.StatusNotModified},
+		{name: "changed", since: "Wed, 01 May 2024 11:59:59 GMT", expectedCode: http.StatusOK},
+		{name: "invalid", since: "yesterday", expectedCode: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUseCase := new(mocks.ContainerStatusUseCaseInterface)
+			mockLogger := new(mocks.LoggerInterface)
+
+			handler := handlers.NewContainerStatusHandler(mockUseCase, mockLogger)
+
+			mockUseCase.On("GetContainerStatusesVersion", mock.Anything, mock.Anything).Return(listVersion, nil)
+			mockUseCase.On("FindContainerStatuses", mock.Anything, mock.Anything).Return([]*adto.ContainerStatusDTO{}, nil).Maybe()
+			mockLogger.On("Debugf", mock.Anything, mock.Anything).Return()
+
+			req := httptest.NewRequest(http.MethodGet, "/container_status", http.NoBody)
+			req.Header.Set("If-Modified-Since", tt.since)
+			rec := httptest.NewRecorder()
+
+			handler.GetFilteredContainerStatuses(rec, req)
+
+			assert.Equal(t, tt.expectedCode, rec.Code)
+		})
+	}
+}
+
+func TestGetContainerStatuses_ErrorFromVersion_ReturnsInternalServerError(t *testing.T) {
+	mockUseCase := new(mocks.ContainerStatusUseCaseInterface)
+	mockLogger := new(mocks.LoggerInterface)
+
+	handler := handlers.NewContainerStatusHandler(mockUseCase, mockLogger)
+
+	mockUseCase.On("GetContainerStatusesVersion", mock.Anything, mock.Anything).Return(nil, fmt.Errorf("database error"))
+	mockLogger.On("Debugf", mock.Anything, mock.Anything).Return()
+	mockLogger.On("Errorf", mock.Anything, mock.Anything).Return()
+
+	req := httptest.NewRequest(http.MethodGet, "/container_status", http.NoBody)
+	rec := httptest.NewRecorder()
+
+	handler.GetFilteredContainerStatuses(rec, req)
+
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	mockUseCase.AssertNotCalled(t, "FindContainerStatuses", mock.Anything, mock.Anything)
+}
+
 func TestGetContainerStatuses_InvalidID_ReturnsInternalServerError(t *testing.T) {
 	mockUseCase := new(mocks.ContainerStatusUseCaseInterface)
 	mockLogger := new(mocks.LoggerInterface)
@@ -119,7 +250,8 @@ func TestGetContainerStatuses_InvalidID_ReturnsInternalServerError(t *testing.T)
 	mockLogger.On("Debugf", mock.Anything, mock.Anything).Return()
 	mockLogger.On("Errorf", mock.Anything, mock.Anything).Return()
 
-	mockUseCase.On("FindContainerStatuses", mock.Anything).
+	mockUseCase.On("GetContainerStatusesVersion", mock.Anything, mock.Anything).Return(listVersion, nil)
+	mockUseCase.On("FindContainerStatuses", mock.Anything, mock.Anything).
 		Return(nil, fmt.Errorf("invalid id")).Once()
 
 	req := httptest.NewRequest(http.MethodGet, "/container_status?container_id=invalid", http.NoBody)
@@ -300,7 +432,7 @@ func TestCreateContainerStatus_SuccessfullyCreatesContainer(t *testing.T) {
 	}
 	jsonBody, _ := json.Marshal(requestBody)
 
-	mockUseCase.On("CreateContainerStatus", mock.Anything).Return(&adto.ContainerStatusDTO{
+	mockUseCase.On("CreateContainerStatus", mock.Anything, mock.Anything).Return(&adto.ContainerStatusDTO{
 		ContainerID:        containerID,
 		IPAddress:          ipAddress,
 		Name:               "test_container",
@@ -379,7 +511,7 @@ func TestCreateContainerStatus_ErrorFromUseCase_ReturnsInternalServerError(t *te
 	}
 	jsonBody, _ := json.Marshal(requestBody)
 
-	mockUseCase.On("CreateContainerStatus", mock.Anything).
+	mockUseCase.On("CreateContainerStatus", mock.Anything, mock.Anything).
 		Return(nil, fmt.Errorf("database error"))
 	mockLogger.On("Debugf", mock.Anything, mock.Anything).Return()
 	mockLogger.On("Errorf", mock.Anything, mock.Anything).Return()
@@ -408,7 +540,7 @@ func TestUpdateContainerStatus_SuccessfullyUpdatesContainer(t *testing.T) {
 	assert.NoError(t, err)
 
 	mockUseCase.
-		On("UpdateContainerStatus", containerID, mock.AnythingOfType("*dto.ContainerStatusDTO")).
+		On("UpdateContainerStatus", mock.Anything, containerID, mock.AnythingOfType("*dto.ContainerStatusDTO")).
 		Return(nil).
 		Once()
 	mockLogger.On("Debugf", mock.Anything, mock.Anything).Return()
@@ -442,7 +574,7 @@ func TestUpdateContainerStatus_ErrorFromUseCase_ReturnsInternalServerError(t *te
 	assert.NoError(t, err)
 
 	mockUseCase.
-		On("UpdateContainerStatus", containerID, mock.AnythingOfType("*dto.ContainerStatusDTO")).
+		On("UpdateContainerStatus", mock.Anything, containerID, mock.AnythingOfType("*dto.ContainerStatusDTO")).
 		Return(fmt.Errorf("update failed")).
 		Once()
 	mockLogger.On("Debugf", mock.Anything, mock.Anything).Return()
@@ -465,6 +597,79 @@ func TestUpdateContainerStatus_ErrorFromUseCase_ReturnsInternalServerError(t *te
 	mockLogger.AssertExpectations(t)
 }
 
+func TestUpdateContainerStatus_ContainerNotFound_ReturnsNotFound(t *testing.T) {
+	mockUseCase := new(mocks.ContainerStatusUseCaseInterface)
+	mockLogger := new(mocks.LoggerInterface)
+
+	handler := handlers.NewContainerStatusHandler(mockUseCase, mockLogger)
+
+	requestBody := pdto.UpdateContainerStatusRequest{
+		PingTime: pingTime,
+	}
+	jsonBody, err := json.Marshal(requestBody)
+	assert.NoError(t, err)
+
+	expectedErr := fmt.Errorf("container status with container ID %s not found", containerID)
+	mockUseCase.
+		On("UpdateContainerStatus", mock.Anything, containerID, mock.AnythingOfType("*dto.ContainerStatusDTO")).
+		Return(expectedErr).
+		Once()
+	mockLogger.On("Debugf", mock.Anything, mock.Anything).Return()
+	mockLogger.On("Warnf", mock.Anything, mock.Anything).Return()
+
+	req := httptest.NewRequest(
+		http.MethodPatch,
+		"/container_status/"+containerID,
+		bytes.NewReader(jsonBody),
+	)
+	req = mux.SetURLVars(req, map[string]string{"container_id": containerID})
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+
+	handler.UpdateContainerStatus(rec, req)
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	mockUseCase.AssertExpectations(t)
+	mockLogger.AssertExpectations(t)
+}
+
+func TestUpdateContainerStatus_Stale_ReturnsConflict(t *testing.T) {
+	mockUseCase := new(mocks.ContainerStatusUseCaseInterface)
+	mockLogger := new(mocks.LoggerInterface)
+
+	handler := handlers.NewContainerStatusHandler(mockUseCase, mockLogger)
+
+	checkedAt := time.Now().Add(-time.Hour).UTC().Truncate(time.Second)
+	requestBody := pdto.UpdateContainerStatusRequest{
+		PingTime:  pingTime,
+		CheckedAt: checkedAt,
+	}
+	jsonBody, err := json.Marshal(requestBody)
+	assert.NoError(t, err)
+
+	mockUseCase.
+		On("UpdateContainerStatus", mock.Anything, containerID, mock.MatchedBy(func(status *adto.ContainerStatusDTO) bool {
+			return status.CheckedAt.Equal(checkedAt)
+		})).
+		Return(fmt.Errorf("%w: checked at %s", usecases.ErrStaleContainerStatus, checkedAt)).
+		Once()
+	mockLogger.On("Debugf", mock.Anything, mock.Anything).Return()
+
+	req := httptest.NewRequest(
+		http.MethodPatch,
+		"/container_status/"+containerID,
+		bytes.NewReader(jsonBody),
+	)
+	req = mux.SetURLVars(req, map[string]string{"container_id": containerID})
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+
+	handler.UpdateContainerStatus(rec, req)
+
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	mockUseCase.AssertExpectations(t)
+}
+
 func TestUpdateContainerStatus_InvalidJSON_ReturnsBadRequest(t *testing.T) {
 	mockUseCase := new(mocks.ContainerStatusUseCaseInterface)
 	mockLogger := new(mocks.LoggerInterface)
@@ -526,7 +731,7 @@ func TestDeleteContainerStatus_SuccessfullyDeletesContainer(t *testing.T) {
 
 	handler := handlers.NewContainerStatusHandler(mockUseCase, mockLogger)
 
-	mockUseCase.On("DeleteContainerStatusByContainerID", containerID).Return(nil)
+	mockUseCase.On("DeleteContainerStatusByContainerID", mock.Anything, containerID).Return(nil)
 	mockLogger.On("Debugf", mock.Anything, mock.Anything).Return()
 
 	req := httptest.NewRequest(http.MethodDelete, "/container_status/"+containerID, http.NoBody)
@@ -548,7 +753,7 @@ func TestDeleteContainerStatus_ErrorFromUseCase_ReturnsInternalServerError(t *te
 	handler := handlers.NewContainerStatusHandler(mockUseCase, mockLogger)
 
 	mockUseCase.
-		On("DeleteContainerStatusByContainerID", containerID).
+		On("DeleteContainerStatusByContainerID", mock.Anything, containerID).
 		Return(fmt.Errorf("delete failed"))
 	mockLogger.On("Debugf", mock.Anything, mock.Anything).Return()
 	mockLogger.On("Errorf", mock.Anything, mock.Anything, mock.Anything).Return()
@@ -572,7 +777,7 @@ func TestDeleteContainerStatus_ContainerNotFound_ReturnsNotFound(t *testing.T) {
 
 	expectedErr := fmt.Errorf("container status with container_id %s not found", containerID)
 	mockUseCase.
-		On("DeleteContainerStatusByContainerID", containerID).
+		On("DeleteContainerStatusByContainerID", mock.Anything, containerID).
 		Return(expectedErr)
 	mockLogger.On("Debugf", mock.Anything, mock.Anything).Return()
 	mockLogger.On("Warnf", mock.Anything, mock.Anything).Return()
@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/k6zma/DockerMonitoringApp/backend/internal/application/usecases"
+	pdto "github.com/k6zma/DockerMonitoringApp/backend/internal/presentation/dto"
+	"github.com/k6zma/DockerMonitoringApp/backend/internal/presentation/mapper"
+	"github.com/k6zma/DockerMonitoringApp/backend/pkg/utils"
+)
+
+const readinessTimeout = 3 * time.Second
+
+type HealthHandler struct {
+	useCase usecases.HealthUseCaseInterface
+	logger  utils.LoggerInterface
+}
+
+func NewHealthHandler(
+	useCase usecases.HealthUseCaseInterface,
+	logger utils.LoggerInterface,
+) *HealthHandler {
+	return &HealthHandler{
+		useCase: useCase,
+		logger:  logger,
+	}
+}
+
+// Liveness reports that the process is up and serving requests. It does not touch
+// any dependency, so a database outage does not get the service restarted.
+func (h *HealthHandler) Liveness(w http.ResponseWriter, _ *http.Request) {
+	h.writeHealth(w, http.StatusOK, pdto.HealthResponse{Status: pdto.HealthStatusOK})
+}
+
+// Readiness reports whether the service can handle API requests: the database is
+// reachable and the schema is migrated to the expected version.
+func (h *HealthHandler) Readiness(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), readinessTimeout)
+	defer cancel()
+
+	report := h.useCase.CheckReadiness(ctx)
+
+	statusCode := http.StatusOK
+	if !report.Healthy {
+		statusCode = http.StatusServiceUnavailable
+	}
+
+	h.writeHealth(w, statusCode, mapper.MapHealthReportToResponse(report))
+}
+
+func (h *HealthHandler) writeHealth(w http.ResponseWriter, statusCode int, response pdto.HealthResponse) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(statusCode)
+
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		h.logger.Errorf("HANDLERS: failed to encode health response: %v", err)
+	}
+}
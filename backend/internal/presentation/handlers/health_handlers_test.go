@@ -0,0 +1,88 @@
+package handlers_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	adto "github.com/k6zma/DockerMonitoringApp/backend/internal/application/dto"
+	pdto "github.com/k6zma/DockerMonitoringApp/backend/internal/presentation/dto"
+	"github.com/k6zma/DockerMonitoringApp/backend/internal/presentation/handlers"
+	"github.com/k6zma/DockerMonitoringApp/backend/mocks"
+)
+
+func TestLiveness_ReturnsOK(t *testing.T) {
+	mockUseCase := new(mocks.HealthUseCaseInterface)
+	mockLogger := new(mocks.LoggerInterface)
+
+	handler := handlers.NewHealthHandler(mockUseCase, mockLogger)
+
+	req := httptest.NewRequest(http.MethodGet, "/healthz", http.NoBody)
+	rec := httptest.NewRecorder()
+
+	handler.Liveness(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	mockUseCase.AssertNotCalled(t, "CheckReadiness", mock.Anything)
+}
+
+func TestReadiness_Ready_ReturnsOK(t *testing.T) {
+	mockUseCase := new(mocks.HealthUseCaseInterface)
+	mockLogger := new(mocks.LoggerInterface)
+
+	handler := handlers.NewHealthHandler(mockUseCase, mockLogger)
+
+	mockUseCase.On("CheckReadiness", mock.Anything).Return(&adto.HealthReportDTO{
+		Healthy: true,
+		Checks: []adto.HealthCheckDTO{
+			{Name: "database", Healthy: true, Message: "ok"},
+			{Name: "migrations", Healthy: true, Message: "version 3"},
+		},
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/readyz", http.NoBody)
+	rec := httptest.NewRecorder()
+
+	handler.Readiness(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var response pdto.HealthResponse
+	err := json.Unmarshal(rec.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, pdto.HealthStatusOK, response.Status)
+	assert.Equal(t, "version 3", response.Checks["migrations"].Message)
+	mockUseCase.AssertExpectations(t)
+}
+
+func TestReadiness_NotReady_ReturnsServiceUnavailable(t *testing.T) {
+	mockUseCase := new(mocks.HealthUseCaseInterface)
+	mockLogger := new(mocks.LoggerInterface)
+
+	handler := handlers.NewHealthHandler(mockUseCase, mockLogger)
+
+	mockUseCase.On("CheckReadiness", mock.Anything).Return(&adto.HealthReportDTO{
+		Healthy: false,
+		Checks: []adto.HealthCheckDTO{
+			{Name: "database", Healthy: false, Message: "connection refused"},
+		},
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/readyz", http.NoBody)
+	rec := httptest.NewRecorder()
+
+	handler.Readiness(rec, req)
+
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+
+	var response pdto.HealthResponse
+	err := json.Unmarshal(rec.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, pdto.HealthStatusUnavailable, response.Status)
+	assert.Equal(t, pdto.HealthStatusUnavailable, response.Checks["database"].Status)
+	mockUseCase.AssertExpectations(t)
+}
@@ -0,0 +1,148 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gorilla/mux"
+
+	"github.com/k6zma/DockerMonitoringApp/backend/internal/application/usecases"
+	pdto "github.com/k6zma/DockerMonitoringApp/backend/internal/presentation/dto"
+	"github.com/k6zma/DockerMonitoringApp/backend/internal/presentation/mapper"
+	"github.com/k6zma/DockerMonitoringApp/backend/pkg/utils"
+)
+
+type RoleHandler struct {
+	useCase  usecases.RoleUseCaseInterface
+	validate *validator.Validate
+	logger   utils.LoggerInterface
+}
+
+func NewRoleHandler(
+	useCase usecases.RoleUseCaseInterface,
+	logger utils.LoggerInterface,
+) *RoleHandler {
+	return &RoleHandler{
+		useCase:  useCase,
+		validate: validator.New(),
+		logger:   logger,
+	}
+}
+
+// ListRoleAssignments godoc
+// @Summary List role assignments
+// @Description Returns the roles assigned to users, ordered by subject
+// @Tags Roles
+// @Produce json
+// @Success 200 {array} dto.GetRoleAssignmentResponse
+// @Failure 403 {string} string "Forbidden"
+// @Failure 500 {string} string "Internal Server Error"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /role_assignments [get].
+func (h *RoleHandler) ListRoleAssignments(w http.ResponseWriter, r *http.Request) {
+	logger := utils.ContextLogger(r.Context(), h.logger)
+
+	logger.Debugf("HANDLERS: received ListRoleAssignments request")
+
+	assignments, err := h.useCase.ListRoleAssignments(r.Context())
+	if err != nil {
+		logger.Errorf("HANDLERS: listRoleAssignments error: %v", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+
+	h.writeJSON(w, r, http.StatusOK, mapper.MapRoleAssignmentDTOsToResponse(assignments))
+}
+
+// AssignRole godoc
+// @Summary Assign a role
+// @Description Assigns a role to the user authenticated by a bearer token under the given subject, replacing the role assigned before. The role is added to the roles mapped from the token claims
+// @Tags Roles
+// @Accept json
+// @Produce json
+// @Param subject path string true "User name from the token"
+// @Param request body dto.AssignRoleRequest true "Role to assign"
+// @Success 200 {object} dto.GetRoleAssignmentResponse
+// @Failure 400 {string} string "Bad Request"
+// @Failure 403 {string} string "Forbidden"
+// @Failure 500 {string} string "Internal Server Error"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /role_assignments/{subject} [put].
+func (h *RoleHandler) AssignRole(w http.ResponseWriter, r *http.Request) {
+	subject := mux.Vars(r)["subject"]
+	logger := utils.ContextLogger(r.Context(), h.logger, "subject", subject)
+
+	logger.Debugf("HANDLERS: received AssignRole request for subject: %s", subject)
+
+	var req pdto.AssignRoleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Errorf("HANDLERS: assignRole decode error: %v", err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		logger.Errorf("HANDLERS: assignRole validation error: %v", err)
+		http.Error(w, "Validation error: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	assignment, err := h.useCase.AssignRole(r.Context(), subject, req.Role)
+	if errors.Is(err, usecases.ErrUnknownRole) {
+		logger.Errorf("HANDLERS: assignRole validation error: %v", err)
+		http.Error(w, "Validation error: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		logger.Errorf("HANDLERS: failed to assign role to %s: %v", subject, err)
+		http.Error(w, "Failed to assign role", http.StatusInternalServerError)
+		return
+	}
+
+	h.writeJSON(w, r, http.StatusOK, mapper.MapRoleAssignmentDTOToResponse(*assignment))
+}
+
+// RemoveRoleAssignment godoc
+// @Summary Remove a role assignment
+// @Description Removes the role assigned to the user. Roles mapped from the token claims are not affected
+// @Tags Roles
+// @Param subject path string true "User name from the token"
+// @Success 204 "No Content"
+// @Failure 403 {string} string "Forbidden"
+// @Failure 404 {string} string "Not Found"
+// @Failure 500 {string} string "Internal Server Error"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /role_assignments/{subject} [delete].
+func (h *RoleHandler) RemoveRoleAssignment(w http.ResponseWriter, r *http.Request) {
+	subject := mux.Vars(r)["subject"]
+	logger := utils.ContextLogger(r.Context(), h.logger, "subject", subject)
+
+	logger.Debugf("HANDLERS: received RemoveRoleAssignment request for subject: %s", subject)
+
+	err := h.useCase.RemoveRoleAssignment(r.Context(), subject)
+	if errors.Is(err, usecases.ErrRoleAssignmentNotFound) {
+		logger.Warnf("HANDLERS: no role assigned to %s", subject)
+		http.Error(w, "Role assignment not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		logger.Errorf("HANDLERS: failed to remove role of %s: %v", subject, err)
+		http.Error(w, "Failed to remove role assignment", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *RoleHandler) writeJSON(w http.ResponseWriter, r *http.Request, status int, response interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		utils.ContextLogger(r.Context(), h.logger).Errorf("HANDLERS: error encoding response: %v", err)
+	}
+}
@@ -0,0 +1,86 @@
+package handlers_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	adto "github.com/k6zma/DockerMonitoringApp/backend/internal/application/dto"
+	"github.com/k6zma/DockerMonitoringApp/backend/internal/application/usecases"
+	pdto "github.com/k6zma/DockerMonitoringApp/backend/internal/presentation/dto"
+	"github.com/k6zma/DockerMonitoringApp/backend/internal/presentation/handlers"
+	"github.com/k6zma/DockerMonitoringApp/backend/mocks"
+)
+
+func TestAssignRole_ReturnsAssignment(t *testing.T) {
+	mockUseCase := new(mocks.RoleUseCaseInterface)
+	mockLogger := new(mocks.LoggerInterface)
+
+	handler := handlers.NewRoleHandler(mockUseCase, mockLogger)
+
+	mockUseCase.On("AssignRole", mock.Anything, "alice", "operator").
+		Return(&adto.RoleAssignmentDTO{Subject: "alice", Role: "operator"}, nil)
+	mockLogger.On("Debugf", mock.Anything, mock.Anything).Return()
+
+	body, _ := json.Marshal(pdto.AssignRoleRequest{Role: "operator"})
+	req := httptest.NewRequest(http.MethodPut, "/role_assignments/alice", bytes.NewReader(body))
+	req = mux.SetURLVars(req, map[string]string{"subject": "alice"})
+	rec := httptest.NewRecorder()
+
+	handler.AssignRole(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var response pdto.GetRoleAssignmentResponse
+	err := json.NewDecoder(rec.Body).Decode(&response)
+	assert.NoError(t, err)
+	assert.Equal(t, "alice", response.Subject)
+	assert.Equal(t, "operator", response.Role)
+
+	mockUseCase.AssertExpectations(t)
+}
+
+func TestAssignRole_UnknownRole_ReturnsBadRequest(t *testing.T) {
+	mockUseCase := new(mocks.RoleUseCaseInterface)
+	mockLogger := new(mocks.LoggerInterface)
+
+	handler := handlers.NewRoleHandler(mockUseCase, mockLogger)
+
+	mockLogger.On("Debugf", mock.Anything, mock.Anything).Return()
+	mockLogger.On("Errorf", mock.Anything, mock.Anything).Return()
+
+	req := httptest.NewRequest(http.MethodPut, "/role_assignments/alice", bytes.NewBufferString(`{"role":"superuser"}`))
+	req = mux.SetURLVars(req, map[string]string{"subject": "alice"})
+	rec := httptest.NewRecorder()
+
+	handler.AssignRole(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	mockUseCase.AssertNotCalled(t, "AssignRole", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestRemoveRoleAssignment_NotFound_ReturnsNotFound(t *testing.T) {
+	mockUseCase := new(mocks.RoleUseCaseInterface)
+	mockLogger := new(mocks.LoggerInterface)
+
+	handler := handlers.NewRoleHandler(mockUseCase, mockLogger)
+
+	mockUseCase.On("RemoveRoleAssignment", mock.Anything, "alice").Return(usecases.ErrRoleAssignmentNotFound)
+	mockLogger.On("Debugf", mock.Anything, mock.Anything).Return()
+	mockLogger.On("Warnf", mock.Anything, mock.Anything).Return()
+
+	req := httptest.NewRequest(http.MethodDelete, "/role_assignments/alice", http.NoBody)
+	req = mux.SetURLVars(req, map[string]string{"subject": "alice"})
+	rec := httptest.NewRecorder()
+
+	handler.RemoveRoleAssignment(rec, req)
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	mockUseCase.AssertExpectations(t)
+}
@@ -0,0 +1,414 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gorilla/websocket"
+
+	adto "github.com/k6zma/DockerMonitoringApp/backend/internal/application/dto"
+	"github.com/k6zma/DockerMonitoringApp/backend/internal/application/usecases"
+	"github.com/k6zma/DockerMonitoringApp/backend/internal/domain"
+	pdto "github.com/k6zma/DockerMonitoringApp/backend/internal/presentation/dto"
+	"github.com/k6zma/DockerMonitoringApp/backend/internal/presentation/mapper"
+	"github.com/k6zma/DockerMonitoringApp/backend/pkg/utils"
+)
+
+// StatusStreamProtocol is the WebSocket subprotocol of the status stream.
+// Browsers must offer it to have the connection accepted when they pass their
+// credentials as a subprotocol.
+const StatusStreamProtocol = "container-status.v1"
+
+const (
+	// streamWriteTimeout bounds every write to a status stream client.
+	streamWriteTimeout = 10 * time.Second
+	// maxStreamRequestSize limits the messages read from a client.
+	maxStreamRequestSize = 4096
+	// eventStreamRetry is how long event stream clients wait before they
+	// reconnect.
+	eventStreamRetry = 3 * time.Second
+)
+
+type StatusStreamHandler struct {
+	subscriber   usecases.StatusSubscriber
+	upgrader     websocket.Upgrader
+	pingInterval time.Duration
+	validate     *validator.Validate
+	logger       utils.LoggerInterface
+}
+
+// NewStatusStreamHandler creates the handler of the WebSocket status stream.
+// Handshakes from the origin of the API itself are accepted, as are those from
+// the origins allowedOrigin accepts.
+func NewStatusStreamHandler(
+	subscriber usecases.StatusSubscriber,
+	pingInterval time.Duration,
+	allowedOrigin func(origin string) bool,
+	logger utils.LoggerInterface,
+) *StatusStreamHandler {
+	return &StatusStreamHandler{
+		subscriber: subscriber,
+		upgrader: websocket.Upgrader{
+			HandshakeTimeout: streamWriteTimeout,
+			Subprotocols:     []string{StatusStreamProtocol},
+			CheckOrigin: func(r *http.Request) bool {
+				origin := r.Header.Get("Origin")
+				if origin == "" {
+					return true
+				}
+
+				u, err := url.Parse(origin)
+				if err == nil && strings.EqualFold(u.Host, r.Host) {
+					return true
+				}
+
+				return allowedOrigin(origin)
+			},
+		},
+		pingInterval: pingInterval,
+		validate:     validator.New(),
+		logger:       logger,
+	}
+}
+
+// StreamContainerStatuses godoc
+// @Summary Stream container status changes
+// @Description Upgrades to a WebSocket that receives a message whenever a container status is created, updated or deleted.
+// @Description The filter can be replaced by sending {"action": "subscribe", "names": [], "statuses": [], "labels": {}}.
+// @Description An update is sent when the container matches the filter before or after it.
+// @Tags Containers
+// @Param name query string false "Comma separated list of container names"
+// @Param status query string false "Comma separated list of statuses"
+// @Param label query string false "Comma separated list of key=value labels the container must all have"
+// @Success 101 {object} dto.StatusChangeMessage "Switching Protocols; one message per change follows"
+// @Failure 400 {string} string "Bad Request"
+// @Failure 403 {string} string "Forbidden"
+// @Failure 503 {string} string "Service Unavailable"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /ws [get].
+func (h *StatusStreamHandler) StreamContainerStatuses(w http.ResponseWriter, r *http.Request) {
+	logger := utils.ContextLogger(r.Context(), h.logger)
+
+	logger.Debugf("HANDLERS: received StreamContainerStatuses request with query: %s", r.URL.RawQuery)
+
+	filter, err := parseStreamFilter(r.URL.Query())
+	if err != nil {
+		logger.Errorf("HANDLERS: error parsing status stream filter: %v", err)
+		http.Error(w, "Invalid label param", http.StatusBadRequest)
+		return
+	}
+
+	subscription, ok := h.subscribe(w, r, &filter, "")
+	if !ok {
+		return
+	}
+	defer h.subscriber.Unsubscribe(subscription)
+
+	// The upgrader answers failed handshakes itself.
+	conn, err := h.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		logger.Warnf("HANDLERS: status stream handshake failed: %v", err)
+		return
+	}
+	defer conn.Close()
+
+	logger.Debugf("HANDLERS: status stream connected with filter: %+v", filter)
+
+	done := make(chan struct{})
+	go h.readRequests(r, conn, subscription, done)
+
+	h.writeChanges(r, conn, subscription, done)
+}
+
+// StreamContainerStatusEvents godoc
+// @Summary Stream container status changes and alerts as server-sent events
+// @Description Sends a "status" event whenever a container status is created, updated or deleted and an "alert" event
+// @Description whenever a crash loop is detected or resolved. Every event has an id; a client reconnecting with it in
+// @Description Last-Event-ID (or last_event_id) first receives the events it missed. When they are no longer kept, a
+// @Description "reset" event tells the client to reload the statuses.
+// @Tags Containers
+// @Produce text/event-stream
+// @Param name query string false "Comma separated list of container names"
+// @Param status query string false "Comma separated list of statuses"
+// @Param label query string false "Comma separated list of key=value labels the container must all have"
+// @Param last_event_id query string false "ID of the last received event, for clients that cannot set Last-Event-ID"
+// @Param Last-Event-ID header string false "ID of the last received event"
+// @Success 200 {object} dto.StatusChangeMessage "status events; alert events carry a dto.StatusAlertMessage"
+// @Failure 400 {string} string "Bad Request"
+// @Failure 503 {string} string "Service Unavailable"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /stream [get].
+func (h *StatusStreamHandler) StreamContainerStatusEvents(w http.ResponseWriter, r *http.Request) {
+	logger := utils.ContextLogger(r.Context(), h.logger)
+
+	logger.Debugf("HANDLERS: received StreamContainerStatusEvents request with query: %s", r.URL.RawQuery)
+
+	filter, err := parseStreamFilter(r.URL.Query())
+	if err != nil {
+		logger.Errorf("HANDLERS: error parsing status stream filter: %v", err)
+		http.Error(w, "Invalid label param", http.StatusBadRequest)
+		return
+	}
+	filter.Alerts = true
+
+	lastEventID := r.Header.Get("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = r.URL.Query().Get("last_event_id")
+	}
+
+	subscription, ok := h.subscribe(w, r, &filter, lastEventID)
+	if !ok {
+		return
+	}
+	defer h.subscriber.Unsubscribe(subscription)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	// Keeps nginx from buffering the events.
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	stream := &eventStream{w: w, rc: http.NewResponseController(w)}
+
+	// A stream that has not delivered anything yet resumes from the position
+	// it started at.
+	stream.printf("retry: %d\n", eventStreamRetry.Milliseconds())
+	switch {
+	case subscription.HistoryLost():
+		logger.Debugf("HANDLERS: events after %q are no longer kept", lastEventID)
+		stream.printf("id: %s\nevent: reset\ndata: {}\n\n", subscription.LastEventID())
+	case lastEventID == "":
+		stream.printf("id: %s\n\n", subscription.LastEventID())
+	default:
+		stream.printf("\n")
+	}
+	if err := stream.flush(); err != nil {
+		logger.Warnf("HANDLERS: failed to start event stream: %v", err)
+		return
+	}
+
+	logger.Debugf("HANDLERS: event stream connected with filter: %+v", filter)
+
+	ticker := time.NewTicker(h.pingInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case change, ok := <-subscription.Changes():
+			if !ok {
+				// The client reconnects and resumes where it stopped.
+				logger.Debugf("HANDLERS: event stream ended: %v", subscription.Err())
+				return
+			}
+
+			if change.Type == domain.StatusChangeAlert {
+				stream.event(change.ID, "alert", mapper.MapStatusChangeDTOToAlertMessage(*change))
+			} else {
+				stream.event(change.ID, "status", mapper.MapStatusChangeDTOToMessage(*change))
+			}
+		case <-ticker.C:
+			stream.printf(": ping\n\n")
+		case <-r.Context().Done():
+			return
+		}
+
+		if err := stream.flush(); err != nil {
+			logger.Warnf("HANDLERS: failed to send to event stream client: %v", err)
+			return
+		}
+	}
+}
+
+// subscribe subscribes to the changes matching filter, answering the request
+// itself when that fails.
+func (h *StatusStreamHandler) subscribe(
+	w http.ResponseWriter,
+	r *http.Request,
+	filter *adto.StatusChangeFilter,
+	lastEventID string,
+) (*usecases.StatusSubscription, bool) {
+	logger := utils.ContextLogger(r.Context(), h.logger)
+
+	subscription, err := h.subscriber.Subscribe(r.Context(), filter, lastEventID)
+	if errors.Is(err, usecases.ErrTooManySubscribers) || errors.Is(err, usecases.ErrStatusHubClosed) {
+		logger.Warnf("HANDLERS: refusing status stream: %v", err)
+		w.Header().Set("Retry-After", "5")
+		http.Error(w, "Service Unavailable", http.StatusServiceUnavailable)
+		return nil, false
+	}
+	if err != nil {
+		logger.Errorf("HANDLERS: failed to subscribe to status changes: %v", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return nil, false
+	}
+
+	return subscription, true
+}
+
+// readRequests applies the filters sent by the client and extends the read
+// deadline whenever it answers a ping. done is closed when the connection is
+// closed or fails.
+func (h *StatusStreamHandler) readRequests(
+	r *http.Request,
+	conn *websocket.Conn,
+	subscription *usecases.StatusSubscription,
+	done chan<- struct{},
+) {
+	defer close(done)
+
+	logger := utils.ContextLogger(r.Context(), h.logger)
+
+	conn.SetReadLimit(maxStreamRequestSize)
+	_ = conn.SetReadDeadline(time.Now().Add(2 * h.pingInterval))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(2 * h.pingInterval))
+	})
+
+	for {
+		_, message, err := conn.ReadMessage()
+		if err != nil {
+			if websocket.IsUnexpectedCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
+				logger.Warnf("HANDLERS: status stream read error: %v", err)
+			}
+			return
+		}
+
+		var req pdto.StatusStreamRequest
+		err = json.Unmarshal(message, &req)
+		if err == nil {
+			err = h.validate.Struct(req)
+		}
+		if err != nil {
+			logger.Errorf("HANDLERS: invalid status stream request: %v", err)
+			closeStream(conn, websocket.CloseUnsupportedData, "invalid request")
+			return
+		}
+
+		filter := mapper.MapStatusStreamRequestToFilter(req)
+		subscription.SetFilter(&filter)
+
+		logger.Debugf("HANDLERS: status stream filter changed to: %+v", filter)
+	}
+}
+
+// writeChanges sends the changes and the pings to the client until the
+// connection or the subscription ends.
+func (h *StatusStreamHandler) writeChanges(
+	r *http.Request,
+	conn *websocket.Conn,
+	subscription *usecases.StatusSubscription,
+	done <-chan struct{},
+) {
+	logger := utils.ContextLogger(r.Context(), h.logger)
+
+	ticker := time.NewTicker(h.pingInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case change, ok := <-subscription.Changes():
+			if !ok {
+				switch {
+				case errors.Is(subscription.Err(), usecases.ErrSubscriberTooSlow):
+					closeStream(conn, websocket.CloseTryAgainLater, "too many undelivered changes")
+				case errors.Is(subscription.Err(), usecases.ErrChangesMissed):
+					closeStream(conn, websocket.CloseTryAgainLater, "changes were missed")
+				case errors.Is(subscription.Err(), usecases.ErrStatusHubClosed):
+					closeStream(conn, websocket.CloseGoingAway, "server is shutting down")
+				}
+				return
+			}
+
+			_ = conn.SetWriteDeadline(time.Now().Add(streamWriteTimeout))
+			if err := conn.WriteJSON(mapper.MapStatusChangeDTOToMessage(*change)); err != nil {
+				logger.Warnf("HANDLERS: failed to send status change: %v", err)
+				return
+			}
+		case <-ticker.C:
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(streamWriteTimeout)); err != nil {
+				logger.Warnf("HANDLERS: failed to ping status stream client: %v", err)
+				return
+			}
+		case <-done:
+			return
+		}
+	}
+}
+
+// parseStreamFilter reads the name, status and label params of a status
+// stream.
+func parseStreamFilter(queryParams url.Values) (adto.StatusChangeFilter, error) {
+	filter := adto.StatusChangeFilter{}
+
+	if names := queryParams.Get("name"); names != "" {
+		filter.Names = strings.Split(names, ",")
+	}
+
+	if statuses := queryParams.Get("status"); statuses != "" {
+		filter.Statuses = strings.Split(statuses, ",")
+	}
+
+	if labels := queryParams.Get("label"); labels != "" {
+		filter.Labels = make(map[string]string)
+		for _, label := range strings.Split(labels, ",") {
+			key, value, ok := strings.Cut(label, "=")
+			if !ok || key == "" {
+				return filter, fmt.Errorf("label %q is not key=value", label)
+			}
+			filter.Labels[key] = value
+		}
+	}
+
+	return filter, nil
+}
+
+// eventStream writes server-sent events. The first failed write is kept and
+// returned by flush, later writes are skipped.
+type eventStream struct {
+	w   http.ResponseWriter
+	rc  *http.ResponseController
+	err error
+}
+
+func (s *eventStream) printf(format string, args ...any) {
+	if s.err != nil {
+		return
+	}
+
+	// Writes are only bounded by this deadline, the server write timeout
+	// would end the stream.
+	_ = s.rc.SetWriteDeadline(time.Now().Add(streamWriteTimeout))
+	_, s.err = fmt.Fprintf(s.w, format, args...)
+}
+
+func (s *eventStream) event(id, event string, data any) {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		s.err = err
+		return
+	}
+
+	s.printf("id: %s\nevent: %s\ndata: %s\n\n", id, event, payload)
+}
+
+func (s *eventStream) flush() error {
+	if s.err != nil {
+		return s.err
+	}
+
+	return s.rc.Flush()
+}
+
+// closeStream sends a close frame; the connection is closed by the caller.
+func closeStream(conn *websocket.Conn, code int, reason string) {
+	_ = conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(code, reason),
+		time.Now().Add(streamWriteTimeout))
+}
@@ -0,0 +1,289 @@
+package handlers_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/k6zma/DockerMonitoringApp/backend/internal/application/usecases"
+	"github.com/k6zma/DockerMonitoringApp/backend/internal/domain"
+	pdto "github.com/k6zma/DockerMonitoringApp/backend/internal/presentation/dto"
+	"github.com/k6zma/DockerMonitoringApp/backend/internal/presentation/handlers"
+	"github.com/k6zma/DockerMonitoringApp/backend/mocks"
+)
+
+func newStreamServer(t *testing.T, hub *usecases.StatusHub) string {
+	t.Helper()
+
+	mockLogger := new(mocks.LoggerInterface)
+	mockLogger.On("Debugf", mock.Anything, mock.Anything).Return()
+	mockLogger.On("Warnf", mock.Anything, mock.Anything).Return()
+	mockLogger.On("Errorf", mock.Anything, mock.Anything).Return()
+
+	handler := handlers.NewStatusStreamHandler(hub, time.Minute, func(string) bool { return false }, mockLogger)
+
+	server := httptest.NewServer(http.HandlerFunc(handler.StreamContainerStatuses))
+	t.Cleanup(server.Close)
+
+	return "ws" + strings.TrimPrefix(server.URL, "http")
+}
+
+func publishStatus(hub *usecases.StatusHub, name, status string, labels map[string]string) {
+	hub.Publish(context.Background(), &domain.ContainerStatusChange{
+		Type:       domain.StatusChangeUpdated,
+		Status:     domain.ContainerStatus{ContainerID: name + "-id", Name: name, Status: status, Labels: labels},
+		OccurredAt: time.Now(),
+	})
+}
+
+func TestStreamContainerStatuses_SendsMatchingChanges(t *testing.T) {
+	hub := usecases.NewStatusHub(10, 10, 10, new(mocks.LoggerInterface))
+	url := newStreamServer(t, hub)
+
+	conn, resp, err := websocket.DefaultDialer.Dial(url+"?label=team=payments", http.Header{
+		"Sec-WebSocket-Protocol": {handlers.StatusStreamProtocol},
+	})
+	require.NoError(t, err)
+	defer conn.Close()
+	assert.Equal(t, handlers.StatusStreamProtocol, resp.Header.Get("Sec-WebSocket-Protocol"))
+
+	publishStatus(hub, "db", "running", map[string]string{"team": "storage"})
+	publishStatus(hub, "web", "running", map[string]string{"team": "payments"})
+
+	var message pdto.StatusChangeMessage
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(time.Second)))
+	require.NoError(t, conn.ReadJSON(&message))
+	assert.Equal(t, domain.StatusChangeUpdated, message.Type)
+	assert.Equal(t, "web", message.Container.Name)
+	assert.Equal(t, map[string]string{"team": "payments"}, message.Container.Labels)
+}
+
+func TestStreamContainerStatuses_SubscribeReplacesFilter(t *testing.T) {
+	hub := usecases.NewStatusHub(10, 10, 10, new(mocks.LoggerInterface))
+	url := newStreamServer(t, hub)
+
+	conn, _, err := websocket.DefaultDialer.Dial(url+"?name=db", nil)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	require.NoError(t, conn.WriteJSON(pdto.StatusStreamRequest{Action: "subscribe", Names: []string{"web"}}))
+	// The request is applied asynchronously.
+	time.Sleep(50 * time.Millisecond)
+
+	publishStatus(hub, "db", "running", nil)
+	publishStatus(hub, "web", "exited", nil)
+
+	var message pdto.StatusChangeMessage
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(time.Second)))
+	require.NoError(t, conn.ReadJSON(&message))
+	assert.Equal(t, "web", message.Container.Name)
+	assert.Equal(t, "exited", message.Container.Status)
+}
+
+func TestStreamContainerStatuses_InvalidRequest_ClosesConnection(t *testing.T) {
+	hub := usecases.NewStatusHub(10, 10, 10, new(mocks.LoggerInterface))
+	url := newStreamServer(t, hub)
+
+	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	require.NoError(t, conn.WriteJSON(map[string]string{"action": "unsubscribe"}))
+
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(time.Second)))
+	_, _, err = conn.ReadMessage()
+	assert.True(t, websocket.IsCloseError(err, websocket.CloseUnsupportedData), "unexpected error %v", err)
+}
+
+func TestStreamContainerStatuses_HubClosed_ClosesConnection(t *testing.T) {
+	hub := usecases.NewStatusHub(10, 10, 10, new(mocks.LoggerInterface))
+	url := newStreamServer(t, hub)
+
+	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	hub.Close()
+
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(time.Second)))
+	_, _, err = conn.ReadMessage()
+	assert.True(t, websocket.IsCloseError(err, websocket.CloseGoingAway), "unexpected error %v", err)
+}
+
+func TestStreamContainerStatuses_Refused(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		code  int
+	}{
+		{name: "invalid label", query: "?label=team", code: http.StatusBadRequest},
+		{name: "too many subscribers", code: http.StatusServiceUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockLogger := new(mocks.LoggerInterface)
+			mockLogger.On("Debugf", mock.Anything, mock.Anything).Return()
+			mockLogger.On("Warnf", mock.Anything, mock.Anything).Return()
+			mockLogger.On("Errorf", mock.Anything, mock.Anything).Return()
+
+			hub := usecases.NewStatusHub(1, 10, 10, mockLogger)
+			_, err := hub.Subscribe(context.Background(), nil, "")
+			require.NoError(t, err)
+
+			handler := handlers.NewStatusStreamHandler(hub, time.Minute, func(string) bool { return false }, mockLogger)
+
+			req := httptest.NewRequest(http.MethodGet, "/ws"+tt.query, http.NoBody)
+			rec := httptest.NewRecorder()
+
+			handler.StreamContainerStatuses(rec, req)
+
+			assert.Equal(t, tt.code, rec.Code)
+		})
+	}
+}
+
+func TestStreamContainerStatuses_ForeignOrigin_IsRejected(t *testing.T) {
+	hub := usecases.NewStatusHub(10, 10, 10, new(mocks.LoggerInterface))
+	url := newStreamServer(t, hub)
+
+	_, resp, err := websocket.DefaultDialer.Dial(url, http.Header{"Origin": {"https://evil.example.com"}})
+
+	require.Error(t, err)
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+}
+
+func newEventStreamServer(t *testing.T, hub *usecases.StatusHub) string {
+	t.Helper()
+
+	mockLogger := new(mocks.LoggerInterface)
+	mockLogger.On("Debugf", mock.Anything, mock.Anything).Return()
+	mockLogger.On("Warnf", mock.Anything, mock.Anything).Return()
+
+	handler := handlers.NewStatusStreamHandler(hub, time.Minute, func(string) bool { return false }, mockLogger)
+
+	server := httptest.NewServer(http.HandlerFunc(handler.StreamContainerStatusEvents))
+	t.Cleanup(server.Close)
+
+	return server.URL
+}
+
+func openEventStream(t *testing.T, url, lastEventID string) *bufio.Reader {
+	t.Helper()
+
+	req, err := http.NewRequest(http.MethodGet, url, http.NoBody)
+	require.NoError(t, err)
+	if lastEventID != "" {
+		req.Header.Set("Last-Event-ID", lastEventID)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	t.Cleanup(func() { resp.Body.Close() })
+
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+	return bufio.NewReader(resp.Body)
+}
+
+// readEvent reads the fields of the next event up to the empty line ending it.
+func readEvent(t *testing.T, reader *bufio.Reader) map[string]string {
+	t.Helper()
+
+	fields := make(map[string]string)
+	for {
+		line, err := reader.ReadString('\n')
+		require.NoError(t, err)
+
+		line = strings.TrimSuffix(line, "\n")
+		if line == "" {
+			return fields
+		}
+
+		name, value, _ := strings.Cut(line, ": ")
+		fields[name] = value
+	}
+}
+
+func TestStreamContainerStatusEvents_SendsChangesAndAlerts(t *testing.T) {
+	hub := usecases.NewStatusHub(10, 10, 10, new(mocks.LoggerInterface))
+	reader := openEventStream(t, newEventStreamServer(t, hub)+"?name=web", "")
+
+	start := readEvent(t, reader)
+	assert.Equal(t, "3000", start["retry"])
+	assert.NotEmpty(t, start["id"])
+
+	publishStatus(hub, "db", "running", nil)
+	publishStatus(hub, "web", "restarting", nil)
+	hub.Publish(context.Background(), &domain.ContainerStatusChange{
+		Type:   domain.StatusChangeAlert,
+		Status: domain.ContainerStatus{ContainerID: "web-id", Name: "web", Status: "restarting", CrashLooping: true},
+		Event: &domain.ContainerEvent{
+			ID:          7,
+			ContainerID: "web-id",
+			Type:        domain.EventTypeCrashLoopDetected,
+			Status:      "restarting",
+		},
+		OccurredAt: time.Now(),
+	})
+
+	status := readEvent(t, reader)
+	assert.Equal(t, "status", status["event"])
+	assert.NotEqual(t, start["id"], status["id"])
+
+	var change pdto.StatusChangeMessage
+	require.NoError(t, json.Unmarshal([]byte(status["data"]), &change))
+	assert.Equal(t, "web", change.Container.Name)
+	assert.Equal(t, "restarting", change.Container.Status)
+
+	alert := readEvent(t, reader)
+	assert.Equal(t, "alert", alert["event"])
+
+	var message pdto.StatusAlertMessage
+	require.NoError(t, json.Unmarshal([]byte(alert["data"]), &message))
+	assert.Equal(t, domain.EventTypeCrashLoopDetected, message.Event.Type)
+	assert.Equal(t, int64(7), message.Event.ID)
+	assert.Equal(t, []string{domain.ConditionCrashLooping}, message.Container.Conditions)
+}
+
+func TestStreamContainerStatusEvents_ResumesFromLastEventID(t *testing.T) {
+	hub := usecases.NewStatusHub(10, 10, 10, new(mocks.LoggerInterface))
+	url := newEventStreamServer(t, hub)
+
+	subscription, err := hub.Subscribe(context.Background(), nil, "")
+	require.NoError(t, err)
+	publishStatus(hub, "web", "running", nil)
+	lastEventID := (<-subscription.Changes()).ID
+	publishStatus(hub, "web", "exited", nil)
+
+	reader := openEventStream(t, url, lastEventID)
+
+	assert.Equal(t, map[string]string{"retry": "3000"}, readEvent(t, reader))
+
+	missed := readEvent(t, reader)
+	assert.Equal(t, "status", missed["event"])
+
+	var change pdto.StatusChangeMessage
+	require.NoError(t, json.Unmarshal([]byte(missed["data"]), &change))
+	assert.Equal(t, "exited", change.Container.Status)
+}
+
+func TestStreamContainerStatusEvents_UnknownLastEventID_SendsReset(t *testing.T) {
+	hub := usecases.NewStatusHub(10, 10, 10, new(mocks.LoggerInterface))
+	reader := openEventStream(t, newEventStreamServer(t, hub), "before-restart")
+
+	reset := readEvent(t, reader)
+	assert.Equal(t, "reset", reset["event"])
+	assert.NotEmpty(t, reset["id"])
+}
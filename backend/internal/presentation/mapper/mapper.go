@@ -2,6 +2,7 @@ package mapper
 
 import (
 	adto "github.com/k6zma/DockerMonitoringApp/backend/internal/application/dto"
+	"github.com/k6zma/DockerMonitoringApp/backend/internal/domain"
 	pdto "github.com/k6zma/DockerMonitoringApp/backend/internal/presentation/dto"
 )
 
@@ -13,6 +14,9 @@ func MapCreateRequestToAppDTO(req pdto.CreateContainerStatusRequest) adto.Contai
 		Status:             req.Status,
 		PingTime:           req.PingTime,
 		LastSuccessfulPing: req.LastSuccessfulPing,
+		RestartCount:       &req.RestartCount,
+		Labels:             req.Labels,
+		CheckedAt:          req.CheckedAt,
 	}
 }
 
@@ -22,6 +26,9 @@ func MapUpdateRequestToAppDTO(req pdto.UpdateContainerStatusRequest) adto.Contai
 		Status:             req.Status,
 		PingTime:           req.PingTime,
 		LastSuccessfulPing: req.LastSuccessfulPing,
+		RestartCount:       req.RestartCount,
+		Labels:             req.Labels,
+		CheckedAt:          req.CheckedAt,
 	}
 }
 
@@ -33,6 +40,9 @@ func MapAppDTOToResponse(appDTO adto.ContainerStatusDTO) pdto.GetContainerStatus
 		Status:             appDTO.Status,
 		PingTime:           appDTO.PingTime,
 		LastSuccessfulPing: appDTO.LastSuccessfulPing,
+		RestartCount:       restartCount(appDTO),
+		Conditions:         mapConditions(appDTO),
+		Labels:             mapLabels(appDTO.Labels),
 		CreatedAt:          appDTO.CreatedAt,
 		UpdatedAt:          appDTO.UpdatedAt,
 	}
@@ -46,3 +56,183 @@ func MapAppDTOsToResponse(appDTOs []*adto.ContainerStatusDTO) []pdto.GetContaine
 
 	return responses
 }
+
+func MapStatusChangeDTOToMessage(appDTO adto.ContainerStatusChangeDTO) pdto.StatusChangeMessage {
+	return pdto.StatusChangeMessage{
+		Type:       appDTO.Type,
+		Container:  MapAppDTOToResponse(appDTO.Status),
+		OccurredAt: appDTO.OccurredAt,
+	}
+}
+
+func MapStatusChangeDTOToAlertMessage(appDTO adto.ContainerStatusChangeDTO) pdto.StatusAlertMessage {
+	return pdto.StatusAlertMessage{
+		Event:      MapEventDTOToResponse(*appDTO.Event),
+		Container:  MapAppDTOToResponse(appDTO.Status),
+		OccurredAt: appDTO.OccurredAt,
+	}
+}
+
+func MapStatusStreamRequestToFilter(req pdto.StatusStreamRequest) adto.StatusChangeFilter {
+	return adto.StatusChangeFilter{
+		Names:    req.Names,
+		Statuses: req.Statuses,
+		Labels:   req.Labels,
+	}
+}
+
+// mapConditions derives the list of conditions reported for a container.
+func mapConditions(appDTO adto.ContainerStatusDTO) []string {
+	conditions := make([]string, 0, 1)
+	if appDTO.CrashLooping {
+		conditions = append(conditions, domain.ConditionCrashLooping)
+	}
+
+	return conditions
+}
+
+// restartCount returns the restart count of appDTO, zero when it is not set.
+func restartCount(appDTO adto.ContainerStatusDTO) int {
+	if appDTO.RestartCount == nil {
+		return 0
+	}
+
+	return *appDTO.RestartCount
+}
+
+// mapLabels returns labels, or an empty map so that they are never encoded as
+// null.
+func mapLabels(labels map[string]string) map[string]string {
+	if labels == nil {
+		return map[string]string{}
+	}
+
+	return labels
+}
+
+func MapEventDTOToResponse(appDTO adto.ContainerEventDTO) pdto.GetContainerEventResponse {
+	return pdto.GetContainerEventResponse{
+		ID:          appDTO.ID,
+		ContainerID: appDTO.ContainerID,
+		Type:        appDTO.Type,
+		Status:      appDTO.Status,
+		Message:     appDTO.Message,
+		CreatedAt:   appDTO.CreatedAt,
+	}
+}
+
+func MapEventDTOsToResponse(appDTOs []*adto.ContainerEventDTO) []pdto.GetContainerEventResponse {
+	var responses = make([]pdto.GetContainerEventResponse, 0, len(appDTOs))
+	for _, dto := range appDTOs {
+		responses = append(responses, MapEventDTOToResponse(*dto))
+	}
+
+	return responses
+}
+
+func MapHealthReportToResponse(report *adto.HealthReportDTO) pdto.HealthResponse {
+	checks := make(map[string]pdto.HealthCheckResponse, len(report.Checks))
+	for _, check := range report.Checks {
+		checks[check.Name] = pdto.HealthCheckResponse{
+			Status:  healthStatus(check.Healthy),
+			Message: check.Message,
+		}
+	}
+
+	return pdto.HealthResponse{
+		Status: healthStatus(report.Healthy),
+		Checks: checks,
+	}
+}
+
+func healthStatus(healthy bool) string {
+	if healthy {
+		return pdto.HealthStatusOK
+	}
+
+	return pdto.HealthStatusUnavailable
+}
+
+func MapCreateAPIKeyRequestToAppDTO(req pdto.CreateAPIKeyRequest) adto.CreateAPIKeyDTO {
+	return adto.CreateAPIKeyDTO{
+		Name:      req.Name,
+		Scopes:    req.Scopes,
+		AuthMode:  req.AuthMode,
+		ExpiresAt: req.ExpiresAt,
+	}
+}
+
+func MapAPIKeyDTOToResponse(appDTO adto.APIKeyDTO) pdto.GetAPIKeyResponse {
+	return pdto.GetAPIKeyResponse{
+		ID:         appDTO.ID,
+		Name:       appDTO.Name,
+		Prefix:     appDTO.Prefix,
+		Scopes:     appDTO.Scopes,
+		AuthMode:   appDTO.AuthMode,
+		CreatedAt:  appDTO.CreatedAt,
+		LastUsedAt: appDTO.LastUsedAt,
+		ExpiresAt:  appDTO.ExpiresAt,
+		RevokedAt:  appDTO.RevokedAt,
+	}
+}
+
+func MapAPIKeyDTOsToResponse(appDTOs []*adto.APIKeyDTO) []pdto.GetAPIKeyResponse {
+	var responses = make([]pdto.GetAPIKeyResponse, 0, len(appDTOs))
+	for _, dto := range appDTOs {
+		responses = append(responses, MapAPIKeyDTOToResponse(*dto))
+	}
+
+	return responses
+}
+
+func MapIssuedAPIKeyDTOToResponse(appDTO adto.IssuedAPIKeyDTO) pdto.IssuedAPIKeyResponse {
+	return pdto.IssuedAPIKeyResponse{
+		GetAPIKeyResponse: MapAPIKeyDTOToResponse(appDTO.APIKeyDTO),
+		Key:               appDTO.Key,
+	}
+}
+
+func MapRoleAssignmentDTOToResponse(appDTO adto.RoleAssignmentDTO) pdto.GetRoleAssignmentResponse {
+	return pdto.GetRoleAssignmentResponse{
+		Subject:   appDTO.Subject,
+		Role:      appDTO.Role,
+		CreatedAt: appDTO.CreatedAt,
+		UpdatedAt: appDTO.UpdatedAt,
+	}
+}
+
+func MapRoleAssignmentDTOsToResponse(appDTOs []*adto.RoleAssignmentDTO) []pdto.GetRoleAssignmentResponse {
+	var responses = make([]pdto.GetRoleAssignmentResponse, 0, len(appDTOs))
+	for _, dto := range appDTOs {
+		responses = append(responses, MapRoleAssignmentDTOToResponse(*dto))
+	}
+
+	return responses
+}
+
+func MapAuditEntryDTOToResponse(appDTO adto.AuditEntryDTO) pdto.GetAuditEntryResponse {
+	return pdto.GetAuditEntryResponse{
+		ID:          appDTO.ID,
+		Actor:       appDTO.Actor,
+		AuthMethod:  appDTO.AuthMethod,
+		ClientIP:    appDTO.ClientIP,
+		Method:      appDTO.Method,
+		Route:       appDTO.Route,
+		Path:        appDTO.Path,
+		ContainerID: appDTO.ContainerID,
+		StatusCode:  appDTO.StatusCode,
+		Before:      appDTO.Before,
+		After:       appDTO.After,
+		RequestID:   appDTO.RequestID,
+		CreatedAt:   appDTO.CreatedAt,
+	}
+}
+
+func MapAuditEntryDTOsToResponse(appDTOs []*adto.AuditEntryDTO) []pdto.GetAuditEntryResponse {
+	var responses = make([]pdto.GetAuditEntryResponse, 0, len(appDTOs))
+	for _, dto := range appDTOs {
+		responses = append(responses, MapAuditEntryDTOToResponse(*dto))
+	}
+
+	return responses
+}
@@ -0,0 +1,117 @@
+package metrics
+
+import (
+	"context"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/k6zma/DockerMonitoringApp/backend/internal/application/dto"
+	"github.com/k6zma/DockerMonitoringApp/backend/internal/application/usecases"
+	"github.com/k6zma/DockerMonitoringApp/backend/pkg/utils"
+)
+
+const namespace = "backend"
+
+var containerLabels = []string{"container_id", "name"}
+
+// ContainerStatusCollector exports the stored container statuses. The statuses
+// are read on every scrape, so the values always match the database.
+type ContainerStatusCollector struct {
+	useCase usecases.ContainerStatusUseCaseInterface
+	logger  utils.LoggerInterface
+
+	lastPing     *prometheus.Desc
+	up           *prometheus.Desc
+	status       *prometheus.Desc
+	restartCount *prometheus.Desc
+	crashLooping *prometheus.Desc
+}
+
+func NewContainerStatusCollector(
+	useCase usecases.ContainerStatusUseCaseInterface,
+	logger utils.LoggerInterface,
+) *ContainerStatusCollector {
+	return &ContainerStatusCollector{
+		useCase: useCase,
+		logger:  logger,
+		lastPing: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "container", "last_successful_ping_timestamp_seconds"),
+			"Unix time of the last successful ping of the container.",
+			containerLabels, nil,
+		),
+		up: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "container", "up"),
+			"Whether the last ping of the container received a reply (1) or not (0).",
+			containerLabels, nil,
+		),
+		status: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "container", "status"),
+			"Docker state of the container reported by the pinger; the current state is set to 1.",
+			[]string{"container_id", "name", "status"}, nil,
+		),
+		restartCount: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "container", "restart_count"),
+			"Number of restarts of the container reported by the Docker daemon.",
+			containerLabels, nil,
+		),
+		crashLooping: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "container", "crash_looping"),
+			"Whether the container is considered to be crash looping.",
+			containerLabels, nil,
+		),
+	}
+}
+
+func (c *ContainerStatusCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.lastPing
+	ch <- c.up
+	ch <- c.status
+	ch <- c.restartCount
+	ch <- c.crashLooping
+}
+
+func (c *ContainerStatusCollector) Collect(ch chan<- prometheus.Metric) {
+	statuses, err := c.useCase.FindContainerStatuses(context.Background(), &dto.ContainerStatusFilter{})
+	if err != nil {
+		c.logger.Errorf("METRICS: failed to collect container statuses: %v", err)
+		ch <- prometheus.NewInvalidMetric(c.up, err)
+		return
+	}
+
+	for _, status := range statuses {
+		ch <- prometheus.MustNewConstMetric(c.lastPing, prometheus.GaugeValue,
+			timestamp(status), status.ContainerID, status.Name)
+		ch <- prometheus.MustNewConstMetric(c.up, prometheus.GaugeValue,
+			boolToFloat(status.PingTime > 0), status.ContainerID, status.Name)
+		ch <- prometheus.MustNewConstMetric(c.status, prometheus.GaugeValue,
+			1, status.ContainerID, status.Name, status.Status)
+		ch <- prometheus.MustNewConstMetric(c.restartCount, prometheus.GaugeValue,
+			restartCount(status), status.ContainerID, status.Name)
+		ch <- prometheus.MustNewConstMetric(c.crashLooping, prometheus.GaugeValue,
+			boolToFloat(status.CrashLooping), status.ContainerID, status.Name)
+	}
+}
+
+func restartCount(status *dto.ContainerStatusDTO) float64 {
+	if status.RestartCount == nil {
+		return 0
+	}
+
+	return float64(*status.RestartCount)
+}
+
+func timestamp(status *dto.ContainerStatusDTO) float64 {
+	if status.LastSuccessfulPing.IsZero() {
+		return 0
+	}
+
+	return float64(status.LastSuccessfulPing.UnixMilli()) / 1000
+}
+
+func boolToFloat(value bool) float64 {
+	if value {
+		return 1
+	}
+
+	return 0
+}
@@ -0,0 +1,92 @@
+package metrics_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	adto "github.com/k6zma/DockerMonitoringApp/backend/internal/application/dto"
+	"github.com/k6zma/DockerMonitoringApp/backend/internal/presentation/metrics"
+	"github.com/k6zma/DockerMonitoringApp/backend/mocks"
+)
+
+func TestContainerStatusCollector_ExportsStoredStatuses(t *testing.T) {
+	mockUseCase := new(mocks.ContainerStatusUseCaseInterface)
+	mockLogger := new(mocks.LoggerInterface)
+
+	collector := metrics.NewContainerStatusCollector(mockUseCase, mockLogger)
+
+	mockUseCase.
+		On("FindContainerStatuses", mock.Anything, mock.AnythingOfType("*dto.ContainerStatusFilter")).
+		Return([]*adto.ContainerStatusDTO{
+			{
+				ContainerID:        "container123",
+				Name:               "web",
+				Status:             "running",
+				PingTime:           1500,
+				LastSuccessfulPing: time.Unix(1700000000, 0),
+				RestartCount:       intPtr(2),
+			},
+			{
+				ContainerID:  "container456",
+				Name:         "worker",
+				Status:       "restarting",
+				PingTime:     -1,
+				RestartCount: intPtr(7),
+				CrashLooping: true,
+			},
+		}, nil)
+
+	expected := `
+# HELP backend_container_crash_looping Whether the container is considered to be crash looping.
+# TYPE backend_container_crash_looping gauge
+backend_container_crash_looping{container_id="container123",name="web"} 0
+backend_container_crash_looping{container_id="container456",name="worker"} 1
+# HELP backend_container_last_successful_ping_timestamp_seconds Unix time of the last successful ping of the container.
+# TYPE backend_container_last_successful_ping_timestamp_seconds gauge
+backend_container_last_successful_ping_timestamp_seconds{container_id="container123",name="web"} 1.7e+09
+backend_container_last_successful_ping_timestamp_seconds{container_id="container456",name="worker"} 0
+# HELP backend_container_restart_count Number of restarts of the container reported by the Docker daemon.
+# TYPE backend_container_restart_count gauge
+backend_container_restart_count{container_id="container123",name="web"} 2
+backend_container_restart_count{container_id="container456",name="worker"} 7
+# HELP backend_container_status Docker state of the container reported by the pinger; the current state is set to 1.
+# TYPE backend_container_status gauge
+backend_container_status{container_id="container123",name="web",status="running"} 1
+backend_container_status{container_id="container456",name="worker",status="restarting"} 1
+# HELP backend_container_up Whether the last ping of the container received a reply (1) or not (0).
+# TYPE backend_container_up gauge
+backend_container_up{container_id="container123",name="web"} 1
+backend_container_up{container_id="container456",name="worker"} 0
+`
+
+	err := testutil.CollectAndCompare(collector, strings.NewReader(expected))
+	assert.NoError(t, err)
+	mockUseCase.AssertExpectations(t)
+}
+
+func TestContainerStatusCollector_UseCaseError_ReportsInvalidMetric(t *testing.T) {
+	mockUseCase := new(mocks.ContainerStatusUseCaseInterface)
+	mockLogger := new(mocks.LoggerInterface)
+
+	collector := metrics.NewContainerStatusCollector(mockUseCase, mockLogger)
+
+	mockUseCase.
+		On("FindContainerStatuses", mock.Anything, mock.AnythingOfType("*dto.ContainerStatusFilter")).
+		Return(nil, errors.New("database unavailable"))
+	mockLogger.On("Errorf", mock.Anything, mock.Anything).Return()
+
+	_, err := testutil.CollectAndLint(collector)
+	assert.Error(t, err)
+	mockUseCase.AssertExpectations(t)
+	mockLogger.AssertExpectations(t)
+}
+
+func intPtr(v int) *int {
+	return &v
+}
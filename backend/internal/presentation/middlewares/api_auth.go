@@ -1,26 +1,185 @@
 package middlewares
 
 import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
 	"net/http"
+	"strconv"
+	"strings"
 
-	"github.com/k6zma/DockerMonitoringApp/backend/internal/infrastructure/config"
+	"github.com/gorilla/websocket"
+
+	"github.com/k6zma/DockerMonitoringApp/backend/internal/application/dto"
+	"github.com/k6zma/DockerMonitoringApp/backend/internal/application/usecases"
+	"github.com/k6zma/DockerMonitoringApp/backend/internal/domain"
 	"github.com/k6zma/DockerMonitoringApp/backend/pkg/utils"
 )
 
+// SignatureScheme is the Authorization scheme of requests signed with an HMAC
+// key:
+//
+//	Authorization: HMAC-SHA256 KeyId=<key ID>, Timestamp=<unix seconds>, Nonce=<nonce>, Signature=<hex>
+const SignatureScheme = "HMAC-SHA256"
+
+// maxSignedBodySize limits the body read into memory to verify a signature.
+const maxSignedBodySize = 1 << 20
+
+// Browsers cannot set headers on WebSocket handshakes, so they may offer their
+// credentials as a subprotocol instead, base64url encoded without padding.
+const (
+	webSocketAPIKeyProtocol = "api-key."
+	webSocketBearerProtocol = "bearer."
+)
+
+type principalKey struct{}
+
+// PrincipalFromContext returns the caller authenticated by AuthMiddleware.
+func PrincipalFromContext(ctx context.Context) (*domain.Principal, bool) {
+	principal, ok := ctx.Value(principalKey{}).(*domain.Principal)
+	return principal, ok
+}
+
+// AuthMiddleware authenticates the request and stores the caller in the request
+// context. Requests signed with the SignatureScheme are verified by
+// apiKeyUseCase, requests with an Authorization: Bearer header by tokenVerifier,
+// which is nil when JWT authentication is disabled, and all other requests by
+// the X-Api-Key header. WebSocket handshakes without these headers are
+// authenticated by the credentials offered as a subprotocol. The roles assigned
+// through the API are added to the principal by roleUseCase.
 func AuthMiddleware(
-	cfg *config.Config,
+	apiKeyUseCase usecases.APIKeyUseCaseInterface,
+	tokenVerifier usecases.TokenVerifier,
+	roleUseCase usecases.RoleUseCaseInterface,
 	logger utils.LoggerInterface,
 ) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var principal *domain.Principal
+			var err error
+
+			authorization := r.Header.Get("Authorization")
 			apiKey := r.Header.Get("X-Api-Key")
-			if apiKey == "" || apiKey != cfg.AuthAPI.APIKey {
-				logger.Warnf("MIDDLEWARE: unauthorized access attempt")
+			if authorization == "" && apiKey == "" {
+				authorization, apiKey = webSocketCredentials(r)
+			}
+			scheme, credentials, _ := strings.Cut(authorization, " ")
+
+			switch {
+			case strings.EqualFold(scheme, SignatureScheme):
+				principal, err = authenticateSignature(r, credentials, apiKeyUseCase)
+			case authorization != "":
+				principal, err = authenticateBearer(r, authorization, tokenVerifier)
+			default:
+				principal, err = apiKeyUseCase.Authenticate(r.Context(), apiKey)
+			}
+
+			if errors.Is(err, usecases.ErrInvalidAPIKey) ||
+				errors.Is(err, usecases.ErrInvalidToken) ||
+				errors.Is(err, usecases.ErrInvalidSignature) {
+				utils.ContextLogger(r.Context(), logger).Warnf("MIDDLEWARE: unauthorized access attempt")
+				switch {
+				case errors.Is(err, usecases.ErrInvalidToken):
+					w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+				case errors.Is(err, usecases.ErrInvalidSignature):
+					w.Header().Set("WWW-Authenticate", SignatureScheme)
+				}
 				http.Error(w, "Unauthorized", http.StatusUnauthorized)
 				return
 			}
+			if err == nil {
+				err = roleUseCase.ResolveRoles(r.Context(), principal)
+			}
+			if err != nil {
+				utils.ContextLogger(r.Context(), logger).Errorf("MIDDLEWARE: failed to authenticate request: %v", err)
+				http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+				return
+			}
 
-			next.ServeHTTP(w, r)
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), principalKey{}, principal)))
 		})
 	}
 }
+
+// webSocketCredentials returns the bearer token, as an Authorization header
+// value, or the API key offered as a subprotocol of a WebSocket handshake.
+func webSocketCredentials(r *http.Request) (authorization, apiKey string) {
+	if !websocket.IsWebSocketUpgrade(r) {
+		return "", ""
+	}
+
+	for _, protocol := range websocket.Subprotocols(r) {
+		switch {
+		case strings.HasPrefix(protocol, webSocketBearerProtocol):
+			token, err := base64.RawURLEncoding.DecodeString(strings.TrimPrefix(protocol, webSocketBearerProtocol))
+			if err == nil {
+				authorization = "Bearer " + string(token)
+			}
+		case strings.HasPrefix(protocol, webSocketAPIKeyProtocol):
+			key, err := base64.RawURLEncoding.DecodeString(strings.TrimPrefix(protocol, webSocketAPIKeyProtocol))
+			if err == nil {
+				apiKey = string(key)
+			}
+		}
+	}
+
+	return authorization, apiKey
+}
+
+func authenticateBearer(
+	r *http.Request,
+	authorization string,
+	tokenVerifier usecases.TokenVerifier,
+) (*domain.Principal, error) {
+	scheme, token, ok := strings.Cut(authorization, " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") || tokenVerifier == nil {
+		return nil, usecases.ErrInvalidToken
+	}
+
+	return tokenVerifier.Verify(r.Context(), strings.TrimSpace(token))
+}
+
+// authenticateSignature verifies a request signed with the SignatureScheme. The
+// body is read to compute its digest and replaced for the handlers.
+func authenticateSignature(
+	r *http.Request,
+	credentials string,
+	apiKeyUseCase usecases.APIKeyUseCaseInterface,
+) (*domain.Principal, error) {
+	params := make(map[string]string, 4)
+	for _, param := range strings.Split(credentials, ",") {
+		name, value, _ := strings.Cut(strings.TrimSpace(param), "=")
+		params[name] = value
+	}
+
+	timestamp, err := strconv.ParseInt(params["Timestamp"], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid timestamp", usecases.ErrInvalidSignature)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxSignedBodySize+1))
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to read body: %v", usecases.ErrInvalidSignature, err)
+	}
+	if len(body) > maxSignedBodySize {
+		return nil, fmt.Errorf("%w: body exceeds %d bytes", usecases.ErrInvalidSignature, maxSignedBodySize)
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+
+	bodyHash := sha256.Sum256(body)
+
+	return apiKeyUseCase.AuthenticateSignature(r.Context(), &dto.RequestSignatureDTO{
+		KeyID:     params["KeyId"],
+		Timestamp: timestamp,
+		Nonce:     params["Nonce"],
+		Signature: params["Signature"],
+		Method:    r.Method,
+		Path:      r.RequestURI,
+		BodyHash:  hex.EncodeToString(bodyHash[:]),
+	})
+}
@@ -0,0 +1,436 @@
+package middlewares_test
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/k6zma/DockerMonitoringApp/backend/internal/application/usecases"
+	"github.com/k6zma/DockerMonitoringApp/backend/internal/domain"
+	"github.com/k6zma/DockerMonitoringApp/backend/internal/presentation/middlewares"
+	"github.com/k6zma/DockerMonitoringApp/backend/mocks"
+)
+
+func newProtectedRouter(logger *mocks.LoggerInterface, roleRepo *mocks.RoleAssignmentRepository) *mux.Router {
+	repo := new(mocks.APIKeyRepository)
+	repo.On("FindByHash", mock.Anything, mock.Anything).Return(nil, nil)
+
+	apiKeyUseCase := usecases.NewAPIKeyUseCase(repo, nil, []usecases.StaticAPIKey{
+		{Name: "pinger", Key: "pinger-key", Scopes: []domain.Scope{domain.ScopeStatusRead, domain.ScopeStatusWrite}},
+		{Name: "dashboard", Key: "dashboard-key", Scopes: []domain.Scope{domain.ScopeStatusRead}},
+		{Name: "admin", Key: "admin-key", Scopes: []domain.Scope{domain.ScopeAdmin}},
+	}, time.Minute, "", logger)
+	if roleRepo == nil {
+		roleRepo = new(mocks.RoleAssignmentRepository)
+	}
+	roleUseCase := usecases.NewRoleUseCase(roleRepo, logger)
+
+	return newRouter(apiKeyUseCase, nil, roleUseCase, logger)
+}
+
+func newRouter(
+	apiKeyUseCase usecases.APIKeyUseCaseInterface,
+	tokenVerifier usecases.TokenVerifier,
+	roleUseCase usecases.RoleUseCaseInterface,
+	logger *mocks.LoggerInterface,
+) *mux.Router {
+	handler := func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}
+
+	policy := middlewares.NewPolicy()
+	policy.Allow(http.MethodGet, "/container_status", domain.ScopeStatusRead)
+	policy.Allow(http.MethodDelete, "/container_status/{container_id}", domain.ScopeStatusWrite)
+	policy.Allow(http.MethodGet, "/api_keys", domain.ScopeAdmin)
+
+	router := mux.NewRouter()
+	router.Use(middlewares.AuthMiddleware(apiKeyUseCase, tokenVerifier, roleUseCase, logger))
+	router.Use(middlewares.AuthorizationMiddleware(policy, logger))
+	router.HandleFunc("/container_status", handler).Methods(http.MethodGet)
+	router.HandleFunc("/container_status/{container_id}", handler).Methods(http.MethodDelete)
+	router.HandleFunc("/api_keys", handler).Methods(http.MethodGet)
+	router.HandleFunc("/undeclared", handler).Methods(http.MethodGet)
+
+	return router
+}
+
+func TestAuthMiddleware_UnknownKey_ReturnsUnauthorized(t *testing.T) {
+	mockLogger := new(mocks.LoggerInterface)
+	mockLogger.On("Warnf", mock.Anything).Return()
+
+	router := newProtectedRouter(mockLogger, nil)
+
+	for _, key := range []string{"", "unknown-key"} {
+		req := httptest.NewRequest(http.MethodGet, "/container_status", http.NoBody)
+		req.Header.Set("X-Api-Key", key)
+		rec := httptest.NewRecorder()
+
+		router.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	}
+}
+
+func TestAuthorizationMiddleware_DeniesMissingScope(t *testing.T) {
+	tests := []struct {
+		name   string
+		key    string
+		method string
+		path   string
+	}{
+		{name: "read key cannot write", key: "dashboard-key", method: http.MethodDelete, path: "/container_status/abc"},
+		{name: "write key cannot manage keys", key: "pinger-key", method: http.MethodGet, path: "/api_keys"},
+		{name: "undeclared route", key: "admin-key", method: http.MethodGet, path: "/undeclared"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockLogger := new(mocks.LoggerInterface)
+			mockLogger.On("Warnf", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return()
+
+			req := httptest.NewRequest(tt.method, tt.path, http.NoBody)
+			req.Header.Set("X-Api-Key", tt.key)
+			rec := httptest.NewRecorder()
+
+			newProtectedRouter(mockLogger, nil).ServeHTTP(rec, req)
+
+			assert.Equal(t, http.StatusForbidden, rec.Code)
+		})
+	}
+}
+
+func TestAuthorizationMiddleware_GrantedScope_PassesThrough(t *testing.T) {
+	mockLogger := new(mocks.LoggerInterface)
+
+	tests := []struct {
+		name   string
+		key    string
+		method string
+		path   string
+	}{
+		{name: "read key reads", key: "dashboard-key", method: http.MethodGet, path: "/container_status"},
+		{name: "write key writes", key: "pinger-key", method: http.MethodDelete, path: "/container_status/abc"},
+		{name: "admin key writes", key: "admin-key", method: http.MethodDelete, path: "/container_status/abc"},
+		{name: "admin key manages keys", key: "admin-key", method: http.MethodGet, path: "/api_keys"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, http.NoBody)
+			req.Header.Set("X-Api-Key", tt.key)
+			rec := httptest.NewRecorder()
+
+			newProtectedRouter(mockLogger, nil).ServeHTTP(rec, req)
+
+			assert.Equal(t, http.StatusNoContent, rec.Code)
+		})
+	}
+}
+
+func TestAuthMiddleware_WebSocketSubprotocolCredentials(t *testing.T) {
+	tests := []struct {
+		name     string
+		upgrade  bool
+		protocol string
+		code     int
+	}{
+		{name: "API key", upgrade: true, protocol: "api-key." + base64.RawURLEncoding.EncodeToString([]byte("dashboard-key")), code: http.StatusNoContent},
+		{name: "unknown API key", upgrade: true, protocol: "api-key." + base64.RawURLEncoding.EncodeToString([]byte("unknown")), code: http.StatusUnauthorized},
+		{name: "not a WebSocket handshake", protocol: "api-key." + base64.RawURLEncoding.EncodeToString([]byte("dashboard-key")), code: http.StatusUnauthorized},
+		{name: "bearer token without verifier", upgrade: true, protocol: "bearer.ZXlKaGJHY2lPaUpJVXpJMU5pSjk", code: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockLogger := new(mocks.LoggerInterface)
+			mockLogger.On("Warnf", mock.Anything).Return()
+
+			req := httptest.NewRequest(http.MethodGet, "/container_status", http.NoBody)
+			req.Header.Set("Sec-WebSocket-Protocol", "container-status.v1, "+tt.protocol)
+			if tt.upgrade {
+				req.Header.Set("Connection", "Upgrade")
+				req.Header.Set("Upgrade", "websocket")
+			}
+			rec := httptest.NewRecorder()
+
+			newProtectedRouter(mockLogger, nil).ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.code, rec.Code)
+		})
+	}
+}
+
+func TestAuthMiddleware_LookupFailure_ReturnsInternalServerError(t *testing.T) {
+	mockLogger := new(mocks.LoggerInterface)
+	mockLogger.On("Errorf", mock.Anything, mock.Anything).Return()
+
+	mockUseCase := new(mocks.APIKeyUseCaseInterface)
+	mockUseCase.On("Authenticate", mock.Anything, "some-key").Return(nil, errors.New("connection refused"))
+
+	handler := middlewares.AuthMiddleware(mockUseCase, nil, new(mocks.RoleUseCaseInterface), mockLogger)(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/container_status", http.NoBody)
+	req.Header.Set("X-Api-Key", "some-key")
+	rec := httptest.NewRecorder()
+
+	handler.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	mockUseCase.AssertExpectations(t)
+}
+
+func TestAuthMiddleware_BearerToken(t *testing.T) {
+	mockLogger := new(mocks.LoggerInterface)
+	mockLogger.On("Warnf", mock.Anything).Return()
+
+	mockUseCase := new(mocks.APIKeyUseCaseInterface)
+	mockVerifier := new(mocks.TokenVerifier)
+	mockVerifier.On("Verify", mock.Anything, "valid-token").Return(&domain.Principal{
+		Name:   "alice",
+		Method: domain.AuthMethodJWT,
+		Scopes: []domain.Scope{domain.ScopeStatusRead},
+	}, nil)
+	mockVerifier.On("Verify", mock.Anything, "expired-token").Return(nil, usecases.ErrInvalidToken)
+	mockRoleUseCase := new(mocks.RoleUseCaseInterface)
+	mockRoleUseCase.On("ResolveRoles", mock.Anything, mock.AnythingOfType("*domain.Principal")).Return(nil)
+
+	var principal *domain.Principal
+	handler := middlewares.AuthMiddleware(mockUseCase, mockVerifier, mockRoleUseCase, mockLogger)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		principal, _ = middlewares.PrincipalFromContext(r.Context())
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	tests := []struct {
+		name          string
+		authorization string
+		expected      int
+	}{
+		{name: "valid token", authorization: "Bearer valid-token", expected: http.StatusNoContent},
+		{name: "expired token", authorization: "Bearer expired-token", expected: http.StatusUnauthorized},
+		{name: "other scheme", authorization: "Basic dXNlcjpwYXNz", expected: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/container_status", http.NoBody)
+			req.Header.Set("Authorization", tt.authorization)
+			rec := httptest.NewRecorder()
+
+			handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expected, rec.Code)
+			if tt.expected == http.StatusUnauthorized {
+				assert.Equal(t, `Bearer error="invalid_token"`, rec.Header().Get("WWW-Authenticate"))
+			}
+		})
+	}
+
+	assert.Equal(t, "alice", principal.Name)
+	mockUseCase.AssertNotCalled(t, "Authenticate", mock.Anything, mock.Anything)
+}
+
+func TestAuthMiddleware_BearerTokenWithoutVerifier_ReturnsUnauthorized(t *testing.T) {
+	mockLogger := new(mocks.LoggerInterface)
+	mockLogger.On("Warnf", mock.Anything).Return()
+
+	router := newProtectedRouter(mockLogger, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/container_status", http.NoBody)
+	req.Header.Set("Authorization", "Bearer some-token")
+	req.Header.Set("X-Api-Key", "dashboard-key")
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
+
+func TestAuthMiddleware_BearerToken_AssignedRoleGrantsAccess(t *testing.T) {
+	mockLogger := new(mocks.LoggerInterface)
+	mockLogger.On("Warnf", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return()
+
+	mockVerifier := new(mocks.TokenVerifier)
+	mockVerifier.On("Verify", mock.Anything, "alice-token").Return(&domain.Principal{
+		Name:   "alice",
+		Method: domain.AuthMethodJWT,
+		Roles:  []domain.Role{domain.RoleViewer},
+	}, nil)
+	mockVerifier.On("Verify", mock.Anything, "bob-token").Return(&domain.Principal{
+		Name:   "bob",
+		Method: domain.AuthMethodJWT,
+		Roles:  []domain.Role{domain.RoleViewer},
+	}, nil)
+
+	mockRoleRepo := new(mocks.RoleAssignmentRepository)
+	mockRoleRepo.On("FindBySubject", mock.Anything, "alice").
+		Return(&domain.RoleAssignment{Subject: "alice", Role: domain.RoleOperator}, nil)
+	mockRoleRepo.On("FindBySubject", mock.Anything, "bob").Return(nil, nil)
+
+	router := newRouter(new(mocks.APIKeyUseCaseInterface), mockVerifier,
+		usecases.NewRoleUseCase(mockRoleRepo, mockLogger), mockLogger)
+
+	tests := []struct {
+		token    string
+		expected int
+	}{
+		{token: "alice-token", expected: http.StatusNoContent},
+		{token: "bob-token", expected: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodDelete, "/container_status/abc", http.NoBody)
+		req.Header.Set("Authorization", "Bearer "+tt.token)
+		rec := httptest.NewRecorder()
+
+		router.ServeHTTP(rec, req)
+
+		assert.Equal(t, tt.expected, rec.Code, tt.token)
+	}
+
+	mockRoleRepo.AssertExpectations(t)
+}
+
+func signedRequest(method, target, body, rawKey, keyID, nonce string, signedAt time.Time) *http.Request {
+	bodyHash := sha256.Sum256([]byte(body))
+	secret := hmac.New(sha256.New, []byte(rawKey))
+	secret.Write([]byte("DockerMonitoringApp request signing"))
+
+	mac := hmac.New(sha256.New, secret.Sum(nil))
+	fmt.Fprintf(mac, "%s\n%s\n%d\n%s\n%s", method, target, signedAt.Unix(), nonce, hex.EncodeToString(bodyHash[:]))
+
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	req.Header.Set("Authorization", fmt.Sprintf("HMAC-SHA256 KeyId=%s, Timestamp=%d, Nonce=%s, Signature=%s",
+		keyID, signedAt.Unix(), nonce, hex.EncodeToString(mac.Sum(nil))))
+
+	return req
+}
+
+func TestAuthMiddleware_SignedRequest(t *testing.T) {
+	mockLogger := new(mocks.LoggerInterface)
+	mockLogger.On("Warnf", mock.Anything, mock.Anything, mock.Anything).Return()
+	mockLogger.On("Warnf", mock.Anything).Return()
+
+	repo := new(mocks.APIKeyRepository)
+	repo.On("FindByHash", mock.Anything, mock.Anything).Return(nil, nil)
+	repo.On("FindSigningKey", mock.Anything, mock.Anything).Return(nil, nil)
+
+	nonceRepo := new(mocks.RequestNonceRepository)
+	nonceRepo.On("Claim", mock.Anything, "pinger", "nonce-1", mock.Anything, mock.Anything).Return(true, nil).Once()
+	nonceRepo.On("Claim", mock.Anything, "pinger", "nonce-1", mock.Anything, mock.Anything).Return(false, nil)
+	nonceRepo.On("DeleteExpired", mock.Anything, mock.Anything).Return(nil)
+
+	apiKeyUseCase := usecases.NewAPIKeyUseCase(repo, nonceRepo, []usecases.StaticAPIKey{
+		{Name: "pinger", Key: "pinger-key", Scopes: []domain.Scope{domain.ScopeStatusWrite}, AuthMode: domain.APIKeyAuthModeHMAC},
+	}, time.Minute, "", mockLogger)
+	roleUseCase := usecases.NewRoleUseCase(new(mocks.RoleAssignmentRepository), mockLogger)
+
+	// The handler echoes the body to show that it survives the verification.
+	handler := middlewares.AuthMiddleware(apiKeyUseCase, nil, roleUseCase, mockLogger)(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			principal, _ := middlewares.PrincipalFromContext(r.Context())
+			assert.Equal(t, domain.AuthMethodHMAC, principal.Method)
+			_, _ = io.Copy(w, r.Body)
+		}),
+	)
+
+	const target = "/api/v1/container_status/abc?force=true"
+	const body = `{"status":"running"}`
+	now := time.Now()
+
+	req := signedRequest(http.MethodPatch, target, body, "pinger-key", "pinger", "nonce-1", now)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, body, rec.Body.String())
+
+	rejected := map[string]*http.Request{
+		"replayed nonce":  signedRequest(http.MethodPatch, target, body, "pinger-key", "pinger", "nonce-1", now),
+		"stale timestamp": signedRequest(http.MethodPatch, target, body, "pinger-key", "pinger", "nonce-2", now.Add(-time.Hour)),
+		"wrong key":       signedRequest(http.MethodPatch, target, body, "other-key", "pinger", "nonce-3", now),
+		"unknown key ID":  signedRequest(http.MethodPatch, target, body, "pinger-key", "dm_unknown", "nonce-4", now),
+	}
+
+	tampered := signedRequest(http.MethodPatch, target, body, "pinger-key", "pinger", "nonce-5", now)
+	tampered.Body = io.NopCloser(strings.NewReader(`{"status":"exited"}`))
+	rejected["tampered body"] = tampered
+
+	plain := httptest.NewRequest(http.MethodPatch, target, strings.NewReader(body))
+	plain.Header.Set("X-Api-Key", "pinger-key")
+	rejected["key sent in header"] = plain
+
+	for name, req := range rejected {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusUnauthorized, rec.Code, name)
+	}
+}
+
+// pingerSignedRequest is a request signed by the signing transport of the
+// pinger, recorded by its tests.
+type pingerSignedRequest struct {
+	APIKey        string `json:"api_key"`
+	KeyID         string `json:"key_id"`
+	Method        string `json:"method"`
+	Target        string `json:"target"`
+	Body          string `json:"body"`
+	Authorization string `json:"authorization"`
+}
+
+func TestAuthMiddleware_PingerSignedRequest(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("..", "..", "..", "..",
+		"pinger", "internal", "infrastructure", "backend", "testdata", "signed_request.json"))
+	require.NoError(t, err)
+
+	var signed pingerSignedRequest
+	require.NoError(t, json.Unmarshal(data, &signed))
+
+	mockLogger := new(mocks.LoggerInterface)
+
+	nonceRepo := new(mocks.RequestNonceRepository)
+	nonceRepo.On("Claim", mock.Anything, signed.KeyID, mock.Anything, mock.Anything, mock.Anything).Return(true, nil)
+	nonceRepo.On("DeleteExpired", mock.Anything, mock.Anything).Return(nil)
+
+	// The request was recorded at a fixed time, which the clock skew covers.
+	apiKeyUseCase := usecases.NewAPIKeyUseCase(new(mocks.APIKeyRepository), nonceRepo, []usecases.StaticAPIKey{
+		{Name: signed.KeyID, Key: signed.APIKey, Scopes: []domain.Scope{domain.ScopeStatusWrite}, AuthMode: domain.APIKeyAuthModeHMAC},
+	}, 100*365*24*time.Hour, "", mockLogger)
+	roleUseCase := usecases.NewRoleUseCase(new(mocks.RoleAssignmentRepository), mockLogger)
+
+	handler := middlewares.AuthMiddleware(apiKeyUseCase, nil, roleUseCase, mockLogger)(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			principal, _ := middlewares.PrincipalFromContext(r.Context())
+			assert.Equal(t, domain.AuthMethodHMAC, principal.Method)
+			w.WriteHeader(http.StatusNoContent)
+		}),
+	)
+
+	req := httptest.NewRequest(signed.Method, signed.Target, strings.NewReader(signed.Body))
+	req.Header.Set("Authorization", signed.Authorization)
+	rec := httptest.NewRecorder()
+
+	handler.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	nonceRepo.AssertExpectations(t)
+}
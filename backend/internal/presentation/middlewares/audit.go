@@ -0,0 +1,64 @@
+package middlewares
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/k6zma/DockerMonitoringApp/backend/internal/application/dto"
+	"github.com/k6zma/DockerMonitoringApp/backend/internal/application/usecases"
+	"github.com/k6zma/DockerMonitoringApp/backend/pkg/utils"
+)
+
+var auditedMethods = map[string]bool{
+	http.MethodPost:   true,
+	http.MethodPut:    true,
+	http.MethodPatch:  true,
+	http.MethodDelete: true,
+}
+
+// AuditMiddleware records every mutating request in the audit log once it has
+// been handled, including the ones that were denied or failed. It must run
+// after AuthMiddleware; a failure to record is logged and does not change the
+// response.
+func AuditMiddleware(
+	auditUseCase usecases.AuditUseCaseInterface,
+	logger utils.LoggerInterface,
+) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !auditedMethods[r.Method] {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			ctx, change := usecases.ContextWithAuditChange(r.Context())
+			wrapper := &responseWriterWrapper{
+				ResponseWriter: w,
+				statusCode:     http.StatusOK,
+			}
+
+			next.ServeHTTP(wrapper, r.WithContext(ctx))
+
+			entry := &dto.AuditEntryDTO{
+				ClientIP:    utils.GetClientIP(r),
+				Method:      r.Method,
+				Route:       routeTemplate(r),
+				Path:        r.URL.Path,
+				ContainerID: mux.Vars(r)["container_id"],
+				StatusCode:  wrapper.statusCode,
+				RequestID:   utils.RequestIDFromContext(r.Context()),
+			}
+			if principal, ok := PrincipalFromContext(r.Context()); ok {
+				entry.Actor = principal.Name
+				entry.AuthMethod = string(principal.Method)
+			}
+
+			// The client may be gone by now, the record is still written.
+			if err := auditUseCase.Record(context.WithoutCancel(r.Context()), entry, change); err != nil {
+				utils.ContextLogger(r.Context(), logger).Errorf("MIDDLEWARE: failed to record audit entry: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package middlewares_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/k6zma/DockerMonitoringApp/backend/internal/application/dto"
+	"github.com/k6zma/DockerMonitoringApp/backend/internal/application/usecases"
+	"github.com/k6zma/DockerMonitoringApp/backend/internal/domain"
+	"github.com/k6zma/DockerMonitoringApp/backend/internal/presentation/middlewares"
+	"github.com/k6zma/DockerMonitoringApp/backend/mocks"
+)
+
+func newAuditedRouter(auditUseCase usecases.AuditUseCaseInterface, logger *mocks.LoggerInterface) *mux.Router {
+	repo := new(mocks.APIKeyRepository)
+	apiKeyUseCase := usecases.NewAPIKeyUseCase(repo, nil, []usecases.StaticAPIKey{
+		{Name: "pinger", Key: "pinger-key", Scopes: []domain.Scope{domain.ScopeStatusRead, domain.ScopeStatusWrite}},
+	}, time.Minute, "", logger)
+	roleUseCase := usecases.NewRoleUseCase(new(mocks.RoleAssignmentRepository), logger)
+
+	handler := func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}
+
+	// httptest requests come from 192.0.2.1, which stands in for the proxy.
+	router := mux.NewRouter()
+	router.Use(middlewares.ClientIPMiddleware([]netip.Prefix{netip.MustParsePrefix("192.0.2.0/24")}))
+	router.Use(middlewares.AuthMiddleware(apiKeyUseCase, nil, roleUseCase, logger))
+	router.Use(middlewares.AuditMiddleware(auditUseCase, logger))
+	router.HandleFunc("/container_status", handler).Methods(http.MethodGet)
+	router.HandleFunc("/container_status/{container_id}", handler).Methods(http.MethodDelete)
+
+	return router
+}
+
+func TestAuditMiddleware_RecordsMutatingRequest(t *testing.T) {
+	mockLogger := new(mocks.LoggerInterface)
+	mockAuditUseCase := new(mocks.AuditUseCaseInterface)
+	mockAuditUseCase.On("Record", mock.Anything, &dto.AuditEntryDTO{
+		Actor:       "pinger",
+		AuthMethod:  "api_key",
+		ClientIP:    "10.0.0.7",
+		Method:      http.MethodDelete,
+		Route:       "/container_status/{container_id}",
+		Path:        "/container_status/abc",
+		ContainerID: "abc",
+		StatusCode:  http.StatusNoContent,
+	}, mock.AnythingOfType("*usecases.AuditChange")).Return(nil)
+
+	req := httptest.NewRequest(http.MethodDelete, "/container_status/abc", http.NoBody)
+	req.Header.Set("X-Api-Key", "pinger-key")
+	req.Header.Set("X-Forwarded-For", "10.0.0.7")
+	rec := httptest.NewRecorder()
+
+	newAuditedRouter(mockAuditUseCase, mockLogger).ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	mockAuditUseCase.AssertExpectations(t)
+}
+
+func TestAuditMiddleware_SkipsReads(t *testing.T) {
+	mockLogger := new(mocks.LoggerInterface)
+	mockAuditUseCase := new(mocks.AuditUseCaseInterface)
+
+	req := httptest.NewRequest(http.MethodGet, "/container_status", http.NoBody)
+	req.Header.Set("X-Api-Key", "pinger-key")
+	rec := httptest.NewRecorder()
+
+	newAuditedRouter(mockAuditUseCase, mockLogger).ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	mockAuditUseCase.AssertNotCalled(t, "Record", mock.Anything, mock.Anything, mock.Anything)
+}
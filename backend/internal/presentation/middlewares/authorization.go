@@ -0,0 +1,70 @@
+package middlewares
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/k6zma/DockerMonitoringApp/backend/internal/domain"
+	"github.com/k6zma/DockerMonitoringApp/backend/pkg/utils"
+)
+
+// Policy maps each route, identified by its method and path template, to the
+// scope a caller needs to use it.
+type Policy struct {
+	scopes map[string]domain.Scope
+}
+
+func NewPolicy() *Policy {
+	return &Policy{scopes: make(map[string]domain.Scope)}
+}
+
+// Allow grants the route to callers with scope.
+func (p *Policy) Allow(method, pathTemplate string, scope domain.Scope) {
+	p.scopes[method+" "+pathTemplate] = scope
+}
+
+// Scope returns the scope required for the route and false if the route is not
+// part of the policy.
+func (p *Policy) Scope(method, pathTemplate string) (domain.Scope, bool) {
+	scope, ok := p.scopes[method+" "+pathTemplate]
+	return scope, ok
+}
+
+// AuthorizationMiddleware checks the principal stored by AuthMiddleware against
+// policy. Routes missing from the policy are denied, so a route cannot be
+// exposed by forgetting to declare its permission.
+func AuthorizationMiddleware(policy *Policy, logger utils.LoggerInterface) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			principal, ok := PrincipalFromContext(r.Context())
+			if !ok {
+				utils.ContextLogger(r.Context(), logger).Warnf("MIDDLEWARE: unauthenticated request to %s", r.URL.Path)
+				http.Error(w, "Unauthorized", http.StatusUnauthorized)
+				return
+			}
+
+			var pathTemplate string
+			if route := mux.CurrentRoute(r); route != nil {
+				pathTemplate, _ = route.GetPathTemplate()
+			}
+
+			scope, ok := policy.Scope(r.Method, pathTemplate)
+			if !ok {
+				utils.ContextLogger(r.Context(), logger).Warnf("MIDDLEWARE: no permission declared for %s %s",
+					r.Method, r.URL.Path)
+				http.Error(w, "Forbidden", http.StatusForbidden)
+				return
+			}
+
+			if !principal.HasScope(scope) {
+				utils.ContextLogger(r.Context(), logger).Warnf("MIDDLEWARE: %s lacks scope %s for %s %s",
+					principal.Name, scope, r.Method, r.URL.Path)
+				http.Error(w, "Forbidden", http.StatusForbidden)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,36 @@
+package middlewares
+
+import (
+	"net/http"
+
+	"github.com/k6zma/DockerMonitoringApp/backend/internal/domain"
+	"github.com/k6zma/DockerMonitoringApp/backend/pkg/utils"
+)
+
+// RequireClientCertMiddleware refuses requests of machine callers that did not
+// arrive over a TLS connection authenticated with a client certificate. The
+// certificate itself is verified against the client CA bundle during the
+// handshake. Users authenticated with a JWT are let through without one, they
+// reach the API from a browser through the dashboard.
+func RequireClientCertMiddleware(logger utils.LoggerInterface) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if principal, ok := PrincipalFromContext(r.Context()); ok && principal.Method == domain.AuthMethodJWT {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if r.TLS == nil || len(r.TLS.VerifiedChains) == 0 {
+				utils.ContextLogger(r.Context(), logger).Warnf("MIDDLEWARE: %s %s without a verified client certificate",
+					r.Method, r.URL.Path)
+				http.Error(w, "Client certificate required", http.StatusForbidden)
+				return
+			}
+
+			utils.ContextLogger(r.Context(), logger).Debugf("MIDDLEWARE: client certificate %s",
+				r.TLS.VerifiedChains[0][0].Subject)
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package middlewares_test
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/k6zma/DockerMonitoringApp/backend/internal/domain"
+	"github.com/k6zma/DockerMonitoringApp/backend/internal/presentation/middlewares"
+	"github.com/k6zma/DockerMonitoringApp/backend/mocks"
+)
+
+func TestRequireClientCertMiddleware(t *testing.T) {
+	verified := &tls.ConnectionState{
+		VerifiedChains: [][]*x509.Certificate{{{Subject: pkix.Name{CommonName: "pinger"}}}},
+	}
+
+	tests := []struct {
+		name           string
+		tls            *tls.ConnectionState
+		expectedStatus int
+	}{
+		{name: "plain HTTP", tls: nil, expectedStatus: http.StatusForbidden},
+		{name: "TLS without client certificate", tls: &tls.ConnectionState{}, expectedStatus: http.StatusForbidden},
+		{name: "verified client certificate", tls: verified, expectedStatus: http.StatusNoContent},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockLogger := new(mocks.LoggerInterface)
+			mockLogger.On("Warnf", mock.Anything, mock.Anything, mock.Anything).Return()
+			mockLogger.On("Debugf", mock.Anything, mock.Anything).Return()
+
+			handler := middlewares.RequireClientCertMiddleware(mockLogger)(
+				http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+					w.WriteHeader(http.StatusNoContent)
+				}),
+			)
+
+			req := httptest.NewRequest(http.MethodPatch, "/api/v1/container_status/abc", http.NoBody)
+			req.TLS = tt.tls
+			rec := httptest.NewRecorder()
+
+			handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+		})
+	}
+}
+
+func TestRequireClientCertMiddleware_JWTUser(t *testing.T) {
+	mockLogger := new(mocks.LoggerInterface)
+	mockLogger.On("Warnf", mock.Anything, mock.Anything, mock.Anything).Return()
+
+	tokenVerifier := new(mocks.TokenVerifier)
+	tokenVerifier.On("Verify", mock.Anything, "token").
+		Return(&domain.Principal{Name: "alice", Method: domain.AuthMethodJWT, Roles: []domain.Role{domain.RoleOperator}}, nil)
+	apiKeyUseCase := new(mocks.APIKeyUseCaseInterface)
+	apiKeyUseCase.On("Authenticate", mock.Anything, "pinger-key").
+		Return(&domain.Principal{Name: "pinger", Method: domain.AuthMethodAPIKey}, nil)
+	roleUseCase := new(mocks.RoleUseCaseInterface)
+	roleUseCase.On("ResolveRoles", mock.Anything, mock.Anything).Return(nil)
+
+	handler := middlewares.AuthMiddleware(apiKeyUseCase, tokenVerifier, roleUseCase, mockLogger)(
+		middlewares.RequireClientCertMiddleware(mockLogger)(
+			http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusNoContent)
+			}),
+		),
+	)
+
+	for header, expectedStatus := range map[string]int{"Authorization": http.StatusNoContent, "X-Api-Key": http.StatusForbidden} {
+		req := httptest.NewRequest(http.MethodDelete, "/api/v1/container_status/abc", http.NoBody)
+		req.TLS = &tls.ConnectionState{}
+		if header == "Authorization" {
+			req.Header.Set(header, "Bearer token")
+		} else {
+			req.Header.Set(header, "pinger-key")
+		}
+		rec := httptest.NewRecorder()
+
+		handler.ServeHTTP(rec, req)
+
+		assert.Equal(t, expectedStatus, rec.Code, header)
+	}
+}
@@ -0,0 +1,70 @@
+package middlewares
+
+import (
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+
+	"github.com/k6zma/DockerMonitoringApp/backend/pkg/utils"
+)
+
+// ClientIPMiddleware resolves the IP address a request was made from for the
+// logs, the audit log and the rate limiter. X-Forwarded-For is only believed
+// when the request comes from one of the trustedProxies, and then only up to
+// the rightmost address that is not a trusted proxy itself: that is the client
+// the nearest proxy saw, everything left of it may have been made up by the
+// client.
+func ClientIPMiddleware(trustedProxies []netip.Prefix) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			clientIP := resolveClientIP(r, trustedProxies)
+
+			next.ServeHTTP(w, r.WithContext(utils.ContextWithClientIP(r.Context(), clientIP)))
+		})
+	}
+}
+
+func resolveClientIP(r *http.Request, trustedProxies []netip.Prefix) string {
+	peer := r.RemoteAddr
+	if host, _, err := net.SplitHostPort(peer); err == nil {
+		peer = host
+	}
+
+	addr, err := netip.ParseAddr(peer)
+	if err != nil || !isTrustedProxy(addr, trustedProxies) {
+		return peer
+	}
+
+	var hops []string
+	for _, header := range r.Header.Values("X-Forwarded-For") {
+		hops = append(hops, strings.Split(header, ",")...)
+	}
+
+	clientIP := peer
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop, err := netip.ParseAddr(strings.TrimSpace(hops[i]))
+		if err != nil {
+			// Whatever is left of a malformed entry cannot be trusted.
+			break
+		}
+
+		clientIP = hop.Unmap().String()
+		if !isTrustedProxy(hop, trustedProxies) {
+			break
+		}
+	}
+
+	return clientIP
+}
+
+func isTrustedProxy(addr netip.Addr, trustedProxies []netip.Prefix) bool {
+	addr = addr.Unmap()
+	for _, prefix := range trustedProxies {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+
+	return false
+}
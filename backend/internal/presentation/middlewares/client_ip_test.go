@@ -0,0 +1,111 @@
+package middlewares_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/k6zma/DockerMonitoringApp/backend/internal/presentation/middlewares"
+	"github.com/k6zma/DockerMonitoringApp/backend/pkg/utils"
+)
+
+func TestClientIPMiddleware(t *testing.T) {
+	trustedProxies := []netip.Prefix{
+		netip.MustParsePrefix("172.16.0.0/12"),
+		netip.MustParsePrefix("fd00::/8"),
+	}
+
+	tests := []struct {
+		name         string
+		remoteAddr   string
+		forwardedFor []string
+		expectedIP   string
+	}{
+		{
+			name:       "no proxy",
+			remoteAddr: "203.0.113.5:4000",
+			expectedIP: "203.0.113.5",
+		},
+		{
+			name:         "untrusted peer cannot spoof its address",
+			remoteAddr:   "203.0.113.5:4000",
+			forwardedFor: []string{"10.0.0.7"},
+			expectedIP:   "203.0.113.5",
+		},
+		{
+			name:         "trusted proxy",
+			remoteAddr:   "172.18.0.2:4000",
+			forwardedFor: []string{"203.0.113.5"},
+			expectedIP:   "203.0.113.5",
+		},
+		{
+			name:         "rightmost untrusted hop wins over a spoofed one",
+			remoteAddr:   "172.18.0.2:4000",
+			forwardedFor: []string{"10.0.0.7, 203.0.113.5"},
+			expectedIP:   "203.0.113.5",
+		},
+		{
+			name:         "chain of trusted proxies",
+			remoteAddr:   "172.18.0.2:4000",
+			forwardedFor: []string{"10.0.0.7, 203.0.113.5, 172.18.0.3"},
+			expectedIP:   "203.0.113.5",
+		},
+		{
+			name:         "every hop trusted",
+			remoteAddr:   "172.18.0.2:4000",
+			forwardedFor: []string{"172.18.0.4, 172.18.0.3"},
+			expectedIP:   "172.18.0.4",
+		},
+		{
+			name:         "several headers",
+			remoteAddr:   "172.18.0.2:4000",
+			forwardedFor: []string{"10.0.0.7", "203.0.113.5, 172.18.0.3"},
+			expectedIP:   "203.0.113.5",
+		},
+		{
+			name:         "malformed hop",
+			remoteAddr:   "172.18.0.2:4000",
+			forwardedFor: []string{"203.0.113.5, not-an-ip, 172.18.0.3"},
+			expectedIP:   "172.18.0.3",
+		},
+		{
+			name:       "trusted proxy without header",
+			remoteAddr: "172.18.0.2:4000",
+			expectedIP: "172.18.0.2",
+		},
+		{
+			name:         "IPv6",
+			remoteAddr:   "[fd00::2]:4000",
+			forwardedFor: []string{"2001:db8::5"},
+			expectedIP:   "2001:db8::5",
+		},
+		{
+			name:         "IPv4-mapped IPv6 proxy",
+			remoteAddr:   "[::ffff:172.18.0.2]:4000",
+			forwardedFor: []string{"::ffff:203.0.113.5"},
+			expectedIP:   "203.0.113.5",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var clientIP string
+			handler := middlewares.ClientIPMiddleware(trustedProxies)(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+				clientIP = utils.GetClientIP(r)
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/container_status", http.NoBody)
+			req.RemoteAddr = tt.remoteAddr
+			for _, value := range tt.forwardedFor {
+				req.Header.Add("X-Forwarded-For", value)
+			}
+
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+
+			assert.Equal(t, tt.expectedIP, clientIP)
+		})
+	}
+}
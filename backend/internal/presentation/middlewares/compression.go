@@ -0,0 +1,350 @@
+package middlewares
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/klauspost/compress/gzip"
+	"github.com/klauspost/compress/zstd"
+
+	"github.com/k6zma/DockerMonitoringApp/backend/pkg/utils"
+)
+
+// The content codings the API negotiates, in order of preference.
+const (
+	encodingZstd = "zstd"
+	encodingGzip = "gzip"
+)
+
+var supportedEncodings = []string{encodingZstd, encodingGzip}
+
+// incompressibleTypes are the media types, or prefixes of them, of responses
+// that are streamed or already compressed.
+var incompressibleTypes = []string{
+	"text/event-stream",
+	"image/",
+	"video/",
+	"audio/",
+	"application/gzip",
+	"application/x-gzip",
+	"application/zip",
+	"application/zstd",
+	"application/octet-stream",
+}
+
+// CompressionPolicy configures the compression of API traffic. Responses
+// smaller than MinSize bytes are sent as they are. Compressed request bodies
+// may expand to at most MaxRequestSize bytes.
+type CompressionPolicy struct {
+	MinSize        int
+	MaxRequestSize int64
+}
+
+// encoder is the part of the gzip and zstd writers the responses need.
+type encoder interface {
+	io.WriteCloser
+	Flush() error
+	Reset(w io.Writer)
+}
+
+var encoders = map[string]*sync.Pool{
+	encodingZstd: {New: func() any {
+		enc, _ := zstd.NewWriter(nil, zstd.WithEncoderConcurrency(1))
+		return enc
+	}},
+	encodingGzip: {New: func() any {
+		return gzip.NewWriter(nil)
+	}},
+}
+
+// CompressionMiddleware compresses responses with the coding the client
+// prefers among zstd and gzip. Responses below policy.MinSize, responses that
+// already carry a Content-Encoding, event streams, compressed media types and
+// upgraded connections are sent as they are.
+func CompressionMiddleware(policy CompressionPolicy) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Upgrade") != "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			w.Header().Add("Vary", "Accept-Encoding")
+
+			encoding := negotiateEncoding(r.Header.Get("Accept-Encoding"))
+			if encoding == "" || r.Method == http.MethodHead {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			cw := &compressWriter{
+				ResponseWriter: w,
+				encoding:       encoding,
+				minSize:        policy.MinSize,
+				statusCode:     http.StatusOK,
+			}
+			defer cw.close()
+
+			next.ServeHTTP(cw, r)
+		})
+	}
+}
+
+// negotiateEncoding picks the supported coding with the highest quality in an
+// Accept-Encoding header, preferring zstd on ties. It returns an empty string
+// when the response is to be sent as it is.
+func negotiateEncoding(header string) string {
+	qualities := make(map[string]float64)
+	for _, part := range strings.Split(header, ",") {
+		name, params, _ := strings.Cut(part, ";")
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+
+		quality := 1.0
+		for _, param := range strings.Split(params, ";") {
+			if value, ok := strings.CutPrefix(strings.TrimSpace(param), "q="); ok {
+				if parsed, err := strconv.ParseFloat(value, 64); err == nil {
+					quality = parsed
+				}
+			}
+		}
+		qualities[name] = quality
+	}
+
+	best, bestQuality := "", 0.0
+	for _, encoding := range supportedEncodings {
+		quality, ok := qualities[encoding]
+		if !ok {
+			quality = qualities["*"]
+		}
+		if quality > bestQuality {
+			best, bestQuality = encoding, quality
+		}
+	}
+
+	return best
+}
+
+// compressWriter holds back the status and the first minSize bytes of a
+// response to decide whether it is compressed. A flush before that sends the
+// response as it is, it is being streamed.
+type compressWriter struct {
+	http.ResponseWriter
+	encoding   string
+	minSize    int
+	statusCode int
+	buf        []byte
+	decided    bool
+	encoder    encoder
+}
+
+func (cw *compressWriter) WriteHeader(code int) {
+	if cw.decided {
+		cw.ResponseWriter.WriteHeader(code)
+		return
+	}
+
+	// Informational responses precede the actual one.
+	if code >= 100 && code < 200 && code != http.StatusSwitchingProtocols {
+		cw.ResponseWriter.WriteHeader(code)
+		return
+	}
+
+	cw.statusCode = code
+	if !cw.compressible() {
+		cw.passThrough()
+	}
+}
+
+func (cw *compressWriter) Write(p []byte) (int, error) {
+	if !cw.decided {
+		if !cw.compressible() {
+			cw.passThrough()
+			return cw.ResponseWriter.Write(p)
+		}
+
+		cw.buf = append(cw.buf, p...)
+		if len(cw.buf) < cw.minSize {
+			return len(p), nil
+		}
+
+		if err := cw.startEncoding(); err != nil {
+			return 0, err
+		}
+
+		return len(p), nil
+	}
+
+	if cw.encoder != nil {
+		return cw.encoder.Write(p)
+	}
+
+	return cw.ResponseWriter.Write(p)
+}
+
+// Flush sends what was written so far, compressed if the response is.
+func (cw *compressWriter) Flush() {
+	if !cw.decided {
+		cw.passThrough()
+	}
+
+	if cw.encoder != nil {
+		_ = cw.encoder.Flush()
+	}
+
+	_ = http.NewResponseController(cw.ResponseWriter).Flush()
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer.
+func (cw *compressWriter) Unwrap() http.ResponseWriter {
+	return cw.ResponseWriter
+}
+
+// Hijack hands the connection over to a WebSocket handler.
+func (cw *compressWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	cw.decided = true
+
+	return http.NewResponseController(cw.ResponseWriter).Hijack()
+}
+
+// compressible reports whether the response may be compressed, judging by its
+// status and headers.
+func (cw *compressWriter) compressible() bool {
+	if cw.statusCode < http.StatusOK || cw.statusCode == http.StatusNoContent || cw.statusCode == http.StatusNotModified {
+		return false
+	}
+
+	header := cw.Header()
+	if header.Get("Content-Encoding") != "" {
+		return false
+	}
+
+	mediaType, _, _ := strings.Cut(header.Get("Content-Type"), ";")
+	mediaType = strings.ToLower(strings.TrimSpace(mediaType))
+	for _, incompressible := range incompressibleTypes {
+		if strings.HasPrefix(mediaType, incompressible) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// passThrough sends the response as it is, starting with what was held back.
+func (cw *compressWriter) passThrough() {
+	cw.decided = true
+	cw.ResponseWriter.WriteHeader(cw.statusCode)
+
+	if len(cw.buf) > 0 {
+		_, _ = cw.ResponseWriter.Write(cw.buf)
+		cw.buf = nil
+	}
+}
+
+// startEncoding sends the headers of the compressed response and the held
+// back bytes through the encoder.
+func (cw *compressWriter) startEncoding() error {
+	cw.decided = true
+
+	header := cw.Header()
+	if header.Get("Content-Type") == "" {
+		// Sniffing the compressed bytes would not work.
+		header.Set("Content-Type", http.DetectContentType(cw.buf))
+	}
+	header.Del("Content-Length")
+	header.Set("Content-Encoding", cw.encoding)
+	if etag := header.Get("ETag"); etag != "" && !strings.HasPrefix(etag, "W/") {
+		header.Set("ETag", "W/"+etag)
+	}
+	cw.ResponseWriter.WriteHeader(cw.statusCode)
+
+	cw.encoder = encoders[cw.encoding].Get().(encoder)
+	cw.encoder.Reset(cw.ResponseWriter)
+
+	buf := cw.buf
+	cw.buf = nil
+	if _, err := cw.encoder.Write(buf); err != nil {
+		return fmt.Errorf("failed to compress response: %w", err)
+	}
+
+	return nil
+}
+
+// close finishes the response once the handler returned.
+func (cw *compressWriter) close() {
+	if !cw.decided {
+		if len(cw.buf) == 0 {
+			cw.passThrough()
+			return
+		}
+
+		// The whole response is held back, so it is below minSize.
+		cw.Header().Set("Content-Length", strconv.Itoa(len(cw.buf)))
+		cw.passThrough()
+		return
+	}
+
+	if cw.encoder != nil {
+		_ = cw.encoder.Close()
+		cw.encoder.Reset(nil)
+		encoders[cw.encoding].Put(cw.encoder)
+		cw.encoder = nil
+	}
+}
+
+// DecompressionMiddleware decodes request bodies sent with a gzip or zstd
+// Content-Encoding for the handlers. Decoded bodies larger than maxSize fail
+// to read; other codings are refused with 415.
+func DecompressionMiddleware(maxSize int64, logger utils.LoggerInterface) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			encoding := strings.ToLower(strings.TrimSpace(r.Header.Get("Content-Encoding")))
+			if encoding == "" || encoding == "identity" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			var body io.Reader
+			switch encoding {
+			case encodingGzip:
+				reader, err := gzip.NewReader(r.Body)
+				if err != nil {
+					utils.ContextLogger(r.Context(), logger).Warnf("MIDDLEWARE: invalid gzip request body: %v", err)
+					http.Error(w, "Bad Request", http.StatusBadRequest)
+					return
+				}
+				defer reader.Close()
+				body = reader
+			case encodingZstd:
+				reader, err := zstd.NewReader(r.Body, zstd.WithDecoderConcurrency(1), zstd.WithDecoderMaxMemory(uint64(maxSize)))
+				if err != nil {
+					utils.ContextLogger(r.Context(), logger).Warnf("MIDDLEWARE: invalid zstd request body: %v", err)
+					http.Error(w, "Bad Request", http.StatusBadRequest)
+					return
+				}
+				defer reader.Close()
+				body = reader
+			default:
+				utils.ContextLogger(r.Context(), logger).Warnf("MIDDLEWARE: unsupported request Content-Encoding %q", encoding)
+				w.Header().Set("Accept-Encoding", strings.Join(supportedEncodings, ", "))
+				http.Error(w, "Unsupported Media Type", http.StatusUnsupportedMediaType)
+				return
+			}
+
+			r.Body = http.MaxBytesReader(w, io.NopCloser(body), maxSize)
+			r.ContentLength = -1
+			r.Header.Del("Content-Encoding")
+			r.Header.Del("Content-Length")
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,222 @@
+package middlewares_test
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/klauspost/compress/gzip"
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/k6zma/DockerMonitoringApp/backend/internal/presentation/middlewares"
+	"github.com/k6zma/DockerMonitoringApp/backend/mocks"
+)
+
+var largeBody = `[` + strings.Repeat(`{"container_id":"abc123","status":"running"},`, 100) + `{}]`
+
+func serveCompressed(acceptEncoding string, handler http.HandlerFunc) *httptest.ResponseRecorder {
+	compression := middlewares.CompressionMiddleware(middlewares.CompressionPolicy{MinSize: 1024})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/container_status", http.NoBody)
+	if acceptEncoding != "" {
+		req.Header.Set("Accept-Encoding", acceptEncoding)
+	}
+	rec := httptest.NewRecorder()
+
+	compression(handler).ServeHTTP(rec, req)
+
+	return rec
+}
+
+func jsonHandler(body string) http.HandlerFunc {
+	return func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, body)
+	}
+}
+
+func TestCompressionMiddleware_Negotiation(t *testing.T) {
+	tests := []struct {
+		name           string
+		acceptEncoding string
+		expected       string
+	}{
+		{name: "none", acceptEncoding: "", expected: ""},
+		{name: "gzip", acceptEncoding: "gzip, deflate", expected: "gzip"},
+		{name: "zstd preferred", acceptEncoding: "gzip, zstd, br", expected: "zstd"},
+		{name: "quality", acceptEncoding: "zstd;q=0.5, gzip", expected: "gzip"},
+		{name: "refused", acceptEncoding: "gzip;q=0, zstd;q=0", expected: ""},
+		{name: "wildcard", acceptEncoding: "*", expected: "zstd"},
+		{name: "wildcard without zstd", acceptEncoding: "zstd;q=0, *;q=0.1", expected: "gzip"},
+		{name: "unsupported", acceptEncoding: "br", expected: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serveCompressed(tt.acceptEncoding, jsonHandler(largeBody))
+
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.Equal(t, tt.expected, rec.Header().Get("Content-Encoding"))
+			assert.Equal(t, "Accept-Encoding", rec.Header().Get("Vary"))
+		})
+	}
+}
+
+func TestCompressionMiddleware_Gzip(t *testing.T) {
+	rec := serveCompressed("gzip", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Content-Length", "4600")
+		w.Header().Set("ETag", `"v1"`)
+		w.WriteHeader(http.StatusCreated)
+		_, _ = io.WriteString(w, largeBody[:512])
+		_, _ = io.WriteString(w, largeBody[512:])
+	})
+
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.Equal(t, "gzip", rec.Header().Get("Content-Encoding"))
+	assert.Empty(t, rec.Header().Get("Content-Length"))
+	assert.Equal(t, `W/"v1"`, rec.Header().Get("ETag"))
+	assert.Less(t, rec.Body.Len(), len(largeBody))
+
+	reader, err := gzip.NewReader(rec.Body)
+	require.NoError(t, err)
+	body, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	assert.Equal(t, largeBody, string(body))
+}
+
+func TestCompressionMiddleware_Zstd(t *testing.T) {
+	// Encoders are reused, every response must decode on its own.
+	for range 3 {
+		rec := serveCompressed("zstd", jsonHandler(largeBody))
+
+		assert.Equal(t, "zstd", rec.Header().Get("Content-Encoding"))
+
+		decoder, err := zstd.NewReader(rec.Body)
+		require.NoError(t, err)
+		body, err := io.ReadAll(decoder)
+		decoder.Close()
+		require.NoError(t, err)
+		assert.Equal(t, largeBody, string(body))
+	}
+}
+
+func TestCompressionMiddleware_SendsAsIs(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+	}{
+		{name: "below minimum size", handler: jsonHandler(`[]`)},
+		{name: "event stream", handler: func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Content-Type", "text/event-stream")
+			_, _ = io.WriteString(w, largeBody)
+		}},
+		{name: "already encoded", handler: func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.Header().Set("Content-Encoding", "br")
+			_, _ = io.WriteString(w, largeBody)
+		}},
+		{name: "compressed media type", handler: func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Content-Type", "image/png")
+			_, _ = io.WriteString(w, largeBody)
+		}},
+		{name: "not modified", handler: func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusNotModified)
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expected := httptest.NewRecorder()
+			tt.handler(expected, httptest.NewRequest(http.MethodGet, "/", http.NoBody))
+
+			rec := serveCompressed("gzip, zstd", tt.handler)
+
+			assert.Equal(t, expected.Code, rec.Code)
+			assert.Equal(t, expected.Header().Get("Content-Encoding"), rec.Header().Get("Content-Encoding"))
+			assert.Equal(t, expected.Body.String(), rec.Body.String())
+		})
+	}
+}
+
+func TestCompressionMiddleware_FlushStreams(t *testing.T) {
+	rec := serveCompressed("gzip", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		_, _ = io.WriteString(w, "{}\n")
+		assert.NoError(t, http.NewResponseController(w).Flush())
+		_, _ = io.WriteString(w, largeBody)
+	})
+
+	assert.True(t, rec.Flushed)
+	assert.Empty(t, rec.Header().Get("Content-Encoding"))
+	assert.Equal(t, "{}\n"+largeBody, rec.Body.String())
+}
+
+func serveDecompressed(t *testing.T, encoding string, body []byte) (*httptest.ResponseRecorder, string) {
+	t.Helper()
+
+	mockLogger := new(mocks.LoggerInterface)
+	mockLogger.On("Warnf", mock.Anything, mock.Anything).Return()
+
+	var received string
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		data, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, "Bad Request", http.StatusBadRequest)
+			return
+		}
+		received = string(data)
+		w.WriteHeader(http.StatusNoContent)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/container_status", bytes.NewReader(body))
+	req.Header.Set("Content-Encoding", encoding)
+	rec := httptest.NewRecorder()
+
+	middlewares.DecompressionMiddleware(4096, mockLogger)(http.HandlerFunc(handler)).ServeHTTP(rec, req)
+
+	return rec, received
+}
+
+func TestDecompressionMiddleware_DecodesBodies(t *testing.T) {
+	payload := `{"container_id":"abc123","status":"running"}`
+
+	var gzipped bytes.Buffer
+	gzipWriter := gzip.NewWriter(&gzipped)
+	_, _ = io.WriteString(gzipWriter, payload)
+	require.NoError(t, gzipWriter.Close())
+
+	zstdWriter, err := zstd.NewWriter(nil)
+	require.NoError(t, err)
+	zstded := zstdWriter.EncodeAll([]byte(payload), nil)
+
+	for encoding, body := range map[string][]byte{"gzip": gzipped.Bytes(), "zstd": zstded, "": []byte(payload)} {
+		rec, received := serveDecompressed(t, encoding, body)
+
+		assert.Equal(t, http.StatusNoContent, rec.Code, encoding)
+		assert.Equal(t, payload, received, encoding)
+	}
+}
+
+func TestDecompressionMiddleware_RefusesBodies(t *testing.T) {
+	var bomb bytes.Buffer
+	gzipWriter := gzip.NewWriter(&bomb)
+	_, _ = gzipWriter.Write(make([]byte, 1<<20))
+	require.NoError(t, gzipWriter.Close())
+
+	rec, _ := serveDecompressed(t, "gzip", bomb.Bytes())
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec, _ = serveDecompressed(t, "gzip", []byte("not gzip"))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec, _ = serveDecompressed(t, "br", []byte("{}"))
+	assert.Equal(t, http.StatusUnsupportedMediaType, rec.Code)
+	assert.Equal(t, "zstd, gzip", rec.Header().Get("Accept-Encoding"))
+}
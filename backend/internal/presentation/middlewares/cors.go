@@ -1,18 +1,119 @@
 package middlewares
 
-import "net/http"
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 
-func CorsMiddleware(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Access-Control-Allow-Origin", "*")
-		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PATCH, DELETE, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, X-Api-Key")
+	"github.com/gorilla/mux"
+)
+
+// CORSPolicy lists what browsers on other origins may do with the API. An
+// AllowedOrigins entry of "*" allows every origin.
+type CORSPolicy struct {
+	AllowedOrigins   []string
+	AllowedMethods   []string
+	AllowedHeaders   []string
+	ExposedHeaders   []string
+	AllowCredentials bool
+	MaxAge           time.Duration
+}
+
+// AllowsOrigin reports whether browsers on origin may use the API.
+func (p CORSPolicy) AllowsOrigin(origin string) bool {
+	return slices.Contains(p.AllowedOrigins, "*") || slices.Contains(p.AllowedOrigins, origin)
+}
+
+// CorsMiddleware answers preflight requests and adds the CORS headers to the
+// responses for allowed origins. A preflight is only granted for a method
+// allowed by policy that router serves at the requested path; the granted
+// methods are listed per route. Requests from other origins are passed on
+// without CORS headers, so browsers refuse to expose the response.
+func CorsMiddleware(policy CORSPolicy, router *mux.Router) func(http.Handler) http.Handler {
+	allowedHeaders := strings.Join(policy.AllowedHeaders, ", ")
+	exposedHeaders := strings.Join(policy.ExposedHeaders, ", ")
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			origin := r.Header.Get("Origin")
+			preflight := r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != ""
+
+			w.Header().Add("Vary", "Origin")
+			if preflight {
+				w.Header().Add("Vary", "Access-Control-Request-Method")
+				w.Header().Add("Vary", "Access-Control-Request-Headers")
+			}
+
+			if origin == "" || !policy.AllowsOrigin(origin) {
+				if preflight {
+					http.Error(w, "CORS preflight rejected", http.StatusForbidden)
+					return
+				}
+				if r.Method == http.MethodOptions {
+					w.WriteHeader(http.StatusNoContent)
+					return
+				}
+
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if policy.AllowCredentials || !slices.Contains(policy.AllowedOrigins, "*") {
+				w.Header().Set("Access-Control-Allow-Origin", origin)
+			} else {
+				w.Header().Set("Access-Control-Allow-Origin", "*")
+			}
+			if policy.AllowCredentials {
+				w.Header().Set("Access-Control-Allow-Credentials", "true")
+			}
+
+			if !preflight {
+				if exposedHeaders != "" {
+					w.Header().Set("Access-Control-Expose-Headers", exposedHeaders)
+				}
+				if r.Method == http.MethodOptions {
+					w.WriteHeader(http.StatusNoContent)
+					return
+				}
+
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			methods := routeMethods(router, r, policy.AllowedMethods)
+			if !slices.Contains(methods, r.Header.Get("Access-Control-Request-Method")) {
+				http.Error(w, "CORS preflight rejected", http.StatusForbidden)
+				return
+			}
+
+			w.Header().Set("Access-Control-Allow-Methods", strings.Join(methods, ", "))
+			if allowedHeaders != "" {
+				w.Header().Set("Access-Control-Allow-Headers", allowedHeaders)
+			}
+			if policy.MaxAge > 0 {
+				w.Header().Set("Access-Control-Max-Age", strconv.Itoa(int(policy.MaxAge.Seconds())))
+			}
 
-		if r.Method == "OPTIONS" {
 			w.WriteHeader(http.StatusNoContent)
-			return
+		})
+	}
+}
+
+// routeMethods returns the methods out of allowed that router serves at the
+// path of r.
+func routeMethods(router *mux.Router, r *http.Request, allowed []string) []string {
+	methods := make([]string, 0, len(allowed))
+	for _, method := range allowed {
+		probe := r.Clone(r.Context())
+		probe.Method = method
+
+		var match mux.RouteMatch
+		if router.Match(probe, &match) && match.MatchErr == nil {
+			methods = append(methods, method)
 		}
+	}
 
-		next.ServeHTTP(w, r)
-	})
+	return methods
 }
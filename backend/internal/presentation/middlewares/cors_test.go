@@ -0,0 +1,125 @@
+package middlewares_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/k6zma/DockerMonitoringApp/backend/internal/presentation/middlewares"
+)
+
+func newCORSRouter(policy middlewares.CORSPolicy) *mux.Router {
+	handler := func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}
+
+	router := mux.NewRouter()
+	router.Use(middlewares.CorsMiddleware(policy, router))
+	router.HandleFunc("/container_status", handler).Methods(http.MethodGet, http.MethodOptions)
+	router.HandleFunc("/container_status", handler).Methods(http.MethodPost, http.MethodOptions)
+	router.HandleFunc("/container_status/{container_id}", handler).Methods(http.MethodPatch, http.MethodOptions)
+	router.HandleFunc("/container_status/{container_id}", handler).Methods(http.MethodDelete, http.MethodOptions)
+
+	return router
+}
+
+var dashboardPolicy = middlewares.CORSPolicy{
+	AllowedOrigins: []string{"https://dashboard.example.com"},
+	AllowedMethods: []string{http.MethodGet, http.MethodPost, http.MethodPatch},
+	AllowedHeaders: []string{"Content-Type", "X-Api-Key"},
+	ExposedHeaders: []string{"X-Request-ID"},
+	MaxAge:         10 * time.Minute,
+}
+
+func preflight(router http.Handler, path, origin, method string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodOptions, path, http.NoBody)
+	req.Header.Set("Origin", origin)
+	req.Header.Set("Access-Control-Request-Method", method)
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, req)
+
+	return rec
+}
+
+func TestCorsMiddleware_Preflight_ListsMethodsOfRoute(t *testing.T) {
+	router := newCORSRouter(dashboardPolicy)
+
+	rec := preflight(router, "/container_status/abc", "https://dashboard.example.com", http.MethodPatch)
+
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	assert.Equal(t, "https://dashboard.example.com", rec.Header().Get("Access-Control-Allow-Origin"))
+	// DELETE is served at the path but not allowed by the policy, GET is allowed but not served.
+	assert.Equal(t, "PATCH", rec.Header().Get("Access-Control-Allow-Methods"))
+	assert.Equal(t, "Content-Type, X-Api-Key", rec.Header().Get("Access-Control-Allow-Headers"))
+	assert.Equal(t, "600", rec.Header().Get("Access-Control-Max-Age"))
+	assert.Contains(t, rec.Header().Values("Vary"), "Origin")
+
+	rec = preflight(router, "/container_status", "https://dashboard.example.com", http.MethodGet)
+
+	assert.Equal(t, "GET, POST", rec.Header().Get("Access-Control-Allow-Methods"))
+}
+
+func TestCorsMiddleware_Preflight_Rejected(t *testing.T) {
+	router := newCORSRouter(dashboardPolicy)
+
+	tests := []struct {
+		name   string
+		path   string
+		origin string
+		method string
+	}{
+		{name: "unknown origin", path: "/container_status", origin: "https://evil.example.com", method: http.MethodGet},
+		{name: "method not allowed by policy", path: "/container_status/abc", origin: "https://dashboard.example.com", method: http.MethodDelete},
+		{name: "method not served by route", path: "/container_status/abc", origin: "https://dashboard.example.com", method: http.MethodGet},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := preflight(router, tt.path, tt.origin, tt.method)
+
+			assert.Equal(t, http.StatusForbidden, rec.Code)
+			assert.Empty(t, rec.Header().Get("Access-Control-Allow-Methods"))
+		})
+	}
+}
+
+func TestCorsMiddleware_SimpleRequest(t *testing.T) {
+	tests := []struct {
+		name           string
+		policy         middlewares.CORSPolicy
+		origin         string
+		expectedOrigin string
+	}{
+		{name: "allowed origin", policy: dashboardPolicy, origin: "https://dashboard.example.com", expectedOrigin: "https://dashboard.example.com"},
+		{name: "other origin", policy: dashboardPolicy, origin: "https://evil.example.com", expectedOrigin: ""},
+		{name: "no origin", policy: dashboardPolicy, origin: "", expectedOrigin: ""},
+		{name: "any origin", policy: middlewares.CORSPolicy{AllowedOrigins: []string{"*"}}, origin: "https://a.example.com", expectedOrigin: "*"},
+		{
+			name:           "any origin with credentials",
+			policy:         middlewares.CORSPolicy{AllowedOrigins: []string{"*"}, AllowCredentials: true},
+			origin:         "https://a.example.com",
+			expectedOrigin: "https://a.example.com",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/container_status", http.NoBody)
+			if tt.origin != "" {
+				req.Header.Set("Origin", tt.origin)
+			}
+			rec := httptest.NewRecorder()
+
+			newCORSRouter(tt.policy).ServeHTTP(rec, req)
+
+			assert.Equal(t, http.StatusNoContent, rec.Code)
+			assert.Equal(t, tt.expectedOrigin, rec.Header().Get("Access-Control-Allow-Origin"))
+			assert.Equal(t, []string{"Origin"}, rec.Header().Values("Vary"))
+		})
+	}
+}
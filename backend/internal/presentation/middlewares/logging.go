@@ -1,9 +1,14 @@
 package middlewares
 
 import (
+	"bufio"
+	"net"
 	"net/http"
 	"time"
 
+	"github.com/gorilla/mux"
+
+	"github.com/k6zma/DockerMonitoringApp/backend/internal/presentation/metrics"
 	"github.com/k6zma/DockerMonitoringApp/backend/pkg/utils"
 )
 
@@ -17,7 +22,32 @@ func (rw *responseWriterWrapper) WriteHeader(code int) {
 	rw.ResponseWriter.WriteHeader(code)
 }
 
-func LoggingMiddleware(logger utils.LoggerInterface) func(http.Handler) http.Handler {
+// Unwrap lets http.ResponseController reach the underlying writer, so event
+// streams can flush and extend their write deadline.
+func (rw *responseWriterWrapper) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
+
+// Hijack hands the connection over to a WebSocket handler.
+func (rw *responseWriterWrapper) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	conn, buf, err := http.NewResponseController(rw.ResponseWriter).Hijack()
+	if err == nil {
+		rw.statusCode = http.StatusSwitchingProtocols
+	}
+
+	return conn, buf, err
+}
+
+// unmatchedRoute labels requests that did not match any route, keeping the
+// number of metric series bounded.
+const unmatchedRoute = "unmatched"
+
+// LoggingMiddleware logs every request and, when httpMetrics is not nil, records
+// it in the HTTP metrics under its route template.
+func LoggingMiddleware(
+	logger utils.LoggerInterface,
+	httpMetrics *metrics.HTTPMetrics,
+) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			start := time.Now()
@@ -31,18 +61,40 @@ func LoggingMiddleware(logger utils.LoggerInterface) func(http.Handler) http.Han
 			next.ServeHTTP(wrapper, r)
 			duration := time.Since(start)
 
-			if wrapper.statusCode >= 200 && wrapper.statusCode < 400 {
-				logger.Infof(
-					"REQUESTS: %s - %s - %s - %d - %s",
-					clientIP,
-					r.Method,
-					r.URL.Path,
-					wrapper.statusCode,
-					duration,
-				)
+			route := routeTemplate(r)
+			if httpMetrics != nil {
+				httpMetrics.Observe(r.Method, route, wrapper.statusCode, duration)
+			}
+
+			fields := []interface{}{
+				"request_id", utils.RequestIDFromContext(r.Context()),
+				"client_ip", clientIP,
+				"method", r.Method,
+				"path", r.URL.Path,
+				"route", route,
+				"status", wrapper.statusCode,
+				"duration", duration,
+			}
+
+			if wrapper.statusCode < http.StatusBadRequest {
+				logger.Infow("REQUESTS: request handled", fields...)
 			} else {
-				logger.Errorf("REQUESTS: %s - %s - %s - %d - %s", clientIP, r.Method, r.URL.Path, wrapper.statusCode, duration)
+				logger.Errorw("REQUESTS: request failed", fields...)
 			}
 		})
 	}
 }
+
+func routeTemplate(r *http.Request) string {
+	route := mux.CurrentRoute(r)
+	if route == nil {
+		return unmatchedRoute
+	}
+
+	template, err := route.GetPathTemplate()
+	if err != nil {
+		return unmatchedRoute
+	}
+
+	return template
+}
@@ -0,0 +1,180 @@
+package middlewares
+
+import (
+	"math"
+	"net/http"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	"github.com/k6zma/DockerMonitoringApp/backend/pkg/utils"
+)
+
+// Route groups share a rate limit.
+const (
+	RouteGroupRead  = "read"
+	RouteGroupWrite = "write"
+	RouteGroupAdmin = "admin"
+)
+
+// RateLimit is a token bucket refilled with RequestsPerSecond tokens per
+// second up to Burst.
+type RateLimit struct {
+	RequestsPerSecond float64
+	Burst             int
+}
+
+type bucket struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+// RateLimiter keeps a token bucket per client for every route group. Clients
+// are told apart by IP address before authentication and by key or user name
+// after it, so neither many addresses sharing a key nor many keys used from one
+// address get around the limit.
+type RateLimiter struct {
+	limits      map[string]RateLimit
+	routes      map[string]string
+	idleTimeout time.Duration
+	logger      utils.LoggerInterface
+
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+}
+
+func NewRateLimiter(
+	limits map[string]RateLimit,
+	idleTimeout time.Duration,
+	logger utils.LoggerInterface,
+) *RateLimiter {
+	return &RateLimiter{
+		limits:      limits,
+		routes:      make(map[string]string),
+		idleTimeout: idleTimeout,
+		logger:      logger,
+		buckets:     make(map[string]*bucket),
+		lastSweep:   time.Now(),
+	}
+}
+
+// Assign puts the route into group. Routes without a group, or in a group
+// without a limit, are not limited.
+func (l *RateLimiter) Assign(method, pathTemplate, group string) {
+	l.routes[method+" "+pathTemplate] = group
+}
+
+// ByClientIP limits requests per client IP. It should run before
+// authentication, so that floods of invalid keys are limited as well.
+func (l *RateLimiter) ByClientIP(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		l.serve(w, r, next, "ip:"+utils.GetClientIP(r))
+	})
+}
+
+// ByPrincipal limits requests per API key or user. It must run after
+// AuthMiddleware.
+func (l *RateLimiter) ByPrincipal(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		principal, ok := PrincipalFromContext(r.Context())
+		if !ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		l.serve(w, r, next, string(principal.Method)+":"+principal.Name)
+	})
+}
+
+func (l *RateLimiter) serve(w http.ResponseWriter, r *http.Request, next http.Handler, client string) {
+	group, ok := l.routes[r.Method+" "+routeTemplate(r)]
+	if !ok {
+		next.ServeHTTP(w, r)
+		return
+	}
+
+	if delay := l.reserve(group, client, time.Now()); delay > 0 {
+		utils.ContextLogger(r.Context(), l.logger).Warnf("MIDDLEWARE: rate limit of %s group exceeded by %s",
+			group, client)
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(delay.Seconds()))))
+		http.Error(w, "Too Many Requests", http.StatusTooManyRequests)
+		return
+	}
+
+	next.ServeHTTP(w, r)
+}
+
+// reserve takes a token from the client's bucket and returns zero, or returns
+// how long the client has to wait for the next token without taking it.
+func (l *RateLimiter) reserve(group, client string, now time.Time) time.Duration {
+	limit, ok := l.limits[group]
+	if !ok {
+		return 0
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if now.Sub(l.lastSweep) >= l.idleTimeout {
+		for key, b := range l.buckets {
+			if now.Sub(b.lastSeen) >= l.idleTimeout {
+				delete(l.buckets, key)
+			}
+		}
+		l.lastSweep = now
+	}
+
+	key := group + "|" + client
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{limiter: rate.NewLimiter(rate.Limit(limit.RequestsPerSecond), limit.Burst)}
+		l.buckets[key] = b
+	}
+	b.lastSeen = now
+
+	reservation := b.limiter.ReserveN(now, 1)
+	if delay := reservation.DelayFrom(now); delay > 0 {
+		reservation.CancelAt(now)
+		return delay
+	}
+
+	return 0
+}
+
+// ConcurrencyLimitMiddleware refuses requests with 503 while maxInFlight
+// requests are being handled, shedding load before the database connections
+// run out. Requests to the streamRoutes templates are not counted, they stay
+// open for long and are capped by the status streams.
+func ConcurrencyLimitMiddleware(
+	maxInFlight int,
+	streamRoutes []string,
+	logger utils.LoggerInterface,
+) func(http.Handler) http.Handler {
+	inFlight := make(chan struct{}, maxInFlight)
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if slices.Contains(streamRoutes, routeTemplate(r)) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			select {
+			case inFlight <- struct{}{}:
+				defer func() { <-inFlight }()
+			default:
+				utils.ContextLogger(r.Context(), logger).Warnf("MIDDLEWARE: %d requests in flight, refusing %s %s",
+					maxInFlight, r.Method, r.URL.Path)
+				w.Header().Set("Retry-After", "1")
+				http.Error(w, "Service Unavailable", http.StatusServiceUnavailable)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
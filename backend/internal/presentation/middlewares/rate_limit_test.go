@@ -0,0 +1,152 @@
+package middlewares_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/k6zma/DockerMonitoringApp/backend/internal/application/usecases"
+	"github.com/k6zma/DockerMonitoringApp/backend/internal/domain"
+	"github.com/k6zma/DockerMonitoringApp/backend/internal/presentation/middlewares"
+	"github.com/k6zma/DockerMonitoringApp/backend/mocks"
+)
+
+func newRateLimitedRouter(logger *mocks.LoggerInterface) *mux.Router {
+	repo := new(mocks.APIKeyRepository)
+	repo.On("FindByHash", mock.Anything, mock.Anything).Return(nil, nil)
+
+	apiKeyUseCase := usecases.NewAPIKeyUseCase(repo, nil, []usecases.StaticAPIKey{
+		{Name: "pinger", Key: "pinger-key", Scopes: []domain.Scope{domain.ScopeStatusRead}},
+		{Name: "dashboard", Key: "dashboard-key", Scopes: []domain.Scope{domain.ScopeStatusRead}},
+	}, time.Minute, "", logger)
+	roleUseCase := usecases.NewRoleUseCase(new(mocks.RoleAssignmentRepository), logger)
+
+	rateLimiter := middlewares.NewRateLimiter(map[string]middlewares.RateLimit{
+		middlewares.RouteGroupRead: {RequestsPerSecond: 0.01, Burst: 2},
+	}, time.Hour, logger)
+	rateLimiter.Assign(http.MethodGet, "/container_status", middlewares.RouteGroupRead)
+
+	handler := func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}
+
+	router := mux.NewRouter()
+	router.Use(rateLimiter.ByClientIP)
+	router.Use(middlewares.AuthMiddleware(apiKeyUseCase, nil, roleUseCase, logger))
+	router.Use(rateLimiter.ByPrincipal)
+	router.HandleFunc("/container_status", handler).Methods(http.MethodGet)
+	router.HandleFunc("/events", handler).Methods(http.MethodGet)
+
+	return router
+}
+
+func doRequest(router http.Handler, path, key, remoteAddr string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, http.NoBody)
+	req.Header.Set("X-Api-Key", key)
+	req.RemoteAddr = remoteAddr
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, req)
+
+	return rec
+}
+
+func TestRateLimiter_PerKey(t *testing.T) {
+	mockLogger := new(mocks.LoggerInterface)
+	mockLogger.On("Warnf", mock.Anything, mock.Anything, mock.Anything).Return()
+
+	router := newRateLimitedRouter(mockLogger)
+
+	// The same key from different addresses shares one bucket.
+	assert.Equal(t, http.StatusNoContent, doRequest(router, "/container_status", "pinger-key", "10.0.0.1:1000").Code)
+	assert.Equal(t, http.StatusNoContent, doRequest(router, "/container_status", "pinger-key", "10.0.0.2:1000").Code)
+
+	rec := doRequest(router, "/container_status", "pinger-key", "10.0.0.3:1000")
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.Equal(t, "100", rec.Header().Get("Retry-After"))
+
+	// Other keys and routes outside the group are not affected.
+	assert.Equal(t, http.StatusNoContent, doRequest(router, "/container_status", "dashboard-key", "10.0.0.4:1000").Code)
+	assert.Equal(t, http.StatusNoContent, doRequest(router, "/events", "pinger-key", "10.0.0.5:1000").Code)
+}
+
+func TestRateLimiter_PerClientIP(t *testing.T) {
+	mockLogger := new(mocks.LoggerInterface)
+	mockLogger.On("Warnf", mock.Anything, mock.Anything, mock.Anything).Return()
+	mockLogger.On("Warnf", mock.Anything).Return()
+
+	router := newRateLimitedRouter(mockLogger)
+
+	// Connections from one address share a bucket, even with invalid keys.
+	assert.Equal(t, http.StatusUnauthorized, doRequest(router, "/container_status", "wrong-key", "10.0.0.1:1000").Code)
+	assert.Equal(t, http.StatusNoContent, doRequest(router, "/container_status", "dashboard-key", "10.0.0.1:2000").Code)
+	assert.Equal(t, http.StatusTooManyRequests, doRequest(router, "/container_status", "pinger-key", "10.0.0.1:3000").Code)
+}
+
+func TestConcurrencyLimitMiddleware_ShedsLoad(t *testing.T) {
+	mockLogger := new(mocks.LoggerInterface)
+	mockLogger.On("Warnf", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return()
+
+	release := make(chan struct{})
+	started := make(chan struct{})
+	handler := middlewares.ConcurrencyLimitMiddleware(1, nil, mockLogger)(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		close(started)
+		<-release
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/container_status", http.NoBody))
+	}()
+	<-started
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/container_status", http.NoBody))
+
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.Equal(t, "1", rec.Header().Get("Retry-After"))
+
+	close(release)
+	wg.Wait()
+}
+
+func TestConcurrencyLimitMiddleware_SkipsStreams(t *testing.T) {
+	release := make(chan struct{})
+	started := make(chan struct{})
+
+	router := mux.NewRouter()
+	router.Use(middlewares.ConcurrencyLimitMiddleware(1, []string{"/stream"}, new(mocks.LoggerInterface)))
+	router.HandleFunc("/container_status", func(w http.ResponseWriter, _ *http.Request) {
+		close(started)
+		<-release
+		w.WriteHeader(http.StatusNoContent)
+	})
+	router.HandleFunc("/stream", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/container_status", http.NoBody))
+	}()
+	<-started
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/stream", http.NoBody))
+
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+
+	close(release)
+	wg.Wait()
+}
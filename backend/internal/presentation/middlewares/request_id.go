@@ -0,0 +1,45 @@
+package middlewares
+
+import (
+	"net/http"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/k6zma/DockerMonitoringApp/backend/pkg/utils"
+)
+
+const maxRequestIDLength = 128
+
+// RequestIDMiddleware keeps the X-Request-ID sent by the client or generates a
+// new one, returns it in the response and stores it in the request context so
+// that every log line of the request carries it.
+func RequestIDMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestID := r.Header.Get(utils.RequestIDHeader)
+		if !isValidRequestID(requestID) {
+			requestID = utils.NewRequestID()
+		}
+
+		w.Header().Set(utils.RequestIDHeader, requestID)
+		trace.SpanFromContext(r.Context()).SetAttributes(attribute.String("request.id", requestID))
+
+		next.ServeHTTP(w, r.WithContext(utils.ContextWithRequestID(r.Context(), requestID)))
+	})
+}
+
+// isValidRequestID accepts IDs of printable ASCII without spaces, so a client
+// cannot inject arbitrary content into the logs.
+func isValidRequestID(requestID string) bool {
+	if requestID == "" || len(requestID) > maxRequestIDLength {
+		return false
+	}
+
+	for i := 0; i < len(requestID); i++ {
+		if requestID[i] <= ' ' || requestID[i] > '~' {
+			return false
+		}
+	}
+
+	return true
+}
@@ -2,44 +2,129 @@ package routes
 
 import (
 	"net/http"
+	"net/netip"
 
 	"github.com/gorilla/mux"
 	httpSwagger "github.com/swaggo/http-swagger"
 
-	"github.com/k6zma/DockerMonitoringApp/backend/internal/infrastructure/config"
+	"github.com/k6zma/DockerMonitoringApp/backend/internal/application/usecases"
+	"github.com/k6zma/DockerMonitoringApp/backend/internal/domain"
 	"github.com/k6zma/DockerMonitoringApp/backend/internal/presentation/handlers"
+	"github.com/k6zma/DockerMonitoringApp/backend/internal/presentation/metrics"
 	"github.com/k6zma/DockerMonitoringApp/backend/internal/presentation/middlewares"
 	"github.com/k6zma/DockerMonitoringApp/backend/pkg/utils"
 )
 
 func InitRoutes(
-	cfg *config.Config,
 	errHandler *handlers.ErrorHandlers,
 	conHandler *handlers.ContainerStatusHandler,
+	eventHandler *handlers.ContainerEventHandler,
+	healthHandler *handlers.HealthHandler,
+	apiKeyHandler *handlers.APIKeyHandler,
+	roleHandler *handlers.RoleHandler,
+	auditHandler *handlers.AuditHandler,
+	streamHandler *handlers.StatusStreamHandler,
+	apiKeyUseCase usecases.APIKeyUseCaseInterface,
+	tokenVerifier usecases.TokenVerifier,
+	roleUseCase usecases.RoleUseCaseInterface,
+	auditUseCase usecases.AuditUseCaseInterface,
+	rateLimiter *middlewares.RateLimiter,
+	maxInFlight int,
+	requireClientCert bool,
+	trustedProxies []netip.Prefix,
+	corsPolicy middlewares.CORSPolicy,
+	compression *middlewares.CompressionPolicy,
+	httpMetrics *metrics.HTTPMetrics,
+	metricsHandler http.Handler,
 	logger utils.LoggerInterface,
 ) *mux.Router {
 	router := mux.NewRouter()
 
-	router.Use(middlewares.LoggingMiddleware(logger))
-	router.Use(middlewares.CorsMiddleware)
+	router.Use(middlewares.ClientIPMiddleware(trustedProxies))
+	router.Use(middlewares.TracingMiddleware)
+	router.Use(middlewares.RequestIDMiddleware)
+	router.Use(middlewares.LoggingMiddleware(logger, httpMetrics))
+	router.Use(middlewares.CorsMiddleware(corsPolicy, router))
+	if compression != nil {
+		router.Use(middlewares.CompressionMiddleware(*compression))
+	}
 
 	router.PathPrefix("/swagger/").Handler(httpSwagger.WrapHandler)
 
+	router.HandleFunc("/healthz", healthHandler.Liveness).Methods(http.MethodGet)
+	router.HandleFunc("/readyz", healthHandler.Readiness).Methods(http.MethodGet)
+
+	if metricsHandler != nil {
+		router.Handle("/metrics", metricsHandler).Methods(http.MethodGet)
+	}
+
 	router.NotFoundHandler = http.HandlerFunc(errHandler.NotFoundHandler)
 	router.MethodNotAllowedHandler = http.HandlerFunc(errHandler.MethodNotAllowedHandler)
 
 	apiRouter := router.PathPrefix("/api/v1").Subrouter()
 
-	apiRouter.Use(middlewares.AuthMiddleware(cfg, logger))
+	// Every API route is declared together with the scope it requires and the
+	// group it is rate limited in; the authorization middleware denies routes
+	// that are not listed here.
+	policy := middlewares.NewPolicy()
+	handle := func(method, path string, scope domain.Scope, group string, handler http.HandlerFunc) {
+		apiRouter.HandleFunc(path, handler).Methods(method, http.MethodOptions)
+		policy.Allow(method, "/api/v1"+path, scope)
+		if rateLimiter != nil {
+			rateLimiter.Assign(method, "/api/v1"+path, group)
+		}
+	}
+
+	// Ingestion routes, which are called by the pinger, additionally require a
+	// verified client certificate from API key callers when mutual TLS is
+	// configured. Operators signed in with a JWT use them without one.
+	ingest := func(handler http.HandlerFunc) http.HandlerFunc {
+		if !requireClientCert {
+			return handler
+		}
+
+		return middlewares.RequireClientCertMiddleware(logger)(handler).ServeHTTP
+	}
+
+	if maxInFlight > 0 {
+		streams := []string{"/api/v1/ws", "/api/v1/stream"}
+		apiRouter.Use(middlewares.ConcurrencyLimitMiddleware(maxInFlight, streams, logger))
+	}
+	if rateLimiter != nil {
+		apiRouter.Use(rateLimiter.ByClientIP)
+	}
+	apiRouter.Use(middlewares.AuthMiddleware(apiKeyUseCase, tokenVerifier, roleUseCase, logger))
+	if rateLimiter != nil {
+		apiRouter.Use(rateLimiter.ByPrincipal)
+	}
+	apiRouter.Use(middlewares.AuditMiddleware(auditUseCase, logger))
+	apiRouter.Use(middlewares.AuthorizationMiddleware(policy, logger))
+	// Request bodies are decoded once the request is authorized, signatures
+	// cover the body as it was sent.
+	if compression != nil {
+		apiRouter.Use(middlewares.DecompressionMiddleware(compression.MaxRequestSize, logger))
+	}
+
+	handle(http.MethodGet, "/container_status", domain.ScopeStatusRead, middlewares.RouteGroupRead, conHandler.GetFilteredContainerStatuses)
+	handle(http.MethodPost, "/container_status", domain.ScopeStatusWrite, middlewares.RouteGroupWrite, ingest(conHandler.CreateContainerStatus))
+	handle(http.MethodPatch, "/container_status/{container_id}", domain.ScopeStatusWrite, middlewares.RouteGroupWrite, ingest(conHandler.UpdateContainerStatus))
+	handle(http.MethodDelete, "/container_status/{container_id}", domain.ScopeStatusWrite, middlewares.RouteGroupWrite, ingest(conHandler.DeleteContainerStatus))
+
+	handle(http.MethodGet, "/events", domain.ScopeStatusRead, middlewares.RouteGroupRead, eventHandler.GetContainerEvents)
+
+	handle(http.MethodGet, "/ws", domain.ScopeStatusRead, middlewares.RouteGroupRead, streamHandler.StreamContainerStatuses)
+	handle(http.MethodGet, "/stream", domain.ScopeStatusRead, middlewares.RouteGroupRead, streamHandler.StreamContainerStatusEvents)
+
+	handle(http.MethodGet, "/api_keys", domain.ScopeAdmin, middlewares.RouteGroupAdmin, apiKeyHandler.ListAPIKeys)
+	handle(http.MethodPost, "/api_keys", domain.ScopeAdmin, middlewares.RouteGroupAdmin, apiKeyHandler.CreateAPIKey)
+	handle(http.MethodDelete, "/api_keys/{id:[0-9]+}", domain.ScopeAdmin, middlewares.RouteGroupAdmin, apiKeyHandler.RevokeAPIKey)
+	handle(http.MethodPost, "/api_keys/{id:[0-9]+}/rotate", domain.ScopeAdmin, middlewares.RouteGroupAdmin, apiKeyHandler.RotateAPIKey)
+
+	handle(http.MethodGet, "/role_assignments", domain.ScopeAdmin, middlewares.RouteGroupAdmin, roleHandler.ListRoleAssignments)
+	handle(http.MethodPut, "/role_assignments/{subject}", domain.ScopeAdmin, middlewares.RouteGroupAdmin, roleHandler.AssignRole)
+	handle(http.MethodDelete, "/role_assignments/{subject}", domain.ScopeAdmin, middlewares.RouteGroupAdmin, roleHandler.RemoveRoleAssignment)
 
-	apiRouter.HandleFunc("/container_status", conHandler.GetFilteredContainerStatuses).
-		Methods(http.MethodGet, http.MethodOptions)
-	apiRouter.HandleFunc("/container_status", conHandler.CreateContainerStatus).
-		Methods(http.MethodPost, http.MethodOptions)
-	apiRouter.HandleFunc("/container_status/{container_id}", conHandler.UpdateContainerStatus).
-		Methods(http.MethodPatch, http.MethodOptions)
-	apiRouter.HandleFunc("/container_status/{container_id}", conHandler.DeleteContainerStatus).
-		Methods(http.MethodDelete, http.MethodOptions)
+	handle(http.MethodGet, "/audit", domain.ScopeAdmin, middlewares.RouteGroupAdmin, auditHandler.GetAuditEntries)
 
 	return router
 }
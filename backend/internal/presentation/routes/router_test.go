@@ -0,0 +1,170 @@
+package routes_test
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+
+	"github.com/k6zma/DockerMonitoringApp/backend/internal/domain"
+	"github.com/k6zma/DockerMonitoringApp/backend/internal/presentation/handlers"
+	"github.com/k6zma/DockerMonitoringApp/backend/internal/presentation/middlewares"
+	"github.com/k6zma/DockerMonitoringApp/backend/internal/presentation/routes"
+	"github.com/k6zma/DockerMonitoringApp/backend/mocks"
+	"github.com/k6zma/DockerMonitoringApp/backend/pkg/utils"
+)
+
+var errUnavailable = errors.New("unavailable")
+
+// newRouter builds the API with mutual TLS required on the ingestion routes.
+// Every use case fails, so an authorized request is answered by its handler
+// with an error other than 401 or 403.
+func newRouter() *mux.Router {
+	logger := &utils.Logger{SugaredLogger: zap.NewNop().Sugar()}
+
+	statusUseCase := new(mocks.ContainerStatusUseCaseInterface)
+	statusUseCase.On("GetContainerStatusesVersion", mock.Anything, mock.Anything).Return(nil, errUnavailable)
+	statusUseCase.On("DeleteContainerStatusByContainerID", mock.Anything, mock.Anything).Return(errUnavailable)
+
+	eventUseCase := new(mocks.ContainerEventUseCaseInterface)
+	eventUseCase.On("FindContainerEvents", mock.Anything, mock.Anything).Return(nil, errUnavailable)
+
+	apiKeyUseCase := new(mocks.APIKeyUseCaseInterface)
+	apiKeyUseCase.On("Authenticate", mock.Anything, "pinger-key").Return(&domain.Principal{
+		Name:   "pinger",
+		Method: domain.AuthMethodAPIKey,
+		Scopes: []domain.Scope{domain.ScopeStatusRead, domain.ScopeStatusWrite},
+	}, nil)
+	apiKeyUseCase.On("ListAPIKeys", mock.Anything).Return(nil, errUnavailable)
+	apiKeyUseCase.On("RevokeAPIKey", mock.Anything, mock.Anything).Return(errUnavailable)
+	apiKeyUseCase.On("RotateAPIKey", mock.Anything, mock.Anything, mock.Anything).Return(nil, errUnavailable)
+
+	tokenVerifier := new(mocks.TokenVerifier)
+	for _, role := range []domain.Role{domain.RoleViewer, domain.RoleOperator, domain.RoleAdmin} {
+		tokenVerifier.On("Verify", mock.Anything, string(role)).Return(&domain.Principal{
+			Name:   "alice",
+			Method: domain.AuthMethodJWT,
+			Roles:  []domain.Role{role},
+		}, nil)
+	}
+
+	roleUseCase := new(mocks.RoleUseCaseInterface)
+	roleUseCase.On("ResolveRoles", mock.Anything, mock.Anything).Return(nil)
+	roleUseCase.On("ListRoleAssignments", mock.Anything).Return(nil, errUnavailable)
+	roleUseCase.On("RemoveRoleAssignment", mock.Anything, mock.Anything).Return(errUnavailable)
+
+	auditUseCase := new(mocks.AuditUseCaseInterface)
+	auditUseCase.On("Record", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	auditUseCase.On("FindAuditEntries", mock.Anything, mock.Anything).Return(nil, errUnavailable)
+
+	return routes.InitRoutes(
+		handlers.NewErrorHandlers(logger),
+		handlers.NewContainerStatusHandler(statusUseCase, logger),
+		handlers.NewContainerEventHandler(eventUseCase, logger),
+		handlers.NewHealthHandler(new(mocks.HealthUseCaseInterface), logger),
+		handlers.NewAPIKeyHandler(apiKeyUseCase, logger),
+		handlers.NewRoleHandler(roleUseCase, logger),
+		handlers.NewAuditHandler(auditUseCase, logger),
+		handlers.NewStatusStreamHandler(nil, time.Minute, nil, logger),
+		apiKeyUseCase,
+		tokenVerifier,
+		roleUseCase,
+		auditUseCase,
+		nil,
+		0,
+		true,
+		nil,
+		middlewares.CORSPolicy{},
+		nil,
+		nil,
+		nil,
+		logger,
+	)
+}
+
+type caller struct {
+	name       string
+	header     string
+	value      string
+	clientCert bool
+}
+
+var (
+	viewer       = caller{name: "viewer", header: "Authorization", value: "Bearer viewer"}
+	operator     = caller{name: "operator", header: "Authorization", value: "Bearer operator"}
+	admin        = caller{name: "admin", header: "Authorization", value: "Bearer admin"}
+	pinger       = caller{name: "pinger", header: "X-Api-Key", value: "pinger-key", clientCert: true}
+	pingerNoCert = caller{name: "pinger without certificate", header: "X-Api-Key", value: "pinger-key"}
+)
+
+func TestInitRoutes_RoleToRoute(t *testing.T) {
+	router := newRouter()
+
+	tests := []struct {
+		method  string
+		path    string
+		allowed []caller
+	}{
+		{http.MethodGet, "/api/v1/container_status", []caller{viewer, operator, admin, pinger, pingerNoCert}},
+		{http.MethodPost, "/api/v1/container_status", []caller{operator, admin, pinger}},
+		{http.MethodPatch, "/api/v1/container_status/abc123", []caller{operator, admin, pinger}},
+		{http.MethodDelete, "/api/v1/container_status/abc123", []caller{operator, admin, pinger}},
+		{http.MethodGet, "/api/v1/events", []caller{viewer, operator, admin, pinger, pingerNoCert}},
+		{http.MethodGet, "/api/v1/api_keys", []caller{admin}},
+		{http.MethodPost, "/api/v1/api_keys", []caller{admin}},
+		{http.MethodDelete, "/api/v1/api_keys/1", []caller{admin}},
+		{http.MethodPost, "/api/v1/api_keys/1/rotate", []caller{admin}},
+		{http.MethodGet, "/api/v1/role_assignments", []caller{admin}},
+		{http.MethodPut, "/api/v1/role_assignments/bob", []caller{admin}},
+		{http.MethodDelete, "/api/v1/role_assignments/bob", []caller{admin}},
+		{http.MethodGet, "/api/v1/audit", []caller{admin}},
+	}
+
+	verified := &tls.ConnectionState{
+		VerifiedChains: [][]*x509.Certificate{{{Subject: pkix.Name{CommonName: "pinger"}}}},
+	}
+
+	for _, tt := range tests {
+		for _, c := range []caller{viewer, operator, admin, pinger, pingerNoCert} {
+			t.Run(tt.method+" "+tt.path+" as "+c.name, func(t *testing.T) {
+				req := httptest.NewRequest(tt.method, tt.path, strings.NewReader("{}"))
+				req.Header.Set(c.header, c.value)
+				req.Header.Set("Content-Type", "application/json")
+				req.TLS = &tls.ConnectionState{}
+				if c.clientCert {
+					req.TLS = verified
+				}
+				rec := httptest.NewRecorder()
+
+				router.ServeHTTP(rec, req)
+
+				assert.NotEqual(t, http.StatusUnauthorized, rec.Code)
+				if containsCaller(tt.allowed, c) {
+					assert.NotEqual(t, http.StatusForbidden, rec.Code)
+				} else {
+					assert.Equal(t, http.StatusForbidden, rec.Code)
+				}
+			})
+		}
+	}
+}
+
+func containsCaller(callers []caller, c caller) bool {
+	for _, allowed := range callers {
+		if allowed == c {
+			return true
+		}
+	}
+
+	return false
+}
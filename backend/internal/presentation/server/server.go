@@ -1,50 +1,230 @@
 package server
 
 import (
+	"context"
+	"crypto/tls"
 	"errors"
 	"fmt"
 	"net/http"
+	"net/netip"
 	"time"
 
 	"github.com/jmoiron/sqlx"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 
 	"github.com/k6zma/DockerMonitoringApp/backend/internal/application/usecases"
+	"github.com/k6zma/DockerMonitoringApp/backend/internal/domain"
 	"github.com/k6zma/DockerMonitoringApp/backend/internal/infrastructure/config"
 	"github.com/k6zma/DockerMonitoringApp/backend/internal/infrastructure/db/postgres/repositories"
 	"github.com/k6zma/DockerMonitoringApp/backend/internal/presentation/handlers"
+	"github.com/k6zma/DockerMonitoringApp/backend/internal/presentation/metrics"
+	"github.com/k6zma/DockerMonitoringApp/backend/internal/presentation/middlewares"
 	"github.com/k6zma/DockerMonitoringApp/backend/internal/presentation/routes"
 	"github.com/k6zma/DockerMonitoringApp/backend/pkg/utils"
 )
 
 type Server struct {
-	httpServer *http.Server
-	logger     utils.LoggerInterface
+	httpServer     *http.Server
+	statusHub      *usecases.StatusHub
+	statusListener *repositories.StatusChangeListener
+	listenCtx      context.Context
+	stopListening  context.CancelFunc
+	logger         utils.LoggerInterface
 }
 
-func NewServer(cfg *config.Config, db *sqlx.DB, logger utils.LoggerInterface) *Server {
+func NewServer(
+	cfg *config.Config,
+	db *sqlx.DB,
+	tokenVerifier usecases.TokenVerifier,
+	tlsConfig *tls.Config,
+	expectedMigrationVersion uint,
+	logger utils.LoggerInterface,
+) *Server {
 	repo := repositories.NewContainerStatusRepositoryImpl(db, logger)
-	useCase := usecases.NewContainerStatusUseCase(repo, logger)
+	eventRepo := repositories.NewContainerEventRepositoryImpl(db, logger)
+	crashLoopPolicy := usecases.CrashLoopPolicy{
+		Window:    cfg.CrashLoop.Window,
+		Threshold: cfg.CrashLoop.RestartThreshold,
+	}
+	// Changes reach the subscribers of every instance through Postgres
+	// notifications, the writing instance included.
+	statusHub := usecases.NewStatusHub(cfg.Stream.MaxSubscribers, cfg.Stream.BufferSize, cfg.Stream.HistorySize, logger)
+	statusNotifier := repositories.NewStatusChangeNotifier(db, logger)
+	statusListener := repositories.NewStatusChangeListener(db, statusHub, logger)
+	transactor := repositories.NewTransactor(db, logger)
+	useCase := usecases.NewContainerStatusUseCase(repo, eventRepo, transactor, crashLoopPolicy, statusNotifier, logger)
+	eventUseCase := usecases.NewContainerEventUseCase(eventRepo, logger)
 	containerHandler := handlers.NewContainerStatusHandler(useCase, logger)
+	eventHandler := handlers.NewContainerEventHandler(eventUseCase, logger)
+	healthRepo := repositories.NewHealthRepositoryImpl(db, logger)
+	healthUseCase := usecases.NewHealthUseCase(healthRepo, expectedMigrationVersion, logger)
+	healthHandler := handlers.NewHealthHandler(healthUseCase, logger)
+	apiKeyRepo := repositories.NewAPIKeyRepositoryImpl(db, logger)
+	nonceRepo := repositories.NewRequestNonceRepositoryImpl(db, logger)
+	apiKeyUseCase := usecases.NewAPIKeyUseCase(
+		apiKeyRepo,
+		nonceRepo,
+		staticAPIKeys(cfg.AuthAPI),
+		cfg.AuthAPI.Signatures.MaxClockSkew,
+		cfg.AuthAPI.Signatures.SecretKey,
+		logger,
+	)
+	apiKeyHandler := handlers.NewAPIKeyHandler(apiKeyUseCase, logger)
+	roleRepo := repositories.NewRoleAssignmentRepositoryImpl(db, logger)
+	roleUseCase := usecases.NewRoleUseCase(roleRepo, logger)
+	roleHandler := handlers.NewRoleHandler(roleUseCase, logger)
+	auditRepo := repositories.NewAuditRepositoryImpl(db, logger)
+	auditUseCase := usecases.NewAuditUseCase(auditRepo, logger)
+	auditHandler := handlers.NewAuditHandler(auditUseCase, logger)
+	corsPolicy := middlewares.CORSPolicy{
+		AllowedOrigins:   cfg.CORS.AllowedOrigins,
+		AllowedMethods:   cfg.CORS.AllowedMethods,
+		AllowedHeaders:   cfg.CORS.AllowedHeaders,
+		ExposedHeaders:   cfg.CORS.ExposedHeaders,
+		AllowCredentials: cfg.CORS.AllowCredentials,
+		MaxAge:           cfg.CORS.MaxAge,
+	}
+	streamHandler := handlers.NewStatusStreamHandler(statusHub, cfg.Stream.PingInterval, corsPolicy.AllowsOrigin, logger)
 	errHandler := handlers.NewErrorHandlers(logger)
 
-	router := routes.InitRoutes(cfg, errHandler, containerHandler, logger)
+	var rateLimiter *middlewares.RateLimiter
+	if cfg.RateLimit.Enabled {
+		rateLimiter = middlewares.NewRateLimiter(rateLimits(cfg.RateLimit), cfg.RateLimit.IdleTimeout, logger)
+	}
+
+	var compression *middlewares.CompressionPolicy
+	if cfg.Server.Compression.Enabled {
+		compression = &middlewares.CompressionPolicy{
+			MinSize:        cfg.Server.Compression.MinSize,
+			MaxRequestSize: cfg.Server.Compression.MaxRequestSize,
+		}
+	}
+
+	var httpMetrics *metrics.HTTPMetrics
+	var metricsHandler http.Handler
+	if cfg.Metrics.Enabled {
+		registry := prometheus.NewRegistry()
+		registry.MustRegister(
+			collectors.NewGoCollector(),
+			collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+			collectors.NewDBStatsCollector(db.DB, cfg.DB.DataBaseName),
+			metrics.NewContainerStatusCollector(useCase, logger),
+		)
+		httpMetrics = metrics.NewHTTPMetrics(registry)
+		metricsHandler = promhttp.HandlerFor(registry, promhttp.HandlerOpts{
+			ErrorHandling: promhttp.ContinueOnError,
+		})
+	}
+
+	router := routes.InitRoutes(
+		errHandler,
+		containerHandler,
+		eventHandler,
+		healthHandler,
+		apiKeyHandler,
+		roleHandler,
+		auditHandler,
+		streamHandler,
+		apiKeyUseCase,
+		tokenVerifier,
+		roleUseCase,
+		auditUseCase,
+		rateLimiter,
+		cfg.Server.MaxInFlight,
+		tlsConfig != nil && cfg.Server.TLS.ClientCAFile != "",
+		trustedProxies(cfg.Server.TrustedProxies),
+		corsPolicy,
+		compression,
+		httpMetrics,
+		metricsHandler,
+		logger,
+	)
 
 	httpServer := &http.Server{
 		Addr:         fmt.Sprintf(":%d", cfg.Server.Port),
 		Handler:      router,
+		TLSConfig:    tlsConfig,
 		ReadTimeout:  10 * time.Second,
 		WriteTimeout: 10 * time.Second,
 		IdleTimeout:  15 * time.Second,
 	}
 
+	listenCtx, stopListening := context.WithCancel(context.Background())
+
 	return &Server{
-		httpServer: httpServer,
-		logger:     logger,
+		httpServer:     httpServer,
+		statusHub:      statusHub,
+		statusListener: statusListener,
+		listenCtx:      listenCtx,
+		stopListening:  stopListening,
+		logger:         logger,
+	}
+}
+
+// staticAPIKeys converts the keys from the configuration, which bootstrap access
+// before any key is stored in the database.
+func staticAPIKeys(cfg *config.AuthAPIConfig) []usecases.StaticAPIKey {
+	keys := make([]usecases.StaticAPIKey, 0, len(cfg.Keys))
+	for _, key := range cfg.Keys {
+		scopes := make([]domain.Scope, 0, len(key.Scopes))
+		for _, scope := range key.Scopes {
+			scopes = append(scopes, domain.Scope(scope))
+		}
+
+		keys = append(keys, usecases.StaticAPIKey{
+			Name:     key.Name,
+			Key:      key.Key,
+			Scopes:   scopes,
+			AuthMode: domain.APIKeyAuthMode(key.AuthMode),
+		})
 	}
+
+	return keys
 }
 
+// rateLimits converts the rate limits of the route groups.
+func rateLimits(cfg *config.RateLimitConfig) map[string]middlewares.RateLimit {
+	limits := make(map[string]middlewares.RateLimit, len(cfg.Groups))
+	for group, limit := range cfg.Groups {
+		limits[group] = middlewares.RateLimit{
+			RequestsPerSecond: limit.RequestsPerSecond,
+			Burst:             limit.Burst,
+		}
+	}
+
+	return limits
+}
+
+// trustedProxies parses the proxy addresses and CIDR ranges validated by the
+// config, a single address being a range of its own.
+func trustedProxies(addresses []string) []netip.Prefix {
+	prefixes := make([]netip.Prefix, 0, len(addresses))
+	for _, address := range addresses {
+		if prefix, err := netip.ParsePrefix(address); err == nil {
+			prefixes = append(prefixes, prefix.Masked())
+		} else if addr, err := netip.ParseAddr(address); err == nil {
+			prefixes = append(prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+		}
+	}
+
+	return prefixes
+}
+
+// Start serves HTTPS when the server was given a TLS configuration and plain
+// HTTP otherwise. The status change notifications are listened for meanwhile.
 func (s *Server) Start() error {
-	if err := s.httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+	go s.statusListener.Run(s.listenCtx)
+
+	var err error
+	if s.httpServer.TLSConfig != nil {
+		err = s.httpServer.ListenAndServeTLS("", "")
+	} else {
+		err = s.httpServer.ListenAndServe()
+	}
+
+	if err != nil && !errors.Is(err, http.ErrServerClosed) {
 		s.logger.Infof("SERVER: failed to start HTTP server: %v\n", err)
 		return fmt.Errorf("failed to start HTTP server: %w", err)
 	}
@@ -52,7 +232,12 @@ func (s *Server) Start() error {
 	return nil
 }
 
+// Stop closes the server. The status stream connections, which the server
+// does not track once upgraded, are told to go away.
 func (s *Server) Stop() error {
+	s.stopListening()
+	s.statusHub.Close()
+
 	if err := s.httpServer.Close(); err != nil {
 		s.logger.Infof("SERVER: failed to stop HTTP server: %v\n", err)
 		return fmt.Errorf("failed to stop HTTP server: %w", err)
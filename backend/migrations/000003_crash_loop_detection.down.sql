@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_container_events_container_id_created_at;
+
+DROP TABLE IF EXISTS container_events;
+
+ALTER TABLE container_status
+    DROP COLUMN IF EXISTS crash_looping,
+    DROP COLUMN IF EXISTS restart_count;
\ No newline at end of file
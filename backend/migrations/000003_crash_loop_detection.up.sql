@@ -0,0 +1,14 @@
+ALTER TABLE container_status
+    ADD COLUMN restart_count INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN crash_looping BOOLEAN NOT NULL DEFAULT false;
+
+CREATE TABLE container_events (
+    id BIGSERIAL PRIMARY KEY,
+    container_id TEXT NOT NULL,
+    type VARCHAR(64) NOT NULL,
+    status VARCHAR(255) NOT NULL DEFAULT '',
+    message TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP DEFAULT now()
+);
+
+CREATE INDEX idx_container_events_container_id_created_at ON container_events(container_id, created_at);
\ No newline at end of file
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_modified_at;
+ALTER TABLE container_status DROP COLUMN IF EXISTS modified_at;
\ No newline at end of file
@@ -0,0 +1,6 @@
+-- updated_at holds when the pinger checked a container, which lies in the past
+-- for results delivered late from its outbox. modified_at holds when the row
+-- was last written.
+ALTER TABLE container_status ADD COLUMN modified_at TIMESTAMP NOT NULL DEFAULT now();
+
+CREATE INDEX idx_modified_at ON container_status(modified_at);
\ No newline at end of file
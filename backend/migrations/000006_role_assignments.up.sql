@@ -0,0 +1,6 @@
+CREATE TABLE role_assignments (
+    subject VARCHAR(255) PRIMARY KEY,
+    role VARCHAR(32) NOT NULL CHECK (role IN ('viewer', 'operator', 'admin')),
+    created_at TIMESTAMP NOT NULL DEFAULT now(),
+    updated_at TIMESTAMP NOT NULL DEFAULT now()
+);
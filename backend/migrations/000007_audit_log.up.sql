@@ -0,0 +1,19 @@
+CREATE TABLE audit_log (
+    id BIGSERIAL PRIMARY KEY,
+    actor VARCHAR(255) NOT NULL,
+    auth_method VARCHAR(16) NOT NULL,
+    client_ip VARCHAR(255) NOT NULL DEFAULT '',
+    method VARCHAR(16) NOT NULL,
+    route TEXT NOT NULL,
+    path TEXT NOT NULL,
+    container_id TEXT NOT NULL DEFAULT '',
+    status_code INTEGER NOT NULL,
+    before JSONB,
+    after JSONB,
+    request_id VARCHAR(64) NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_audit_log_created_at ON audit_log(created_at);
+CREATE INDEX idx_audit_log_actor_created_at ON audit_log(actor, created_at);
+CREATE INDEX idx_audit_log_container_id_created_at ON audit_log(container_id, created_at);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_api_keys_hmac_prefix;
+
+ALTER TABLE api_keys DROP COLUMN IF EXISTS auth_mode;
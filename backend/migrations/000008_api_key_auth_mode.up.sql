@@ -0,0 +1,4 @@
+ALTER TABLE api_keys ADD COLUMN auth_mode VARCHAR(16) NOT NULL DEFAULT 'api_key' CHECK (auth_mode IN ('api_key', 'hmac'));
+
+-- Signed requests name their key by its prefix.
+CREATE UNIQUE INDEX idx_api_keys_hmac_prefix ON api_keys(key_prefix) WHERE auth_mode = 'hmac';
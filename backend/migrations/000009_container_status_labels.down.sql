@@ -0,0 +1 @@
+ALTER TABLE container_status DROP COLUMN IF EXISTS labels;
\ No newline at end of file
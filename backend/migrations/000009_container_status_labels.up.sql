@@ -0,0 +1 @@
+ALTER TABLE container_status ADD COLUMN labels JSONB NOT NULL DEFAULT '{}'::jsonb;
\ No newline at end of file
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS container_status_deletions;
\ No newline at end of file
@@ -0,0 +1,6 @@
+-- Deleted rows leave no updated_at behind, the time of the last deletion is
+-- kept in a single row for the Last-Modified of the container status list.
+CREATE TABLE container_status_deletions (
+    id BOOLEAN PRIMARY KEY DEFAULT true CHECK (id),
+    deleted_at TIMESTAMP NOT NULL
+);
\ No newline at end of file
@@ -0,0 +1,16 @@
+-- The secret HMAC keys sign requests with, encrypted with the server secret
+-- key. It is derived from the API key but cannot be derived from key_hash, so
+-- the lookup digests stored next to it are of no use for forging signatures.
+-- HMAC keys created before this column existed have none and must be rotated.
+ALTER TABLE api_keys ADD COLUMN signing_secret BYTEA;
+
+-- Nonces of accepted signatures, shared by all backend replicas. A nonce is
+-- kept until the timestamp it was signed with falls out of the clock skew.
+CREATE TABLE request_nonces (
+    key_id VARCHAR(255) NOT NULL,
+    nonce VARCHAR(128) NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (key_id, nonce)
+);
+
+CREATE INDEX idx_request_nonces_expires_at ON request_nonces(expires_at);
\ No newline at end of file
@@ -0,0 +1,201 @@
+// Code generated by mockery v2.47.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	dto "github.com/k6zma/DockerMonitoringApp/backend/internal/application/dto"
+	domain "github.com/k6zma/DockerMonitoringApp/backend/internal/domain"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// APIKeyUseCaseInterface is an autogenerated mock type for the APIKeyUseCaseInterface type
+type APIKeyUseCaseInterface struct {
+	mock.Mock
+}
+
+// Authenticate provides a mock function with given fields: ctx, rawKey
+func (_m *APIKeyUseCaseInterface) Authenticate(ctx context.Context, rawKey string) (*domain.Principal, error) {
+	ret := _m.Called(ctx, rawKey)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Authenticate")
+	}
+
+	var r0 *domain.Principal
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*domain.Principal, error)); ok {
+		return rf(ctx, rawKey)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *domain.Principal); ok {
+		r0 = rf(ctx, rawKey)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Principal)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, rawKey)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// AuthenticateSignature provides a mock function with given fields: ctx, signature
+func (_m *APIKeyUseCaseInterface) AuthenticateSignature(ctx context.Context, signature *dto.RequestSignatureDTO) (*domain.Principal, error) {
+	ret := _m.Called(ctx, signature)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AuthenticateSignature")
+	}
+
+	var r0 *domain.Principal
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *dto.RequestSignatureDTO) (*domain.Principal, error)); ok {
+		return rf(ctx, signature)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *dto.RequestSignatureDTO) *domain.Principal); ok {
+		r0 = rf(ctx, signature)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Principal)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *dto.RequestSignatureDTO) error); ok {
+		r1 = rf(ctx, signature)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateAPIKey provides a mock function with given fields: ctx, keyDTO
+func (_m *APIKeyUseCaseInterface) CreateAPIKey(ctx context.Context, keyDTO *dto.CreateAPIKeyDTO) (*dto.IssuedAPIKeyDTO, error) {
+	ret := _m.Called(ctx, keyDTO)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateAPIKey")
+	}
+
+	var r0 *dto.IssuedAPIKeyDTO
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *dto.CreateAPIKeyDTO) (*dto.IssuedAPIKeyDTO, error)); ok {
+		return rf(ctx, keyDTO)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *dto.CreateAPIKeyDTO) *dto.IssuedAPIKeyDTO); ok {
+		r0 = rf(ctx, keyDTO)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dto.IssuedAPIKeyDTO)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *dto.CreateAPIKeyDTO) error); ok {
+		r1 = rf(ctx, keyDTO)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListAPIKeys provides a mock function with given fields: ctx
+func (_m *APIKeyUseCaseInterface) ListAPIKeys(ctx context.Context) ([]*dto.APIKeyDTO, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListAPIKeys")
+	}
+
+	var r0 []*dto.APIKeyDTO
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]*dto.APIKeyDTO, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []*dto.APIKeyDTO); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*dto.APIKeyDTO)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RevokeAPIKey provides a mock function with given fields: ctx, id
+func (_m *APIKeyUseCaseInterface) RevokeAPIKey(ctx context.Context, id int64) error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokeAPIKey")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RotateAPIKey provides a mock function with given fields: ctx, id, gracePeriod
+func (_m *APIKeyUseCaseInterface) RotateAPIKey(ctx context.Context, id int64, gracePeriod time.Duration) (*dto.IssuedAPIKeyDTO, error) {
+	ret := _m.Called(ctx, id, gracePeriod)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RotateAPIKey")
+	}
+
+	var r0 *dto.IssuedAPIKeyDTO
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, time.Duration) (*dto.IssuedAPIKeyDTO, error)); ok {
+		return rf(ctx, id, gracePeriod)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, time.Duration) *dto.IssuedAPIKeyDTO); ok {
+		r0 = rf(ctx, id, gracePeriod)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dto.IssuedAPIKeyDTO)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, time.Duration) error); ok {
+		r1 = rf(ctx, id, gracePeriod)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewAPIKeyUseCaseInterface creates a new instance of APIKeyUseCaseInterface. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewAPIKeyUseCaseInterface(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *APIKeyUseCaseInterface {
+	mock := &APIKeyUseCaseInterface{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,79 @@
+// Code generated by mockery v2.47.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	dto "github.com/k6zma/DockerMonitoringApp/backend/internal/application/dto"
+	mock "github.com/stretchr/testify/mock"
+
+	usecases "github.com/k6zma/DockerMonitoringApp/backend/internal/application/usecases"
+)
+
+// AuditUseCaseInterface is an autogenerated mock type for the AuditUseCaseInterface type
+type AuditUseCaseInterface struct {
+	mock.Mock
+}
+
+// FindAuditEntries provides a mock function with given fields: ctx, filter
+func (_m *AuditUseCaseInterface) FindAuditEntries(ctx context.Context, filter *dto.AuditFilter) ([]*dto.AuditEntryDTO, error) {
+	ret := _m.Called(ctx, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindAuditEntries")
+	}
+
+	var r0 []*dto.AuditEntryDTO
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *dto.AuditFilter) ([]*dto.AuditEntryDTO, error)); ok {
+		return rf(ctx, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *dto.AuditFilter) []*dto.AuditEntryDTO); ok {
+		r0 = rf(ctx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*dto.AuditEntryDTO)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *dto.AuditFilter) error); ok {
+		r1 = rf(ctx, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Record provides a mock function with given fields: ctx, entry, change
+func (_m *AuditUseCaseInterface) Record(ctx context.Context, entry *dto.AuditEntryDTO, change *usecases.AuditChange) error {
+	ret := _m.Called(ctx, entry, change)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Record")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *dto.AuditEntryDTO, *usecases.AuditChange) error); ok {
+		r0 = rf(ctx, entry, change)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewAuditUseCaseInterface creates a new instance of AuditUseCaseInterface. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewAuditUseCaseInterface(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *AuditUseCaseInterface {
+	mock := &AuditUseCaseInterface{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
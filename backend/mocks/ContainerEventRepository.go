@@ -0,0 +1,107 @@
+// Code generated by mockery v2.47.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	dto "github.com/k6zma/DockerMonitoringApp/backend/internal/application/dto"
+	domain "github.com/k6zma/DockerMonitoringApp/backend/internal/domain"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// ContainerEventRepository is an autogenerated mock type for the ContainerEventRepository type
+type ContainerEventRepository struct {
+	mock.Mock
+}
+
+// Count provides a mock function with given fields: ctx, filter
+func (_m *ContainerEventRepository) Count(ctx context.Context, filter *dto.ContainerEventFilter) (int, error) {
+	ret := _m.Called(ctx, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Count")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *dto.ContainerEventFilter) (int, error)); ok {
+		return rf(ctx, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *dto.ContainerEventFilter) int); ok {
+		r0 = rf(ctx, filter)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *dto.ContainerEventFilter) error); ok {
+		r1 = rf(ctx, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Create provides a mock function with given fields: ctx, event
+func (_m *ContainerEventRepository) Create(ctx context.Context, event *domain.ContainerEvent) error {
+	ret := _m.Called(ctx, event)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Create")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.ContainerEvent) error); ok {
+		r0 = rf(ctx, event)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Find provides a mock function with given fields: ctx, filter
+func (_m *ContainerEventRepository) Find(ctx context.Context, filter *dto.ContainerEventFilter) ([]*domain.ContainerEvent, error) {
+	ret := _m.Called(ctx, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Find")
+	}
+
+	var r0 []*domain.ContainerEvent
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *dto.ContainerEventFilter) ([]*domain.ContainerEvent, error)); ok {
+		return rf(ctx, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *dto.ContainerEventFilter) []*domain.ContainerEvent); ok {
+		r0 = rf(ctx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.ContainerEvent)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *dto.ContainerEventFilter) error); ok {
+		r1 = rf(ctx, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewContainerEventRepository creates a new instance of ContainerEventRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewContainerEventRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *ContainerEventRepository {
+	mock := &ContainerEventRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,59 @@
+// Code generated by mockery v2.47.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	dto "github.com/k6zma/DockerMonitoringApp/backend/internal/application/dto"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// ContainerEventUseCaseInterface is an autogenerated mock type for the ContainerEventUseCaseInterface type
+type ContainerEventUseCaseInterface struct {
+	mock.Mock
+}
+
+// FindContainerEvents provides a mock function with given fields: ctx, filter
+func (_m *ContainerEventUseCaseInterface) FindContainerEvents(ctx context.Context, filter *dto.ContainerEventFilter) ([]*dto.ContainerEventDTO, error) {
+	ret := _m.Called(ctx, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindContainerEvents")
+	}
+
+	var r0 []*dto.ContainerEventDTO
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *dto.ContainerEventFilter) ([]*dto.ContainerEventDTO, error)); ok {
+		return rf(ctx, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *dto.ContainerEventFilter) []*dto.ContainerEventDTO); ok {
+		r0 = rf(ctx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*dto.ContainerEventDTO)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *dto.ContainerEventFilter) error); ok {
+		r1 = rf(ctx, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewContainerEventUseCaseInterface creates a new instance of ContainerEventUseCaseInterface. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewContainerEventUseCaseInterface(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *ContainerEventUseCaseInterface {
+	mock := &ContainerEventUseCaseInterface{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
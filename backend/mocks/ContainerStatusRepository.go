@@ -3,6 +3,8 @@
 package mocks
 
 import (
+	context "context"
+
 	dto "github.com/k6zma/DockerMonitoringApp/backend/internal/application/dto"
 	domain "github.com/k6zma/DockerMonitoringApp/backend/internal/domain"
 
@@ -14,17 +16,17 @@ type ContainerStatusRepository struct {
 	mock.Mock
 }
 
-// Create provides a mock function with given fields: status
-func (_m *ContainerStatusRepository) Create(status *domain.ContainerStatus) error {
-	ret := _m.Called(status)
+// Create provides a mock function with given fields: ctx, status
+func (_m *ContainerStatusRepository) Create(ctx context.Context, status *domain.ContainerStatus) error {
+	ret := _m.Called(ctx, status)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Create")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(*domain.ContainerStatus) error); ok {
-		r0 = rf(status)
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.ContainerStatus) error); ok {
+		r0 = rf(ctx, status)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -32,17 +34,17 @@ func (_m *ContainerStatusRepository) Create(status *domain.ContainerStatus) erro
 	return r0
 }
 
-// DeleteByContainerID provides a mock function with given fields: containerID
-func (_m *ContainerStatusRepository) DeleteByContainerID(containerID string) error {
-	ret := _m.Called(containerID)
+// DeleteByContainerID provides a mock function with given fields: ctx, containerID
+func (_m *ContainerStatusRepository) DeleteByContainerID(ctx context.Context, containerID string) error {
+	ret := _m.Called(ctx, containerID)
 
 	if len(ret) == 0 {
 		panic("no return value specified for DeleteByContainerID")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string) error); ok {
-		r0 = rf(containerID)
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, containerID)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -50,9 +52,9 @@ func (_m *ContainerStatusRepository) DeleteByContainerID(containerID string) err
 	return r0
 }
 
-// Find provides a mock function with given fields: filter
-func (_m *ContainerStatusRepository) Find(filter *dto.ContainerStatusFilter) ([]*domain.ContainerStatus, error) {
-	ret := _m.Called(filter)
+// Find provides a mock function with given fields: ctx, filter
+func (_m *ContainerStatusRepository) Find(ctx context.Context, filter *dto.ContainerStatusFilter) ([]*domain.ContainerStatus, error) {
+	ret := _m.Called(ctx, filter)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Find")
@@ -60,19 +62,19 @@ func (_m *ContainerStatusRepository) Find(filter *dto.ContainerStatusFilter) ([]
 
 	var r0 []*domain.ContainerStatus
 	var r1 error
-	if rf, ok := ret.Get(0).(func(*dto.ContainerStatusFilter) ([]*domain.ContainerStatus, error)); ok {
-		return rf(filter)
+	if rf, ok := ret.Get(0).(func(context.Context, *dto.ContainerStatusFilter) ([]*domain.ContainerStatus, error)); ok {
+		return rf(ctx, filter)
 	}
-	if rf, ok := ret.Get(0).(func(*dto.ContainerStatusFilter) []*domain.ContainerStatus); ok {
-		r0 = rf(filter)
+	if rf, ok := ret.Get(0).(func(context.Context, *dto.ContainerStatusFilter) []*domain.ContainerStatus); ok {
+		r0 = rf(ctx, filter)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]*domain.ContainerStatus)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(*dto.ContainerStatusFilter) error); ok {
-		r1 = rf(filter)
+	if rf, ok := ret.Get(1).(func(context.Context, *dto.ContainerStatusFilter) error); ok {
+		r1 = rf(ctx, filter)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -80,17 +82,17 @@ func (_m *ContainerStatusRepository) Find(filter *dto.ContainerStatusFilter) ([]
 	return r0, r1
 }
 
-// Update provides a mock function with given fields: status
-func (_m *ContainerStatusRepository) Update(status *domain.ContainerStatus) error {
-	ret := _m.Called(status)
+// Update provides a mock function with given fields: ctx, status
+func (_m *ContainerStatusRepository) Update(ctx context.Context, status *domain.ContainerStatus) error {
+	ret := _m.Called(ctx, status)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Update")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(*domain.ContainerStatus) error); ok {
-		r0 = rf(status)
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.ContainerStatus) error); ok {
+		r0 = rf(ctx, status)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -98,6 +100,36 @@ func (_m *ContainerStatusRepository) Update(status *domain.ContainerStatus) erro
 	return r0
 }
 
+// Version provides a mock function with given fields: ctx, filter
+func (_m *ContainerStatusRepository) Version(ctx context.Context, filter *dto.ContainerStatusFilter) (*domain.ContainerStatusListVersion, error) {
+	ret := _m.Called(ctx, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Version")
+	}
+
+	var r0 *domain.ContainerStatusListVersion
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *dto.ContainerStatusFilter) (*domain.ContainerStatusListVersion, error)); ok {
+		return rf(ctx, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *dto.ContainerStatusFilter) *domain.ContainerStatusListVersion); ok {
+		r0 = rf(ctx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.ContainerStatusListVersion)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *dto.ContainerStatusFilter) error); ok {
+		r1 = rf(ctx, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // NewContainerStatusRepository creates a new instance of ContainerStatusRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewContainerStatusRepository(t interface {
@@ -3,6 +3,8 @@
 package mocks
 
 import (
+	context "context"
+
 	dto "github.com/k6zma/DockerMonitoringApp/backend/internal/application/dto"
 	mock "github.com/stretchr/testify/mock"
 )
@@ -12,9 +14,9 @@ type ContainerStatusUseCaseInterface struct {
 	mock.Mock
 }
 
-// CreateContainerStatus provides a mock function with given fields: statusDTO
-func (_m *ContainerStatusUseCaseInterface) CreateContainerStatus(statusDTO *dto.ContainerStatusDTO) (*dto.ContainerStatusDTO, error) {
-	ret := _m.Called(statusDTO)
+// CreateContainerStatus provides a mock function with given fields: ctx, statusDTO
+func (_m *ContainerStatusUseCaseInterface) CreateContainerStatus(ctx context.Context, statusDTO *dto.ContainerStatusDTO) (*dto.ContainerStatusDTO, error) {
+	ret := _m.Called(ctx, statusDTO)
 
 	if len(ret) == 0 {
 		panic("no return value specified for CreateContainerStatus")
@@ -22,19 +24,19 @@ func (_m *ContainerStatusUseCaseInterface) CreateContainerStatus(statusDTO *dto.
 
 	var r0 *dto.ContainerStatusDTO
 	var r1 error
-	if rf, ok := ret.Get(0).(func(*dto.ContainerStatusDTO) (*dto.ContainerStatusDTO, error)); ok {
-		return rf(statusDTO)
+	if rf, ok := ret.Get(0).(func(context.Context, *dto.ContainerStatusDTO) (*dto.ContainerStatusDTO, error)); ok {
+		return rf(ctx, statusDTO)
 	}
-	if rf, ok := ret.Get(0).(func(*dto.ContainerStatusDTO) *dto.ContainerStatusDTO); ok {
-		r0 = rf(statusDTO)
+	if rf, ok := ret.Get(0).(func(context.Context, *dto.ContainerStatusDTO) *dto.ContainerStatusDTO); ok {
+		r0 = rf(ctx, statusDTO)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*dto.ContainerStatusDTO)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(*dto.ContainerStatusDTO) error); ok {
-		r1 = rf(statusDTO)
+	if rf, ok := ret.Get(1).(func(context.Context, *dto.ContainerStatusDTO) error); ok {
+		r1 = rf(ctx, statusDTO)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -42,17 +44,17 @@ func (_m *ContainerStatusUseCaseInterface) CreateContainerStatus(statusDTO *dto.
 	return r0, r1
 }
 
-// DeleteContainerStatusByContainerID provides a mock function with given fields: containerID
-func (_m *ContainerStatusUseCaseInterface) DeleteContainerStatusByContainerID(containerID string) error {
-	ret := _m.Called(containerID)
+// DeleteContainerStatusByContainerID provides a mock function with given fields: ctx, containerID
+func (_m *ContainerStatusUseCaseInterface) DeleteContainerStatusByContainerID(ctx context.Context, containerID string) error {
+	ret := _m.Called(ctx, containerID)
 
 	if len(ret) == 0 {
 		panic("no return value specified for DeleteContainerStatusByContainerID")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string) error); ok {
-		r0 = rf(containerID)
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, containerID)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -60,9 +62,9 @@ func (_m *ContainerStatusUseCaseInterface) DeleteContainerStatusByContainerID(co
 	return r0
 }
 
-// FindContainerStatuses provides a mock function with given fields: filter
-func (_m *ContainerStatusUseCaseInterface) FindContainerStatuses(filter *dto.ContainerStatusFilter) ([]*dto.ContainerStatusDTO, error) {
-	ret := _m.Called(filter)
+// FindContainerStatuses provides a mock function with given fields: ctx, filter
+func (_m *ContainerStatusUseCaseInterface) FindContainerStatuses(ctx context.Context, filter *dto.ContainerStatusFilter) ([]*dto.ContainerStatusDTO, error) {
+	ret := _m.Called(ctx, filter)
 
 	if len(ret) == 0 {
 		panic("no return value specified for FindContainerStatuses")
@@ -70,19 +72,49 @@ func (_m *ContainerStatusUseCaseInterface) FindContainerStatuses(filter *dto.Con
 
 	var r0 []*dto.ContainerStatusDTO
 	var r1 error
-	if rf, ok := ret.Get(0).(func(*dto.ContainerStatusFilter) ([]*dto.ContainerStatusDTO, error)); ok {
-		return rf(filter)
+	if rf, ok := ret.Get(0).(func(context.Context, *dto.ContainerStatusFilter) ([]*dto.ContainerStatusDTO, error)); ok {
+		return rf(ctx, filter)
 	}
-	if rf, ok := ret.Get(0).(func(*dto.ContainerStatusFilter) []*dto.ContainerStatusDTO); ok {
-		r0 = rf(filter)
+	if rf, ok := ret.Get(0).(func(context.Context, *dto.ContainerStatusFilter) []*dto.ContainerStatusDTO); ok {
+		r0 = rf(ctx, filter)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]*dto.ContainerStatusDTO)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(*dto.ContainerStatusFilter) error); ok {
-		r1 = rf(filter)
+	if rf, ok := ret.Get(1).(func(context.Context, *dto.ContainerStatusFilter) error); ok {
+		r1 = rf(ctx, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetContainerStatusesVersion provides a mock function with given fields: ctx, filter
+func (_m *ContainerStatusUseCaseInterface) GetContainerStatusesVersion(ctx context.Context, filter *dto.ContainerStatusFilter) (*dto.ContainerStatusListVersionDTO, error) {
+	ret := _m.Called(ctx, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetContainerStatusesVersion")
+	}
+
+	var r0 *dto.ContainerStatusListVersionDTO
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *dto.ContainerStatusFilter) (*dto.ContainerStatusListVersionDTO, error)); ok {
+		return rf(ctx, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *dto.ContainerStatusFilter) *dto.ContainerStatusListVersionDTO); ok {
+		r0 = rf(ctx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dto.ContainerStatusListVersionDTO)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *dto.ContainerStatusFilter) error); ok {
+		r1 = rf(ctx, filter)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -90,17 +122,17 @@ func (_m *ContainerStatusUseCaseInterface) FindContainerStatuses(filter *dto.Con
 	return r0, r1
 }
 
-// UpdateContainerStatus provides a mock function with given fields: containerID, statusDTO
-func (_m *ContainerStatusUseCaseInterface) UpdateContainerStatus(containerID string, statusDTO *dto.ContainerStatusDTO) error {
-	ret := _m.Called(containerID, statusDTO)
+// UpdateContainerStatus provides a mock function with given fields: ctx, containerID, statusDTO
+func (_m *ContainerStatusUseCaseInterface) UpdateContainerStatus(ctx context.Context, containerID string, statusDTO *dto.ContainerStatusDTO) error {
+	ret := _m.Called(ctx, containerID, statusDTO)
 
 	if len(ret) == 0 {
 		panic("no return value specified for UpdateContainerStatus")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, *dto.ContainerStatusDTO) error); ok {
-		r0 = rf(containerID, statusDTO)
+	if rf, ok := ret.Get(0).(func(context.Context, string, *dto.ContainerStatusDTO) error); ok {
+		r0 = rf(ctx, containerID, statusDTO)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -0,0 +1,49 @@
+// Code generated by mockery v2.47.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	dto "github.com/k6zma/DockerMonitoringApp/backend/internal/application/dto"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// HealthUseCaseInterface is an autogenerated mock type for the HealthUseCaseInterface type
+type HealthUseCaseInterface struct {
+	mock.Mock
+}
+
+// CheckReadiness provides a mock function with given fields: ctx
+func (_m *HealthUseCaseInterface) CheckReadiness(ctx context.Context) *dto.HealthReportDTO {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CheckReadiness")
+	}
+
+	var r0 *dto.HealthReportDTO
+	if rf, ok := ret.Get(0).(func(context.Context) *dto.HealthReportDTO); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dto.HealthReportDTO)
+		}
+	}
+
+	return r0
+}
+
+// NewHealthUseCaseInterface creates a new instance of HealthUseCaseInterface. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewHealthUseCaseInterface(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *HealthUseCaseInterface {
+	mock := &HealthUseCaseInterface{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
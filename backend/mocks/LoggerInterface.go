@@ -39,6 +39,14 @@ func (_m *LoggerInterface) Debugf(template string, args ...interface{}) {
 	_m.Called(_ca...)
 }
 
+// Debugw provides a mock function with given fields: msg, keysAndValues
+func (_m *LoggerInterface) Debugw(msg string, keysAndValues ...interface{}) {
+	var _ca []interface{}
+	_ca = append(_ca, msg)
+	_ca = append(_ca, keysAndValues...)
+	_m.Called(_ca...)
+}
+
 // Error provides a mock function with given fields: args
 func (_m *LoggerInterface) Error(args ...interface{}) {
 	var _ca []interface{}
@@ -54,6 +62,14 @@ func (_m *LoggerInterface) Errorf(template string, args ...interface{}) {
 	_m.Called(_ca...)
 }
 
+// Errorw provides a mock function with given fields: msg, keysAndValues
+func (_m *LoggerInterface) Errorw(msg string, keysAndValues ...interface{}) {
+	var _ca []interface{}
+	_ca = append(_ca, msg)
+	_ca = append(_ca, keysAndValues...)
+	_m.Called(_ca...)
+}
+
 // Fatal provides a mock function with given fields: args
 func (_m *LoggerInterface) Fatal(args ...interface{}) {
 	var _ca []interface{}
@@ -84,6 +100,14 @@ func (_m *LoggerInterface) Infof(template string, args ...interface{}) {
 	_m.Called(_ca...)
 }
 
+// Infow provides a mock function with given fields: msg, keysAndValues
+func (_m *LoggerInterface) Infow(msg string, keysAndValues ...interface{}) {
+	var _ca []interface{}
+	_ca = append(_ca, msg)
+	_ca = append(_ca, keysAndValues...)
+	_m.Called(_ca...)
+}
+
 // Warn provides a mock function with given fields: args
 func (_m *LoggerInterface) Warn(args ...interface{}) {
 	var _ca []interface{}
@@ -99,6 +123,14 @@ func (_m *LoggerInterface) Warnf(template string, args ...interface{}) {
 	_m.Called(_ca...)
 }
 
+// Warnw provides a mock function with given fields: msg, keysAndValues
+func (_m *LoggerInterface) Warnw(msg string, keysAndValues ...interface{}) {
+	var _ca []interface{}
+	_ca = append(_ca, msg)
+	_ca = append(_ca, keysAndValues...)
+	_m.Called(_ca...)
+}
+
 // NewLoggerInterface creates a new instance of LoggerInterface. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewLoggerInterface(t interface {
@@ -0,0 +1,76 @@
+// Code generated by mockery v2.47.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// RequestNonceRepository is an autogenerated mock type for the RequestNonceRepository type
+type RequestNonceRepository struct {
+	mock.Mock
+}
+
+// Claim provides a mock function with given fields: ctx, keyID, nonce, expiresAt, now
+func (_m *RequestNonceRepository) Claim(ctx context.Context, keyID string, nonce string, expiresAt time.Time, now time.Time) (bool, error) {
+	ret := _m.Called(ctx, keyID, nonce, expiresAt, now)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Claim")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, time.Time, time.Time) (bool, error)); ok {
+		return rf(ctx, keyID, nonce, expiresAt, now)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, time.Time, time.Time) bool); ok {
+		r0 = rf(ctx, keyID, nonce, expiresAt, now)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, time.Time, time.Time) error); ok {
+		r1 = rf(ctx, keyID, nonce, expiresAt, now)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteExpired provides a mock function with given fields: ctx, now
+func (_m *RequestNonceRepository) DeleteExpired(ctx context.Context, now time.Time) error {
+	ret := _m.Called(ctx, now)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteExpired")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) error); ok {
+		r0 = rf(ctx, now)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewRequestNonceRepository creates a new instance of RequestNonceRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewRequestNonceRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *RequestNonceRepository {
+	mock := &RequestNonceRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,135 @@
+// Code generated by mockery v2.47.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domain "github.com/k6zma/DockerMonitoringApp/backend/internal/domain"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// RoleAssignmentRepository is an autogenerated mock type for the RoleAssignmentRepository type
+type RoleAssignmentRepository struct {
+	mock.Mock
+}
+
+// Delete provides a mock function with given fields: ctx, subject
+func (_m *RoleAssignmentRepository) Delete(ctx context.Context, subject string) (bool, error) {
+	ret := _m.Called(ctx, subject)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Delete")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (bool, error)); ok {
+		return rf(ctx, subject)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) bool); ok {
+		r0 = rf(ctx, subject)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, subject)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindAll provides a mock function with given fields: ctx
+func (_m *RoleAssignmentRepository) FindAll(ctx context.Context) ([]*domain.RoleAssignment, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindAll")
+	}
+
+	var r0 []*domain.RoleAssignment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]*domain.RoleAssignment, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []*domain.RoleAssignment); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.RoleAssignment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindBySubject provides a mock function with given fields: ctx, subject
+func (_m *RoleAssignmentRepository) FindBySubject(ctx context.Context, subject string) (*domain.RoleAssignment, error) {
+	ret := _m.Called(ctx, subject)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindBySubject")
+	}
+
+	var r0 *domain.RoleAssignment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*domain.RoleAssignment, error)); ok {
+		return rf(ctx, subject)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *domain.RoleAssignment); ok {
+		r0 = rf(ctx, subject)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.RoleAssignment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, subject)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Upsert provides a mock function with given fields: ctx, assignment
+func (_m *RoleAssignmentRepository) Upsert(ctx context.Context, assignment *domain.RoleAssignment) error {
+	ret := _m.Called(ctx, assignment)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Upsert")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.RoleAssignment) error); ok {
+		r0 = rf(ctx, assignment)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewRoleAssignmentRepository creates a new instance of RoleAssignmentRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewRoleAssignmentRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *RoleAssignmentRepository {
+	mock := &RoleAssignmentRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
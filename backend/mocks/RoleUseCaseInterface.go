@@ -0,0 +1,127 @@
+// Code generated by mockery v2.47.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	dto "github.com/k6zma/DockerMonitoringApp/backend/internal/application/dto"
+	domain "github.com/k6zma/DockerMonitoringApp/backend/internal/domain"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// RoleUseCaseInterface is an autogenerated mock type for the RoleUseCaseInterface type
+type RoleUseCaseInterface struct {
+	mock.Mock
+}
+
+// AssignRole provides a mock function with given fields: ctx, subject, role
+func (_m *RoleUseCaseInterface) AssignRole(ctx context.Context, subject string, role string) (*dto.RoleAssignmentDTO, error) {
+	ret := _m.Called(ctx, subject, role)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AssignRole")
+	}
+
+	var r0 *dto.RoleAssignmentDTO
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*dto.RoleAssignmentDTO, error)); ok {
+		return rf(ctx, subject, role)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *dto.RoleAssignmentDTO); ok {
+		r0 = rf(ctx, subject, role)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dto.RoleAssignmentDTO)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, subject, role)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListRoleAssignments provides a mock function with given fields: ctx
+func (_m *RoleUseCaseInterface) ListRoleAssignments(ctx context.Context) ([]*dto.RoleAssignmentDTO, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListRoleAssignments")
+	}
+
+	var r0 []*dto.RoleAssignmentDTO
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]*dto.RoleAssignmentDTO, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []*dto.RoleAssignmentDTO); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*dto.RoleAssignmentDTO)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RemoveRoleAssignment provides a mock function with given fields: ctx, subject
+func (_m *RoleUseCaseInterface) RemoveRoleAssignment(ctx context.Context, subject string) error {
+	ret := _m.Called(ctx, subject)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RemoveRoleAssignment")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, subject)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ResolveRoles provides a mock function with given fields: ctx, principal
+func (_m *RoleUseCaseInterface) ResolveRoles(ctx context.Context, principal *domain.Principal) error {
+	ret := _m.Called(ctx, principal)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ResolveRoles")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Principal) error); ok {
+		r0 = rf(ctx, principal)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewRoleUseCaseInterface creates a new instance of RoleUseCaseInterface. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewRoleUseCaseInterface(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *RoleUseCaseInterface {
+	mock := &RoleUseCaseInterface{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
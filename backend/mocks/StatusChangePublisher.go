@@ -0,0 +1,34 @@
+// Code generated by mockery v2.47.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domain "github.com/k6zma/DockerMonitoringApp/backend/internal/domain"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// StatusChangePublisher is an autogenerated mock type for the StatusChangePublisher type
+type StatusChangePublisher struct {
+	mock.Mock
+}
+
+// Publish provides a mock function with given fields: ctx, change
+func (_m *StatusChangePublisher) Publish(ctx context.Context, change *domain.ContainerStatusChange) {
+	_m.Called(ctx, change)
+}
+
+// NewStatusChangePublisher creates a new instance of StatusChangePublisher. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewStatusChangePublisher(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *StatusChangePublisher {
+	mock := &StatusChangePublisher{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
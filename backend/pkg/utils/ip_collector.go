@@ -1,10 +1,27 @@
 package utils
 
-import "net/http"
+import (
+	"context"
+	"net"
+	"net/http"
+)
 
+type clientIPKey struct{}
+
+// ContextWithClientIP stores the IP address the request was made from.
+func ContextWithClientIP(ctx context.Context, clientIP string) context.Context {
+	return context.WithValue(ctx, clientIPKey{}, clientIP)
+}
+
+// GetClientIP returns the client IP stored in the request context, or else the
+// address of the connecting peer without the port.
 func GetClientIP(r *http.Request) string {
-	if forwarded := r.Header.Get("X-Forwarded-For"); forwarded != "" {
-		return forwarded
+	if clientIP, ok := r.Context().Value(clientIPKey{}).(string); ok {
+		return clientIP
+	}
+
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		return host
 	}
 
 	return r.RemoteAddr
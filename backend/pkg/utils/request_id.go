@@ -0,0 +1,47 @@
+package utils
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// RequestIDHeader carries the ID that correlates log lines of one request
+// across the pinger and the backend.
+const RequestIDHeader = "X-Request-ID"
+
+type requestIDKey struct{}
+
+func NewRequestID() string {
+	return uuid.NewString()
+}
+
+func ContextWithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+// RequestIDFromContext returns the request ID stored in ctx or an empty string.
+func RequestIDFromContext(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey{}).(string)
+	return requestID
+}
+
+// ContextLogger returns logger with the request ID from ctx and the given
+// key-value pairs attached as structured fields. Loggers other than *Logger,
+// such as test mocks, are returned unchanged.
+func ContextLogger(ctx context.Context, logger LoggerInterface, keysAndValues ...interface{}) LoggerInterface {
+	l, ok := logger.(*Logger)
+	if !ok {
+		return logger
+	}
+
+	if requestID := RequestIDFromContext(ctx); requestID != "" {
+		keysAndValues = append([]interface{}{"request_id", requestID}, keysAndValues...)
+	}
+
+	if len(keysAndValues) == 0 {
+		return logger
+	}
+
+	return l.With(keysAndValues...)
+}
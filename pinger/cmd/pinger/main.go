@@ -3,25 +3,44 @@ package main
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"github.com/k6zma/DockerMonitoringApp/pinger/internal/application/repositories"
 	"github.com/k6zma/DockerMonitoringApp/pinger/internal/application/usecases"
+	"github.com/k6zma/DockerMonitoringApp/pinger/internal/domain"
 	"github.com/k6zma/DockerMonitoringApp/pinger/internal/infrastructure/backend"
 	"github.com/k6zma/DockerMonitoringApp/pinger/internal/infrastructure/config"
 	"github.com/k6zma/DockerMonitoringApp/pinger/internal/infrastructure/docker"
 	"github.com/k6zma/DockerMonitoringApp/pinger/internal/infrastructure/flags"
+	"github.com/k6zma/DockerMonitoringApp/pinger/internal/infrastructure/httpserver"
+	"github.com/k6zma/DockerMonitoringApp/pinger/internal/infrastructure/icmp"
+	"github.com/k6zma/DockerMonitoringApp/pinger/internal/infrastructure/metrics"
+	"github.com/k6zma/DockerMonitoringApp/pinger/internal/infrastructure/outbox"
+	"github.com/k6zma/DockerMonitoringApp/pinger/internal/infrastructure/tlsreload"
+	"github.com/k6zma/DockerMonitoringApp/pinger/internal/infrastructure/tracing"
 	"github.com/k6zma/DockerMonitoringApp/pinger/pkg/utils"
 )
 
+const (
+	httpShutdownTimeout    = 5 * time.Second
+	tracingShutdownTimeout = 5 * time.Second
+)
+
 func main() {
 	flagsData, err := flags.ParseFlags()
 	if err != nil {
 		panic(fmt.Errorf("flags parsing failed: %w", err))
 	}
 
-	logger, err := utils.NewLogger(flagsData.LoggerLevel)
+	logger, err := utils.NewLogger(flagsData.LoggerLevel, flagsData.LoggerFormat)
 	if err != nil {
 		panic(fmt.Errorf("logger init failed: %w", err))
 	}
@@ -31,29 +50,120 @@ func main() {
 	if err != nil {
 		logger.Fatalf("Config error: %v", err)
 	}
-	logger.Infof("Config loaded: Backend - %+v, Ping - %+v, Docker - %+v", *cfg.Backend, *cfg.Ping, *cfg.Docker)
+	logger.Infof("Config loaded: Backend - %+v, Ping - %+v, Docker - %+v, Outbox - %+v, HTTP - %+v, Tracing - %+v",
+		*cfg.Backend, *cfg.Ping, *cfg.Docker, *cfg.Outbox, *cfg.HTTP, *cfg.Tracing)
+	logger.Infof("Backend transport: Retry - %+v, CircuitBreaker - %+v, TLS - %+v",
+		*cfg.Backend.Retry, *cfg.Backend.CircuitBreaker, *cfg.Backend.TLS)
+
+	shutdownTracing, err := tracing.Setup(context.Background(), cfg.Tracing)
+	if err != nil {
+		logger.Fatalf("Tracing init failed: %v", err)
+	}
+	defer func() {
+		shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), tracingShutdownTimeout)
+		defer shutdownCancel()
+
+		if err := shutdownTracing(shutdownCtx); err != nil {
+			logger.Errorf("Tracing shutdown failed: %v", err)
+		}
+	}()
+
+	pingMode, err := icmp.DetectMode(domain.PingMode(cfg.Ping.Mode), logger)
+	if err != nil {
+		logger.Fatalf("Ping self-check failed: %v", err)
+	}
+	logger.Infof("Ping self-check passed, using %s ping mode (requested: %s)", pingMode, cfg.Ping.Mode)
+
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+
+	recorder, err := metrics.NewPrometheusRecorder(registry)
+	if err != nil {
+		logger.Fatalf("Metrics init failed: %v", err)
+	}
+	recorder.SetPingMode(pingMode)
 
 	containerRepo, err := docker.NewDockerContainerRepo(cfg, logger)
 	if err != nil {
 		logger.Fatalf("Docker repository init failed: %v", err)
 	}
 
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	tlsReloader, err := tlsreload.NewReloader(ctx, cfg.Backend.TLS, logger)
+	if err != nil {
+		logger.Fatalf("Backend TLS init failed: %v", err)
+	}
+
+	var signingKeyID string
+	if cfg.Backend.AuthMode == "hmac" {
+		signingKeyID = cfg.Backend.KeyID
+	}
+
 	statusRepo := backend.NewBackendStatusRepo(
 		cfg.Backend.URL,
 		cfg.Backend.APIKey,
+		signingKeyID,
+		cfg.Backend.Timeout,
+		backend.RetryPolicy{
+			MaxAttempts:    cfg.Backend.Retry.MaxAttempts,
+			InitialBackoff: cfg.Backend.Retry.InitialBackoff,
+			MaxBackoff:     cfg.Backend.Retry.MaxBackoff,
+			MaxRetryAfter:  cfg.Backend.Retry.MaxRetryAfter,
+			Methods:        cfg.Backend.Retry.Methods,
+		},
+		backend.CircuitBreakerPolicy{
+			FailureThreshold: cfg.Backend.CircuitBreaker.FailureThreshold,
+			OpenTimeout:      cfg.Backend.CircuitBreaker.OpenTimeout,
+		},
+		backend.CompressionPolicy{
+			Encoding: cfg.Backend.Compression.Encoding,
+			MinSize:  cfg.Backend.Compression.MinSize,
+		},
+		tlsReloader.DialTLSContext,
 		logger,
 	)
 
+	var outboxRepo repositories.OutboxRepository
+	if cfg.Outbox.Enabled {
+		outboxRepo, err = outbox.NewFileOutbox(cfg.Outbox.Path, cfg.Outbox.MaxItems, logger)
+		if err != nil {
+			logger.Fatalf("Outbox init failed: %v", err)
+		}
+
+		if err := recorder.RegisterOutbox(outboxRepo); err != nil {
+			logger.Fatalf("Outbox metrics init failed: %v", err)
+		}
+	}
+
 	pinger := usecases.NewPingerUsecase(
 		containerRepo,
 		statusRepo,
+		outboxRepo,
 		cfg.Ping.PingInterval,
+		domain.ProbeSettings{
+			Count:          cfg.Ping.Count,
+			Timeout:        cfg.Ping.Timeout,
+			PacketInterval: cfg.Ping.PacketInterval,
+			Size:           cfg.Ping.Size,
+			TTL:            cfg.Ping.TTL,
+		},
+		domain.CycleSettings{
+			MaxConcurrency: cfg.Ping.MaxConcurrency,
+			OverlapPolicy:  domain.OverlapPolicy(cfg.Ping.OverlapPolicy),
+			Jitter:         cfg.Ping.Jitter,
+			ReplayBatch:    cfg.Outbox.ReplayBatch,
+			ReplayTimeout:  cfg.Outbox.ReplayTimeout,
+		},
+		pingMode,
+		recorder,
 		logger,
 	)
 
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
-
 	stop := make(chan os.Signal, 1)
 	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
 	go func() {
@@ -62,8 +172,38 @@ func main() {
 		cancel()
 	}()
 
+	var httpServer *httpserver.Server
+	if cfg.HTTP.Enabled {
+		healthHandler := httpserver.NewHealthHandler(
+			usecases.NewHealthUsecase(containerRepo, statusRepo, pinger, cfg.HTTP.MaxCycleAge, logger),
+			logger,
+		)
+
+		httpServer = httpserver.NewServer(cfg.HTTP.Address, logger)
+		httpServer.Handle("/metrics", promhttp.HandlerFor(registry, promhttp.HandlerOpts{}))
+		httpServer.Handle("/healthz", http.HandlerFunc(healthHandler.Liveness))
+		httpServer.Handle("/readyz", http.HandlerFunc(healthHandler.Readiness))
+
+		errCh := httpServer.Start()
+		go func() {
+			if err := <-errCh; err != nil {
+				logger.Errorf("HTTP server error: %v", err)
+				cancel()
+			}
+		}()
+	}
+
 	logger.Info("Starting pinger service")
 	if err := pinger.Run(ctx); err != nil {
 		logger.Fatalf("Pinger service failed: %v", err)
 	}
+
+	if httpServer != nil {
+		shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), httpShutdownTimeout)
+		defer shutdownCancel()
+
+		if err := httpServer.Shutdown(shutdownCtx); err != nil {
+			logger.Errorf("%v", err)
+		}
+	}
 }
@@ -8,4 +8,5 @@ import (
 
 type ContainerRepository interface {
 	GetContainers(ctx context.Context) ([]domain.ContainerInfo, error)
+	Ping(ctx context.Context) error
 }
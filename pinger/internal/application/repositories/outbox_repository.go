@@ -0,0 +1,16 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/k6zma/DockerMonitoringApp/pinger/internal/domain"
+)
+
+// DeliverFunc delivers a queued result to the backend.
+type DeliverFunc func(ctx context.Context, result *domain.PingResult) error
+
+type OutboxRepository interface {
+	Enqueue(result *domain.PingResult) error
+	Replay(ctx context.Context, limit int, deliver DeliverFunc) (int, error)
+	Stats() domain.OutboxStats
+}
@@ -2,13 +2,23 @@ package repositories
 
 import (
 	"context"
+	"errors"
 
 	"github.com/k6zma/DockerMonitoringApp/pinger/internal/domain"
 )
 
+// ErrStatusRejected is returned when the backend permanently rejects a status,
+// e.g. because it fails validation. Retrying such a request is pointless.
+var ErrStatusRejected = errors.New("status rejected by backend")
+
+// ErrStatusNotFound is returned when the backend has no status for a container
+// yet and it has to be created.
+var ErrStatusNotFound = errors.New("status not found in backend")
+
 type StatusRepository interface {
-	UpdateStatus(ctx context.Context, containerID string, pingTime int64, name, status string, success bool) error
-	CreateStatus(ctx context.Context, containerID, ip string, pingTime int64, name, status string) error
+	UpdateStatus(ctx context.Context, result *domain.PingResult) error
+	CreateStatus(ctx context.Context, result *domain.PingResult) error
 	DeleteStatus(ctx context.Context, containerID string) error
 	GetStatuses(ctx context.Context) ([]domain.PingResult, error)
+	Ping(ctx context.Context) error
 }
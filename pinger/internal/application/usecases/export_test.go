@@ -0,0 +1,8 @@
+package usecases
+
+import "github.com/k6zma/DockerMonitoringApp/pinger/internal/domain"
+
+// ProbeSettings exposes probeSettings to the tests.
+func (uc *PingerUsecase) ProbeSettings(container domain.ContainerInfo) domain.ProbeSettings {
+	return uc.probeSettings(container)
+}
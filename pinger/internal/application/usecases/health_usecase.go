@@ -0,0 +1,100 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/k6zma/DockerMonitoringApp/pinger/internal/application/repositories"
+	"github.com/k6zma/DockerMonitoringApp/pinger/internal/domain"
+	"github.com/k6zma/DockerMonitoringApp/pinger/pkg/utils"
+)
+
+const (
+	HealthCheckCycle           = "cycle"
+	HealthCheckSuccessfulCycle = "successful_cycle"
+	HealthCheckDocker          = "docker"
+	HealthCheckBackend         = "backend"
+)
+
+// HealthUsecase reports liveness and readiness of the pinger.
+type HealthUsecase struct {
+	containerRepo repositories.ContainerRepository
+	statusRepo    repositories.StatusRepository
+	pinger        *PingerUsecase
+	maxCycleAge   time.Duration
+	logger        utils.LoggerInterface
+}
+
+func NewHealthUsecase(
+	cr repositories.ContainerRepository,
+	sr repositories.StatusRepository,
+	pinger *PingerUsecase,
+	maxCycleAge time.Duration,
+	logger utils.LoggerInterface,
+) *HealthUsecase {
+	return &HealthUsecase{
+		containerRepo: cr,
+		statusRepo:    sr,
+		pinger:        pinger,
+		maxCycleAge:   maxCycleAge,
+		logger:        logger,
+	}
+}
+
+// Liveness fails when monitoring cycles stopped finishing, i.e. the process is
+// wedged and should be restarted. Dependency outages do not affect liveness.
+func (uc *HealthUsecase) Liveness() domain.HealthReport {
+	return uc.report(uc.checkCycleAge(HealthCheckCycle, uc.pinger.LastCycle()))
+}
+
+// Readiness checks the Docker daemon, the backend and that a monitoring cycle
+// succeeded recently.
+func (uc *HealthUsecase) Readiness(ctx context.Context) domain.HealthReport {
+	return uc.report(
+		uc.checkDependency(HealthCheckDocker, uc.containerRepo.Ping(ctx)),
+		uc.checkDependency(HealthCheckBackend, uc.statusRepo.Ping(ctx)),
+		uc.checkCycleAge(HealthCheckSuccessfulCycle, uc.pinger.LastSuccessfulCycle()),
+	)
+}
+
+func (uc *HealthUsecase) report(checks ...domain.HealthCheck) domain.HealthReport {
+	report := domain.HealthReport{
+		Healthy:  true,
+		PingMode: uc.pinger.PingMode(),
+		Checks:   checks,
+	}
+
+	for _, check := range checks {
+		if !check.Healthy {
+			report.Healthy = false
+			uc.logger.Warnf("Health check %s failed: %s", check.Name, check.Message)
+		}
+	}
+
+	return report
+}
+
+func (uc *HealthUsecase) checkDependency(name string, err error) domain.HealthCheck {
+	if err != nil {
+		return domain.HealthCheck{Name: name, Message: err.Error()}
+	}
+
+	return domain.HealthCheck{Name: name, Healthy: true, Message: "ok"}
+}
+
+func (uc *HealthUsecase) checkCycleAge(name string, finishedAt time.Time) domain.HealthCheck {
+	age := time.Since(finishedAt).Truncate(time.Millisecond)
+	if age > uc.maxCycleAge {
+		return domain.HealthCheck{
+			Name:    name,
+			Message: fmt.Sprintf("last cycle finished %s ago, limit is %s", age, uc.maxCycleAge),
+		}
+	}
+
+	return domain.HealthCheck{
+		Name:    name,
+		Healthy: true,
+		Message: fmt.Sprintf("last cycle finished %s ago", age),
+	}
+}
@@ -0,0 +1,21 @@
+package usecases
+
+import (
+	"time"
+
+	"github.com/k6zma/DockerMonitoringApp/pinger/internal/domain"
+)
+
+// MetricsRecorder receives measurements taken during monitoring cycles.
+type MetricsRecorder interface {
+	// ObserveProbe records a completed probe of a container.
+	ObserveProbe(result *domain.PingResult, duration time.Duration)
+	// ObserveProbeError records a probe that could not be executed.
+	ObserveProbeError(container domain.ContainerInfo, duration time.Duration)
+	ObserveCycle(duration time.Duration)
+	// RetainContainers drops per-container series of containers that are no
+	// longer probed.
+	RetainContainers(containerIDs map[string]bool)
+	DockerAPIError(operation string)
+	BackendDeliveryError(operation string)
+}
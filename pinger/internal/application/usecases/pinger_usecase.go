@@ -2,12 +2,17 @@ package usecases
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"hash/fnv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	probing "github.com/prometheus-community/pro-bing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 
 	"github.com/k6zma/DockerMonitoringApp/pinger/internal/application/repositories"
 	"github.com/k6zma/DockerMonitoringApp/pinger/internal/domain"
@@ -17,136 +22,332 @@ import (
 type PingerUsecase struct {
 	containerRepo repositories.ContainerRepository
 	statusRepo    repositories.StatusRepository
+	outbox        repositories.OutboxRepository
 	interval      time.Duration
+	probe         domain.ProbeSettings
+	cycle         domain.CycleSettings
+	pingMode      domain.PingMode
+	metrics       MetricsRecorder
 	logger        utils.LoggerInterface
+
+	cycleMu      sync.Mutex
+	cycleRunning bool
+	cycleQueued  bool
+	cycleWG      sync.WaitGroup
+
+	// lastCycle and lastSuccessfulCycle hold Unix nanoseconds. Both start at
+	// construction time so health checks allow for the first cycle to finish.
+	lastCycle           atomic.Int64
+	lastSuccessfulCycle atomic.Int64
 }
 
 func NewPingerUsecase(
 	cr repositories.ContainerRepository,
 	sr repositories.StatusRepository,
+	ob repositories.OutboxRepository,
 	inter time.Duration,
+	probe domain.ProbeSettings,
+	cycle domain.CycleSettings,
+	pingMode domain.PingMode,
+	metrics MetricsRecorder,
 	logger utils.LoggerInterface,
 ) *PingerUsecase {
-	return &PingerUsecase{
+	uc := &PingerUsecase{
 		containerRepo: cr,
 		statusRepo:    sr,
+		outbox:        ob,
 		interval:      inter,
+		probe:         probe,
+		cycle:         cycle,
+		pingMode:      pingMode,
+		metrics:       metrics,
 		logger:        logger,
 	}
+
+	now := time.Now().UnixNano()
+	uc.lastCycle.Store(now)
+	uc.lastSuccessfulCycle.Store(now)
+
+	return uc
+}
+
+// PingMode returns the ICMP mode selected by the startup self-check.
+func (uc *PingerUsecase) PingMode() domain.PingMode {
+	return uc.pingMode
+}
+
+// LastCycle returns when the last monitoring cycle finished, successfully or not.
+func (uc *PingerUsecase) LastCycle() time.Time {
+	return time.Unix(0, uc.lastCycle.Load())
+}
+
+// LastSuccessfulCycle returns when the last monitoring cycle finished without errors.
+func (uc *PingerUsecase) LastSuccessfulCycle() time.Time {
+	return time.Unix(0, uc.lastSuccessfulCycle.Load())
 }
 
 func (uc *PingerUsecase) Run(ctx context.Context) error {
-	uc.logger.Infof("Starting monitoring with interval %v", uc.interval)
+	uc.logger.Infof("Starting monitoring with interval %v in %s ping mode", uc.interval, uc.pingMode)
 
-	uc.logger.Debugf("Ticker interval: %v", uc.interval)
+	uc.logger.Debugf("Ticker interval: %v, cycle settings: %+v", uc.interval, uc.cycle)
 	ticker := time.NewTicker(uc.interval)
 	defer ticker.Stop()
 
 	for {
 		select {
 		case <-ctx.Done():
-			uc.logger.Info("Shutting down pinger service")
+			uc.logger.Info("Shutting down pinger service, waiting for the running cycle")
+			uc.cycleWG.Wait()
 			return nil
 		case <-ticker.C:
-			if err := uc.checkContainers(ctx); err != nil {
-				uc.logger.Errorf("Monitoring cycle failed: %v", err)
-			}
+			uc.scheduleCycle(ctx)
 		}
 	}
 }
 
+// scheduleCycle starts a monitoring cycle unless one is already running, in which
+// case the due cycle is skipped or queued according to the overlap policy.
+func (uc *PingerUsecase) scheduleCycle(ctx context.Context) {
+	uc.cycleMu.Lock()
+	defer uc.cycleMu.Unlock()
+
+	if uc.cycleRunning {
+		if uc.cycle.OverlapPolicy == domain.OverlapPolicyQueue {
+			uc.logger.Warn("Previous monitoring cycle is still running, queueing the next one")
+			uc.cycleQueued = true
+			return
+		}
+
+		uc.logger.Warn("Previous monitoring cycle is still running, skipping this one")
+		return
+	}
+
+	uc.cycleRunning = true
+	uc.cycleWG.Add(1)
+	go uc.runCycles(ctx)
+}
+
+// runCycles runs a monitoring cycle and then any cycle queued while it was running.
+func (uc *PingerUsecase) runCycles(ctx context.Context) {
+	defer uc.cycleWG.Done()
+
+	for {
+		uc.runCycle(ctx)
+
+		uc.cycleMu.Lock()
+		if !uc.cycleQueued || ctx.Err() != nil {
+			uc.cycleRunning = false
+			uc.cycleQueued = false
+			uc.cycleMu.Unlock()
+			return
+		}
+		uc.cycleQueued = false
+		uc.cycleMu.Unlock()
+	}
+}
+
+// runCycle runs a single monitoring cycle. The outbox replay has a budget of its
+// own, the probes that follow it are bounded by a deadline of one ping interval.
+func (uc *PingerUsecase) runCycle(ctx context.Context) {
+	// Every request the cycle sends to the backend carries the same request ID.
+	requestID := utils.NewRequestID()
+	ctx = utils.ContextWithRequestID(ctx, requestID)
+	logger := utils.ContextLogger(ctx, uc.logger)
+
+	ctx, span := tracer.Start(ctx, "PingerUsecase.runCycle",
+		trace.WithNewRoot(),
+		trace.WithAttributes(
+			attribute.String("pinger.ping_mode", string(uc.pingMode)),
+			attribute.String("request.id", requestID),
+		),
+	)
+
+	start := time.Now()
+	uc.replayOutbox(ctx)
+
+	cycleCtx, cancel := context.WithTimeout(ctx, uc.interval)
+	defer cancel()
+
+	err := uc.checkContainers(cycleCtx)
+	endSpan(span, err)
+	if err != nil {
+		logger.Errorw("Monitoring cycle failed", "error", err)
+	} else {
+		uc.lastSuccessfulCycle.Store(time.Now().UnixNano())
+	}
+	uc.lastCycle.Store(time.Now().UnixNano())
+
+	duration := time.Since(start)
+	uc.metrics.ObserveCycle(duration)
+	logger.Debugw("Monitoring cycle finished", "duration", duration)
+}
+
 func (uc *PingerUsecase) checkContainers(ctx context.Context) error {
+	logger := utils.ContextLogger(ctx, uc.logger)
+
 	containers, err := uc.containerRepo.GetContainers(ctx)
 	if err != nil {
+		uc.metrics.DockerAPIError("list_containers")
 		return fmt.Errorf("failed to get container info: %w", err)
 	}
+	trace.SpanFromContext(ctx).SetAttributes(attribute.Int("pinger.containers", len(containers)))
 
 	activeContainerIDs := make(map[string]bool)
+	probedContainerIDs := make(map[string]bool)
 	containerInfos := make([]string, 0, len(containers))
 	for _, container := range containers {
 		activeContainerIDs[container.ContainerID] = true
+		if container.IP != "" {
+			probedContainerIDs[container.ContainerID] = true
+		}
 		containerInfos = append(containerInfos,
 			fmt.Sprintf("%s (ID: %s, IP: %s) [%s]", container.Name, container.ContainerID, container.IP, container.Status))
 	}
-	uc.logger.Debugf("Discovered %d containers: %s", len(containers), strings.Join(containerInfos, ", "))
+	logger.Debugf("Discovered %d containers: %s", len(containers), strings.Join(containerInfos, ", "))
 
-	uc.logger.Debug("Pinging containers")
+	logger.Debug("Pinging containers")
 	var wg sync.WaitGroup
+	workers := make(chan struct{}, uc.cycle.MaxConcurrency)
 
 	for _, container := range containers {
 		wg.Add(1)
 		go func(container domain.ContainerInfo) {
 			defer wg.Done()
 
+			if !uc.acquireWorker(ctx, workers, container) {
+				return
+			}
+			defer func() { <-workers }()
+
+			containerLogger := utils.ContextLogger(ctx, uc.logger,
+				"container_id", container.ContainerID, "container_name", container.Name)
+
 			var result *domain.PingResult
 			if container.IP == "" {
-				uc.logger.Warnf("No IP for container %s (ID: %s), updating status as %s", container.Name, container.ContainerID, container.Status)
+				containerLogger.Warnw("No IP for container, updating status only", "status", container.Status)
 				result = &domain.PingResult{
-					ContainerID: container.ContainerID,
-					IP:          "",
-					Name:        container.Name,
-					Status:      container.Status,
-					Success:     false,
-					PingTime:    0,
-					LastPing:    time.Now().Format(time.RFC3339),
+					ContainerID:  container.ContainerID,
+					IP:           "",
+					Name:         container.Name,
+					Status:       container.Status,
+					Success:      false,
+					PingTime:     0,
+					LastPing:     time.Now().Format(time.RFC3339),
+					RestartCount: container.RestartCount,
+					Labels:       container.Labels,
+					CheckedAt:    time.Now(),
 				}
 			} else {
-				res, err := uc.ping(container)
+				res, err := uc.ping(ctx, container)
 				if err != nil {
-					uc.logger.Warnf("Ping failed for container %s (ID: %s, IP: %s) [%s]: %v",
-						container.Name, container.ContainerID, container.IP, container.Status, err)
+					containerLogger.Warnw("Ping failed", "ip", container.IP, "status", container.Status, "error", err)
 					res = &domain.PingResult{
-						ContainerID: container.ContainerID,
-						IP:          container.IP,
-						Name:        container.Name,
-						Status:      container.Status,
-						Success:     false,
-						PingTime:    0,
-						LastPing:    time.Now().Format(time.RFC3339),
+						ContainerID:  container.ContainerID,
+						IP:           container.IP,
+						Name:         container.Name,
+						Status:       container.Status,
+						Success:      false,
+						PingTime:     0,
+						LastPing:     time.Now().Format(time.RFC3339),
+						RestartCount: container.RestartCount,
+						Labels:       container.Labels,
+						CheckedAt:    time.Now(),
 					}
 				}
 				result = res
 			}
 
 			if err := uc.updateStatus(ctx, result); err != nil {
-				uc.logger.Errorf("Failed to update status for container %s (ID: %s, IP: %s) [%s]: %v",
-					container.Name, container.ContainerID, container.IP, container.Status, err)
+				containerLogger.Errorw("Failed to update status", "ip", container.IP, "status", container.Status, "error", err)
 			}
 		}(container)
 	}
 	wg.Wait()
+	uc.metrics.RetainContainers(probedContainerIDs)
 
 	if err := uc.cleanupStatuses(ctx, activeContainerIDs); err != nil {
-		uc.logger.Errorf("Cleanup statuses failed: %v", err)
+		logger.Errorf("Cleanup statuses failed: %v", err)
 		return fmt.Errorf("cleanup statuses failed: %w", err)
 	}
 
 	return nil
 }
 
-func (uc *PingerUsecase) ping(container domain.ContainerInfo) (*domain.PingResult, error) {
-	uc.logger.Debugf("Pinging container %s (ID: %s, IP: %s) [%s]",
+// acquireWorker waits for the container's jitter offset and then for a free worker
+// slot. It returns false if the cycle deadline expires first.
+func (uc *PingerUsecase) acquireWorker(ctx context.Context, workers chan struct{}, container domain.ContainerInfo) bool {
+	if delay := uc.jitterDelay(container.ContainerID); delay > 0 {
+		timer := time.NewTimer(delay)
+		defer timer.Stop()
+
+		select {
+		case <-ctx.Done():
+			utils.ContextLogger(ctx, uc.logger, "container_id", container.ContainerID).
+				Warnf("Cycle deadline reached before probing container %s (ID: %s)", container.Name, container.ContainerID)
+			return false
+		case <-timer.C:
+		}
+	}
+
+	select {
+	case <-ctx.Done():
+		utils.ContextLogger(ctx, uc.logger, "container_id", container.ContainerID).
+			Warnf("Cycle deadline reached before probing container %s (ID: %s)", container.Name, container.ContainerID)
+		return false
+	case workers <- struct{}{}:
+		return true
+	}
+}
+
+// jitterDelay spreads probes over the jitter window. The offset is derived from the
+// container ID so every container keeps a stable position within the cycle.
+func (uc *PingerUsecase) jitterDelay(containerID string) time.Duration {
+	if uc.cycle.Jitter <= 0 {
+		return 0
+	}
+
+	hash := fnv.New64a()
+	_, _ = hash.Write([]byte(containerID))
+
+	return time.Duration(hash.Sum64() % uint64(uc.cycle.Jitter))
+}
+
+func (uc *PingerUsecase) ping(ctx context.Context, container domain.ContainerInfo) (_ *domain.PingResult, err error) {
+	ctx, span := tracer.Start(ctx, "PingerUsecase.ping", containerAttributes(container))
+	defer func() { endSpan(span, err) }()
+
+	logger := utils.ContextLogger(ctx, uc.logger, "container_id", container.ContainerID, "container_name", container.Name)
+
+	logger.Debugf("Pinging container %s (ID: %s, IP: %s) [%s]",
 		container.Name, container.ContainerID, container.IP, container.Status)
 
 	pinger, err := probing.NewPinger(container.IP)
 	if err != nil {
-		uc.logger.Errorf("Ping init failed for container %s (ID: %s, IP: %s) [%s]: %v",
+		logger.Errorf("Ping init failed for container %s (ID: %s, IP: %s) [%s]: %v",
 			container.Name, container.ContainerID, container.IP, container.Status, err)
 		return nil, fmt.Errorf("ping init failed: %w", err)
 	}
 
-	pinger.Count = 100
-	pinger.Timeout = 2 * time.Second
-	pinger.SetPrivileged(true)
+	settings := uc.probeSettings(container)
+	logger.Debugf("Probe settings for container %s (ID: %s): %+v", container.Name, container.ContainerID, settings)
 
-	if err := pinger.Run(); err != nil {
-		uc.logger.Errorf("Ping execution failed for container %s (ID: %s, IP: %s) [%s]: %v",
+	pinger.Count = settings.Count
+	pinger.Timeout = settings.Timeout
+	pinger.Interval = settings.PacketInterval
+	pinger.Size = settings.Size
+	pinger.TTL = settings.TTL
+	pinger.SetPrivileged(uc.pingMode == domain.PingModePrivileged)
+
+	start := time.Now()
+	if err := pinger.RunWithContext(ctx); err != nil {
+		uc.metrics.ObserveProbeError(container, time.Since(start))
+		logger.Errorf("Ping execution failed for container %s (ID: %s, IP: %s) [%s]: %v",
 			container.Name, container.ContainerID, container.IP, container.Status, err)
 		return nil, fmt.Errorf("ping execution failed: %w", err)
 	}
 
 	stats := pinger.Statistics()
-	uc.logger.Debugf("Ping stats for container %s (ID: %s, IP: %s) [%s]: %+v",
+	logger.Debugf("Ping stats for container %s (ID: %s, IP: %s) [%s]: %+v",
 		container.Name, container.ContainerID, container.IP, container.Status, stats)
 
 	var pingTime int64 = -1
@@ -154,27 +355,83 @@ func (uc *PingerUsecase) ping(container domain.ContainerInfo) (*domain.PingResul
 		pingTime = stats.AvgRtt.Microseconds()
 	}
 
-	uc.logger.Debugf("Ping time for container %s (ID: %s, IP: %s) [%s]: %.2f ms",
-		container.Name, container.ContainerID, container.IP, container.Status, pingTime)
-
-	return &domain.PingResult{
-		ContainerID: container.ContainerID,
-		IP:          container.IP,
-		Name:        container.Name,
-		Status:      container.Status,
-		Success:     stats.PacketsRecv > 0,
-		PingTime:    pingTime,
-	}, nil
+	result := &domain.PingResult{
+		ContainerID:  container.ContainerID,
+		IP:           container.IP,
+		Name:         container.Name,
+		Status:       container.Status,
+		Success:      stats.PacketsRecv > 0,
+		PingTime:     pingTime,
+		PacketLoss:   stats.PacketLoss / 100,
+		RestartCount: container.RestartCount,
+		Labels:       container.Labels,
+		CheckedAt:    time.Now(),
+	}
+	duration := time.Since(start)
+	uc.metrics.ObserveProbe(result, duration)
+	logger.Debugw("Probe finished",
+		"ip", container.IP,
+		"status", container.Status,
+		"success", result.Success,
+		"ping_time_us", pingTime,
+		"packet_loss", result.PacketLoss,
+		"duration", duration,
+	)
+	span.SetAttributes(
+		attribute.Bool("ping.success", result.Success),
+		attribute.Int("ping.packets_sent", stats.PacketsSent),
+		attribute.Int("ping.packets_received", stats.PacketsRecv),
+		attribute.Float64("ping.packet_loss", result.PacketLoss),
+		attribute.Int64("ping.rtt_us", pingTime),
+	)
+
+	return result, nil
 }
 
+// updateStatus delivers the result to the backend. While older results are waiting
+// in the outbox, or when delivery fails transiently, the result is queued instead
+// so that the backend receives results in the order they were measured.
 func (uc *PingerUsecase) updateStatus(ctx context.Context, result *domain.PingResult) error {
-	if err := uc.statusRepo.UpdateStatus(ctx, result.ContainerID, result.PingTime, result.Name, result.Status, result.Success); err != nil {
-		uc.logger.Warnf("Update failed for container %s (ID: %s, IP: %s) [%s], trying to create: %v",
+	if uc.outbox != nil && uc.outbox.Stats().Depth > 0 {
+		return uc.enqueue(ctx, result)
+	}
+
+	err := uc.deliver(ctx, result)
+	if err == nil || uc.outbox == nil || errors.Is(err, repositories.ErrStatusRejected) {
+		return err
+	}
+
+	if queueErr := uc.enqueue(ctx, result); queueErr != nil {
+		return errors.Join(err, queueErr)
+	}
+
+	return nil
+}
+
+func (uc *PingerUsecase) deliver(ctx context.Context, result *domain.PingResult) (err error) {
+	ctx, span := tracer.Start(ctx, "PingerUsecase.deliver", trace.WithAttributes(
+		attribute.String("container.id", result.ContainerID),
+		attribute.String("container.name", result.Name),
+	))
+	defer func() { endSpan(span, err) }()
+
+	logger := utils.ContextLogger(ctx, uc.logger, "container_id", result.ContainerID, "container_name", result.Name)
+
+	err = uc.statusRepo.UpdateStatus(ctx, result)
+	if err != nil && !errors.Is(err, repositories.ErrStatusNotFound) {
+		uc.metrics.BackendDeliveryError("update")
+		logger.Errorw("Update status failed", "ip", result.IP, "status", result.Status, "error", err)
+		return fmt.Errorf("update status failed for container %s (ID: %s, IP: %s) [%s]: %w",
 			result.Name, result.ContainerID, result.IP, result.Status, err)
+	}
 
-		if err := uc.statusRepo.CreateStatus(ctx, result.ContainerID, result.IP, result.PingTime, result.Name, result.Status); err != nil {
-			uc.logger.Errorf("Create status failed for container %s (ID: %s, IP: %s) [%s]: %v",
-				result.Name, result.ContainerID, result.IP, result.Status, err)
+	if err != nil {
+		logger.Infow("No status in backend, creating it", "ip", result.IP, "status", result.Status)
+
+		err = uc.statusRepo.CreateStatus(ctx, result)
+		if err != nil {
+			uc.metrics.BackendDeliveryError("create")
+			logger.Errorw("Create status failed", "ip", result.IP, "status", result.Status, "error", err)
 			return fmt.Errorf("create status failed for container %s (ID: %s, IP: %s) [%s]: %w",
 				result.Name, result.ContainerID, result.IP, result.Status, err)
 		}
@@ -183,31 +440,81 @@ func (uc *PingerUsecase) updateStatus(ctx context.Context, result *domain.PingRe
 	return nil
 }
 
-func (uc *PingerUsecase) cleanupStatuses(ctx context.Context, activeContainerIDs map[string]bool) error {
-	uc.logger.Debug("Cleaning up statuses")
+func (uc *PingerUsecase) enqueue(ctx context.Context, result *domain.PingResult) error {
+	logger := utils.ContextLogger(ctx, uc.logger, "container_id", result.ContainerID, "container_name", result.Name)
+
+	if err := uc.outbox.Enqueue(result); err != nil {
+		logger.Errorw("Failed to queue result in outbox", "error", err)
+		return fmt.Errorf("outbox enqueue failed: %w", err)
+	}
+
+	return nil
+}
+
+// replayOutbox redelivers up to a batch of queued results within the replay
+// timeout before the new cycle produces fresh ones.
+func (uc *PingerUsecase) replayOutbox(ctx context.Context) {
+	if uc.outbox == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, uc.cycle.ReplayTimeout)
+	defer cancel()
+
+	ctx, span := tracer.Start(ctx, "PingerUsecase.replayOutbox")
+	delivered, err := uc.outbox.Replay(ctx, uc.cycle.ReplayBatch, uc.deliver)
+	stats := uc.outbox.Stats()
+	span.SetAttributes(
+		attribute.Int("outbox.delivered", delivered),
+		attribute.Int("outbox.depth", stats.Depth),
+	)
+	endSpan(span, err)
+
+	logger := utils.ContextLogger(ctx, uc.logger)
+
+	if err != nil {
+		logger.Warnf("Outbox replay incomplete, delivered %d results: %v", delivered, err)
+	} else if delivered > 0 {
+		logger.Infof("Outbox replay delivered %d results", delivered)
+	}
+
+	if stats.Depth > 0 || stats.Dropped > 0 {
+		logger.Infof("Outbox depth: %d, dropped results: %d", stats.Depth, stats.Dropped)
+	}
+}
+
+func (uc *PingerUsecase) cleanupStatuses(ctx context.Context, activeContainerIDs map[string]bool) (err error) {
+	ctx, span := tracer.Start(ctx, "PingerUsecase.cleanupStatuses")
+	defer func() { endSpan(span, err) }()
+
+	logger := utils.ContextLogger(ctx, uc.logger)
+
+	logger.Debug("Cleaning up statuses")
 	statuses, err := uc.statusRepo.GetStatuses(ctx)
 	if err != nil {
-		uc.logger.Errorf("Failed to get statuses: %v", err)
+		uc.metrics.BackendDeliveryError("list")
+		logger.Errorf("Failed to get statuses: %v", err)
 		return fmt.Errorf("failed to get statuses: %w", err)
 	}
 
 	for _, status := range statuses {
-		uc.logger.Debugf("Status: %+v", status)
+		logger.Debugf("Status: %+v", status)
 	}
 
 	for _, status := range statuses {
 		if status.ContainerID == "" {
-			uc.logger.Debugf("Skipping deletion for container %s with empty container_id", status.Name)
+			logger.Debugf("Skipping deletion for container %s with empty container_id", status.Name)
 			continue
 		}
 
 		if !activeContainerIDs[status.ContainerID] {
-			uc.logger.Debugf("Container with container_id %s not found among active containers. Deleting its record.", status.ContainerID)
+			logger.Debugf("Container with container_id %s not found among active containers. Deleting its record.", status.ContainerID)
 			if err := uc.statusRepo.DeleteStatus(ctx, status.ContainerID); err != nil {
-				uc.logger.Errorf("Failed to delete status for container_id %s: %v", status.ContainerID, err)
+				uc.metrics.BackendDeliveryError("delete")
+				logger.Errorf("Failed to delete status for container_id %s: %v", status.ContainerID, err)
 				return fmt.Errorf("failed to delete status for container_id %s: %w", status.ContainerID, err)
 			} else {
-				uc.logger.Debugf("Successfully deleted status for container %s with container_id %s", status.Name, status.ContainerID)
+				logger.Debugf("Successfully deleted status for container %s with container_id %s", status.Name, status.ContainerID)
 			}
 		}
 	}
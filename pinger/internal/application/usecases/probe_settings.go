@@ -0,0 +1,113 @@
+package usecases
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/k6zma/DockerMonitoringApp/pinger/internal/domain"
+)
+
+// Container labels that override the global probe settings, e.g.
+// `monitoring.ping.count=10` or `monitoring.ping.timeout=500ms`.
+const (
+	labelPingCount          = "monitoring.ping.count"
+	labelPingTimeout        = "monitoring.ping.timeout"
+	labelPingPacketInterval = "monitoring.ping.packet_interval"
+	labelPingSize           = "monitoring.ping.size"
+	labelPingTTL            = "monitoring.ping.ttl"
+)
+
+const (
+	minPacketSize = 24
+	maxPacketSize = 65507
+	maxTTL        = 255
+)
+
+// probeSettings returns the global probe settings with the container label
+// overrides applied. Invalid overrides are logged and ignored, and so are all of
+// them when together they no longer fit inside the monitoring cycle.
+func (uc *PingerUsecase) probeSettings(container domain.ContainerInfo) domain.ProbeSettings {
+	settings := uc.probe
+
+	if value, ok := container.Labels[labelPingCount]; ok {
+		if err := parseIntLabel(value, 1, 0, &settings.Count); err != nil {
+			uc.logInvalidLabel(container, labelPingCount, value, err)
+		}
+	}
+
+	if value, ok := container.Labels[labelPingTimeout]; ok {
+		if err := parseDurationLabel(value, uc.interval-uc.cycle.Jitter, &settings.Timeout); err != nil {
+			uc.logInvalidLabel(container, labelPingTimeout, value, err)
+		}
+	}
+
+	if value, ok := container.Labels[labelPingPacketInterval]; ok {
+		if err := parseDurationLabel(value, uc.interval, &settings.PacketInterval); err != nil {
+			uc.logInvalidLabel(container, labelPingPacketInterval, value, err)
+		}
+	}
+
+	if value, ok := container.Labels[labelPingSize]; ok {
+		if err := parseIntLabel(value, minPacketSize, maxPacketSize, &settings.Size); err != nil {
+			uc.logInvalidLabel(container, labelPingSize, value, err)
+		}
+	}
+
+	if value, ok := container.Labels[labelPingTTL]; ok {
+		if err := parseIntLabel(value, 1, maxTTL, &settings.TTL); err != nil {
+			uc.logInvalidLabel(container, labelPingTTL, value, err)
+		}
+	}
+
+	if settings != uc.probe {
+		if err := settings.Fits(uc.interval, uc.cycle.Jitter); err != nil {
+			uc.logger.Warnf("Ignoring probe labels on container %s (ID: %s): %v",
+				container.Name, container.ContainerID, err)
+			return uc.probe
+		}
+	}
+
+	return settings
+}
+
+func (uc *PingerUsecase) logInvalidLabel(container domain.ContainerInfo, label, value string, err error) {
+	uc.logger.Warnf("Ignoring label %s=%q on container %s (ID: %s): %v",
+		label, value, container.Name, container.ContainerID, err)
+}
+
+// parseIntLabel parses value into target if it lies within [minValue, maxValue].
+// A zero maxValue means there is no upper bound.
+func parseIntLabel(value string, minValue, maxValue int, target *int) error {
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		return fmt.Errorf("not an integer: %w", err)
+	}
+
+	if parsed < minValue || (maxValue > 0 && parsed > maxValue) {
+		return fmt.Errorf("value out of range")
+	}
+
+	*target = parsed
+	return nil
+}
+
+// parseDurationLabel parses value into target if it is positive and shorter
+// than limit, so the probe fits inside the monitoring cycle.
+func parseDurationLabel(value string, limit time.Duration, target *time.Duration) error {
+	parsed, err := time.ParseDuration(value)
+	if err != nil {
+		return fmt.Errorf("not a duration: %w", err)
+	}
+
+	if parsed <= 0 {
+		return fmt.Errorf("duration must be positive")
+	}
+
+	if parsed >= limit {
+		return fmt.Errorf("duration must be shorter than %s", limit)
+	}
+
+	*target = parsed
+	return nil
+}
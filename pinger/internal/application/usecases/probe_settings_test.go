@@ -0,0 +1,136 @@
+package usecases_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	"github.com/k6zma/DockerMonitoringApp/pinger/internal/application/usecases"
+	"github.com/k6zma/DockerMonitoringApp/pinger/internal/domain"
+	"github.com/k6zma/DockerMonitoringApp/pinger/pkg/utils"
+)
+
+const (
+	labelPingCount          = "monitoring.ping.count"
+	labelPingTimeout        = "monitoring.ping.timeout"
+	labelPingPacketInterval = "monitoring.ping.packet_interval"
+	labelPingSize           = "monitoring.ping.size"
+	labelPingTTL            = "monitoring.ping.ttl"
+)
+
+var globalProbe = domain.ProbeSettings{
+	Count:          3,
+	Timeout:        2 * time.Second,
+	PacketInterval: 500 * time.Millisecond,
+	Size:           24,
+	TTL:            64,
+}
+
+func newProbeUsecase() (*usecases.PingerUsecase, *observer.ObservedLogs) {
+	core, logs := observer.New(zapcore.WarnLevel)
+	logger := &utils.Logger{SugaredLogger: zap.New(core).Sugar()}
+
+	uc := usecases.NewPingerUsecase(nil, nil, nil, 5*time.Second, globalProbe,
+		domain.CycleSettings{Jitter: time.Second}, domain.PingModeUnprivileged, nil, logger)
+
+	return uc, logs
+}
+
+func TestProbeSettings_Labels(t *testing.T) {
+	tests := []struct {
+		name     string
+		labels   map[string]string
+		expected func(*domain.ProbeSettings)
+		warnings int
+	}{
+		{
+			name:     "no labels",
+			expected: func(*domain.ProbeSettings) {},
+		},
+		{
+			name: "all overridden",
+			labels: map[string]string{
+				labelPingCount:          "5",
+				labelPingTimeout:        "3s",
+				labelPingPacketInterval: "200ms",
+				labelPingSize:           "1472",
+				labelPingTTL:            "8",
+			},
+			expected: func(s *domain.ProbeSettings) {
+				s.Count, s.Timeout, s.PacketInterval, s.Size, s.TTL = 5, 3*time.Second, 200*time.Millisecond, 1472, 8
+			},
+		},
+		{
+			name:     "not a number",
+			labels:   map[string]string{labelPingCount: "many", labelPingTTL: "1.5"},
+			expected: func(*domain.ProbeSettings) {},
+			warnings: 2,
+		},
+		{
+			name:     "not a duration",
+			labels:   map[string]string{labelPingTimeout: "3", labelPingPacketInterval: "soon"},
+			expected: func(*domain.ProbeSettings) {},
+			warnings: 2,
+		},
+		{
+			name: "out of range",
+			labels: map[string]string{
+				labelPingCount:          "0",
+				labelPingPacketInterval: "-1s",
+				labelPingSize:           "23",
+				labelPingTTL:            "256",
+			},
+			expected: func(*domain.ProbeSettings) {},
+			warnings: 4,
+		},
+		{
+			name:     "invalid label ignored, valid one applied",
+			labels:   map[string]string{labelPingSize: "65508", labelPingTTL: "255"},
+			expected: func(s *domain.ProbeSettings) { s.TTL = 255 },
+			warnings: 1,
+		},
+		{
+			name:     "timeout does not leave room for jitter",
+			labels:   map[string]string{labelPingTimeout: "4500ms"},
+			expected: func(*domain.ProbeSettings) {},
+			warnings: 1,
+		},
+		{
+			name:     "packet interval longer than ping interval",
+			labels:   map[string]string{labelPingPacketInterval: "1m"},
+			expected: func(*domain.ProbeSettings) {},
+			warnings: 1,
+		},
+		{
+			name:     "packets do not fit inside timeout",
+			labels:   map[string]string{labelPingCount: "10", labelPingTTL: "8"},
+			expected: func(*domain.ProbeSettings) {},
+			warnings: 1,
+		},
+		{
+			name:   "packets fit inside overridden timeout",
+			labels: map[string]string{labelPingCount: "10", labelPingPacketInterval: "300ms", labelPingTimeout: "3s"},
+			expected: func(s *domain.ProbeSettings) {
+				s.Count, s.PacketInterval, s.Timeout = 10, 300*time.Millisecond, 3*time.Second
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc, logs := newProbeUsecase()
+
+			expected := globalProbe
+			tt.expected(&expected)
+
+			settings := uc.ProbeSettings(domain.ContainerInfo{ContainerID: "abc123", Name: "web", Labels: tt.labels})
+
+			assert.Equal(t, expected, settings)
+			assert.Equal(t, tt.warnings, logs.Len())
+		})
+	}
+}
@@ -0,0 +1,30 @@
+package usecases
+
+import (
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/k6zma/DockerMonitoringApp/pinger/internal/domain"
+)
+
+var tracer = otel.Tracer("github.com/k6zma/DockerMonitoringApp/pinger/internal/application/usecases")
+
+func containerAttributes(container domain.ContainerInfo) trace.SpanStartOption {
+	return trace.WithAttributes(
+		attribute.String("container.id", container.ContainerID),
+		attribute.String("container.name", container.Name),
+		attribute.String("container.ip", container.IP),
+		attribute.String("container.status", container.Status),
+	)
+}
+
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	span.End()
+}
@@ -0,0 +1,24 @@
+package domain
+
+import "time"
+
+// OverlapPolicy defines what happens when a monitoring cycle is due while the
+// previous one is still running.
+type OverlapPolicy string
+
+const (
+	// OverlapPolicySkip drops the due cycle.
+	OverlapPolicySkip OverlapPolicy = "skip"
+	// OverlapPolicyQueue runs one more cycle right after the current one finishes.
+	OverlapPolicyQueue OverlapPolicy = "queue"
+)
+
+// CycleSettings controls how probes are scheduled within a monitoring cycle and
+// how much of the outbox a cycle replays before probing.
+type CycleSettings struct {
+	MaxConcurrency int
+	OverlapPolicy  OverlapPolicy
+	Jitter         time.Duration
+	ReplayBatch    int
+	ReplayTimeout  time.Duration
+}
@@ -0,0 +1,13 @@
+package domain
+
+type HealthCheck struct {
+	Name    string
+	Healthy bool
+	Message string
+}
+
+type HealthReport struct {
+	Healthy  bool
+	PingMode PingMode
+	Checks   []HealthCheck
+}
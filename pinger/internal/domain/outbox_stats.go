@@ -0,0 +1,7 @@
+package domain
+
+// OutboxStats describes the state of the local delivery outbox.
+type OutboxStats struct {
+	Depth   int
+	Dropped uint64
+}
@@ -0,0 +1,13 @@
+package domain
+
+// PingMode is the ICMP socket mode used to probe containers.
+type PingMode string
+
+const (
+	// PingModeAuto detects the mode at startup, preferring raw sockets.
+	PingModeAuto PingMode = "auto"
+	// PingModePrivileged uses raw ICMP sockets and requires CAP_NET_RAW or root.
+	PingModePrivileged PingMode = "privileged"
+	// PingModeUnprivileged uses UDP ICMP sockets allowed by net.ipv4.ping_group_range.
+	PingModeUnprivileged PingMode = "unprivileged"
+)
@@ -1,18 +1,26 @@
 package domain
 
+import "time"
+
 type PingResult struct {
-	ContainerID string `json:"container_id"`
-	IP          string `json:"ip_address"`
-	Name        string `json:"name"`
-	Status      string `json:"status"`
-	Success     bool   `json:"success"`
-	PingTime    int64  `json:"ping_time"`
-	LastPing    string `json:"last_successful_ping"`
+	ContainerID  string            `json:"container_id"`
+	IP           string            `json:"ip_address"`
+	Name         string            `json:"name"`
+	Status       string            `json:"status"`
+	Success      bool              `json:"success"`
+	PingTime     int64             `json:"ping_time"`
+	PacketLoss   float64           `json:"packet_loss"`
+	LastPing     string            `json:"last_successful_ping"`
+	RestartCount int               `json:"restart_count"`
+	Labels       map[string]string `json:"labels,omitempty"`
+	CheckedAt    time.Time         `json:"checked_at"`
 }
 
 type ContainerInfo struct {
-	ContainerID string
-	IP          string
-	Name        string
-	Status      string
+	ContainerID  string
+	IP           string
+	Name         string
+	Status       string
+	RestartCount int
+	Labels       map[string]string
 }
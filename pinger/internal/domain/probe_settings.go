@@ -0,0 +1,30 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// ProbeSettings holds the parameters of a single ICMP probe of a container.
+type ProbeSettings struct {
+	Count          int
+	Timeout        time.Duration
+	PacketInterval time.Duration
+	Size           int
+	TTL            int
+}
+
+// Fits reports whether a probe with these settings can send all its packets
+// before it times out, and finishes within a monitoring cycle of interval even
+// when started jitter late.
+func (s ProbeSettings) Fits(interval, jitter time.Duration) error {
+	if time.Duration(s.Count)*s.PacketInterval > s.Timeout {
+		return fmt.Errorf("%d packets %s apart do not fit inside timeout %s", s.Count, s.PacketInterval, s.Timeout)
+	}
+
+	if s.Timeout+jitter >= interval {
+		return fmt.Errorf("timeout %s plus jitter %s must be shorter than ping interval %s", s.Timeout, jitter, interval)
+	}
+
+	return nil
+}
@@ -0,0 +1,94 @@
+package backend
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/klauspost/compress/gzip"
+	"github.com/klauspost/compress/zstd"
+)
+
+type CompressionPolicy struct {
+	// Encoding is the content coding of request bodies: gzip, zstd or none.
+	Encoding string
+	// MinSize is the smallest body that is compressed.
+	MinSize int
+}
+
+// zstdEncoder compresses whole bodies, which it can do concurrently.
+var zstdEncoder, _ = zstd.NewWriter(nil)
+
+// compressingTransport compresses request bodies of at least MinSize bytes. It
+// wraps the retries, so every attempt, and the signature of each, covers the
+// same compressed body.
+type compressingTransport struct {
+	next   http.RoundTripper
+	policy CompressionPolicy
+}
+
+func newCompressingTransport(next http.RoundTripper, policy CompressionPolicy) *compressingTransport {
+	return &compressingTransport{
+		next:   next,
+		policy: policy,
+	}
+}
+
+func (t *compressingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body == nil || req.Body == http.NoBody || req.Header.Get("Content-Encoding") != "" {
+		return t.next.RoundTrip(req)
+	}
+
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("request body read failed: %w", err)
+	}
+
+	if len(body) < t.policy.MinSize {
+		return t.next.RoundTrip(withBody(req, body))
+	}
+
+	compressed, err := compress(t.policy.Encoding, body)
+	if err != nil {
+		return nil, fmt.Errorf("request body compression failed: %w", err)
+	}
+
+	compressedReq := withBody(req, compressed)
+	compressedReq.Header.Set("Content-Encoding", t.policy.Encoding)
+
+	return t.next.RoundTrip(compressedReq)
+}
+
+// withBody clones req with body, which can be read again for retries.
+func withBody(req *http.Request, body []byte) *http.Request {
+	clone := req.Clone(req.Context())
+	clone.Body = io.NopCloser(bytes.NewReader(body))
+	clone.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(body)), nil
+	}
+	clone.ContentLength = int64(len(body))
+
+	return clone
+}
+
+func compress(encoding string, body []byte) ([]byte, error) {
+	switch encoding {
+	case "zstd":
+		return zstdEncoder.EncodeAll(body, nil), nil
+	case "gzip":
+		var buf bytes.Buffer
+		writer := gzip.NewWriter(&buf)
+		if _, err := writer.Write(body); err != nil {
+			return nil, err
+		}
+		if err := writer.Close(); err != nil {
+			return nil, err
+		}
+
+		return buf.Bytes(), nil
+	default:
+		return nil, fmt.Errorf("unsupported encoding %q", encoding)
+	}
+}
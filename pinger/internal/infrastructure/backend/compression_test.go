@@ -0,0 +1,189 @@
+package backend_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/klauspost/compress/gzip"
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/k6zma/DockerMonitoringApp/pinger/internal/domain"
+	"github.com/k6zma/DockerMonitoringApp/pinger/internal/infrastructure/backend"
+)
+
+// defaultMinSize is the default of backend.compression.min_size.
+const defaultMinSize = 256
+
+func decompress(t *testing.T, encoding string, body []byte) string {
+	t.Helper()
+
+	switch encoding {
+	case "gzip":
+		reader, err := gzip.NewReader(bytes.NewReader(body))
+		require.NoError(t, err)
+		data, err := io.ReadAll(reader)
+		require.NoError(t, err)
+
+		return string(data)
+	case "zstd":
+		decoder, err := zstd.NewReader(nil)
+		require.NoError(t, err)
+		defer decoder.Close()
+		data, err := decoder.DecodeAll(body, nil)
+		require.NoError(t, err)
+
+		return string(data)
+	default:
+		return string(body)
+	}
+}
+
+func TestCompressingTransport(t *testing.T) {
+	large := `{"status":"running","labels":{"note":"` + strings.Repeat("abc", 100) + `"}}`
+
+	tests := []struct {
+		name             string
+		encoding         string
+		body             string
+		contentEncoding  string
+		expectedEncoding string
+	}{
+		{name: "gzip", encoding: "gzip", body: large, expectedEncoding: "gzip"},
+		{name: "zstd", encoding: "zstd", body: large, expectedEncoding: "zstd"},
+		{name: "below min size", encoding: "gzip", body: `{"status":"running"}`},
+		{name: "without body", encoding: "gzip"},
+		{name: "already encoded", encoding: "zstd", body: large, contentEncoding: "gzip", expectedEncoding: "gzip"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var contentEncoding string
+			var contentLength int64
+			stub := &stubTransport{respond: func(req *http.Request, _ int) (*http.Response, error) {
+				contentEncoding = req.Header.Get("Content-Encoding")
+				contentLength = req.ContentLength
+				return response(http.StatusNoContent), nil
+			}}
+
+			transport := backend.NewCompressingTransport(stub, backend.CompressionPolicy{Encoding: tt.encoding, MinSize: defaultMinSize})
+
+			req := newRequest(t, context.Background(), http.MethodPatch, tt.body)
+			if tt.contentEncoding != "" {
+				req.Header.Set("Content-Encoding", tt.contentEncoding)
+			}
+
+			resp, err := transport.RoundTrip(req)
+			require.NoError(t, err)
+			resp.Body.Close()
+
+			assert.Equal(t, tt.expectedEncoding, contentEncoding)
+			require.Len(t, stub.bodies, 1)
+			sent := stub.bodies[0]
+			if tt.contentEncoding == "" {
+				assert.Equal(t, tt.body, decompress(t, contentEncoding, []byte(sent)))
+			} else {
+				assert.Equal(t, tt.body, sent)
+			}
+			if tt.body != "" {
+				assert.Equal(t, int64(len(sent)), contentLength)
+			}
+			if tt.expectedEncoding != "" && tt.contentEncoding == "" {
+				assert.Less(t, len(sent), len(tt.body))
+			}
+		})
+	}
+}
+
+func TestCompressingTransport_RetriesSendTheCompressedBody(t *testing.T) {
+	stub := &stubTransport{respond: func(_ *http.Request, attempt int) (*http.Response, error) {
+		if attempt == 1 {
+			return response(http.StatusServiceUnavailable), nil
+		}
+		return response(http.StatusNoContent), nil
+	}}
+
+	// Compression wraps the retries, so the body is compressed once.
+	transport := backend.NewCompressingTransport(
+		backend.NewResilientTransport(stub, 0, testRetry, backend.CircuitBreakerPolicy{}, nopLogger),
+		backend.CompressionPolicy{Encoding: "gzip", MinSize: 0},
+	)
+
+	resp, err := transport.RoundTrip(newRequest(t, context.Background(), http.MethodPatch, `{"status":"running"}`))
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	require.Len(t, stub.bodies, 2)
+	assert.Equal(t, stub.bodies[0], stub.bodies[1])
+	assert.Equal(t, `{"status":"running"}`, decompress(t, "gzip", []byte(stub.bodies[1])))
+}
+
+// TestBackendStatusRepo_CompressesStatusReports shows what the default
+// min_size compresses: reports of containers started by Docker Compose, which
+// carry its labels, but not those of bare containers, which gain little.
+func TestBackendStatusRepo_CompressesStatusReports(t *testing.T) {
+	type received struct {
+		encoding string
+		body     []byte
+	}
+
+	var mu sync.Mutex
+	var requests []received
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+
+		mu.Lock()
+		requests = append(requests, received{encoding: r.Header.Get("Content-Encoding"), body: body})
+		mu.Unlock()
+
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	repo := backend.NewBackendStatusRepo(server.URL, "pinger-key", "", time.Second, testRetry, backend.CircuitBreakerPolicy{},
+		backend.CompressionPolicy{Encoding: "gzip", MinSize: defaultMinSize}, nil, nopLogger)
+
+	result := &domain.PingResult{
+		ContainerID:  "4f2a9c1e7b3d",
+		Name:         "monitoring-backend-1",
+		Status:       "running",
+		Success:      true,
+		PingTime:     12,
+		RestartCount: 0,
+		CheckedAt:    time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC),
+		Labels: map[string]string{
+			"com.docker.compose.project":              "monitoring",
+			"com.docker.compose.service":              "backend",
+			"com.docker.compose.container-number":     "1",
+			"com.docker.compose.oneoff":               "False",
+			"com.docker.compose.version":              "2.29.1",
+			"com.docker.compose.project.working_dir":  "/srv/monitoring",
+			"com.docker.compose.project.config_files": "/srv/monitoring/docker-compose.yml",
+		},
+	}
+	require.NoError(t, repo.UpdateStatus(context.Background(), result))
+
+	bare := *result
+	bare.Labels = nil
+	require.NoError(t, repo.UpdateStatus(context.Background(), &bare))
+
+	require.Len(t, requests, 2)
+
+	// The report shrinks to about half, though the former default min_size of
+	// 1024 bytes would not have compressed it.
+	compose := decompress(t, requests[0].encoding, requests[0].body)
+	assert.Equal(t, "gzip", requests[0].encoding)
+	assert.Less(t, len(compose), 1024)
+	assert.Less(t, len(requests[0].body), len(compose)*2/3)
+
+	assert.Empty(t, requests[1].encoding)
+	assert.Less(t, len(requests[1].body), defaultMinSize)
+}
@@ -0,0 +1,62 @@
+package backend
+
+import (
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/k6zma/DockerMonitoringApp/pinger/pkg/utils"
+)
+
+// Exposes the transports and the circuit breaker to the tests.
+
+func NewCompressingTransport(next http.RoundTripper, policy CompressionPolicy) http.RoundTripper {
+	return newCompressingTransport(next, policy)
+}
+
+// NewSigningTransport returns a signing transport that takes the time from now
+// and the nonces from random.
+func NewSigningTransport(next http.RoundTripper, apiKey, keyID string, now func() time.Time, random io.Reader) http.RoundTripper {
+	t := newSigningTransport(next, apiKey, keyID)
+	t.now = now
+	t.random = random
+
+	return t
+}
+
+type BreakerState = breakerState
+
+const (
+	BreakerClosed   = breakerClosed
+	BreakerOpen     = breakerOpen
+	BreakerHalfOpen = breakerHalfOpen
+)
+
+var ParseRetryAfter = parseRetryAfter
+
+type ResilientTransport = resilientTransport
+
+func NewResilientTransport(
+	next http.RoundTripper,
+	attemptTimeout time.Duration,
+	retry RetryPolicy,
+	breaker CircuitBreakerPolicy,
+	logger utils.LoggerInterface,
+) *ResilientTransport {
+	return newResilientTransport(next, attemptTimeout, retry, breaker, logger)
+}
+
+func (t *resilientTransport) BreakerState() BreakerState {
+	t.breaker.mu.Lock()
+	defer t.breaker.mu.Unlock()
+
+	return t.breaker.state
+}
+
+func (t *resilientTransport) BreakerAllow() error {
+	return t.breaker.allow()
+}
+
+func (t *resilientTransport) BreakerRecord(failed bool) {
+	t.breaker.record(failed)
+}
@@ -0,0 +1,78 @@
+package backend
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// signatureScheme is the Authorization scheme of signed requests.
+const signatureScheme = "HMAC-SHA256"
+
+// signingSecretLabel is the message the signing secret is derived from, the
+// same as on the backend.
+const signingSecretLabel = "DockerMonitoringApp request signing"
+
+// signingTransport signs every attempt with the API key instead of sending the
+// key itself. The signature is an HMAC-SHA256, keyed with the signing secret
+// derived from the key, over the method, the path with the query, the Unix
+// timestamp, a random nonce and the SHA-256 digest of the body. Each attempt
+// gets its own timestamp and nonce, so the backend can refuse replayed
+// requests.
+type signingTransport struct {
+	next   http.RoundTripper
+	keyID  string
+	secret []byte
+	now    func() time.Time
+	random io.Reader
+}
+
+func newSigningTransport(next http.RoundTripper, apiKey, keyID string) *signingTransport {
+	mac := hmac.New(sha256.New, []byte(apiKey))
+	mac.Write([]byte(signingSecretLabel))
+
+	return &signingTransport{
+		next:   next,
+		keyID:  keyID,
+		secret: mac.Sum(nil),
+		now:    time.Now,
+		random: rand.Reader,
+	}
+}
+
+func (t *signingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil && req.Body != http.NoBody {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("request body read failed: %w", err)
+		}
+	}
+
+	nonce := make([]byte, 16)
+	if _, err := io.ReadFull(t.random, nonce); err != nil {
+		return nil, fmt.Errorf("nonce generation failed: %w", err)
+	}
+
+	timestamp := t.now().Unix()
+	bodyHash := sha256.Sum256(body)
+
+	mac := hmac.New(sha256.New, t.secret)
+	fmt.Fprintf(mac, "%s\n%s\n%d\n%x\n%x", req.Method, req.URL.RequestURI(), timestamp, nonce, bodyHash)
+
+	signed := req.Clone(req.Context())
+	signed.Body = io.NopCloser(bytes.NewReader(body))
+	signed.Header.Del("X-Api-Key")
+	signed.Header.Set("Authorization", fmt.Sprintf("%s KeyId=%s, Timestamp=%d, Nonce=%x, Signature=%s",
+		signatureScheme, t.keyID, timestamp, nonce, hex.EncodeToString(mac.Sum(nil))))
+
+	return t.next.RoundTrip(signed)
+}
@@ -0,0 +1,114 @@
+package backend_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/k6zma/DockerMonitoringApp/pinger/internal/infrastructure/backend"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// signedRequest is a request as it leaves the signing transport. The backend
+// tests verify testdata/signed_request.json with their own code, so both sides
+// agree on the signature scheme.
+type signedRequest struct {
+	APIKey        string `json:"api_key"`
+	KeyID         string `json:"key_id"`
+	Method        string `json:"method"`
+	Target        string `json:"target"`
+	Body          string `json:"body"`
+	Authorization string `json:"authorization"`
+}
+
+func TestSigningTransport_Golden(t *testing.T) {
+	golden := filepath.Join("testdata", "signed_request.json")
+
+	var sent *http.Request
+	next := &stubTransport{respond: func(req *http.Request, _ int) (*http.Response, error) {
+		sent = req
+		return response(http.StatusNoContent), nil
+	}}
+
+	signedAt := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	transport := backend.NewSigningTransport(next, "dm_pinger-signing-key", "dm_pinger-s",
+		func() time.Time { return signedAt }, bytes.NewReader(bytes.Repeat([]byte{0x5a}, 16)))
+
+	const body = `{"name":"web","status":"running"}`
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodPatch,
+		"http://backend/api/v1/container_status/abc123?force=true", strings.NewReader(body))
+	require.NoError(t, err)
+	req.Header.Set("X-Api-Key", "dm_pinger-signing-key")
+
+	resp, err := transport.RoundTrip(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	assert.Empty(t, sent.Header.Get("X-Api-Key"))
+
+	actual := signedRequest{
+		APIKey:        "dm_pinger-signing-key",
+		KeyID:         "dm_pinger-s",
+		Method:        sent.Method,
+		Target:        sent.URL.RequestURI(),
+		Body:          next.bodies[0],
+		Authorization: sent.Header.Get("Authorization"),
+	}
+
+	if *update {
+		data, err := json.MarshalIndent(actual, "", "  ")
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(golden, append(data, '\n'), 0o600))
+	}
+
+	data, err := os.ReadFile(golden)
+	require.NoError(t, err)
+
+	var expected signedRequest
+	require.NoError(t, json.Unmarshal(data, &expected))
+	assert.Equal(t, expected, actual)
+}
+
+func TestSigningTransport_FreshNonceAndTimestampPerAttempt(t *testing.T) {
+	var authorizations []string
+	next := &stubTransport{respond: func(req *http.Request, _ int) (*http.Response, error) {
+		authorizations = append(authorizations, req.Header.Get("Authorization"))
+		return response(http.StatusNoContent), nil
+	}}
+
+	transport := backend.NewSigningTransport(next, "dm_pinger-signing-key", "dm_pinger-s", time.Now, nonceSource())
+
+	for range 2 {
+		req := newRequest(t, context.Background(), http.MethodPatch, `{"status":"running"}`)
+		resp, err := transport.RoundTrip(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+	}
+
+	require.Len(t, authorizations, 2)
+	assert.True(t, strings.HasPrefix(authorizations[0], "HMAC-SHA256 KeyId=dm_pinger-s, "))
+	assert.NotEqual(t, authorizations[0], authorizations[1])
+	assert.Equal(t, []string{`{"status":"running"}`, `{"status":"running"}`}, next.bodies)
+}
+
+// nonceSource returns distinct nonces of 16 bytes.
+func nonceSource() io.Reader {
+	var nonces []byte
+	for i := range 4 {
+		nonces = append(nonces, bytes.Repeat([]byte{byte(i)}, 16)...)
+	}
+
+	return bytes.NewReader(nonces)
+}
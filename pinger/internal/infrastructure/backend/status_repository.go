@@ -5,9 +5,12 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"net"
 	"net/http"
 	"time"
 
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+
 	"github.com/k6zma/DockerMonitoringApp/pinger/internal/application/repositories"
 	"github.com/k6zma/DockerMonitoringApp/pinger/internal/domain"
 	"github.com/k6zma/DockerMonitoringApp/pinger/pkg/utils"
@@ -20,30 +23,60 @@ type BackendStatusRepo struct {
 	logger     utils.LoggerInterface
 }
 
+// NewBackendStatusRepo creates a backend client. Each attempt is bounded by
+// timeout; retries and the circuit breaker are configured by the given policies.
+// Request bodies are compressed as the compression policy says, responses are
+// decompressed by the transport. With a signingKeyID, requests are signed with
+// apiKey instead of carrying it. HTTPS connections are opened with dialTLS when
+// it is set, so the CA bundle and client certificate can change at runtime.
 func NewBackendStatusRepo(
-	baseURL, apiKey string,
+	baseURL, apiKey, signingKeyID string,
+	timeout time.Duration,
+	retry RetryPolicy,
+	breaker CircuitBreakerPolicy,
+	compression CompressionPolicy,
+	dialTLS func(ctx context.Context, network, addr string) (net.Conn, error),
 	logger utils.LoggerInterface,
 ) repositories.StatusRepository {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialTLSContext = dialTLS
+
+	// Signing happens below the retries, so every attempt is signed anew.
+	var next http.RoundTripper = transport
+	if signingKeyID != "" {
+		next = newSigningTransport(transport, apiKey, signingKeyID)
+	}
+
+	// Every attempt gets its own client span and traceparent header.
+	var client http.RoundTripper = newResilientTransport(otelhttp.NewTransport(next), timeout, retry, breaker, logger)
+	if compression.Encoding != "none" {
+		client = newCompressingTransport(client, compression)
+	}
+
 	return &BackendStatusRepo{
 		baseURL:    baseURL,
 		apiKey:     apiKey,
-		httpClient: &http.Client{Timeout: 10 * time.Second},
+		httpClient: &http.Client{Transport: client},
 		logger:     logger,
 	}
 }
 
-func (r *BackendStatusRepo) UpdateStatus(ctx context.Context, containerID string, pingTime int64, name, status string, success bool) error {
-	url := fmt.Sprintf("%s/api/v1/container_status/%s", r.baseURL, containerID)
-	r.logger.Debugf("Sending PATCH request to %s with data: name=%s, status=%s, ping_time=%d", url, name, status, pingTime)
+func (r *BackendStatusRepo) UpdateStatus(ctx context.Context, result *domain.PingResult) error {
+	url := fmt.Sprintf("%s/api/v1/container_status/%s", r.baseURL, result.ContainerID)
+	r.logger.Debugf("Sending PATCH request to %s with data: name=%s, status=%s, ping_time=%d, restart_count=%d",
+		url, result.Name, result.Status, result.PingTime, result.RestartCount)
 
 	payload := map[string]interface{}{
-		"ping_time": pingTime,
-		"name":      name,
-		"status":    status,
+		"ping_time":     result.PingTime,
+		"name":          result.Name,
+		"status":        result.Status,
+		"restart_count": result.RestartCount,
+		"labels":        result.Labels,
+		"checked_at":    result.CheckedAt.Format(time.RFC3339Nano),
 	}
 
-	if success {
-		payload["last_successful_ping"] = time.Now().Format(time.RFC3339)
+	if result.Success {
+		payload["last_successful_ping"] = result.CheckedAt.Format(time.RFC3339)
 	}
 
 	jsonBody, err := json.Marshal(payload)
@@ -59,6 +92,7 @@ func (r *BackendStatusRepo) UpdateStatus(ctx context.Context, containerID string
 	}
 	req.Header.Set("X-Api-Key", r.apiKey)
 	req.Header.Set("Content-Type", "application/json")
+	setRequestID(req)
 
 	resp, err := r.httpClient.Do(req)
 	if err != nil {
@@ -69,24 +103,28 @@ func (r *BackendStatusRepo) UpdateStatus(ctx context.Context, containerID string
 
 	if resp.StatusCode >= 400 {
 		r.logger.Errorf("API returned error status: %s", resp.Status)
-		return fmt.Errorf("api returned error status: %s", resp.Status)
+		return statusError(resp)
 	}
 
-	r.logger.Debugf("Successfully updated status for container ID %s", containerID)
+	r.logger.Debugf("Successfully updated status for container ID %s", result.ContainerID)
 	return nil
 }
 
-func (r *BackendStatusRepo) CreateStatus(ctx context.Context, containerID, ip string, pingTime int64, name, status string) error {
+func (r *BackendStatusRepo) CreateStatus(ctx context.Context, result *domain.PingResult) error {
 	url := fmt.Sprintf("%s/api/v1/container_status", r.baseURL)
-	r.logger.Debugf("Sending POST request to %s with data: container_id=%s, name=%s, status=%s, ping_time=%d", url, containerID, name, status, pingTime)
+	r.logger.Debugf("Sending POST request to %s with data: container_id=%s, name=%s, status=%s, ping_time=%d, restart_count=%d",
+		url, result.ContainerID, result.Name, result.Status, result.PingTime, result.RestartCount)
 
 	payload := map[string]interface{}{
-		"container_id":         containerID,
-		"ip_address":           ip,
-		"ping_time":            pingTime,
-		"last_successful_ping": time.Now().Format(time.RFC3339),
-		"name":                 name,
-		"status":               status,
+		"container_id":         result.ContainerID,
+		"ip_address":           result.IP,
+		"ping_time":            result.PingTime,
+		"last_successful_ping": result.CheckedAt.Format(time.RFC3339),
+		"name":                 result.Name,
+		"status":               result.Status,
+		"restart_count":        result.RestartCount,
+		"labels":               result.Labels,
+		"checked_at":           result.CheckedAt.Format(time.RFC3339Nano),
 	}
 
 	jsonBody, err := json.Marshal(payload)
@@ -102,6 +140,7 @@ func (r *BackendStatusRepo) CreateStatus(ctx context.Context, containerID, ip st
 	}
 	req.Header.Set("X-Api-Key", r.apiKey)
 	req.Header.Set("Content-Type", "application/json")
+	setRequestID(req)
 
 	resp, err := r.httpClient.Do(req)
 	if err != nil {
@@ -111,10 +150,10 @@ func (r *BackendStatusRepo) CreateStatus(ctx context.Context, containerID, ip st
 
 	if resp.StatusCode >= 400 {
 		r.logger.Errorf("API returned error status: %s", resp.Status)
-		return fmt.Errorf("api returned error status: %s", resp.Status)
+		return statusError(resp)
 	}
 
-	r.logger.Infof("Successfully created status for container ID %s", containerID)
+	r.logger.Infof("Successfully created status for container ID %s", result.ContainerID)
 	return nil
 }
 
@@ -129,6 +168,7 @@ func (r *BackendStatusRepo) GetStatuses(ctx context.Context) ([]domain.PingResul
 	}
 	req.Header.Set("X-Api-Key", r.apiKey)
 	req.Header.Set("Content-Type", "application/json")
+	setRequestID(req)
 
 	resp, err := r.httpClient.Do(req)
 	if err != nil {
@@ -162,6 +202,7 @@ func (r *BackendStatusRepo) DeleteStatus(ctx context.Context, containerID string
 	}
 	req.Header.Set("X-Api-Key", r.apiKey)
 	req.Header.Set("Content-Type", "application/json")
+	setRequestID(req)
 
 	resp, err := r.httpClient.Do(req)
 	if err != nil {
@@ -178,3 +219,52 @@ func (r *BackendStatusRepo) DeleteStatus(ctx context.Context, containerID string
 	r.logger.Debugf("Successfully deleted status for container ID %s", containerID)
 	return nil
 }
+
+// Ping checks that the backend is reachable using its liveness endpoint.
+func (r *BackendStatusRepo) Ping(ctx context.Context) error {
+	url := fmt.Sprintf("%s/healthz", r.baseURL)
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, http.NoBody)
+	if err != nil {
+		return fmt.Errorf("request creation failed: %w", err)
+	}
+
+	resp, err := r.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("request execution failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("api returned error status: %s", resp.Status)
+	}
+
+	return nil
+}
+
+// setRequestID forwards the request ID of the monitoring cycle so that backend
+// log lines can be correlated with the cycle that caused them.
+func setRequestID(req *http.Request) {
+	if requestID := utils.RequestIDFromContext(req.Context()); requestID != "" {
+		req.Header.Set(utils.RequestIDHeader, requestID)
+	}
+}
+
+// statusError converts an error response into an error. A missing status is
+// marked with repositories.ErrStatusNotFound. Responses rejecting the request
+// content will never succeed on retry and are marked with
+// repositories.ErrStatusRejected.
+func statusError(resp *http.Response) error {
+	switch resp.StatusCode {
+	case http.StatusNotFound:
+		return fmt.Errorf("%w: api returned error status: %s", repositories.ErrStatusNotFound, resp.Status)
+	case http.StatusBadRequest,
+		http.StatusConflict,
+		http.StatusRequestEntityTooLarge,
+		http.StatusUnsupportedMediaType,
+		http.StatusUnprocessableEntity:
+		return fmt.Errorf("%w: api returned error status: %s", repositories.ErrStatusRejected, resp.Status)
+	default:
+		return fmt.Errorf("api returned error status: %s", resp.Status)
+	}
+}
@@ -0,0 +1,341 @@
+package backend
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/k6zma/DockerMonitoringApp/pinger/pkg/utils"
+)
+
+// ErrCircuitOpen is returned without contacting the backend while the circuit
+// breaker is open.
+var ErrCircuitOpen = errors.New("backend circuit breaker is open")
+
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts including the first one.
+	MaxAttempts    int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	// MaxRetryAfter is the longest delay requested by a Retry-After header
+	// that is waited for. A longer one ends the retries.
+	MaxRetryAfter time.Duration
+	// Methods lists the HTTP methods that are safe to retry.
+	Methods []string
+}
+
+type CircuitBreakerPolicy struct {
+	// FailureThreshold is the number of consecutive failed attempts that opens
+	// the circuit. Zero disables the breaker.
+	FailureThreshold int
+	// OpenTimeout is how long the circuit stays open before a single trial
+	// request is let through.
+	OpenTimeout time.Duration
+}
+
+// resilientTransport retries idempotent requests on transient failures with
+// exponential backoff and full jitter, honours Retry-After and guards the
+// backend with a circuit breaker. Every attempt has its own timeout.
+type resilientTransport struct {
+	next           http.RoundTripper
+	attemptTimeout time.Duration
+	retry          RetryPolicy
+	retryMethods   map[string]struct{}
+	breaker        *circuitBreaker
+	logger         utils.LoggerInterface
+}
+
+func newResilientTransport(
+	next http.RoundTripper,
+	attemptTimeout time.Duration,
+	retry RetryPolicy,
+	breaker CircuitBreakerPolicy,
+	logger utils.LoggerInterface,
+) *resilientTransport {
+	methods := make(map[string]struct{}, len(retry.Methods))
+	for _, method := range retry.Methods {
+		methods[strings.ToUpper(method)] = struct{}{}
+	}
+
+	if retry.MaxAttempts < 1 {
+		retry.MaxAttempts = 1
+	}
+
+	return &resilientTransport{
+		next:           next,
+		attemptTimeout: attemptTimeout,
+		retry:          retry,
+		retryMethods:   methods,
+		breaker:        newCircuitBreaker(breaker, logger),
+		logger:         logger,
+	}
+}
+
+func (t *resilientTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	attempts := 1
+	if _, ok := t.retryMethods[req.Method]; ok && (req.Body == nil || req.Body == http.NoBody || req.GetBody != nil) {
+		attempts = t.retry.MaxAttempts
+	}
+
+	for attempt := 1; ; attempt++ {
+		if err := t.breaker.allow(); err != nil {
+			return nil, err
+		}
+
+		resp, err := t.attempt(req, attempt)
+		if err != nil && req.Context().Err() != nil {
+			// The caller gave up, which says nothing about the backend. Only
+			// the timeout of an attempt itself counts as a failure.
+			t.breaker.release()
+			return nil, err
+		}
+		t.breaker.record(isBackendFailure(resp, err))
+
+		if attempt >= attempts || !isRetryable(req.Context(), resp, err) {
+			return resp, err
+		}
+
+		delay := t.backoff(attempt)
+		if resp != nil {
+			if retryAfter, ok := parseRetryAfter(resp.Header.Get("Retry-After")); ok {
+				if retryAfter > t.retry.MaxRetryAfter {
+					t.logger.Warnf("Backend %s %s returned %s and asked to retry in %s, giving up (attempt %d/%d)",
+						req.Method, req.URL.Path, resp.Status, retryAfter.Round(time.Second), attempt, attempts)
+					return resp, nil
+				}
+				delay = retryAfter
+			}
+			drainAndClose(resp.Body)
+			t.logger.Warnf("Backend %s %s returned %s, retrying in %s (attempt %d/%d)",
+				req.Method, req.URL.Path, resp.Status, delay, attempt, attempts)
+		} else {
+			t.logger.Warnf("Backend %s %s failed: %v, retrying in %s (attempt %d/%d)",
+				req.Method, req.URL.Path, err, delay, attempt, attempts)
+		}
+
+		if deadline, ok := req.Context().Deadline(); ok && time.Until(deadline) < delay {
+			if err == nil {
+				err = fmt.Errorf("backend returned %s", resp.Status)
+			}
+			return nil, fmt.Errorf("retry budget exhausted after %d attempts: %w", attempt, err)
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+	}
+}
+
+func (t *resilientTransport) attempt(req *http.Request, attempt int) (*http.Response, error) {
+	attemptReq := req
+	if attempt > 1 && req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, fmt.Errorf("request body rewind failed: %w", err)
+		}
+		attemptReq = req.Clone(req.Context())
+		attemptReq.Body = body
+	}
+
+	if t.attemptTimeout <= 0 {
+		return t.next.RoundTrip(attemptReq)
+	}
+
+	ctx, cancel := context.WithTimeout(attemptReq.Context(), t.attemptTimeout)
+	resp, err := t.next.RoundTrip(attemptReq.WithContext(ctx))
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	// The attempt context must outlive RoundTrip until the body is consumed.
+	resp.Body = &cancelOnCloseBody{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
+
+// backoff returns a random delay in [0, min(MaxBackoff, InitialBackoff*2^(attempt-1))].
+func (t *resilientTransport) backoff(attempt int) time.Duration {
+	ceiling := t.retry.InitialBackoff << (attempt - 1)
+	if ceiling <= 0 || ceiling > t.retry.MaxBackoff {
+		ceiling = t.retry.MaxBackoff
+	}
+	if ceiling <= 0 {
+		return 0
+	}
+
+	return rand.N(ceiling + 1) //nolint:gosec // backoff jitter does not need a secure source
+}
+
+func isRetryable(ctx context.Context, resp *http.Response, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	if err != nil {
+		return true
+	}
+
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
+// isBackendFailure reports whether an attempt indicates an unhealthy backend.
+// Client errors such as 404 or 422 mean the backend is up and answering.
+func isBackendFailure(resp *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+
+	return resp.StatusCode >= http.StatusInternalServerError
+}
+
+// parseRetryAfter accepts both forms allowed by RFC 9110: delay-seconds and an
+// HTTP-date.
+func parseRetryAfter(value string) (time.Duration, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		return max(time.Until(date), 0), true
+	}
+
+	return 0, false
+}
+
+func drainAndClose(body io.ReadCloser) {
+	_, _ = io.Copy(io.Discard, io.LimitReader(body, 4096))
+	_ = body.Close()
+}
+
+type cancelOnCloseBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelOnCloseBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
+
+type breakerState int
+
+const (
+	breakerClosed breakerState = iota
+	breakerOpen
+	breakerHalfOpen
+)
+
+// circuitBreaker opens after FailureThreshold consecutive failed attempts and
+// rejects requests until OpenTimeout elapses. Then a single trial request is let
+// through: success closes the circuit, failure opens it again.
+type circuitBreaker struct {
+	policy CircuitBreakerPolicy
+	logger utils.LoggerInterface
+
+	mu       sync.Mutex
+	state    breakerState
+	failures int
+	openedAt time.Time
+	trial    bool
+}
+
+func newCircuitBreaker(policy CircuitBreakerPolicy, logger utils.LoggerInterface) *circuitBreaker {
+	return &circuitBreaker{
+		policy: policy,
+		logger: logger,
+	}
+}
+
+func (b *circuitBreaker) allow() error {
+	if b.policy.FailureThreshold <= 0 {
+		return nil
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case breakerOpen:
+		if time.Since(b.openedAt) < b.policy.OpenTimeout {
+			return ErrCircuitOpen
+		}
+		b.state = breakerHalfOpen
+		b.trial = true
+		b.logger.Infof("Backend circuit breaker is half-open, sending a trial request")
+		return nil
+	case breakerHalfOpen:
+		if b.trial {
+			return ErrCircuitOpen
+		}
+		b.trial = true
+		return nil
+	default:
+		return nil
+	}
+}
+
+// release gives back a trial slot taken by a request that was cancelled before
+// its outcome was known.
+func (b *circuitBreaker) release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.trial = false
+}
+
+func (b *circuitBreaker) record(failed bool) {
+	if b.policy.FailureThreshold <= 0 {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if !failed {
+		if b.state != breakerClosed {
+			b.logger.Infof("Backend circuit breaker closed")
+		}
+		b.state = breakerClosed
+		b.failures = 0
+		b.trial = false
+		return
+	}
+
+	b.failures++
+	if b.state == breakerHalfOpen || b.failures >= b.policy.FailureThreshold {
+		if b.state != breakerOpen {
+			b.logger.Warnf("Backend circuit breaker opened after %d consecutive failures, pausing requests for %s",
+				b.failures, b.policy.OpenTimeout)
+		}
+		b.state = breakerOpen
+		b.openedAt = time.Now()
+		b.trial = false
+	}
+}
@@ -0,0 +1,371 @@
+package backend_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/k6zma/DockerMonitoringApp/pinger/internal/infrastructure/backend"
+	"github.com/k6zma/DockerMonitoringApp/pinger/pkg/utils"
+)
+
+var nopLogger = &utils.Logger{SugaredLogger: zap.NewNop().Sugar()}
+
+var testRetry = backend.RetryPolicy{
+	MaxAttempts:    3,
+	InitialBackoff: time.Millisecond,
+	MaxBackoff:     time.Millisecond,
+	MaxRetryAfter:  time.Second,
+	Methods:        []string{"GET", "PATCH", "DELETE"},
+}
+
+// stubTransport answers the attempts it receives with the responses of
+// respond, called with the number of the attempt starting at 1.
+type stubTransport struct {
+	respond func(req *http.Request, attempt int) (*http.Response, error)
+
+	mu     sync.Mutex
+	bodies []string
+}
+
+func (s *stubTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body string
+	if req.Body != nil {
+		data, _ := io.ReadAll(req.Body)
+		body = string(data)
+	}
+
+	s.mu.Lock()
+	s.bodies = append(s.bodies, body)
+	attempt := len(s.bodies)
+	s.mu.Unlock()
+
+	return s.respond(req, attempt)
+}
+
+func (s *stubTransport) attempts() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return len(s.bodies)
+}
+
+func response(code int, header ...string) *http.Response {
+	resp := &http.Response{
+		StatusCode: code,
+		Status:     http.StatusText(code),
+		Header:     make(http.Header),
+		Body:       io.NopCloser(strings.NewReader("")),
+	}
+	for i := 0; i+1 < len(header); i += 2 {
+		resp.Header.Set(header[i], header[i+1])
+	}
+
+	return resp
+}
+
+func newRequest(t *testing.T, ctx context.Context, method, body string) *http.Request {
+	t.Helper()
+
+	var reader io.Reader = http.NoBody
+	if body != "" {
+		reader = strings.NewReader(body)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, "http://backend/api/v1/container_status/abc123", reader)
+	require.NoError(t, err)
+
+	return req
+}
+
+func TestResilientTransport_Retries(t *testing.T) {
+	errReset := errors.New("connection reset by peer")
+
+	tests := []struct {
+		name     string
+		method   string
+		first    func() (*http.Response, error)
+		attempts int
+		status   int
+		err      error
+	}{
+		{
+			name:     "502 retried",
+			method:   http.MethodPatch,
+			first:    func() (*http.Response, error) { return response(http.StatusBadGateway), nil },
+			attempts: 2,
+			status:   http.StatusOK,
+		},
+		{
+			name:     "network error retried",
+			method:   http.MethodGet,
+			first:    func() (*http.Response, error) { return nil, errReset },
+			attempts: 2,
+			status:   http.StatusOK,
+		},
+		{
+			name:     "503 not retried for POST",
+			method:   http.MethodPost,
+			first:    func() (*http.Response, error) { return response(http.StatusServiceUnavailable), nil },
+			attempts: 1,
+			status:   http.StatusServiceUnavailable,
+		},
+		{
+			name:     "network error not retried for POST",
+			method:   http.MethodPost,
+			first:    func() (*http.Response, error) { return nil, errReset },
+			attempts: 1,
+			err:      errReset,
+		},
+		{
+			name:     "500 not retried",
+			method:   http.MethodPatch,
+			first:    func() (*http.Response, error) { return response(http.StatusInternalServerError), nil },
+			attempts: 1,
+			status:   http.StatusInternalServerError,
+		},
+		{
+			name:     "409 not retried",
+			method:   http.MethodPatch,
+			first:    func() (*http.Response, error) { return response(http.StatusConflict), nil },
+			attempts: 1,
+			status:   http.StatusConflict,
+		},
+		{
+			name:   "Retry-After in seconds honoured",
+			method: http.MethodPatch,
+			first: func() (*http.Response, error) {
+				return response(http.StatusTooManyRequests, "Retry-After", "0"), nil
+			},
+			attempts: 2,
+			status:   http.StatusOK,
+		},
+		{
+			name:   "Retry-After in seconds above the cap",
+			method: http.MethodPatch,
+			first: func() (*http.Response, error) {
+				return response(http.StatusServiceUnavailable, "Retry-After", "120"), nil
+			},
+			attempts: 1,
+			status:   http.StatusServiceUnavailable,
+		},
+		{
+			name:   "Retry-After date in the past honoured",
+			method: http.MethodDelete,
+			first: func() (*http.Response, error) {
+				date := time.Now().Add(-time.Minute).UTC().Format(http.TimeFormat)
+				return response(http.StatusServiceUnavailable, "Retry-After", date), nil
+			},
+			attempts: 2,
+			status:   http.StatusOK,
+		},
+		{
+			name:   "Retry-After date above the cap",
+			method: http.MethodDelete,
+			first: func() (*http.Response, error) {
+				date := time.Now().Add(time.Hour).UTC().Format(http.TimeFormat)
+				return response(http.StatusServiceUnavailable, "Retry-After", date), nil
+			},
+			attempts: 1,
+			status:   http.StatusServiceUnavailable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stub := &stubTransport{respond: func(_ *http.Request, attempt int) (*http.Response, error) {
+				if attempt == 1 {
+					return tt.first()
+				}
+				return response(http.StatusOK), nil
+			}}
+			transport := backend.NewResilientTransport(stub, 0, testRetry, backend.CircuitBreakerPolicy{}, nopLogger)
+
+			resp, err := transport.RoundTrip(newRequest(t, context.Background(), tt.method, `{"status":"running"}`))
+
+			assert.Equal(t, tt.attempts, stub.attempts())
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.status, resp.StatusCode)
+
+			// Every attempt sends the whole body.
+			for _, body := range stub.bodies {
+				assert.Equal(t, `{"status":"running"}`, body)
+			}
+		})
+	}
+}
+
+func TestResilientTransport_GivesUpAfterMaxAttempts(t *testing.T) {
+	stub := &stubTransport{respond: func(*http.Request, int) (*http.Response, error) {
+		return response(http.StatusGatewayTimeout), nil
+	}}
+	transport := backend.NewResilientTransport(stub, 0, testRetry, backend.CircuitBreakerPolicy{}, nopLogger)
+
+	resp, err := transport.RoundTrip(newRequest(t, context.Background(), http.MethodGet, ""))
+
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusGatewayTimeout, resp.StatusCode)
+	assert.Equal(t, testRetry.MaxAttempts, stub.attempts())
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		expected time.Duration
+		ok       bool
+	}{
+		{name: "seconds", value: "3", expected: 3 * time.Second, ok: true},
+		{name: "zero", value: "0", expected: 0, ok: true},
+		{name: "padded", value: " 7 ", expected: 7 * time.Second, ok: true},
+		{name: "negative", value: "-1"},
+		{name: "fraction", value: "1.5"},
+		{name: "date in the past", value: "Wed, 21 Oct 2015 07:28:00 GMT", expected: 0, ok: true},
+		{name: "garbage", value: "soon"},
+		{name: "empty", value: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			delay, ok := backend.ParseRetryAfter(tt.value)
+
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.expected, delay)
+		})
+	}
+}
+
+func TestParseRetryAfter_FutureDate(t *testing.T) {
+	delay, ok := backend.ParseRetryAfter(time.Now().Add(time.Minute).UTC().Format(http.TimeFormat))
+
+	assert.True(t, ok)
+	assert.InDelta(t, time.Minute, delay, float64(2*time.Second))
+}
+
+func TestCircuitBreaker_Cycle(t *testing.T) {
+	var failing bool
+	stub := &stubTransport{respond: func(*http.Request, int) (*http.Response, error) {
+		if failing {
+			return response(http.StatusInternalServerError), nil
+		}
+		return response(http.StatusOK), nil
+	}}
+	policy := backend.CircuitBreakerPolicy{FailureThreshold: 2, OpenTimeout: 20 * time.Millisecond}
+	transport := backend.NewResilientTransport(stub, 0, backend.RetryPolicy{MaxAttempts: 1}, policy, nopLogger)
+
+	send := func() (*http.Response, error) {
+		return transport.RoundTrip(newRequest(t, context.Background(), http.MethodGet, ""))
+	}
+
+	// Closed: failures below the threshold keep it closed.
+	failing = true
+	_, err := send()
+	require.NoError(t, err)
+	assert.Equal(t, backend.BreakerClosed, transport.BreakerState())
+
+	// Open: the threshold is reached and requests fail without an attempt.
+	_, err = send()
+	require.NoError(t, err)
+	assert.Equal(t, backend.BreakerOpen, transport.BreakerState())
+
+	_, err = send()
+	assert.ErrorIs(t, err, backend.ErrCircuitOpen)
+	assert.Equal(t, 2, stub.attempts())
+
+	// Half-open: after the timeout a failed trial opens it again.
+	time.Sleep(policy.OpenTimeout)
+	_, err = send()
+	require.NoError(t, err)
+	assert.Equal(t, backend.BreakerOpen, transport.BreakerState())
+	assert.Equal(t, 3, stub.attempts())
+
+	// Half-open: only one trial is let through at a time.
+	time.Sleep(policy.OpenTimeout)
+	require.NoError(t, transport.BreakerAllow())
+	assert.Equal(t, backend.BreakerHalfOpen, transport.BreakerState())
+	assert.ErrorIs(t, transport.BreakerAllow(), backend.ErrCircuitOpen)
+
+	// Closed: a successful trial closes it.
+	failing = false
+	transport.BreakerRecord(false)
+	assert.Equal(t, backend.BreakerClosed, transport.BreakerState())
+
+	resp, err := send()
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+func TestCircuitBreaker_ReleasesTrial(t *testing.T) {
+	tests := []struct {
+		name   string
+		ctx    func() (context.Context, context.CancelFunc)
+		cancel bool
+	}{
+		{
+			name:   "canceled",
+			ctx:    func() (context.Context, context.CancelFunc) { return context.WithCancel(context.Background()) },
+			cancel: true,
+		},
+		{
+			name: "deadline exceeded",
+			ctx: func() (context.Context, context.CancelFunc) {
+				return context.WithTimeout(context.Background(), 10*time.Millisecond)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := tt.ctx()
+			defer cancel()
+
+			stub := &stubTransport{respond: func(req *http.Request, _ int) (*http.Response, error) {
+				if tt.cancel {
+					cancel()
+				}
+				<-req.Context().Done()
+				return nil, req.Context().Err()
+			}}
+			policy := backend.CircuitBreakerPolicy{FailureThreshold: 1, OpenTimeout: time.Millisecond}
+			transport := backend.NewResilientTransport(stub, 0, testRetry, policy, nopLogger)
+
+			transport.BreakerRecord(true)
+			time.Sleep(policy.OpenTimeout)
+
+			_, err := transport.RoundTrip(newRequest(t, ctx, http.MethodGet, ""))
+			assert.Error(t, err)
+			assert.Equal(t, 1, stub.attempts())
+
+			// The trial is given back instead of counting as a failure.
+			assert.Equal(t, backend.BreakerHalfOpen, transport.BreakerState())
+			assert.NoError(t, transport.BreakerAllow())
+		})
+	}
+}
+
+func TestCircuitBreaker_AttemptTimeoutIsFailure(t *testing.T) {
+	stub := &stubTransport{respond: func(req *http.Request, _ int) (*http.Response, error) {
+		<-req.Context().Done()
+		return nil, req.Context().Err()
+	}}
+	policy := backend.CircuitBreakerPolicy{FailureThreshold: 1, OpenTimeout: time.Minute}
+	transport := backend.NewResilientTransport(stub, time.Millisecond, backend.RetryPolicy{MaxAttempts: 1}, policy, nopLogger)
+
+	_, err := transport.RoundTrip(newRequest(t, context.Background(), http.MethodGet, ""))
+
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, backend.BreakerOpen, transport.BreakerState())
+}
@@ -6,21 +6,102 @@ import (
 
 	"github.com/go-playground/validator/v10"
 	"github.com/spf13/viper"
+
+	"github.com/k6zma/DockerMonitoringApp/pinger/internal/domain"
 )
 
 type Config struct {
 	Ping    *PingConfig    `mapstructure:"ping" validate:"required"`
 	Docker  *DockerConfig  `mapstructure:"docker"        validate:"required"`
 	Backend *BackendConfig `mapstructure:"backend"       validate:"required"`
+	Outbox  *OutboxConfig  `mapstructure:"outbox"        validate:"required"`
+	HTTP    *HTTPConfig    `mapstructure:"http"          validate:"required"`
+	Tracing *TracingConfig `mapstructure:"tracing"       validate:"required"`
+}
+
+type TracingConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+	// Exporter is otlp, stdout or file. The OTLP exporter also honours the
+	// standard OTEL_EXPORTER_OTLP_* environment variables.
+	Exporter    string  `mapstructure:"exporter"     validate:"required_if=Enabled true,omitempty,oneof=otlp stdout file"`
+	Endpoint    string  `mapstructure:"endpoint"`
+	Insecure    bool    `mapstructure:"insecure"`
+	FilePath    string  `mapstructure:"file_path"    validate:"required_if=Exporter file"`
+	SampleRatio float64 `mapstructure:"sample_ratio" validate:"gte=0,lte=1"`
+	ServiceName string  `mapstructure:"service_name" validate:"required"`
+}
+
+type HTTPConfig struct {
+	Enabled bool   `mapstructure:"enabled"`
+	Address string `mapstructure:"address" validate:"required_if=Enabled true"`
+	// MaxCycleAge is how long ago the last cycle may have finished before the
+	// health checks fail. Defaults to three ping intervals.
+	MaxCycleAge time.Duration `mapstructure:"max_cycle_age" validate:"gte=0"`
 }
 
+// BackendConfig configures the backend client. With AuthMode hmac, requests
+// are signed with APIKey instead of carrying it; KeyID names the key to the
+// backend: its prefix for a stored key or its name for a configured one.
 type BackendConfig struct {
-	URL    string `mapstructure:"url"     validate:"required,url"`
-	APIKey string `mapstructure:"api_key" validate:"required"`
+	URL            string                `mapstructure:"url"             validate:"required,url"`
+	APIKey         string                `mapstructure:"api_key"         validate:"required"`
+	AuthMode       string                `mapstructure:"auth_mode"       validate:"required,oneof=api_key hmac"`
+	KeyID          string                `mapstructure:"key_id"          validate:"required_if=AuthMode hmac"`
+	Timeout        time.Duration         `mapstructure:"timeout"         validate:"required,gt=0"`
+	Retry          *RetryConfig          `mapstructure:"retry"           validate:"required"`
+	CircuitBreaker *CircuitBreakerConfig `mapstructure:"circuit_breaker" validate:"required"`
+	TLS            *BackendTLSConfig     `mapstructure:"tls"             validate:"required"`
+	Compression    *CompressionConfig    `mapstructure:"compression"     validate:"required"`
+}
+
+// BackendTLSConfig configures HTTPS connections to the backend. CAFile replaces
+// the system roots with the given bundle; CertFile and KeyFile are presented as
+// client certificate for mutual TLS. The files are reloaded when they change.
+type BackendTLSConfig struct {
+	CAFile   string `mapstructure:"ca_file"   validate:"omitempty,file"`
+	CertFile string `mapstructure:"cert_file" validate:"required_with=KeyFile,omitempty,file"`
+	KeyFile  string `mapstructure:"key_file"  validate:"required_with=CertFile,omitempty,file"`
+}
+
+// CompressionConfig compresses request bodies of at least MinSize bytes with
+// Encoding. The backend must have compression enabled to accept them.
+type CompressionConfig struct {
+	Encoding string `mapstructure:"encoding" validate:"required,oneof=none gzip zstd"`
+	MinSize  int    `mapstructure:"min_size" validate:"gte=0"`
+}
+
+type RetryConfig struct {
+	MaxAttempts    int           `mapstructure:"max_attempts"    validate:"required,gte=1"`
+	InitialBackoff time.Duration `mapstructure:"initial_backoff" validate:"required,gt=0"`
+	MaxBackoff     time.Duration `mapstructure:"max_backoff"     validate:"required,gtefield=InitialBackoff"`
+	MaxRetryAfter  time.Duration `mapstructure:"max_retry_after" validate:"gt=0"`
+	Methods        []string      `mapstructure:"methods"         validate:"dive,oneof=GET HEAD OPTIONS PUT PATCH DELETE POST"`
+}
+
+type CircuitBreakerConfig struct {
+	FailureThreshold int           `mapstructure:"failure_threshold" validate:"gte=0"`
+	OpenTimeout      time.Duration `mapstructure:"open_timeout"      validate:"required_unless=FailureThreshold 0"`
 }
 
 type PingConfig struct {
-	PingInterval time.Duration `mapstructure:"ping_interval" validate:"required,gt=4s"`
+	PingInterval   time.Duration `mapstructure:"ping_interval"   validate:"required,gt=4s"`
+	Mode           string        `mapstructure:"mode"            validate:"required,oneof=auto privileged unprivileged"`
+	Count          int           `mapstructure:"count"           validate:"required,gt=0"`
+	Timeout        time.Duration `mapstructure:"timeout"         validate:"required,gt=0,ltfield=PingInterval"`
+	PacketInterval time.Duration `mapstructure:"packet_interval" validate:"required,gt=0,ltefield=Timeout"`
+	Size           int           `mapstructure:"size"            validate:"required,gte=24,lte=65507"`
+	TTL            int           `mapstructure:"ttl"             validate:"required,gte=1,lte=255"`
+	MaxConcurrency int           `mapstructure:"max_concurrency" validate:"required,gt=0"`
+	OverlapPolicy  string        `mapstructure:"overlap_policy"  validate:"required,oneof=skip queue"`
+	Jitter         time.Duration `mapstructure:"jitter"          validate:"gte=0"`
+}
+
+type OutboxConfig struct {
+	Enabled       bool          `mapstructure:"enabled"`
+	Path          string        `mapstructure:"path"           validate:"required_if=Enabled true"`
+	MaxItems      int           `mapstructure:"max_items"      validate:"required_if=Enabled true,gte=0"`
+	ReplayBatch   int           `mapstructure:"replay_batch"   validate:"required_if=Enabled true,gte=0"`
+	ReplayTimeout time.Duration `mapstructure:"replay_timeout" validate:"required_if=Enabled true,gte=0"`
 }
 
 type DockerConfig struct {
@@ -30,6 +111,39 @@ type DockerConfig struct {
 func Load(configPath string) (*Config, error) {
 	viper.SetConfigFile(configPath)
 	viper.SetConfigType("json")
+	viper.SetDefault("ping.mode", "auto")
+	viper.SetDefault("ping.count", 3)
+	viper.SetDefault("ping.timeout", "2s")
+	viper.SetDefault("ping.packet_interval", "500ms")
+	viper.SetDefault("ping.size", 24)
+	viper.SetDefault("ping.ttl", 64)
+	viper.SetDefault("ping.max_concurrency", 16)
+	viper.SetDefault("ping.overlap_policy", "skip")
+	viper.SetDefault("ping.jitter", "1s")
+	viper.SetDefault("backend.auth_mode", "api_key")
+	viper.SetDefault("backend.timeout", "5s")
+	viper.SetDefault("backend.retry.max_attempts", 3)
+	viper.SetDefault("backend.retry.initial_backoff", "200ms")
+	viper.SetDefault("backend.retry.max_backoff", "2s")
+	viper.SetDefault("backend.retry.max_retry_after", "5s")
+	viper.SetDefault("backend.retry.methods", []string{"GET", "PATCH", "DELETE"})
+	viper.SetDefault("backend.circuit_breaker.failure_threshold", 5)
+	viper.SetDefault("backend.circuit_breaker.open_timeout", "30s")
+	viper.SetDefault("backend.tls.ca_file", "")
+	viper.SetDefault("backend.tls.cert_file", "")
+	viper.SetDefault("backend.tls.key_file", "")
+	viper.SetDefault("backend.compression.encoding", "none")
+	viper.SetDefault("backend.compression.min_size", 256)
+	viper.SetDefault("outbox.enabled", false)
+	viper.SetDefault("outbox.max_items", 10000)
+	viper.SetDefault("outbox.replay_batch", 500)
+	viper.SetDefault("outbox.replay_timeout", "10s")
+	viper.SetDefault("http.enabled", false)
+	viper.SetDefault("http.address", ":8081")
+	viper.SetDefault("tracing.enabled", false)
+	viper.SetDefault("tracing.exporter", "otlp")
+	viper.SetDefault("tracing.sample_ratio", 1.0)
+	viper.SetDefault("tracing.service_name", "docker-monitoring-pinger")
 
 	if err := viper.ReadInConfig(); err != nil {
 		return nil, fmt.Errorf("config read error: %w", err)
@@ -45,5 +159,14 @@ func Load(configPath string) (*Config, error) {
 		return nil, fmt.Errorf("config validation error: %w", err)
 	}
 
+	if cfg.HTTP.MaxCycleAge == 0 {
+		cfg.HTTP.MaxCycleAge = 3 * cfg.Ping.PingInterval
+	}
+
+	probe := domain.ProbeSettings{Count: cfg.Ping.Count, Timeout: cfg.Ping.Timeout, PacketInterval: cfg.Ping.PacketInterval}
+	if err := probe.Fits(cfg.Ping.PingInterval, cfg.Ping.Jitter); err != nil {
+		return nil, fmt.Errorf("config validation error: ping %w", err)
+	}
+
 	return &cfg, nil
 }
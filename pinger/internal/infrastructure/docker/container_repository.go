@@ -33,6 +33,14 @@ func NewDockerContainerRepo(
 	return &DockerContainerRepo{client: client, logger: logger}, nil
 }
 
+func (r *DockerContainerRepo) Ping(ctx context.Context) error {
+	if _, err := r.client.Ping(ctx); err != nil {
+		return fmt.Errorf("docker ping failed: %w", err)
+	}
+
+	return nil
+}
+
 func (r *DockerContainerRepo) GetContainers(ctx context.Context) ([]domain.ContainerInfo, error) {
 	r.logger.Debug("Getting containers list")
 	containers, err := r.client.ContainerList(ctx, container.ListOptions{
@@ -56,12 +64,26 @@ func (r *DockerContainerRepo) GetContainers(ctx context.Context) ([]domain.Conta
 		}
 
 		containerList = append(containerList, domain.ContainerInfo{
-			ContainerID: containers[i].ID,
-			IP:          ip,
-			Name:        containers[i].Names[0],
-			Status:      containers[i].State,
+			ContainerID:  containers[i].ID,
+			IP:           ip,
+			Name:         containers[i].Names[0],
+			Status:       containers[i].State,
+			RestartCount: r.getRestartCount(ctx, containers[i].ID),
+			Labels:       containers[i].Labels,
 		})
 	}
 
 	return containerList, nil
 }
+
+// getRestartCount inspects the container to read its restart count, which is not
+// part of the container list response. Failures are logged and reported as zero.
+func (r *DockerContainerRepo) getRestartCount(ctx context.Context, containerID string) int {
+	inspect, err := r.client.ContainerInspect(ctx, containerID)
+	if err != nil {
+		r.logger.Warnf("Container inspect failed for %s: %v", containerID, err)
+		return 0
+	}
+
+	return inspect.RestartCount
+}
@@ -10,17 +10,20 @@ import (
 type AppFlags struct {
 	ConfigFilePath string `validate:"required,file"`
 	LoggerLevel    string `validate:"oneof=debug info warn error dpanic panic fatal"`
+	LoggerFormat   string `validate:"oneof=console json"`
 }
 
 func ParseFlags() (*AppFlags, error) {
 	configFile := flag.String("config_path", "config.json", "Path to config file")
 	loggerLevel := flag.String("logger_level", "debug", "Logging level")
+	loggerFormat := flag.String("logger_format", "console", "Logging format (console, json)")
 
 	flag.Parse()
 
 	flags := &AppFlags{
 		ConfigFilePath: *configFile,
 		LoggerLevel:    *loggerLevel,
+		LoggerFormat:   *loggerFormat,
 	}
 
 	validate := validator.New()
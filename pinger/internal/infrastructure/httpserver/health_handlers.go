@@ -0,0 +1,83 @@
+package httpserver
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/k6zma/DockerMonitoringApp/pinger/internal/application/usecases"
+	"github.com/k6zma/DockerMonitoringApp/pinger/internal/domain"
+	"github.com/k6zma/DockerMonitoringApp/pinger/pkg/utils"
+)
+
+const readinessTimeout = 3 * time.Second
+
+type healthCheckResponse struct {
+	Status  string `json:"status"`
+	Message string `json:"message,omitempty"`
+}
+
+type healthResponse struct {
+	Status   string                         `json:"status"`
+	PingMode string                         `json:"ping_mode"`
+	Checks   map[string]healthCheckResponse `json:"checks"`
+}
+
+type HealthHandler struct {
+	usecase *usecases.HealthUsecase
+	logger  utils.LoggerInterface
+}
+
+func NewHealthHandler(usecase *usecases.HealthUsecase, logger utils.LoggerInterface) *HealthHandler {
+	return &HealthHandler{
+		usecase: usecase,
+		logger:  logger,
+	}
+}
+
+func (h *HealthHandler) Liveness(w http.ResponseWriter, _ *http.Request) {
+	h.write(w, h.usecase.Liveness())
+}
+
+func (h *HealthHandler) Readiness(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), readinessTimeout)
+	defer cancel()
+
+	h.write(w, h.usecase.Readiness(ctx))
+}
+
+func (h *HealthHandler) write(w http.ResponseWriter, report domain.HealthReport) {
+	response := healthResponse{
+		Status:   healthStatus(report.Healthy),
+		PingMode: string(report.PingMode),
+		Checks:   make(map[string]healthCheckResponse, len(report.Checks)),
+	}
+	for _, check := range report.Checks {
+		response.Checks[check.Name] = healthCheckResponse{
+			Status:  healthStatus(check.Healthy),
+			Message: check.Message,
+		}
+	}
+
+	statusCode := http.StatusOK
+	if !report.Healthy {
+		statusCode = http.StatusServiceUnavailable
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(statusCode)
+
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		h.logger.Errorf("Failed to encode health response: %v", err)
+	}
+}
+
+func healthStatus(healthy bool) string {
+	if healthy {
+		return "ok"
+	}
+
+	return "unavailable"
+}
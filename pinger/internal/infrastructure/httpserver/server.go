@@ -0,0 +1,63 @@
+package httpserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/k6zma/DockerMonitoringApp/pinger/pkg/utils"
+)
+
+const readHeaderTimeout = 5 * time.Second
+
+// Server is the optional HTTP listener exposing operational endpoints of the pinger.
+type Server struct {
+	server *http.Server
+	mux    *http.ServeMux
+	logger utils.LoggerInterface
+}
+
+func NewServer(address string, logger utils.LoggerInterface) *Server {
+	mux := http.NewServeMux()
+
+	return &Server{
+		server: &http.Server{
+			Addr:              address,
+			Handler:           mux,
+			ReadHeaderTimeout: readHeaderTimeout,
+		},
+		mux:    mux,
+		logger: logger,
+	}
+}
+
+func (s *Server) Handle(pattern string, handler http.Handler) {
+	s.mux.Handle(pattern, handler)
+}
+
+// Start serves requests in the background. Errors other than a graceful shutdown
+// are reported on the returned channel.
+func (s *Server) Start() <-chan error {
+	errCh := make(chan error, 1)
+
+	go func() {
+		s.logger.Infof("HTTP server listening on %s", s.server.Addr)
+		if err := s.server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			errCh <- fmt.Errorf("http server failed: %w", err)
+		}
+		close(errCh)
+	}()
+
+	return errCh
+}
+
+func (s *Server) Shutdown(ctx context.Context) error {
+	if err := s.server.Shutdown(ctx); err != nil {
+		return fmt.Errorf("http server shutdown failed: %w", err)
+	}
+
+	s.logger.Info("HTTP server stopped")
+	return nil
+}
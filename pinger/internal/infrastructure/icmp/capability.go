@@ -0,0 +1,121 @@
+package icmp
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	probing "github.com/prometheus-community/pro-bing"
+
+	"github.com/k6zma/DockerMonitoringApp/pinger/internal/domain"
+	"github.com/k6zma/DockerMonitoringApp/pinger/pkg/utils"
+)
+
+const (
+	pingGroupRangePath = "/proc/sys/net/ipv4/ping_group_range"
+	selfCheckAddress   = "127.0.0.1"
+	selfCheckTimeout   = time.Second
+)
+
+// DetectMode runs a startup self-check and returns the ICMP mode the pinger should use.
+// With the auto mode, raw sockets are tried first and UDP ICMP sockets are used as a fallback.
+// An explicitly requested mode is verified and returned only if it works.
+func DetectMode(requested domain.PingMode, logger utils.LoggerInterface) (domain.PingMode, error) {
+	switch requested {
+	case domain.PingModePrivileged:
+		if err := selfCheck(true); err != nil {
+			return "", fmt.Errorf("privileged ping mode unavailable (CAP_NET_RAW or root required): %w", err)
+		}
+		return domain.PingModePrivileged, nil
+	case domain.PingModeUnprivileged:
+		if err := checkUnprivileged(); err != nil {
+			return "", fmt.Errorf("unprivileged ping mode unavailable: %w", err)
+		}
+		return domain.PingModeUnprivileged, nil
+	case domain.PingModeAuto:
+		privErr := selfCheck(true)
+		if privErr == nil {
+			return domain.PingModePrivileged, nil
+		}
+		logger.Warnf("Privileged ping self-check failed, falling back to unprivileged mode: %v", privErr)
+
+		unprivErr := checkUnprivileged()
+		if unprivErr == nil {
+			return domain.PingModeUnprivileged, nil
+		}
+
+		return "", fmt.Errorf("no usable ping mode: privileged: %w; unprivileged: %w", privErr, unprivErr)
+	default:
+		return "", fmt.Errorf("unknown ping mode: %s", requested)
+	}
+}
+
+func checkUnprivileged() error {
+	if err := checkPingGroupRange(); err != nil {
+		return err
+	}
+
+	return selfCheck(false)
+}
+
+// selfCheck sends a single echo request to the loopback address in the given mode.
+func selfCheck(privileged bool) error {
+	pinger, err := probing.NewPinger(selfCheckAddress)
+	if err != nil {
+		return fmt.Errorf("ping init failed: %w", err)
+	}
+
+	pinger.Count = 1
+	pinger.Timeout = selfCheckTimeout
+	pinger.SetPrivileged(privileged)
+
+	if err := pinger.Run(); err != nil {
+		return fmt.Errorf("ping execution failed: %w", err)
+	}
+
+	if pinger.Statistics().PacketsRecv == 0 {
+		return errors.New("no reply received from loopback")
+	}
+
+	return nil
+}
+
+// checkPingGroupRange verifies that one of the process groups is allowed to open
+// UDP ICMP sockets according to net.ipv4.ping_group_range.
+func checkPingGroupRange() error {
+	data, err := os.ReadFile(pingGroupRangePath)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", pingGroupRangePath, err)
+	}
+
+	fields := strings.Fields(string(data))
+	if len(fields) != 2 {
+		return fmt.Errorf("unexpected %s content: %q", pingGroupRangePath, string(data))
+	}
+
+	low, err := strconv.ParseUint(fields[0], 10, 32)
+	if err != nil {
+		return fmt.Errorf("invalid ping_group_range lower bound: %w", err)
+	}
+	high, err := strconv.ParseUint(fields[1], 10, 32)
+	if err != nil {
+		return fmt.Errorf("invalid ping_group_range upper bound: %w", err)
+	}
+
+	groups, err := os.Getgroups()
+	if err != nil {
+		return fmt.Errorf("failed to get process groups: %w", err)
+	}
+	groups = append(groups, os.Getgid())
+
+	for _, gid := range groups {
+		if gid >= 0 && uint64(gid) >= low && uint64(gid) <= high {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("process groups %v are outside net.ipv4.ping_group_range %d-%d", groups, low, high)
+}
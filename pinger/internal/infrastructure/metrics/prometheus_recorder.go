@@ -0,0 +1,205 @@
+package metrics
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/k6zma/DockerMonitoringApp/pinger/internal/application/repositories"
+	"github.com/k6zma/DockerMonitoringApp/pinger/internal/application/usecases"
+	"github.com/k6zma/DockerMonitoringApp/pinger/internal/domain"
+)
+
+const namespace = "pinger"
+
+const (
+	outcomeReachable   = "reachable"
+	outcomeUnreachable = "unreachable"
+	outcomeError       = "error"
+)
+
+// PrometheusRecorder exposes monitoring measurements as Prometheus metrics.
+type PrometheusRecorder struct {
+	rtt            *prometheus.GaugeVec
+	packetLoss     *prometheus.GaugeVec
+	probeDuration  *prometheus.HistogramVec
+	cycleDuration  prometheus.Histogram
+	dockerErrors   *prometheus.CounterVec
+	deliveryErrors *prometheus.CounterVec
+	pingMode       *prometheus.GaugeVec
+	registerer     prometheus.Registerer
+
+	mu    sync.Mutex
+	names map[string]string
+}
+
+func NewPrometheusRecorder(registerer prometheus.Registerer) (*PrometheusRecorder, error) {
+	r := &PrometheusRecorder{
+		rtt: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "container_rtt_seconds",
+			Help:      "Average round-trip time of the last probe. Absent when no reply was received.",
+		}, []string{"container_id", "name"}),
+		packetLoss: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "container_packet_loss_ratio",
+			Help:      "Share of echo requests without a reply in the last probe, from 0 to 1.",
+		}, []string{"container_id", "name"}),
+		probeDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "probe_duration_seconds",
+			Help:      "Duration of container probes by outcome.",
+			Buckets:   []float64{0.005, 0.01, 0.05, 0.1, 0.25, 0.5, 1, 2, 5, 10},
+		}, []string{"outcome"}),
+		cycleDuration: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "cycle_duration_seconds",
+			Help:      "Duration of monitoring cycles.",
+			Buckets:   []float64{0.1, 0.25, 0.5, 1, 2, 5, 10, 30, 60},
+		}),
+		dockerErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "docker_api_errors_total",
+			Help:      "Failed Docker API calls by operation.",
+		}, []string{"operation"}),
+		deliveryErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "backend_delivery_errors_total",
+			Help:      "Failed backend calls by operation.",
+		}, []string{"operation"}),
+		pingMode: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "ping_mode",
+			Help:      "ICMP mode selected by the startup self-check; the active mode is set to 1.",
+		}, []string{"mode"}),
+		registerer: registerer,
+		names:      make(map[string]string),
+	}
+
+	collectors := []prometheus.Collector{
+		r.rtt,
+		r.packetLoss,
+		r.probeDuration,
+		r.cycleDuration,
+		r.dockerErrors,
+		r.deliveryErrors,
+		r.pingMode,
+	}
+	for _, collector := range collectors {
+		if err := registerer.Register(collector); err != nil {
+			return nil, fmt.Errorf("metrics registration failed: %w", err)
+		}
+	}
+
+	return r, nil
+}
+
+var _ usecases.MetricsRecorder = (*PrometheusRecorder)(nil)
+
+func (r *PrometheusRecorder) ObserveProbe(result *domain.PingResult, duration time.Duration) {
+	r.trackName(result.ContainerID, result.Name)
+
+	r.packetLoss.WithLabelValues(result.ContainerID, result.Name).Set(result.PacketLoss)
+
+	outcome := outcomeUnreachable
+	if result.Success {
+		outcome = outcomeReachable
+		r.rtt.WithLabelValues(result.ContainerID, result.Name).Set((time.Duration(result.PingTime) * time.Microsecond).Seconds())
+	} else {
+		r.rtt.DeleteLabelValues(result.ContainerID, result.Name)
+	}
+
+	r.probeDuration.WithLabelValues(outcome).Observe(duration.Seconds())
+}
+
+func (r *PrometheusRecorder) ObserveProbeError(container domain.ContainerInfo, duration time.Duration) {
+	r.forget(container.ContainerID)
+	r.probeDuration.WithLabelValues(outcomeError).Observe(duration.Seconds())
+}
+
+func (r *PrometheusRecorder) ObserveCycle(duration time.Duration) {
+	r.cycleDuration.Observe(duration.Seconds())
+}
+
+func (r *PrometheusRecorder) RetainContainers(containerIDs map[string]bool) {
+	r.mu.Lock()
+	stale := make([]string, 0)
+	for containerID := range r.names {
+		if !containerIDs[containerID] {
+			stale = append(stale, containerID)
+		}
+	}
+	r.mu.Unlock()
+
+	for _, containerID := range stale {
+		r.forget(containerID)
+	}
+}
+
+func (r *PrometheusRecorder) DockerAPIError(operation string) {
+	r.dockerErrors.WithLabelValues(operation).Inc()
+}
+
+func (r *PrometheusRecorder) BackendDeliveryError(operation string) {
+	r.deliveryErrors.WithLabelValues(operation).Inc()
+}
+
+// SetPingMode marks mode as the active ICMP mode.
+func (r *PrometheusRecorder) SetPingMode(mode domain.PingMode) {
+	r.pingMode.Reset()
+	r.pingMode.WithLabelValues(string(mode)).Set(1)
+}
+
+// RegisterOutbox exposes the depth and the number of dropped results of the outbox.
+func (r *PrometheusRecorder) RegisterOutbox(outbox repositories.OutboxRepository) error {
+	depth := prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "outbox_depth",
+		Help:      "Number of results waiting in the outbox.",
+	}, func() float64 {
+		return float64(outbox.Stats().Depth)
+	})
+
+	dropped := prometheus.NewCounterFunc(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "outbox_dropped_total",
+		Help:      "Results dropped from the outbox because it was full or the backend rejected them.",
+	}, func() float64 {
+		return float64(outbox.Stats().Dropped)
+	})
+
+	if err := r.registerer.Register(depth); err != nil {
+		return fmt.Errorf("outbox metrics registration failed: %w", err)
+	}
+
+	if err := r.registerer.Register(dropped); err != nil {
+		return fmt.Errorf("outbox metrics registration failed: %w", err)
+	}
+
+	return nil
+}
+
+// trackName remembers the name a container is reported under and drops its old
+// series when the container was renamed.
+func (r *PrometheusRecorder) trackName(containerID, name string) {
+	r.mu.Lock()
+	previous, ok := r.names[containerID]
+	r.names[containerID] = name
+	r.mu.Unlock()
+
+	if ok && previous != name {
+		r.rtt.DeleteLabelValues(containerID, previous)
+		r.packetLoss.DeleteLabelValues(containerID, previous)
+	}
+}
+
+func (r *PrometheusRecorder) forget(containerID string) {
+	r.mu.Lock()
+	delete(r.names, containerID)
+	r.mu.Unlock()
+
+	r.rtt.DeletePartialMatch(prometheus.Labels{"container_id": containerID})
+	r.packetLoss.DeletePartialMatch(prometheus.Labels{"container_id": containerID})
+}
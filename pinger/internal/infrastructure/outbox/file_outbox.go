@@ -0,0 +1,284 @@
+package outbox
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+
+	"github.com/k6zma/DockerMonitoringApp/pinger/internal/application/repositories"
+	"github.com/k6zma/DockerMonitoringApp/pinger/internal/domain"
+	"github.com/k6zma/DockerMonitoringApp/pinger/pkg/utils"
+)
+
+const filePermissions = 0o600
+
+// FileOutbox is a bounded write-ahead queue of undelivered results stored as
+// JSON lines. Every enqueued result is appended and synced to disk before
+// Enqueue returns, so queued results survive a pinger restart. When the outbox
+// is full the oldest result is dropped.
+type FileOutbox struct {
+	path     string
+	maxItems int
+	logger   utils.LoggerInterface
+
+	// replayMu serializes replays, mu guards the queue.
+	replayMu sync.Mutex
+	mu       sync.Mutex
+	items    []*domain.PingResult
+	dropped  uint64
+}
+
+func NewFileOutbox(
+	path string,
+	maxItems int,
+	logger utils.LoggerInterface,
+) (repositories.OutboxRepository, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return nil, fmt.Errorf("outbox directory creation failed: %w", err)
+	}
+
+	o := &FileOutbox{
+		path:     path,
+		maxItems: maxItems,
+		logger:   logger,
+	}
+
+	if err := o.load(); err != nil {
+		return nil, err
+	}
+
+	if len(o.items) > 0 {
+		logger.Infof("Loaded %d undelivered results from outbox %s", len(o.items), path)
+	}
+
+	return o, nil
+}
+
+func (o *FileOutbox) Enqueue(result *domain.PingResult) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if len(o.items) >= o.maxItems {
+		dropped := len(o.items) - o.maxItems + 1
+		o.items = o.items[dropped:]
+		o.dropped += uint64(dropped)
+		o.items = append(o.items, result)
+
+		o.logger.Warnf("Outbox is full (%d items), dropped %d oldest results", o.maxItems, dropped)
+		return o.rewrite()
+	}
+
+	line, err := json.Marshal(result)
+	if err != nil {
+		return fmt.Errorf("outbox marshal failed: %w", err)
+	}
+
+	file, err := os.OpenFile(o.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, filePermissions)
+	if err != nil {
+		return fmt.Errorf("outbox open failed: %w", err)
+	}
+	defer file.Close()
+
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("outbox write failed: %w", err)
+	}
+
+	if err := file.Sync(); err != nil {
+		return fmt.Errorf("outbox sync failed: %w", err)
+	}
+
+	o.items = append(o.items, result)
+	o.logger.Debugf("Queued result for container %s in outbox (depth %d)", result.ContainerID, len(o.items))
+
+	return nil
+}
+
+// Replay delivers up to limit queued results in order, oldest first. It stops
+// at the first transient failure and keeps the remaining results queued.
+// Results rejected by the backend are dropped. The results are delivered
+// without holding the queue lock, so Enqueue and Stats do not wait for the
+// backend. It returns the number of delivered results.
+func (o *FileOutbox) Replay(ctx context.Context, limit int, deliver repositories.DeliverFunc) (int, error) {
+	o.replayMu.Lock()
+	defer o.replayMu.Unlock()
+
+	batch, depth := o.batch(limit)
+	if len(batch) == 0 {
+		return 0, nil
+	}
+
+	o.logger.Infof("Replaying %d of %d results from outbox", len(batch), depth)
+
+	delivered := 0
+	rejected := 0
+	processed := 0
+	var replayErr error
+
+	for _, result := range batch {
+		if err := ctx.Err(); err != nil {
+			replayErr = err
+			break
+		}
+
+		err := deliver(ctx, result)
+		if errors.Is(err, repositories.ErrStatusRejected) {
+			o.logger.Warnf("Dropping queued result for container %s rejected by backend: %v", result.ContainerID, err)
+			rejected++
+			processed++
+			continue
+		}
+		if err != nil {
+			replayErr = err
+			break
+		}
+
+		delivered++
+		processed++
+	}
+
+	left, err := o.commit(batch[:processed], rejected)
+	if err != nil {
+		return delivered, err
+	}
+
+	if replayErr != nil {
+		return delivered, fmt.Errorf("outbox replay stopped with %d results left: %w", left, replayErr)
+	}
+
+	return delivered, nil
+}
+
+// batch returns a copy of the oldest limit results and the depth of the queue.
+func (o *FileOutbox) batch(limit int) ([]*domain.PingResult, int) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	return slices.Clone(o.items[:min(limit, len(o.items))]), len(o.items)
+}
+
+// commit removes the processed results from the queue and returns its depth.
+// Results are only ever appended or dropped from the front, so those processed
+// results Enqueue has not dropped in the meantime are at the front of the queue.
+func (o *FileOutbox) commit(processed []*domain.PingResult, rejected int) (int, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.dropped += uint64(rejected)
+	if len(processed) == 0 {
+		return len(o.items), nil
+	}
+
+	done := make(map[*domain.PingResult]struct{}, len(processed))
+	for _, result := range processed {
+		done[result] = struct{}{}
+	}
+
+	removed := 0
+	for _, result := range o.items {
+		if _, ok := done[result]; !ok {
+			break
+		}
+		removed++
+	}
+
+	o.items = o.items[removed:]
+
+	return len(o.items), o.rewrite()
+}
+
+func (o *FileOutbox) Stats() domain.OutboxStats {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	return domain.OutboxStats{
+		Depth:   len(o.items),
+		Dropped: o.dropped,
+	}
+}
+
+func (o *FileOutbox) load() error {
+	file, err := os.Open(o.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("outbox open failed: %w", err)
+	}
+	defer file.Close()
+
+	corrupted := 0
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var result domain.PingResult
+		if err := json.Unmarshal(scanner.Bytes(), &result); err != nil {
+			// A torn write at the end of the file is expected after a crash.
+			o.logger.Warnf("Skipping corrupted outbox entry: %v", err)
+			corrupted++
+			continue
+		}
+		o.items = append(o.items, &result)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("outbox read failed: %w", err)
+	}
+
+	if len(o.items) > o.maxItems {
+		dropped := len(o.items) - o.maxItems
+		o.items = o.items[dropped:]
+		o.dropped += uint64(dropped)
+		return o.rewrite()
+	}
+
+	// A torn line lacks its newline, the next append would continue it.
+	if corrupted > 0 {
+		return o.rewrite()
+	}
+
+	return nil
+}
+
+// rewrite atomically replaces the outbox file with the in-memory queue.
+func (o *FileOutbox) rewrite() error {
+	tmpPath := o.path + ".tmp"
+
+	file, err := os.OpenFile(tmpPath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, filePermissions)
+	if err != nil {
+		return fmt.Errorf("outbox temp file creation failed: %w", err)
+	}
+
+	writer := bufio.NewWriter(file)
+	encoder := json.NewEncoder(writer)
+	for _, result := range o.items {
+		if err := encoder.Encode(result); err != nil {
+			file.Close()
+			return fmt.Errorf("outbox marshal failed: %w", err)
+		}
+	}
+
+	if err := writer.Flush(); err != nil {
+		file.Close()
+		return fmt.Errorf("outbox write failed: %w", err)
+	}
+
+	if err := file.Sync(); err != nil {
+		file.Close()
+		return fmt.Errorf("outbox sync failed: %w", err)
+	}
+
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("outbox close failed: %w", err)
+	}
+
+	if err := os.Rename(tmpPath, o.path); err != nil {
+		return fmt.Errorf("outbox rename failed: %w", err)
+	}
+
+	return nil
+}
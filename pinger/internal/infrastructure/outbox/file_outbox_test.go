@@ -0,0 +1,273 @@
+package outbox_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/k6zma/DockerMonitoringApp/pinger/internal/application/repositories"
+	"github.com/k6zma/DockerMonitoringApp/pinger/internal/domain"
+	"github.com/k6zma/DockerMonitoringApp/pinger/internal/infrastructure/outbox"
+	"github.com/k6zma/DockerMonitoringApp/pinger/pkg/utils"
+)
+
+var logger = &utils.Logger{SugaredLogger: zap.NewNop().Sugar()}
+
+func newOutbox(t *testing.T, path string, maxItems int) repositories.OutboxRepository {
+	t.Helper()
+
+	o, err := outbox.NewFileOutbox(path, maxItems, logger)
+	require.NoError(t, err)
+
+	return o
+}
+
+func result(id string) *domain.PingResult {
+	return &domain.PingResult{
+		ContainerID: id,
+		Status:      "running",
+		CheckedAt:   time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+}
+
+// replayAll drains o and returns the IDs of the delivered results in order.
+func replayAll(t *testing.T, o repositories.OutboxRepository) []string {
+	t.Helper()
+
+	var delivered []string
+	_, err := o.Replay(context.Background(), 100, func(_ context.Context, result *domain.PingResult) error {
+		delivered = append(delivered, result.ContainerID)
+		return nil
+	})
+	require.NoError(t, err)
+
+	return delivered
+}
+
+func TestFileOutbox_DropsOldest(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "outbox.jsonl")
+	o := newOutbox(t, path, 3)
+
+	for i := range 5 {
+		require.NoError(t, o.Enqueue(result(fmt.Sprintf("c%d", i))))
+	}
+
+	assert.Equal(t, domain.OutboxStats{Depth: 3, Dropped: 2}, o.Stats())
+
+	// The file holds the same results as the queue.
+	assert.Equal(t, []string{"c2", "c3", "c4"}, replayAll(t, newOutbox(t, path, 3)))
+	assert.Equal(t, []string{"c2", "c3", "c4"}, replayAll(t, o))
+}
+
+func TestFileOutbox_DropsOldestOnLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "outbox.jsonl")
+	o := newOutbox(t, path, 5)
+	for i := range 5 {
+		require.NoError(t, o.Enqueue(result(fmt.Sprintf("c%d", i))))
+	}
+
+	smaller := newOutbox(t, path, 2)
+
+	assert.Equal(t, domain.OutboxStats{Depth: 2, Dropped: 3}, smaller.Stats())
+	assert.Equal(t, []string{"c3", "c4"}, replayAll(t, smaller))
+}
+
+func TestFileOutbox_Replay(t *testing.T) {
+	errBackendDown := errors.New("backend down")
+
+	tests := []struct {
+		name      string
+		outcomes  map[string]error
+		delivered []string
+		left      []string
+		dropped   uint64
+		wantErr   error
+	}{
+		{
+			name:      "all delivered",
+			delivered: []string{"c0", "c1", "c2", "c3"},
+		},
+		{
+			name:      "stops at transient failure",
+			outcomes:  map[string]error{"c2": errBackendDown},
+			delivered: []string{"c0", "c1"},
+			left:      []string{"c2", "c3"},
+			wantErr:   errBackendDown,
+		},
+		{
+			name:      "drops rejected",
+			outcomes:  map[string]error{"c1": fmt.Errorf("%w: 409 Conflict", repositories.ErrStatusRejected)},
+			delivered: []string{"c0", "c2", "c3"},
+			dropped:   1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "outbox.jsonl")
+			o := newOutbox(t, path, 10)
+			for i := range 4 {
+				require.NoError(t, o.Enqueue(result(fmt.Sprintf("c%d", i))))
+			}
+
+			var delivered []string
+			count, err := o.Replay(context.Background(), 100, func(_ context.Context, result *domain.PingResult) error {
+				if err := tt.outcomes[result.ContainerID]; err != nil {
+					return err
+				}
+				delivered = append(delivered, result.ContainerID)
+				return nil
+			})
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, len(tt.delivered), count)
+			assert.Equal(t, tt.delivered, delivered)
+			assert.Equal(t, domain.OutboxStats{Depth: len(tt.left), Dropped: tt.dropped}, o.Stats())
+
+			// What is left survives a restart, in order.
+			assert.Equal(t, tt.left, replayAll(t, newOutbox(t, path, 10)))
+		})
+	}
+}
+
+func TestFileOutbox_ReplayStopsWhenCanceled(t *testing.T) {
+	o := newOutbox(t, filepath.Join(t.TempDir(), "outbox.jsonl"), 10)
+	require.NoError(t, o.Enqueue(result("c0")))
+	require.NoError(t, o.Enqueue(result("c1")))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	count, err := o.Replay(ctx, 100, func(context.Context, *domain.PingResult) error {
+		cancel()
+		return nil
+	})
+
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, 1, count)
+	assert.Equal(t, 1, o.Stats().Depth)
+}
+
+func TestFileOutbox_ReplayLimit(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "outbox.jsonl")
+	o := newOutbox(t, path, 10)
+	for i := range 4 {
+		require.NoError(t, o.Enqueue(result(fmt.Sprintf("c%d", i))))
+	}
+
+	var delivered []string
+	count, err := o.Replay(context.Background(), 3, func(_ context.Context, result *domain.PingResult) error {
+		delivered = append(delivered, result.ContainerID)
+		return nil
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, count)
+	assert.Equal(t, []string{"c0", "c1", "c2"}, delivered)
+	assert.Equal(t, []string{"c3"}, replayAll(t, newOutbox(t, path, 10)))
+}
+
+func TestFileOutbox_ReplayDeliversWithoutLock(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "outbox.jsonl")
+	o := newOutbox(t, path, 3)
+	require.NoError(t, o.Enqueue(result("c0")))
+	require.NoError(t, o.Enqueue(result("c1")))
+
+	// While c0 is being delivered the queue stays usable, and the full outbox
+	// drops c0 to make room for c3.
+	_, err := o.Replay(context.Background(), 100, func(_ context.Context, queued *domain.PingResult) error {
+		if queued.ContainerID == "c0" {
+			assert.Equal(t, 2, o.Stats().Depth)
+			require.NoError(t, o.Enqueue(result("c2")))
+			require.NoError(t, o.Enqueue(result("c3")))
+		}
+		return nil
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, domain.OutboxStats{Depth: 2, Dropped: 1}, o.Stats())
+	assert.Equal(t, []string{"c2", "c3"}, replayAll(t, newOutbox(t, path, 3)))
+}
+
+func TestFileOutbox_RewriteIsAtomic(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "outbox.jsonl")
+	o := newOutbox(t, path, 2)
+	require.NoError(t, o.Enqueue(result("c0")))
+	require.NoError(t, o.Enqueue(result("c1")))
+
+	before, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	// A rewrite that cannot complete leaves the outbox file untouched.
+	require.NoError(t, os.Mkdir(path+".tmp", 0o700))
+	assert.Error(t, o.Enqueue(result("c2")))
+
+	after, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, before, after)
+
+	// Once it can, the temporary file replaces the outbox file.
+	require.NoError(t, os.Remove(path+".tmp"))
+	require.NoError(t, o.Enqueue(result("c3")))
+
+	assert.NoFileExists(t, path+".tmp")
+	assert.Equal(t, []string{"c2", "c3"}, replayAll(t, newOutbox(t, path, 2)))
+}
+
+func TestFileOutbox_IgnoresLeftoverTempFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "outbox.jsonl")
+	o := newOutbox(t, path, 10)
+	require.NoError(t, o.Enqueue(result("c0")))
+
+	// A crash during a rewrite leaves a partial temporary file behind.
+	require.NoError(t, os.WriteFile(path+".tmp", []byte(`{"container_id":"tmp"}`), 0o600))
+
+	assert.Equal(t, []string{"c0"}, replayAll(t, newOutbox(t, path, 10)))
+}
+
+func TestFileOutbox_CorruptedEntries(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{
+			name:    "truncated last line",
+			content: `{"container_id":"c0","status":"running"}` + "\n" + `{"container_id":"c1","sta`,
+		},
+		{
+			name:    "corrupted line",
+			content: `{"container_id":"c0","status":"running"}` + "\n" + "\x00\x00\x00\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "outbox.jsonl")
+			require.NoError(t, os.WriteFile(path, []byte(tt.content), 0o600))
+
+			o := newOutbox(t, path, 10)
+			assert.Equal(t, 1, o.Stats().Depth)
+
+			// The next result does not end up on the torn line.
+			require.NoError(t, o.Enqueue(result("c2")))
+
+			content, err := os.ReadFile(path)
+			require.NoError(t, err)
+			assert.Equal(t, 2, strings.Count(string(content), "\n"))
+			assert.Equal(t, []string{"c0", "c2"}, replayAll(t, newOutbox(t, path, 10)))
+		})
+	}
+}
@@ -0,0 +1,190 @@
+package tlsreload
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+
+	"github.com/fsnotify/fsnotify"
+
+	"github.com/k6zma/DockerMonitoringApp/pinger/internal/infrastructure/config"
+	"github.com/k6zma/DockerMonitoringApp/pinger/pkg/utils"
+)
+
+// Reloader holds the CA bundle and client certificate used to connect to the
+// backend and reloads them when the files change, so renewed certificates are
+// picked up without a restart.
+type Reloader struct {
+	caFile   string
+	certFile string
+	keyFile  string
+
+	mu       sync.RWMutex
+	contents [][]byte
+	rootCAs  *x509.CertPool
+	cert     *tls.Certificate
+
+	logger utils.LoggerInterface
+}
+
+// NewReloader loads the files of cfg and watches their directories until ctx
+// is done. A change that leaves the files unreadable or mismatched is logged
+// and the previous certificates stay in use.
+func NewReloader(ctx context.Context, cfg *config.BackendTLSConfig, logger utils.LoggerInterface) (*Reloader, error) {
+	r := &Reloader{
+		caFile:   cfg.CAFile,
+		certFile: cfg.CertFile,
+		keyFile:  cfg.KeyFile,
+		logger:   logger,
+	}
+
+	if err := r.reload(); err != nil {
+		return nil, err
+	}
+
+	dirs := r.dirs()
+	if len(dirs) == 0 {
+		return r, nil
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, fmt.Errorf("file watcher init failed: %w", err)
+	}
+
+	// Directories are watched instead of the files, so files replaced by a
+	// rename or a symlink swap are noticed as well.
+	for _, dir := range dirs {
+		if err := watcher.Add(dir); err != nil {
+			_ = watcher.Close()
+			return nil, fmt.Errorf("watching %s failed: %w", dir, err)
+		}
+	}
+
+	go r.watch(ctx, watcher)
+
+	return r, nil
+}
+
+// DialTLSContext opens a TLS connection with the latest CA bundle and client
+// certificate. Without a CA bundle the system roots are trusted.
+func (r *Reloader) DialTLSContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	r.mu.RLock()
+	cfg := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		RootCAs:    r.rootCAs,
+	}
+	if r.cert != nil {
+		cfg.Certificates = []tls.Certificate{*r.cert}
+	}
+	r.mu.RUnlock()
+
+	dialer := &tls.Dialer{Config: cfg}
+
+	return dialer.DialContext(ctx, network, addr)
+}
+
+func (r *Reloader) watch(ctx context.Context, watcher *fsnotify.Watcher) {
+	defer watcher.Close()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return
+			}
+			if event.Op == fsnotify.Chmod {
+				continue
+			}
+
+			if err := r.reload(); err != nil {
+				r.logger.Errorf("Backend TLS reload after change of %s failed: %v", event.Name, err)
+			}
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return
+			}
+			r.logger.Errorf("Backend TLS file watcher error: %v", err)
+		}
+	}
+}
+
+// reload reads the files and replaces the CA bundle and the client certificate
+// if their contents changed.
+func (r *Reloader) reload() error {
+	files := []string{r.caFile, r.certFile, r.keyFile}
+
+	contents := make([][]byte, len(files))
+	for i, file := range files {
+		if file == "" {
+			continue
+		}
+
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return fmt.Errorf("reading %s failed: %w", file, err)
+		}
+		contents[i] = data
+	}
+
+	r.mu.RLock()
+	unchanged := r.contents != nil && slices.EqualFunc(contents, r.contents, bytes.Equal)
+	r.mu.RUnlock()
+	if unchanged {
+		return nil
+	}
+
+	var rootCAs *x509.CertPool
+	if r.caFile != "" {
+		rootCAs = x509.NewCertPool()
+		if !rootCAs.AppendCertsFromPEM(contents[0]) {
+			return errors.New("no certificates found in " + r.caFile)
+		}
+	}
+
+	var cert *tls.Certificate
+	if r.certFile != "" {
+		pair, err := tls.X509KeyPair(contents[1], contents[2])
+		if err != nil {
+			return fmt.Errorf("loading key pair from %s and %s failed: %w", r.certFile, r.keyFile, err)
+		}
+		cert = &pair
+	}
+
+	r.mu.Lock()
+	r.contents = contents
+	r.rootCAs = rootCAs
+	r.cert = cert
+	r.mu.Unlock()
+
+	if cert != nil {
+		r.logger.Infof("Backend TLS client certificate for %s loaded, valid until %s", cert.Leaf.Subject, cert.Leaf.NotAfter)
+	}
+	if rootCAs != nil {
+		r.logger.Infof("Backend TLS CA bundle loaded from %s", r.caFile)
+	}
+
+	return nil
+}
+
+func (r *Reloader) dirs() []string {
+	var dirs []string
+	for _, file := range []string{r.caFile, r.certFile, r.keyFile} {
+		if file != "" {
+			dirs = append(dirs, filepath.Dir(file))
+		}
+	}
+	slices.Sort(dirs)
+
+	return slices.Compact(dirs)
+}
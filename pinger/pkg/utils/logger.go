@@ -18,6 +18,10 @@ type LoggerInterface interface {
 	Warnf(template string, args ...interface{})
 	Error(args ...interface{})
 	Errorf(template string, args ...interface{})
+	Debugw(msg string, keysAndValues ...interface{})
+	Infow(msg string, keysAndValues ...interface{})
+	Warnw(msg string, keysAndValues ...interface{})
+	Errorw(msg string, keysAndValues ...interface{})
 	DPanic(args ...interface{})
 	DPanicf(template string, args ...interface{})
 	Fatal(args ...interface{})
@@ -28,10 +32,19 @@ type Logger struct {
 	*zap.SugaredLogger
 }
 
+// Log formats accepted by NewLogger.
+const (
+	LogFormatConsole = "console"
+	LogFormatJSON    = "json"
+)
+
 var LoggerInstance *Logger
 var once sync.Once
 
-func NewLogger(level string) (*Logger, error) {
+// NewLogger creates the process-wide logger. The console format writes colored
+// lines for humans, the json format writes one JSON object per line with
+// structured fields for log collectors.
+func NewLogger(level, format string) (*Logger, error) {
 	var err error
 
 	once.Do(func() {
@@ -43,20 +56,19 @@ func NewLogger(level string) (*Logger, error) {
 			return
 		}
 
-		encoderConfig := zapcore.EncoderConfig{
-			TimeKey:          "time",
-			LevelKey:         "level",
-			NameKey:          "logger",
-			CallerKey:        "caller",
-			MessageKey:       "msg",
-			EncodeTime:       zapcore.ISO8601TimeEncoder,
-			EncodeLevel:      customColorLevelEncoder,
-			EncodeCaller:     zapcore.ShortCallerEncoder,
-			ConsoleSeparator: " ",
+		var encoder zapcore.Encoder
+		switch format {
+		case LogFormatConsole:
+			encoder = zapcore.NewConsoleEncoder(consoleEncoderConfig())
+		case LogFormatJSON:
+			encoder = zapcore.NewJSONEncoder(jsonEncoderConfig())
+		default:
+			err = fmt.Errorf("invalid logger format: %s", format)
+			return
 		}
 
 		core := zapcore.NewCore(
-			zapcore.NewConsoleEncoder(encoderConfig),
+			encoder,
 			zapcore.AddSync(os.Stdout),
 			zap.NewAtomicLevelAt(zapLevel),
 		)
@@ -71,6 +83,36 @@ func NewLogger(level string) (*Logger, error) {
 	return LoggerInstance, err
 }
 
+func consoleEncoderConfig() zapcore.EncoderConfig {
+	return zapcore.EncoderConfig{
+		TimeKey:          "time",
+		LevelKey:         "level",
+		NameKey:          "logger",
+		CallerKey:        "caller",
+		MessageKey:       "msg",
+		EncodeTime:       zapcore.ISO8601TimeEncoder,
+		EncodeLevel:      customColorLevelEncoder,
+		EncodeCaller:     zapcore.ShortCallerEncoder,
+		ConsoleSeparator: " ",
+	}
+}
+
+func jsonEncoderConfig() zapcore.EncoderConfig {
+	return zapcore.EncoderConfig{
+		TimeKey:        "time",
+		LevelKey:       "level",
+		NameKey:        "logger",
+		CallerKey:      "caller",
+		MessageKey:     "msg",
+		StacktraceKey:  "stacktrace",
+		LineEnding:     zapcore.DefaultLineEnding,
+		EncodeTime:     zapcore.ISO8601TimeEncoder,
+		EncodeLevel:    zapcore.LowercaseLevelEncoder,
+		EncodeCaller:   zapcore.ShortCallerEncoder,
+		EncodeDuration: zapcore.StringDurationEncoder,
+	}
+}
+
 func customColorLevelEncoder(level zapcore.Level, enc zapcore.PrimitiveArrayEncoder) {
 	switch level {
 	case zapcore.DebugLevel:
@@ -120,6 +162,27 @@ func (l *Logger) Errorf(template string, args ...interface{}) {
 	l.SugaredLogger.Errorf(template, args...)
 }
 
+func (l *Logger) Debugw(msg string, keysAndValues ...interface{}) {
+	l.SugaredLogger.Debugw(msg, keysAndValues...)
+}
+
+func (l *Logger) Infow(msg string, keysAndValues ...interface{}) {
+	l.SugaredLogger.Infow(msg, keysAndValues...)
+}
+
+func (l *Logger) Warnw(msg string, keysAndValues ...interface{}) {
+	l.SugaredLogger.Warnw(msg, keysAndValues...)
+}
+
+func (l *Logger) Errorw(msg string, keysAndValues ...interface{}) {
+	l.SugaredLogger.Errorw(msg, keysAndValues...)
+}
+
+// With returns a logger that adds the given key-value pairs to every entry.
+func (l *Logger) With(keysAndValues ...interface{}) *Logger {
+	return &Logger{l.SugaredLogger.With(keysAndValues...)}
+}
+
 func (l *Logger) DPanic(args ...interface{}) {
 	l.SugaredLogger.DPanic(args...)
 }